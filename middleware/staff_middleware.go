@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultStaffUsers = "admin"
+
+// StaffMiddleware restringe a rota aos usuários da equipe listados em STAFF_USERS,
+// separados por vírgula ("admin" quando a variável não está definida). Deve ser
+// usado depois de AuthMiddleware, que define o userID a partir do token.
+func StaffMiddleware() gin.HandlerFunc {
+	staff := staffUsers()
+
+	return func(c *gin.Context) {
+		if !staff[c.GetString("userID")] {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access restricted to staff"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func staffUsers() map[string]bool {
+	list := os.Getenv("STAFF_USERS")
+	if list == "" {
+		list = defaultStaffUsers
+	}
+
+	users := make(map[string]bool)
+	for _, user := range strings.Split(list, ",") {
+		if user = strings.TrimSpace(user); user != "" {
+			users[user] = true
+		}
+	}
+	return users
+}
@@ -3,6 +3,9 @@ package routes
 import (
 	"Varejo-Golang-Microservices/auth"
 	"Varejo-Golang-Microservices/middleware"
+	"log"
+	"os"
+	"time"
 
 	customerHandler "Varejo-Golang-Microservices/services/customer-service/api/handler"
 	customerRepository "Varejo-Golang-Microservices/services/customer-service/domain/repository"
@@ -17,11 +20,18 @@ import (
 	orderRepository "Varejo-Golang-Microservices/services/order-service/domain/repository"
 	orderService "Varejo-Golang-Microservices/services/order-service/domain/service"
 	paymentHandler "Varejo-Golang-Microservices/services/payment-service/api/handler"
+	paymentModel "Varejo-Golang-Microservices/services/payment-service/domain/model"
 	paymentRepository "Varejo-Golang-Microservices/services/payment-service/domain/repository"
 	paymentService "Varejo-Golang-Microservices/services/payment-service/domain/service"
+	paymentOrders "Varejo-Golang-Microservices/services/payment-service/infra/orders"
 	productHandler "Varejo-Golang-Microservices/services/product-service/api/handler"
 	productRepository "Varejo-Golang-Microservices/services/product-service/domain/repository"
 	productService "Varejo-Golang-Microservices/services/product-service/domain/service"
+	productFeed "Varejo-Golang-Microservices/services/product-service/infra/feed"
+	productLocations "Varejo-Golang-Microservices/services/product-service/infra/locations"
+	productOrders "Varejo-Golang-Microservices/services/product-service/infra/orders"
+	productSearch "Varejo-Golang-Microservices/services/product-service/infra/search"
+	productStorage "Varejo-Golang-Microservices/services/product-service/infra/storage"
 	promotionHandler "Varejo-Golang-Microservices/services/promotion-service/api/handler"
 	promotionRepository "Varejo-Golang-Microservices/services/promotion-service/domain/repository"
 	promotionService "Varejo-Golang-Microservices/services/promotion-service/domain/service"
@@ -43,6 +53,10 @@ func SetupRoutes(r *gin.Engine, mongoURI string, kafkaBroker string) {
 	authorized := r.Group("/")
 	authorized.Use(middleware.AuthMiddleware())
 
+	// Grupo para operações restritas à equipe da loja
+	staff := authorized.Group("/")
+	staff.Use(middleware.StaffMiddleware())
+
 	// Inicialize conexões, repositórios e serviços do cliente.
 	customerRepo := customerRepository.NewMongoCustomerRepository(mongoURI, kafkaBroker)
 	custService := customerService.NewCustomerService(customerRepo)
@@ -57,21 +71,151 @@ func SetupRoutes(r *gin.Engine, mongoURI string, kafkaBroker string) {
 	locationRepo := locationRepository.NewMongoLocationRepository(mongoURI, kafkaBroker)
 	locService := locationService.NewLocationService(locationRepo)
 	locHandler := locationHandler.NewLocationHandler(locService)
+	inventoryRepo := locationRepository.NewMongoInventoryRepository(mongoURI)
+	movementRepo := locationRepository.NewMongoMovementRepository(mongoURI)
+	inventoryServ := locationService.NewInventoryService(inventoryRepo, locationRepo, movementRepo)
+	inventoryHand := locationHandler.NewInventoryHandler(inventoryServ)
+	cycleCountRepo := locationRepository.NewMongoCycleCountRepository(mongoURI)
+	cycleCountServ := locationService.NewCycleCountService(cycleCountRepo, inventoryRepo, movementRepo, locationRepo, inventoryServ)
+	cycleCountHand := locationHandler.NewCycleCountHandler(cycleCountServ)
+	transferRepo := locationRepository.NewMongoTransferRepository(mongoURI)
+	transferServ := locationService.NewTransferService(transferRepo, locationRepo, inventoryServ)
+	transferHand := locationHandler.NewTransferHandler(transferServ)
+	supplierRepo := locationRepository.NewMongoSupplierRepository(mongoURI)
+	supplierServ := locationService.NewSupplierService(supplierRepo)
+	supplierHand := locationHandler.NewSupplierHandler(supplierServ)
+	purchaseOrderRepo := locationRepository.NewMongoPurchaseOrderRepository(mongoURI)
+	purchaseOrderServ := locationService.NewPurchaseOrderService(purchaseOrderRepo, supplierRepo, locationRepo, inventoryServ)
+	purchaseOrderHand := locationHandler.NewPurchaseOrderHandler(purchaseOrderServ)
+	if err := inventoryServ.OpenLedger(); err != nil {
+		log.Printf("Erro ao lançar os saldos de abertura do livro de estoque: %v\n", err)
+	}
+	go inventoryServ.RunLedgerReconciliation(time.Minute)
 
 	// Inicialize conexões, repositórios e serviços do cliente.
 	orderRepo := orderRepository.NewMongoOrderRepository(mongoURI, kafkaBroker)
 	ordService := orderService.NewOrderService(orderRepo)
 	ordHandler := orderHandler.NewOrderHandler(ordService)
+	go orderService.ListenPaymentIntents(kafkaBroker, ordService)
+	orderServiceURL := os.Getenv("ORDER_SERVICE_URL")
+	if orderServiceURL == "" {
+		orderServiceURL = "http://localhost:8094"
+	}
+	locationServiceURL := os.Getenv("LOCATION_SERVICE_URL")
+	if locationServiceURL == "" {
+		locationServiceURL = "http://localhost:8094"
+	}
 
 	// Inicialize conexões, repositórios e serviços do cliente
+	cardTokenKey := os.Getenv("CARD_TOKEN_KEY")
+	if cardTokenKey == "" {
+		log.Fatal("CARD_TOKEN_KEY não definida: a chave é obrigatória para tokenizar cartões")
+	}
+	paymentModel.SetCardTokenKey(cardTokenKey)
+	giftCardPINKey := os.Getenv("GIFT_CARD_PIN_KEY")
+	if giftCardPINKey == "" {
+		log.Fatal("GIFT_CARD_PIN_KEY não definida: a chave é obrigatória para os PINs de cartão-presente")
+	}
+	paymentModel.SetGiftCardPINKey(giftCardPINKey)
 	payRepo := paymentRepository.NewMongoPaymentRepository(mongoURI, kafkaBroker)
-	payService := paymentService.NewPaymentService(payRepo)
+	intentRepo := paymentRepository.NewMongoPaymentIntentRepository(mongoURI, kafkaBroker)
+	fraudRepo := paymentRepository.NewMongoFraudRepository(mongoURI)
+	fraudServ := paymentService.NewFraudService(fraudRepo, payRepo, intentRepo)
+	payService := paymentService.NewPaymentService(payRepo, fraudServ)
 	payHandler := paymentHandler.NewPaymentHandler(payService)
+	fraudHand := paymentHandler.NewFraudHandler(fraudServ)
+	walletRepo := paymentRepository.NewMongoWalletRepository(mongoURI)
+	walletServ := paymentService.NewWalletService(walletRepo)
+	walletHand := paymentHandler.NewWalletHandler(walletServ)
+	tenderProcessors := paymentService.DefaultTenderProcessors()
+	tenderProcessors[paymentModel.GiftCardPayment] = paymentService.NewGiftCardTenderProcessor(walletServ)
+	tenderProcessors[paymentModel.StoreCreditPayment] = paymentService.NewStoreCreditTenderProcessor(walletServ)
+	intentServ := paymentService.NewPaymentIntentService(intentRepo, tenderProcessors, fraudServ, paymentOrders.NewClient(orderServiceURL))
+	intentHand := paymentHandler.NewPaymentIntentHandler(intentServ)
+	go intentServ.RunRecovery(time.Minute)
+	disputeRepo := paymentRepository.NewMongoDisputeRepository(mongoURI, kafkaBroker)
+	disputeServ := paymentService.NewDisputeService(disputeRepo, payRepo)
+	disputeHand := paymentHandler.NewDisputeHandler(disputeServ, os.Getenv("DISPUTE_WEBHOOK_SECRET"))
 
 	// Initialize product connections, repositories, services, and handlers.
 	prodRepo := productRepository.NewMongoProductRepository(mongoURI, kafkaBroker)
-	prodServ := productService.NewProductService(prodRepo)
+	categoryRepo := productRepository.NewMongoCategoryRepository(mongoURI)
+	categoryServ := productService.NewCategoryService(categoryRepo, prodRepo)
+	if err := categoryServ.BackfillProductCategories(); err != nil {
+		log.Printf("Erro ao vincular produtos às categorias: %v\n", err)
+	}
+	categoryHand := productHandler.NewCategoryHandler(categoryServ)
+	priceRepo := productRepository.NewMongoPriceRepository(mongoURI)
+	priceServ := productService.NewPriceService(priceRepo, prodRepo)
+	priceHand := productHandler.NewPriceHandler(priceServ)
+	go priceServ.RunScheduler(time.Minute)
+	barcodeRepo := productRepository.NewMongoBarcodeRepository(mongoURI)
+	barcodeServ := productService.NewBarcodeService(barcodeRepo, prodRepo, os.Getenv("GS1_COMPANY_PREFIX"))
+	barcodeHand := productHandler.NewBarcodeHandler(barcodeServ)
+	go barcodeServ.SyncRegistry()
+	bundleServ := productService.NewBundleService(prodRepo, priceServ)
+	bundleHand := productHandler.NewBundleHandler(bundleServ)
+	go bundleServ.ListenProductEvents(kafkaBroker)
+	prodServ := productService.NewProductService(prodRepo, categoryServ, priceServ, barcodeServ, bundleServ)
 	prodHand := productHandler.NewProductHandler(prodServ)
+	mediaDir := os.Getenv("MEDIA_DIR")
+	if mediaDir == "" {
+		mediaDir = "./data/media"
+	}
+	mediaBaseURL := os.Getenv("MEDIA_BASE_URL")
+	if mediaBaseURL == "" {
+		mediaBaseURL = "/media"
+	}
+	mediaStorage, err := productStorage.NewLocalStorage(mediaDir, mediaBaseURL)
+	if err != nil {
+		log.Fatalf("Erro ao inicializar o armazenamento de mídia: %v", err)
+	}
+	mediaServ := productService.NewMediaService(prodRepo, mediaStorage)
+	mediaHand := productHandler.NewMediaHandler(mediaServ)
+	reviewRepo := productRepository.NewMongoReviewRepository(mongoURI)
+	reviewServ := productService.NewReviewService(reviewRepo, prodRepo, productOrders.NewClient(orderServiceURL), mediaStorage)
+	reviewHand := productHandler.NewReviewHandler(reviewServ)
+	reservationRepo := productRepository.NewMongoReservationRepository(mongoURI)
+	stockServ := productService.NewStockService(prodRepo, reservationRepo)
+	stockHand := productHandler.NewStockHandler(stockServ)
+	go stockServ.RunExpiry(time.Minute)
+	importJobRepo := productRepository.NewMongoImportJobRepository(mongoURI)
+	importServ := productService.NewImportService(importJobRepo, prodRepo, prodServ, stockServ, categoryServ)
+	importHand := productHandler.NewImportHandler(importServ)
+	importServ.FailInterruptedJobs()
+	searchServ := productService.NewSearchService(prodRepo, productSearch.NewIndex())
+	searchHand := productHandler.NewSearchHandler(searchServ)
+	go searchServ.ListenProductEvents(kafkaBroker)
+	recommendationRepo := productRepository.NewMongoRecommendationRepository(mongoURI)
+	recommendationServ := productService.NewRecommendationService(recommendationRepo, prodRepo, productService.DefaultRecommendationHalfLife)
+	recommendationHand := productHandler.NewRecommendationHandler(recommendationServ)
+	go recommendationServ.ListenOrderEvents(kafkaBroker)
+	feedSiteURL := os.Getenv("FEED_SITE_URL")
+	if feedSiteURL == "" {
+		feedSiteURL = "http://localhost:8094"
+	}
+	feedServ := productService.NewFeedService(prodRepo, categoryServ, productFeed.Config{
+		Title:        "Catálogo de produtos",
+		Description:  "Feed de produtos para o Google Merchant Center",
+		SiteURL:      feedSiteURL,
+		Currency:     "BRL",
+		DefaultBrand: os.Getenv("FEED_DEFAULT_BRAND"),
+	})
+	feedHand := productHandler.NewFeedHandler(feedServ)
+	go feedServ.ListenProductEvents(kafkaBroker)
+	go feedServ.RunRebuild(time.Hour)
+	replenishmentRepo := productRepository.NewMongoReplenishmentRepository(mongoURI)
+	replenishmentServ := productService.NewReplenishmentService(replenishmentRepo, prodRepo, productLocations.NewClient(locationServiceURL))
+	replenishmentHand := productHandler.NewReplenishmentHandler(replenishmentServ)
+	go replenishmentServ.ListenOrderEvents(kafkaBroker)
+	go replenishmentServ.ListenProductEvents(kafkaBroker)
+	go replenishmentServ.RunMonitor(15 * time.Minute)
+	forecastRepo := productRepository.NewMongoForecastRepository(mongoURI)
+	forecastServ := productService.NewForecastService(forecastRepo, prodRepo, productLocations.NewClient(locationServiceURL))
+	forecastHand := productHandler.NewForecastHandler(forecastServ)
+	go forecastServ.RunNightly(productService.DefaultForecastHour)
+	translationServ := productService.NewTranslationService(prodRepo, categoryRepo, categoryServ)
+	translationHand := productHandler.NewTranslationHandler(translationServ)
 
 	// Inicialização do Promotion Service
 	promotionRepo := promotionRepository.NewMongoPromotionRepository(mongoURI, kafkaBroker)
@@ -108,9 +252,59 @@ func SetupRoutes(r *gin.Engine, mongoURI string, kafkaBroker string) {
 	r.POST("/locations", locHandler.AddLocation)
 	r.PUT("/locations/:id", locHandler.UpdateLocation)
 	r.DELETE("/locations/:id", locHandler.DeleteLocation)
+	r.GET("/locations/:id/inventory", inventoryHand.GetLocationInventory)
+
+	// Configura routes para o estoque por localização do location-service
+	r.GET("/inventory/product/:productId", inventoryHand.GetProductInventory)
+	r.GET("/inventory/product/:productId/regions", inventoryHand.GetRegionAvailability)
+	authorized.POST("/inventory/adjust", inventoryHand.AdjustInventory)
+	authorized.POST("/inventory/reserve", inventoryHand.Reserve)
+	authorized.POST("/inventory/release", inventoryHand.Release)
+	authorized.POST("/inventory/commit", inventoryHand.Commit)
+	authorized.POST("/inventory/allocate", inventoryHand.Allocate)
+	r.GET("/inventory/movements", inventoryHand.ListMovements)
+	authorized.POST("/inventory/movements", inventoryHand.RecordMovement)
+	r.GET("/inventory/ledger/balance", inventoryHand.GetLedgerBalance)
+	r.GET("/inventory/reports/shrinkage", inventoryHand.GetShrinkageReport)
+
+	// Configura routes para as contagens cíclicas do location-service
+	r.GET("/cycle-counts", cycleCountHand.ListCounts)
+	authorized.POST("/cycle-counts", cycleCountHand.StartCount)
+	r.GET("/cycle-counts/:id", cycleCountHand.GetCount)
+	authorized.PUT("/cycle-counts/:id/lines", cycleCountHand.RecordCounts)
+	authorized.POST("/cycle-counts/:id/complete", cycleCountHand.CompleteCount)
+	authorized.POST("/cycle-counts/:id/cancel", cycleCountHand.CancelCount)
+
+	// Configura routes para as transferências entre localizações do location-service
+	r.GET("/transfers", transferHand.ListTransfers)
+	authorized.POST("/transfers", transferHand.CreateTransfer)
+	r.GET("/transfers/:id", transferHand.GetTransfer)
+	authorized.POST("/transfers/:id/pick", transferHand.PickTransfer)
+	authorized.POST("/transfers/:id/dispatch", transferHand.DispatchTransfer)
+	authorized.POST("/transfers/:id/receive", transferHand.ReceiveTransfer)
+	authorized.POST("/transfers/:id/cancel", transferHand.CancelTransfer)
+
+	// Configura routes para fornecedores e pedidos de compra do location-service
+	r.GET("/suppliers", supplierHand.ListSuppliers)
+	r.POST("/suppliers", supplierHand.CreateSupplier)
+	r.GET("/suppliers/:id", supplierHand.GetSupplier)
+	r.PUT("/suppliers/:id", supplierHand.UpdateSupplier)
+	r.PUT("/suppliers/:id/prices", supplierHand.SetPrice)
+	r.DELETE("/suppliers/:id/prices/:productId", supplierHand.RemovePrice)
+	r.GET("/purchase-orders", purchaseOrderHand.ListPurchaseOrders)
+	authorized.POST("/purchase-orders", purchaseOrderHand.CreatePurchaseOrder)
+	r.GET("/purchase-orders/:id", purchaseOrderHand.GetPurchaseOrder)
+	authorized.PUT("/purchase-orders/:id", purchaseOrderHand.UpdateDraft)
+	authorized.POST("/purchase-orders/:id/send", purchaseOrderHand.SendPurchaseOrder)
+	authorized.POST("/purchase-orders/:id/receipts", purchaseOrderHand.ReceivePurchaseOrder)
+	authorized.POST("/purchase-orders/:id/receipts/:receiptId/post", purchaseOrderHand.PostReceipt)
+	authorized.POST("/purchase-orders/:id/close", purchaseOrderHand.ClosePurchaseOrder)
+	authorized.POST("/purchase-orders/:id/cancel", purchaseOrderHand.CancelPurchaseOrder)
 
 	// Configura routes para o order-service
 	r.GET("/orders", ordHandler.GetAllOrders)
+	// Consulta interna do serviço de produtos, que se autentica com o token do serviço
+	authorized.GET("/orders/verify-purchase", ordHandler.VerifyPurchase)
 	r.GET("/orders/:id", ordHandler.GetOrderByID)
 	r.POST("/orders", ordHandler.AddOrder)
 	r.PUT("/orders/:id", ordHandler.UpdateOrderStatus)
@@ -123,12 +317,124 @@ func SetupRoutes(r *gin.Engine, mongoURI string, kafkaBroker string) {
 	r.PUT("/payments/:id", payHandler.UpdatePayment)
 	r.DELETE("/payments/:id", payHandler.DeletePayment)
 
+	// Configura routes para o motor de fraude do payment-service
+	r.GET("/fraud/rules", fraudHand.GetRules)
+	authorized.PUT("/fraud/rules", fraudHand.UpdateRules)
+	r.GET("/fraud/assessments", fraudHand.ListAssessments)
+	r.GET("/fraud/reviews", fraudHand.ListReviews)
+	authorized.POST("/fraud/reviews/:id/approve", fraudHand.ApproveReview)
+	authorized.POST("/fraud/reviews/:id/deny", fraudHand.DenyReview)
+	r.GET("/fraud/blacklist", fraudHand.ListBlacklist)
+	authorized.POST("/fraud/blacklist", fraudHand.AddBlacklistEntry)
+	authorized.DELETE("/fraud/blacklist/:id", fraudHand.DeleteBlacklistEntry)
+
+	// Configura routes para pagamento dividido (split tender) do payment-service
+	r.POST("/payment-intents", intentHand.CreateIntent)
+	r.GET("/payment-intents/:id", intentHand.GetIntentByID)
+	r.GET("/payment-intents/order/:orderId", intentHand.GetIntentByOrderID)
+	r.POST("/payment-intents/:id/tenders", intentHand.AddTender)
+	r.DELETE("/payment-intents/:id/tenders/:tenderId", intentHand.RemoveTender)
+	r.POST("/payment-intents/:id/authorize", intentHand.Authorize)
+	r.POST("/payment-intents/:id/capture", intentHand.Capture)
+	r.POST("/payment-intents/:id/cancel", intentHand.Cancel)
+
+	// Configura routes para cartões-presente e crédito em loja do payment-service
+	staff.POST("/gift-cards", walletHand.IssueGiftCard)
+	staff.POST("/gift-cards/:code/activate", walletHand.ActivateGiftCard)
+	staff.POST("/gift-cards/:code/block", walletHand.BlockGiftCard)
+	r.POST("/gift-cards/:code/balance", walletHand.GetGiftCardBalance)
+	r.POST("/gift-cards/:code/transactions", walletHand.ListGiftCardTransactions)
+	r.GET("/store-credit/:customerId", walletHand.GetStoreCredit)
+	staff.POST("/store-credit/:customerId/credit", walletHand.CreditStoreCredit)
+	r.GET("/store-credit/:customerId/transactions", walletHand.ListStoreCreditTransactions)
+
+	// Configura routes para disputas (chargebacks) do payment-service
+	authorized.POST("/disputes", disputeHand.OpenDispute)
+	r.GET("/disputes", disputeHand.ListDisputes)
+	r.GET("/disputes/due", disputeHand.ListDueSoon)
+	r.GET("/disputes/:id", disputeHand.GetDisputeByID)
+	authorized.POST("/disputes/:id/evidence", disputeHand.AddEvidence)
+	authorized.POST("/disputes/:id/submit", disputeHand.SubmitEvidence)
+	authorized.POST("/disputes/:id/resolve", disputeHand.Resolve)
+	r.GET("/disputes/:id/ledger", disputeHand.ListLedgerEntries)
+	r.POST("/webhooks/disputes/:provider", disputeHand.ProviderWebhook)
+
 	// Configura routes para o product-service
 	r.GET("/products", prodHand.ListProducts)
+	r.GET("/products/search", searchHand.SearchProducts)
+	r.GET("/products/export", importHand.ExportCatalog)
+	r.POST("/products/import", importHand.StartImport)
+	r.GET("/products/import/jobs", importHand.ListJobs)
+	r.GET("/products/import/jobs/:id", importHand.GetJob)
+	r.GET("/products/import/jobs/:id/errors", importHand.DownloadErrorReport)
 	r.GET("/products/:id", prodHand.GetProductByID)
 	r.POST("/products", prodHand.AddProduct)
 	r.PUT("/products/:id", prodHand.UpdateProduct)
 	r.DELETE("/products/:id", prodHand.DeleteProduct)
+	r.POST("/products/:id/stock", stockHand.AdjustStock)
+	r.GET("/products/sku/:sku", prodHand.GetProductBySKU)
+	r.GET("/products/by-barcode/:gtin", barcodeHand.GetByBarcode)
+	r.POST("/products/:id/barcode", barcodeHand.GenerateEAN13)
+	r.PUT("/products/:id/options", prodHand.UpdateOptions)
+	r.POST("/products/:id/variants", prodHand.AddVariant)
+	r.PUT("/products/:id/variants/:sku", prodHand.UpdateVariant)
+	r.DELETE("/products/:id/variants/:sku", prodHand.RemoveVariant)
+	r.GET("/products/:id/prices", priceHand.GetPriceHistory)
+	r.POST("/products/:id/prices", priceHand.SchedulePrice)
+	r.DELETE("/products/:id/prices/:changeId", priceHand.CancelPriceChange)
+	r.GET("/products/:id/price-at", priceHand.GetPriceAt)
+	r.GET("/products/:id/media", mediaHand.ListMedia)
+	r.POST("/products/:id/media", mediaHand.UploadMedia)
+	r.PUT("/products/:id/media/order", mediaHand.ReorderMedia)
+	r.DELETE("/products/:id/media/:mediaId", mediaHand.DeleteMedia)
+	r.GET("/media/*key", mediaHand.ServeMedia)
+	r.GET("/products/:id/recommendations", recommendationHand.GetRecommendations)
+	r.POST("/recommendations/recompute", recommendationHand.Recompute)
+	r.GET("/products/:id/reviews", reviewHand.ListReviews)
+	authorized.POST("/products/:id/reviews", reviewHand.CreateReview)
+	authorized.GET("/reviews/moderation", reviewHand.ModerationQueue)
+	authorized.POST("/reviews/:id/approve", reviewHand.Approve)
+	authorized.POST("/reviews/:id/reject", reviewHand.Reject)
+	authorized.POST("/reviews/:id/votes", reviewHand.Vote)
+	r.GET("/feeds/:file", feedHand.GetGoogleFeed)
+	r.GET("/bundles", bundleHand.ListBundles)
+	r.PUT("/products/:id/bundle", bundleHand.UpdateBundle)
+	r.GET("/products/:id/bundle", bundleHand.GetBreakdown)
+	r.PUT("/products/:id/replenishment", replenishmentHand.SetPolicy)
+	r.DELETE("/products/:id/replenishment", replenishmentHand.DeletePolicy)
+	r.GET("/replenishment/policies", replenishmentHand.ListPolicies)
+	r.GET("/replenishment/report", replenishmentHand.GetReport)
+	r.GET("/products/:id/forecasts", forecastHand.GetProductForecasts)
+	r.GET("/forecasts", forecastHand.ListForecasts)
+	r.POST("/forecasts/refresh", forecastHand.Refresh)
+	r.GET("/products/:id/translations", translationHand.GetProductTranslations)
+	r.PUT("/products/:id/translations/:locale", translationHand.SetProductTranslation)
+	r.DELETE("/products/:id/translations/:locale", translationHand.RemoveProductTranslation)
+	r.GET("/categories/:id/translations", translationHand.GetCategoryTranslations)
+	r.PUT("/categories/:id/translations/:locale", translationHand.SetCategoryTranslation)
+	r.DELETE("/categories/:id/translations/:locale", translationHand.RemoveCategoryTranslation)
+	r.GET("/translations/incomplete", translationHand.ListIncomplete)
+
+	// Configura routes para a árvore de categorias do product-service
+	r.GET("/categories", categoryHand.ListCategories)
+	r.GET("/categories/tree", categoryHand.GetTree)
+	r.GET("/categories/path/*path", categoryHand.GetCategoryByPath)
+	r.GET("/categories/:id", categoryHand.GetCategory)
+	r.GET("/categories/:id/products", categoryHand.ListProducts)
+	r.GET("/categories/:id/attributes", categoryHand.GetAttributeSchema)
+	r.PUT("/categories/:id/attributes", categoryHand.SetAttributeSchema)
+	r.PUT("/categories/:id/google-category", feedHand.SetCategoryMapping)
+	r.POST("/categories", categoryHand.CreateCategory)
+	r.PUT("/categories/:id", categoryHand.UpdateCategory)
+	r.POST("/categories/:id/move", categoryHand.MoveCategory)
+	r.POST("/categories/:id/merge", categoryHand.MergeCategory)
+	r.DELETE("/categories/:id", categoryHand.DeleteCategory)
+
+	// Configura routes para reservas de estoque do product-service
+	r.POST("/reservations", stockHand.Reserve)
+	r.GET("/reservations/:id", stockHand.GetReservation)
+	r.POST("/reservations/:id/commit", stockHand.Commit)
+	r.POST("/reservations/:id/release", stockHand.Release)
 
 	// Configura routes para o promotion-service
 	r.GET("/promotions", promHandler.ListPromotions)
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"Varejo-Golang-Microservices/services/location-service/domain/service"
+	"Varejo-Golang-Microservices/services/location-service/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CycleCountHandler struct {
+	Service service.CycleCountService
+}
+
+// Inicializa um novo manipulador de contagens cíclicas com o serviço fornecido
+func NewCycleCountHandler(s service.CycleCountService) *CycleCountHandler {
+	return &CycleCountHandler{
+		Service: s,
+	}
+}
+
+// Abre uma contagem; sem itens, conta todo o estoque da localização
+func (h *CycleCountHandler) StartCount(c *gin.Context) {
+	var countDTO dto.CycleCountDTO
+	if err := c.ShouldBindJSON(&countDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	items := make([]model.AllocationItem, 0, len(countDTO.Items))
+	for _, item := range countDTO.Items {
+		items = append(items, model.AllocationItem{ProductID: item.ProductID, SKU: item.SKU})
+	}
+
+	count, err := h.Service.StartCount(countDTO.LocationID, items, countDTO.Notes, actor(c))
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": "Erro ao abrir contagem. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Contagem aberta com sucesso.", "data": count})
+}
+
+func (h *CycleCountHandler) GetCount(c *gin.Context) {
+	count, err := h.Service.GetCount(c.Param("id"))
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, count)
+}
+
+// Lista as contagens (?locationId, status)
+func (h *CycleCountHandler) ListCounts(c *gin.Context) {
+	counts, err := h.Service.ListCounts(c.Query("locationId"), model.CycleCountStatus(c.Query("status")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar contagens"})
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
+
+// Registra as quantidades contadas de um ou mais itens
+func (h *CycleCountHandler) RecordCounts(c *gin.Context) {
+	var entriesDTO dto.CycleCountEntriesDTO
+	if err := c.ShouldBindJSON(&entriesDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entries := make([]model.CycleCountEntry, 0, len(entriesDTO.Entries))
+	for _, entry := range entriesDTO.Entries {
+		entries = append(entries, model.CycleCountEntry{ProductID: entry.ProductID, SKU: entry.SKU, Counted: entry.Counted})
+	}
+
+	count, err := h.Service.RecordCounts(c.Param("id"), entries, actor(c))
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": "Erro ao registrar contagem. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Contagem registrada com sucesso.", "data": count})
+}
+
+// Conclui a contagem lançando os ajustes das diferenças
+func (h *CycleCountHandler) CompleteCount(c *gin.Context) {
+	count, err := h.Service.CompleteCount(c.Param("id"), actor(c))
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": "Erro ao concluir contagem. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Contagem concluída com sucesso.", "data": count})
+}
+
+func (h *CycleCountHandler) CancelCount(c *gin.Context) {
+	count, err := h.Service.CancelCount(c.Param("id"))
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": "Erro ao cancelar contagem. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Contagem cancelada com sucesso.", "data": count})
+}
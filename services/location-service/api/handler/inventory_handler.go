@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"Varejo-Golang-Microservices/services/location-service/domain/repository"
+	"Varejo-Golang-Microservices/services/location-service/domain/service"
+	"Varejo-Golang-Microservices/services/location-service/dto"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InventoryHandler struct {
+	Service service.InventoryService
+}
+
+// Inicializa um novo manipulador de estoque por localização com o serviço fornecido
+func NewInventoryHandler(s service.InventoryService) *InventoryHandler {
+	return &InventoryHandler{
+		Service: s,
+	}
+}
+
+func inventoryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrLocationNotFound), errors.Is(err, service.ErrCycleCountNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrInsufficientStock), errors.Is(err, service.ErrNoFulfillingLocation),
+		errors.Is(err, repository.ErrCycleCountClosed):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// Lista o estoque de um produto em cada localização
+func (h *InventoryHandler) GetProductInventory(c *gin.Context) {
+	records, err := h.Service.GetProductInventory(c.Param("productId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar estoque do produto"})
+		return
+	}
+
+	c.JSON(http.StatusOK, records)
+}
+
+// Lista o estoque de todos os produtos de uma localização
+func (h *InventoryHandler) GetLocationInventory(c *gin.Context) {
+	records, err := h.Service.GetLocationInventory(c.Param("id"))
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, records)
+}
+
+// Disponibilidade agregada do produto por região (?region= filtra uma região)
+func (h *InventoryHandler) GetRegionAvailability(c *gin.Context) {
+	availability, err := h.Service.GetRegionAvailability(c.Param("productId"), c.Query("region"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar disponibilidade por região"})
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+func (h *InventoryHandler) AdjustInventory(c *gin.Context) {
+	var adjustmentDTO dto.InventoryAdjustmentDTO
+	if err := c.ShouldBindJSON(&adjustmentDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	movement := &model.StockMovement{
+		ProductID:  adjustmentDTO.ProductID,
+		SKU:        adjustmentDTO.SKU,
+		LocationID: adjustmentDTO.LocationID,
+		Type:       adjustmentDTO.Type,
+		Quantity:   adjustmentDTO.OnHandDelta,
+		Reason:     adjustmentDTO.Reason,
+		Reference:  adjustmentDTO.Reference,
+		UserID:     actor(c),
+	}
+
+	record, err := h.Service.AdjustInventory(movement, adjustmentDTO.InTransitDelta)
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": "Erro ao ajustar estoque. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Estoque ajustado com sucesso.", "data": record})
+}
+
+func (h *InventoryHandler) Reserve(c *gin.Context) {
+	h.applyQuantity(c, h.Service.Reserve, "Estoque reservado com sucesso.")
+}
+
+func (h *InventoryHandler) Release(c *gin.Context) {
+	h.applyQuantity(c, h.Service.Release, "Reserva liberada com sucesso.")
+}
+
+// Baixa a reserva lançando a venda no livro; reference é o pedido atendido
+func (h *InventoryHandler) Commit(c *gin.Context) {
+	var quantityDTO dto.InventoryQuantityDTO
+	if err := c.ShouldBindJSON(&quantityDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	record, err := h.Service.Commit(quantityDTO.ProductID, quantityDTO.SKU, quantityDTO.LocationID, quantityDTO.Quantity, quantityDTO.Reference, actor(c))
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reserva baixada do estoque com sucesso.", "data": record})
+}
+
+func (h *InventoryHandler) applyQuantity(c *gin.Context, operation func(productID, sku, locationID string, quantity int) (*model.InventoryRecord, error), message string) {
+	var quantityDTO dto.InventoryQuantityDTO
+	if err := c.ShouldBindJSON(&quantityDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	record, err := operation(quantityDTO.ProductID, quantityDTO.SKU, quantityDTO.LocationID, quantityDTO.Quantity)
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message, "data": record})
+}
+
+// Escolhe a localização que deve atender o pedido
+func (h *InventoryHandler) Allocate(c *gin.Context) {
+	var allocationDTO dto.AllocationDTO
+	if err := c.ShouldBindJSON(&allocationDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	items := make([]model.AllocationItem, 0, len(allocationDTO.Items))
+	for _, item := range allocationDTO.Items {
+		items = append(items, model.AllocationItem{ProductID: item.ProductID, SKU: item.SKU, Quantity: item.Quantity})
+	}
+
+	var origin *model.GeoPoint
+	if allocationDTO.Latitude != nil || allocationDTO.Longitude != nil {
+		if allocationDTO.Latitude == nil || allocationDTO.Longitude == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "informe latitude e longitude juntas"})
+			return
+		}
+		origin = &model.GeoPoint{Latitude: *allocationDTO.Latitude, Longitude: *allocationDTO.Longitude}
+	}
+
+	allocation, err := h.Service.Allocate(items, origin, allocationDTO.Region)
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, allocation)
+}
+
+// Lança um movimento no livro de estoque (venda, devolução, ajuste, transferência,
+// recebimento ou quebra). A quantidade tem o sinal do efeito sobre o estoque físico.
+func (h *InventoryHandler) RecordMovement(c *gin.Context) {
+	var movementDTO dto.StockMovementDTO
+	if err := c.ShouldBindJSON(&movementDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	movement := &model.StockMovement{
+		ProductID:     movementDTO.ProductID,
+		SKU:           movementDTO.SKU,
+		LocationID:    movementDTO.LocationID,
+		Type:          movementDTO.Type,
+		Quantity:      movementDTO.Quantity,
+		Reason:        movementDTO.Reason,
+		ReferenceType: movementDTO.ReferenceType,
+		Reference:     movementDTO.Reference,
+		UserID:        actor(c),
+	}
+
+	record, err := h.Service.RecordMovement(movement)
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": "Erro ao lançar movimento. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Movimento lançado com sucesso.", "data": gin.H{"movement": movement, "inventory": record}})
+}
+
+// Consulta o livro de movimentos.
+// Parâmetros: productId, sku, locationId, type, reference, from, to (RFC 3339), page, pageSize.
+func (h *InventoryHandler) ListMovements(c *gin.Context) {
+	filter := model.MovementFilter{
+		ProductID:  c.Query("productId"),
+		LocationID: c.Query("locationId"),
+		Type:       model.MovementType(c.Query("type")),
+		Reference:  c.Query("reference"),
+	}
+	if sku, ok := c.GetQuery("sku"); ok {
+		filter.SKU = &sku
+	}
+
+	var err error
+	if filter.From, err = queryTime(c, "from"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro from inválido"})
+		return
+	}
+	if filter.To, err = queryTime(c, "to"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro to inválido"})
+		return
+	}
+	page, err := queryInt(c, "page")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro page inválido"})
+		return
+	}
+	pageSize, err := queryInt(c, "pageSize")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro pageSize inválido"})
+		return
+	}
+
+	movements, total, err := h.Service.ListMovements(filter, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao consultar movimentos de estoque"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"total": total, "movements": movements})
+}
+
+// Confere o estoque físico com a soma do livro (?productId, sku, locationId)
+func (h *InventoryHandler) GetLedgerBalance(c *gin.Context) {
+	balance, err := h.Service.GetLedgerBalance(c.Query("productId"), c.Query("sku"), c.Query("locationId"))
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, balance)
+}
+
+// Relatório de quebras do período (?locationId, from, to). Sem período, os últimos 30 dias.
+func (h *InventoryHandler) GetShrinkageReport(c *gin.Context) {
+	from, err := queryTime(c, "from")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro from inválido"})
+		return
+	}
+	to, err := queryTime(c, "to")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro to inválido"})
+		return
+	}
+
+	report, err := h.Service.GetShrinkageReport(c.Query("locationId"), from, to)
+	if err != nil {
+		c.JSON(inventoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// Usuário autenticado pelo token; as rotas de escrita exigem autenticação
+func actor(c *gin.Context) string {
+	return c.GetString("userID")
+}
+
+func queryTime(c *gin.Context, key string) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func queryInt(c *gin.Context, key string) (int, error) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
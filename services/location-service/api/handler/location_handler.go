@@ -29,6 +29,8 @@ func convertDTOToLocationDataWithoutID(locationDTO dto.LocationDTO) model.Locati
 		Description: locationDTO.Description,
 		Address:     locationDTO.Address,
 		Data:        locationDTO.Data,
+		Region:      locationDTO.Region,
+		Type:        locationDTO.Type,
 		CreatedDate: locationDTO.CreatedDate,
 		Status:      locationDTO.Status,
 	}
@@ -107,6 +109,8 @@ func (h *LocationHandler) AddLocation(c *gin.Context) {
 		Description: locationDTO.Description,
 		Address:     locationDTO.Address,
 		Data:        locationDTO.Data,
+		Region:      locationDTO.Region,
+		Type:        locationDTO.Type,
 		CreatedDate: locationDTO.CreatedDate,
 		Status:      locationDTO.Status,
 	}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"Varejo-Golang-Microservices/services/location-service/domain/repository"
+	"Varejo-Golang-Microservices/services/location-service/domain/service"
+	"Varejo-Golang-Microservices/services/location-service/dto"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PurchaseOrderHandler struct {
+	Service service.PurchaseOrderService
+}
+
+// Inicializa um novo manipulador de pedidos de compra com o serviço fornecido
+func NewPurchaseOrderHandler(s service.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{
+		Service: s,
+	}
+}
+
+func purchaseOrderErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrPurchaseOrderNotFound), errors.Is(err, service.ErrSupplierNotFound), errors.Is(err, service.ErrReceiptNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrPurchaseOrderChanged), errors.Is(err, service.ErrReceiptPending):
+		return http.StatusConflict
+	default:
+		return inventoryErrorStatus(err)
+	}
+}
+
+func convertDTOPurchaseLines(itemDTOs []dto.PurchaseItemDTO) []model.PurchaseOrderLine {
+	lines := make([]model.PurchaseOrderLine, 0, len(itemDTOs))
+	for _, item := range itemDTOs {
+		lines = append(lines, model.PurchaseOrderLine{ProductID: item.ProductID, SKU: item.SKU, Quantity: item.Quantity, UnitCost: item.UnitCost})
+	}
+	return lines
+}
+
+func (h *PurchaseOrderHandler) CreatePurchaseOrder(c *gin.Context) {
+	var orderDTO dto.PurchaseOrderDTO
+	if err := c.ShouldBindJSON(&orderDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.Service.CreatePurchaseOrder(orderDTO.SupplierID, orderDTO.LocationID, convertDTOPurchaseLines(orderDTO.Lines), orderDTO.Notes, actor(c))
+	if err != nil {
+		c.JSON(purchaseOrderErrorStatus(err), gin.H{"error": "Erro ao criar pedido de compra. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Pedido de compra criado com sucesso.", "data": order})
+}
+
+func (h *PurchaseOrderHandler) GetPurchaseOrder(c *gin.Context) {
+	order, err := h.Service.GetPurchaseOrder(c.Param("id"))
+	if err != nil {
+		c.JSON(purchaseOrderErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// Lista os pedidos de compra (?supplierId, locationId, status)
+func (h *PurchaseOrderHandler) ListPurchaseOrders(c *gin.Context) {
+	filter := model.PurchaseOrderFilter{
+		SupplierID: c.Query("supplierId"),
+		LocationID: c.Query("locationId"),
+		Status:     model.PurchaseOrderStatus(c.Query("status")),
+	}
+	orders, err := h.Service.ListPurchaseOrders(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar pedidos de compra"})
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+// Substitui os itens de um pedido em rascunho
+func (h *PurchaseOrderHandler) UpdateDraft(c *gin.Context) {
+	var orderDTO dto.PurchaseOrderDTO
+	if err := c.ShouldBindJSON(&orderDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.Service.UpdateDraft(c.Param("id"), convertDTOPurchaseLines(orderDTO.Lines), orderDTO.Notes)
+	if err != nil {
+		c.JSON(purchaseOrderErrorStatus(err), gin.H{"error": "Erro ao atualizar pedido de compra. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pedido de compra atualizado com sucesso.", "data": order})
+}
+
+func (h *PurchaseOrderHandler) SendPurchaseOrder(c *gin.Context) {
+	order, err := h.Service.SendPurchaseOrder(c.Param("id"), actor(c))
+	if err != nil {
+		c.JSON(purchaseOrderErrorStatus(err), gin.H{"error": "Erro ao enviar pedido de compra. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pedido de compra enviado ao fornecedor.", "data": order})
+}
+
+// Registra uma entrega do fornecedor e dá entrada no estoque da localização do pedido
+func (h *PurchaseOrderHandler) ReceivePurchaseOrder(c *gin.Context) {
+	var receiptDTO dto.PurchaseReceiptDTO
+	if err := c.ShouldBindJSON(&receiptDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lines := make([]model.PurchaseReceiptLine, 0, len(receiptDTO.Lines))
+	for _, item := range receiptDTO.Lines {
+		lines = append(lines, model.PurchaseReceiptLine{ProductID: item.ProductID, SKU: item.SKU, Quantity: item.Quantity, UnitCost: item.UnitCost})
+	}
+	order, err := h.Service.ReceivePurchaseOrder(c.Param("id"), lines, receiptDTO.Notes, actor(c))
+	if err != nil {
+		c.JSON(purchaseOrderErrorStatus(err), gin.H{"error": "Erro ao receber pedido de compra. Detalhes: " + err.Error()})
+		return
+	}
+
+	message := "Pedido de compra recebido com sucesso."
+	if order.Status == model.PurchasePartiallyReceived {
+		message = "Pedido de compra recebido parcialmente."
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message, "data": order})
+}
+
+// Completa o lançamento no estoque de um recebimento interrompido
+func (h *PurchaseOrderHandler) PostReceipt(c *gin.Context) {
+	order, err := h.Service.PostReceipt(c.Param("id"), c.Param("receiptId"))
+	if err != nil {
+		c.JSON(purchaseOrderErrorStatus(err), gin.H{"error": "Erro ao lançar recebimento do pedido de compra. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Recebimento lançado no estoque.", "data": order})
+}
+
+func (h *PurchaseOrderHandler) ClosePurchaseOrder(c *gin.Context) {
+	var notesDTO dto.PurchaseOrderNotesDTO
+	if err := bindOptionalJSON(c, &notesDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.Service.ClosePurchaseOrder(c.Param("id"), notesDTO.Notes, actor(c))
+	if err != nil {
+		c.JSON(purchaseOrderErrorStatus(err), gin.H{"error": "Erro ao encerrar pedido de compra. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pedido de compra encerrado com sucesso.", "data": order})
+}
+
+func (h *PurchaseOrderHandler) CancelPurchaseOrder(c *gin.Context) {
+	var notesDTO dto.PurchaseOrderNotesDTO
+	if err := bindOptionalJSON(c, &notesDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.Service.CancelPurchaseOrder(c.Param("id"), notesDTO.Notes, actor(c))
+	if err != nil {
+		c.JSON(purchaseOrderErrorStatus(err), gin.H{"error": "Erro ao cancelar pedido de compra. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pedido de compra cancelado com sucesso.", "data": order})
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"Varejo-Golang-Microservices/services/location-service/domain/repository"
+	"Varejo-Golang-Microservices/services/location-service/domain/service"
+	"Varejo-Golang-Microservices/services/location-service/dto"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SupplierHandler struct {
+	Service service.SupplierService
+}
+
+// Inicializa um novo manipulador de fornecedores com o serviço fornecido
+func NewSupplierHandler(s service.SupplierService) *SupplierHandler {
+	return &SupplierHandler{
+		Service: s,
+	}
+}
+
+func supplierErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrSupplierNotFound), errors.Is(err, repository.ErrPriceNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrDuplicateCNPJ):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrInvalidSupplier), errors.Is(err, model.ErrInvalidCNPJ):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func convertDTOSupplier(supplierDTO dto.SupplierDTO) *model.Supplier {
+	return &model.Supplier{
+		CNPJ:         supplierDTO.CNPJ,
+		LegalName:    supplierDTO.LegalName,
+		TradeName:    supplierDTO.TradeName,
+		Contacts:     supplierDTO.Contacts,
+		LeadTimeDays: supplierDTO.LeadTimeDays,
+		Status:       supplierDTO.Status,
+	}
+}
+
+func (h *SupplierHandler) CreateSupplier(c *gin.Context) {
+	var supplierDTO dto.SupplierDTO
+	if err := c.ShouldBindJSON(&supplierDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	supplier, err := h.Service.CreateSupplier(convertDTOSupplier(supplierDTO))
+	if err != nil {
+		c.JSON(supplierErrorStatus(err), gin.H{"error": "Erro ao cadastrar fornecedor. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Fornecedor cadastrado com sucesso.", "data": supplier})
+}
+
+func (h *SupplierHandler) GetSupplier(c *gin.Context) {
+	supplier, err := h.Service.GetSupplier(c.Param("id"))
+	if err != nil {
+		c.JSON(supplierErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, supplier)
+}
+
+// Lista os fornecedores (?status; productId retorna só os que vendem o produto)
+func (h *SupplierHandler) ListSuppliers(c *gin.Context) {
+	suppliers, err := h.Service.ListSuppliers(model.SupplierStatus(c.Query("status")), c.Query("productId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar fornecedores"})
+		return
+	}
+
+	c.JSON(http.StatusOK, suppliers)
+}
+
+func (h *SupplierHandler) UpdateSupplier(c *gin.Context) {
+	var supplierDTO dto.SupplierDTO
+	if err := c.ShouldBindJSON(&supplierDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	supplier, err := h.Service.UpdateSupplier(c.Param("id"), convertDTOSupplier(supplierDTO))
+	if err != nil {
+		c.JSON(supplierErrorStatus(err), gin.H{"error": "Erro ao atualizar fornecedor. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Fornecedor atualizado com sucesso.", "data": supplier})
+}
+
+// Inclui ou altera o preço de um item na tabela do fornecedor
+func (h *SupplierHandler) SetPrice(c *gin.Context) {
+	var priceDTO dto.SupplierPriceDTO
+	if err := c.ShouldBindJSON(&priceDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	price := model.SupplierPrice{
+		ProductID:    priceDTO.ProductID,
+		SKU:          priceDTO.SKU,
+		SupplierCode: priceDTO.SupplierCode,
+		UnitCost:     priceDTO.UnitCost,
+		MinQuantity:  priceDTO.MinQuantity,
+		LeadTimeDays: priceDTO.LeadTimeDays,
+	}
+	supplier, err := h.Service.SetPrice(c.Param("id"), price)
+	if err != nil {
+		c.JSON(supplierErrorStatus(err), gin.H{"error": "Erro ao atualizar tabela de preços. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tabela de preços atualizada com sucesso.", "data": supplier})
+}
+
+// Remove um item da tabela de preços (?sku para variantes)
+func (h *SupplierHandler) RemovePrice(c *gin.Context) {
+	supplier, err := h.Service.RemovePrice(c.Param("id"), c.Param("productId"), c.Query("sku"))
+	if err != nil {
+		c.JSON(supplierErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item removido da tabela de preços.", "data": supplier})
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"Varejo-Golang-Microservices/services/location-service/domain/repository"
+	"Varejo-Golang-Microservices/services/location-service/domain/service"
+	"Varejo-Golang-Microservices/services/location-service/dto"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TransferHandler struct {
+	Service service.TransferService
+}
+
+// Inicializa um novo manipulador de transferências com o serviço fornecido
+func NewTransferHandler(s service.TransferService) *TransferHandler {
+	return &TransferHandler{
+		Service: s,
+	}
+}
+
+func transferErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrTransferNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrTransferStateChanged):
+		return http.StatusConflict
+	default:
+		return inventoryErrorStatus(err)
+	}
+}
+
+func convertDTOItems(itemDTOs []dto.AllocationItemDTO) []model.AllocationItem {
+	items := make([]model.AllocationItem, 0, len(itemDTOs))
+	for _, item := range itemDTOs {
+		items = append(items, model.AllocationItem{ProductID: item.ProductID, SKU: item.SKU, Quantity: item.Quantity})
+	}
+	return items
+}
+
+func (h *TransferHandler) CreateTransfer(c *gin.Context) {
+	var transferDTO dto.TransferDTO
+	if err := c.ShouldBindJSON(&transferDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transfer, err := h.Service.CreateTransfer(transferDTO.SourceID, transferDTO.DestinationID, convertDTOItems(transferDTO.Lines), transferDTO.Notes, actor(c))
+	if err != nil {
+		c.JSON(transferErrorStatus(err), gin.H{"error": "Erro ao solicitar transferência. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Transferência solicitada com sucesso.", "data": transfer})
+}
+
+func (h *TransferHandler) GetTransfer(c *gin.Context) {
+	transfer, err := h.Service.GetTransfer(c.Param("id"))
+	if err != nil {
+		c.JSON(transferErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+// Lista as transferências com origem ou destino na localização (?locationId, status)
+func (h *TransferHandler) ListTransfers(c *gin.Context) {
+	transfers, err := h.Service.ListTransfers(c.Query("locationId"), model.TransferStatus(c.Query("status")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar transferências"})
+		return
+	}
+
+	c.JSON(http.StatusOK, transfers)
+}
+
+func (h *TransferHandler) PickTransfer(c *gin.Context) {
+	var linesDTO dto.TransferLinesDTO
+	if err := bindOptionalJSON(c, &linesDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transfer, err := h.Service.PickTransfer(c.Param("id"), convertDTOItems(linesDTO.Lines), actor(c))
+	if err != nil {
+		c.JSON(transferErrorStatus(err), gin.H{"error": "Erro ao separar transferência. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Transferência separada com sucesso.", "data": transfer})
+}
+
+func (h *TransferHandler) DispatchTransfer(c *gin.Context) {
+	transfer, err := h.Service.DispatchTransfer(c.Param("id"), actor(c))
+	if err != nil {
+		c.JSON(transferErrorStatus(err), gin.H{"error": "Erro ao despachar transferência. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Transferência despachada com sucesso.", "data": transfer})
+}
+
+func (h *TransferHandler) ReceiveTransfer(c *gin.Context) {
+	var linesDTO dto.TransferLinesDTO
+	if err := bindOptionalJSON(c, &linesDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transfer, err := h.Service.ReceiveTransfer(c.Param("id"), convertDTOItems(linesDTO.Lines), linesDTO.Notes, actor(c))
+	if err != nil {
+		c.JSON(transferErrorStatus(err), gin.H{"error": "Erro ao receber transferência. Detalhes: " + err.Error()})
+		return
+	}
+
+	message := "Transferência recebida com sucesso."
+	if transfer.Status == model.TransferDiscrepancy {
+		message = "Transferência recebida com divergência."
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message, "data": transfer})
+}
+
+func (h *TransferHandler) CancelTransfer(c *gin.Context) {
+	transfer, err := h.Service.CancelTransfer(c.Param("id"), actor(c))
+	if err != nil {
+		c.JSON(transferErrorStatus(err), gin.H{"error": "Erro ao cancelar transferência. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Transferência cancelada com sucesso.", "data": transfer})
+}
+
+// Corpo vazio é aceito e significa a quantidade total
+func bindOptionalJSON(c *gin.Context, target interface{}) error {
+	if c.Request.ContentLength == 0 {
+		return nil
+	}
+	return c.ShouldBindJSON(target)
+}
@@ -5,8 +5,10 @@ import (
 	"Varejo-Golang-Microservices/services/location-service/api/handler"
 	"Varejo-Golang-Microservices/services/location-service/domain/repository"
 	"Varejo-Golang-Microservices/services/location-service/domain/service"
+	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -38,12 +40,84 @@ func main() {
 	locationService := service.NewLocationService(locationRepo)
 	locationHandler := handler.NewLocationHandler(locationService)
 
+	inventoryRepo := repository.NewMongoInventoryRepository(mongoURI)
+	movementRepo := repository.NewMongoMovementRepository(mongoURI)
+	inventoryService := service.NewInventoryService(inventoryRepo, locationRepo, movementRepo)
+	inventoryHandler := handler.NewInventoryHandler(inventoryService)
+	cycleCountRepo := repository.NewMongoCycleCountRepository(mongoURI)
+	cycleCountService := service.NewCycleCountService(cycleCountRepo, inventoryRepo, movementRepo, locationRepo, inventoryService)
+	cycleCountHandler := handler.NewCycleCountHandler(cycleCountService)
+	transferRepo := repository.NewMongoTransferRepository(mongoURI)
+	transferService := service.NewTransferService(transferRepo, locationRepo, inventoryService)
+	transferHandler := handler.NewTransferHandler(transferService)
+	supplierRepo := repository.NewMongoSupplierRepository(mongoURI)
+	supplierService := service.NewSupplierService(supplierRepo)
+	supplierHandler := handler.NewSupplierHandler(supplierService)
+	purchaseOrderRepo := repository.NewMongoPurchaseOrderRepository(mongoURI)
+	purchaseOrderService := service.NewPurchaseOrderService(purchaseOrderRepo, supplierRepo, locationRepo, inventoryService)
+	purchaseOrderHandler := handler.NewPurchaseOrderHandler(purchaseOrderService)
+
+	// O estoque anterior ao livro de movimentos entra como saldo de abertura
+	if err := inventoryService.OpenLedger(); err != nil {
+		log.Printf("Erro ao lançar os saldos de abertura do livro de estoque: %v\n", err)
+	}
+	// Resolve os lançamentos que ficaram pendentes entre o livro e o registro de estoque
+	go inventoryService.RunLedgerReconciliation(time.Minute)
+
 	// Configura as rotas
 	r.GET("/locations", locationHandler.GetLocation)
 	r.GET("/locations/:id", locationHandler.GetLocationByID)
 	r.POST("/locations", locationHandler.AddLocation)
 	r.PUT("/locations/:id", locationHandler.UpdateLocation)
 	r.DELETE("/locations/:id", locationHandler.DeleteLocation)
+	r.GET("/locations/:id/inventory", inventoryHandler.GetLocationInventory)
+
+	// Rotas de estoque por localização
+	r.GET("/inventory/product/:productId", inventoryHandler.GetProductInventory)
+	r.GET("/inventory/product/:productId/regions", inventoryHandler.GetRegionAvailability)
+	authorized.POST("/inventory/adjust", inventoryHandler.AdjustInventory)
+	authorized.POST("/inventory/reserve", inventoryHandler.Reserve)
+	authorized.POST("/inventory/release", inventoryHandler.Release)
+	authorized.POST("/inventory/commit", inventoryHandler.Commit)
+	authorized.POST("/inventory/allocate", inventoryHandler.Allocate)
+	r.GET("/inventory/movements", inventoryHandler.ListMovements)
+	authorized.POST("/inventory/movements", inventoryHandler.RecordMovement)
+	r.GET("/inventory/ledger/balance", inventoryHandler.GetLedgerBalance)
+	r.GET("/inventory/reports/shrinkage", inventoryHandler.GetShrinkageReport)
+
+	// Rotas de contagem cíclica
+	r.GET("/cycle-counts", cycleCountHandler.ListCounts)
+	authorized.POST("/cycle-counts", cycleCountHandler.StartCount)
+	r.GET("/cycle-counts/:id", cycleCountHandler.GetCount)
+	authorized.PUT("/cycle-counts/:id/lines", cycleCountHandler.RecordCounts)
+	authorized.POST("/cycle-counts/:id/complete", cycleCountHandler.CompleteCount)
+	authorized.POST("/cycle-counts/:id/cancel", cycleCountHandler.CancelCount)
+
+	// Rotas de transferência entre localizações
+	r.GET("/transfers", transferHandler.ListTransfers)
+	authorized.POST("/transfers", transferHandler.CreateTransfer)
+	r.GET("/transfers/:id", transferHandler.GetTransfer)
+	authorized.POST("/transfers/:id/pick", transferHandler.PickTransfer)
+	authorized.POST("/transfers/:id/dispatch", transferHandler.DispatchTransfer)
+	authorized.POST("/transfers/:id/receive", transferHandler.ReceiveTransfer)
+	authorized.POST("/transfers/:id/cancel", transferHandler.CancelTransfer)
+
+	// Rotas de fornecedores e pedidos de compra
+	r.GET("/suppliers", supplierHandler.ListSuppliers)
+	r.POST("/suppliers", supplierHandler.CreateSupplier)
+	r.GET("/suppliers/:id", supplierHandler.GetSupplier)
+	r.PUT("/suppliers/:id", supplierHandler.UpdateSupplier)
+	r.PUT("/suppliers/:id/prices", supplierHandler.SetPrice)
+	r.DELETE("/suppliers/:id/prices/:productId", supplierHandler.RemovePrice)
+	r.GET("/purchase-orders", purchaseOrderHandler.ListPurchaseOrders)
+	authorized.POST("/purchase-orders", purchaseOrderHandler.CreatePurchaseOrder)
+	r.GET("/purchase-orders/:id", purchaseOrderHandler.GetPurchaseOrder)
+	authorized.PUT("/purchase-orders/:id", purchaseOrderHandler.UpdateDraft)
+	authorized.POST("/purchase-orders/:id/send", purchaseOrderHandler.SendPurchaseOrder)
+	authorized.POST("/purchase-orders/:id/receipts", purchaseOrderHandler.ReceivePurchaseOrder)
+	authorized.POST("/purchase-orders/:id/receipts/:receiptId/post", purchaseOrderHandler.PostReceipt)
+	authorized.POST("/purchase-orders/:id/close", purchaseOrderHandler.ClosePurchaseOrder)
+	authorized.POST("/purchase-orders/:id/cancel", purchaseOrderHandler.CancelPurchaseOrder)
 
 	// Starting the server
 	r.Run(":8083")
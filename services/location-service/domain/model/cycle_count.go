@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+type CycleCountStatus string
+
+const (
+	CycleCountOpen CycleCountStatus = "OPEN"
+	// Os ajustes estão sendo lançados; uma conclusão interrompida pode ser repetida
+	CycleCountCompleting CycleCountStatus = "COMPLETING"
+	CycleCountCompleted  CycleCountStatus = "COMPLETED"
+	CycleCountCanceled   CycleCountStatus = "CANCELED"
+)
+
+// CycleCount é uma contagem cíclica de itens de uma localização. A quantidade esperada
+// de cada linha é o OnHand no momento em que a contagem é registrada, para que vendas
+// feitas durante a contagem não apareçam como diferença.
+type CycleCount struct {
+	ID          string           `json:"id" bson:"_id"`
+	LocationID  string           `json:"locationId" bson:"locationId"`
+	Status      CycleCountStatus `json:"status" bson:"status"`
+	Lines       []CycleCountLine `json:"lines" bson:"lines"`
+	Notes       string           `json:"notes,omitempty" bson:"notes,omitempty"`
+	CreatedBy   string           `json:"createdBy,omitempty" bson:"createdBy,omitempty"`
+	CreatedAt   time.Time        `json:"createdAt" bson:"createdAt"`
+	CompletedBy string           `json:"completedBy,omitempty" bson:"completedBy,omitempty"`
+	CompletedAt *time.Time       `json:"completedAt,omitempty" bson:"completedAt,omitempty"`
+}
+
+// CycleCountLine é um item da contagem. Counted fica nulo até o item ser contado.
+type CycleCountLine struct {
+	ProductID string     `json:"productId" bson:"productId"`
+	SKU       string     `json:"sku,omitempty" bson:"sku"`
+	Expected  int        `json:"expected" bson:"expected"`
+	Counted   *int       `json:"counted,omitempty" bson:"counted,omitempty"`
+	Variance  int        `json:"variance" bson:"variance"`
+	CountedBy string     `json:"countedBy,omitempty" bson:"countedBy,omitempty"`
+	CountedAt *time.Time `json:"countedAt,omitempty" bson:"countedAt,omitempty"`
+}
+
+// CycleCountEntry é a quantidade contada de um item
+type CycleCountEntry struct {
+	ProductID string
+	SKU       string
+	Counted   int
+}
@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+// InventoryRecord guarda o estoque de um produto (ou de uma variante, pelo SKU) em uma localização.
+// Available é mantido junto com OnHand e Reserved (OnHand - Reserved) para
+// que as reservas possam ser feitas com um único update condicional.
+// AppliedMovements guarda os últimos lançamentos do livro aplicados ao registro, para
+// que a aplicação seja idempotente; OpeningBalance é o saldo anterior ao livro,
+// reservado pela abertura até o lançamento de abertura ser gravado.
+type InventoryRecord struct {
+	ID         string    `json:"id" bson:"_id"`
+	ProductID  string    `json:"productId" bson:"productId"`
+	SKU        string    `json:"sku,omitempty" bson:"sku"`
+	LocationID string    `json:"locationId" bson:"locationId"`
+	OnHand     int       `json:"onHand" bson:"onHand"`
+	Reserved   int       `json:"reserved" bson:"reserved"`
+	Available  int       `json:"available" bson:"available"`
+	InTransit  int       `json:"inTransit" bson:"inTransit"`
+	UpdatedAt  time.Time `json:"updatedAt" bson:"updatedAt"`
+
+	AppliedMovements []string `json:"-" bson:"appliedMovements,omitempty"`
+	OpeningBalance   *int     `json:"-" bson:"openingBalance,omitempty"`
+}
+
+// HasApplied informa se o lançamento já foi aplicado ao registro
+func (r *InventoryRecord) HasApplied(movementID string) bool {
+	for _, id := range r.AppliedMovements {
+		if id == movementID {
+			return true
+		}
+	}
+	return false
+}
+
+// RegionAvailability é o estoque agregado de um produto nas localizações de uma região
+type RegionAvailability struct {
+	Region    string `json:"region"`
+	ProductID string `json:"productId"`
+	OnHand    int    `json:"onHand"`
+	Reserved  int    `json:"reserved"`
+	Available int    `json:"available"`
+	InTransit int    `json:"inTransit"`
+	Locations int    `json:"locations"`
+}
+
+// AllocationItem é um produto e quantidade a ser atendido por uma localização
+type AllocationItem struct {
+	ProductID string `json:"productId"`
+	SKU       string `json:"sku,omitempty"`
+	Quantity  int    `json:"quantity"`
+}
+
+// GeoPoint é a coordenada de entrega usada para ordenar as localizações por distância
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Allocation é a localização escolhida para atender um pedido. DistanceKm só é
+// informada quando a alocação recebeu as coordenadas de entrega.
+type Allocation struct {
+	Location   *Location        `json:"location"`
+	DistanceKm *float64         `json:"distanceKm,omitempty"`
+	Items      []AllocationItem `json:"items"`
+}
@@ -12,6 +12,8 @@ type Location struct {
 	Description string         `json:"description" bson:"description"`
 	Address     string         `json:"address" bson:"address"`
 	Data        string         `json:"data" bson:"data"`
+	Region      string         `json:"region" bson:"region"`
+	Type        LocationType   `json:"type" bson:"type"`
 	CreatedDate time.Time      `json:"createdDate" bson:"createdDate"`
 	Status      LocationStatus `json:"status" bson:"status"`
 }
@@ -23,3 +25,11 @@ const (
 	Inactive LocationStatus = "INACTIVE"
 	Pending  LocationStatus = "PENDING"
 )
+
+// LocationType diferencia lojas de centros de distribuição no estoque multi-local
+type LocationType string
+
+const (
+	Store              LocationType = "STORE"
+	DistributionCenter LocationType = "DISTRIBUTION_CENTER"
+)
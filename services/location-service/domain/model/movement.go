@@ -0,0 +1,113 @@
+package model
+
+import "time"
+
+// MovementType é o motivo de uma alteração no estoque físico (OnHand)
+type MovementType string
+
+const (
+	SaleMovement       MovementType = "SALE"
+	ReturnMovement     MovementType = "RETURN"
+	AdjustmentMovement MovementType = "ADJUSTMENT"
+	TransferMovement   MovementType = "TRANSFER"
+	ReceiptMovement    MovementType = "RECEIPT"
+	ShrinkageMovement  MovementType = "SHRINKAGE"
+)
+
+// ReferenceType identifica o tipo do documento que originou o movimento
+type ReferenceType string
+
+const (
+	OrderReference      ReferenceType = "ORDER"
+	CycleCountReference ReferenceType = "CYCLE_COUNT"
+	TransferReference   ReferenceType = "TRANSFER"
+	OpeningReference    ReferenceType = "OPENING_BALANCE"
+	PurchaseReference   ReferenceType = "PURCHASE_ORDER"
+)
+
+// MovementStatus indica se o lançamento já foi aplicado ao registro de estoque.
+// Lançamentos anteriores ao status não têm o campo e contam como aplicados.
+type MovementStatus string
+
+const (
+	MovementPending   MovementStatus = "PENDING"
+	MovementApplying  MovementStatus = "APPLYING"
+	MovementApplied   MovementStatus = "APPLIED"
+	MovementDiscarded MovementStatus = "DISCARDED"
+)
+
+// StockMovement é um lançamento imutável do livro de estoque. Quantity é o efeito
+// com sinal sobre o OnHand do produto na localização, e o OnHand é a soma dos
+// lançamentos aplicados. O lançamento é gravado como pendente antes de alterar o
+// registro, que é só uma projeção do livro.
+type StockMovement struct {
+	ID            string         `json:"id" bson:"_id"`
+	ProductID     string         `json:"productId" bson:"productId"`
+	SKU           string         `json:"sku,omitempty" bson:"sku"`
+	LocationID    string         `json:"locationId" bson:"locationId"`
+	Type          MovementType   `json:"type" bson:"type"`
+	Quantity      int            `json:"quantity" bson:"quantity"`
+	BalanceAfter  int            `json:"balanceAfter" bson:"balanceAfter"`
+	Reason        string         `json:"reason,omitempty" bson:"reason,omitempty"`
+	UserID        string         `json:"userId,omitempty" bson:"userId,omitempty"`
+	ReferenceType ReferenceType  `json:"referenceType,omitempty" bson:"referenceType,omitempty"`
+	Reference     string         `json:"reference,omitempty" bson:"reference,omitempty"`
+	Status        MovementStatus `json:"status,omitempty" bson:"status,omitempty"`
+	CreatedAt     time.Time      `json:"createdAt" bson:"createdAt"`
+}
+
+// IsApplied informa se o lançamento conta no saldo do livro
+func (m *StockMovement) IsApplied() bool {
+	return m.Status == "" || m.Status == MovementApplied
+}
+
+// MovementFilter restringe a consulta ao livro; campos vazios não filtram
+type MovementFilter struct {
+	ProductID  string
+	SKU        *string
+	LocationID string
+	Type       MovementType
+	Reference  string
+	From       time.Time
+	To         time.Time
+}
+
+// LedgerBalance compara o estoque físico do registro com a soma dos movimentos
+type LedgerBalance struct {
+	ProductID     string `json:"productId"`
+	SKU           string `json:"sku,omitempty"`
+	LocationID    string `json:"locationId"`
+	OnHand        int    `json:"onHand"`
+	LedgerBalance int    `json:"ledgerBalance"`
+	Difference    int    `json:"difference"`
+	Movements     int    `json:"movements"`
+}
+
+// ShrinkageLine consolida as perdas de um produto em uma localização no período.
+// Declared são as perdas lançadas como quebra; CountLosses e CountGains são as
+// diferenças negativas e positivas apuradas nas contagens.
+type ShrinkageLine struct {
+	LocationID  string  `json:"locationId" bson:"locationId"`
+	ProductID   string  `json:"productId" bson:"productId"`
+	SKU         string  `json:"sku,omitempty" bson:"sku"`
+	Declared    int     `json:"declared" bson:"declared"`
+	CountLosses int     `json:"countLosses" bson:"countLosses"`
+	CountGains  int     `json:"countGains" bson:"countGains"`
+	NetLoss     int     `json:"netLoss" bson:"netLoss"`
+	Sold        int     `json:"sold" bson:"sold"`
+	LossRate    float64 `json:"lossRate" bson:"-"`
+}
+
+// ShrinkageReport é o relatório de quebras do período
+type ShrinkageReport struct {
+	LocationID  string           `json:"locationId,omitempty"`
+	From        time.Time        `json:"from"`
+	To          time.Time        `json:"to"`
+	Declared    int              `json:"declared"`
+	CountLosses int              `json:"countLosses"`
+	CountGains  int              `json:"countGains"`
+	NetLoss     int              `json:"netLoss"`
+	Sold        int              `json:"sold"`
+	LossRate    float64          `json:"lossRate"`
+	Lines       []*ShrinkageLine `json:"lines"`
+}
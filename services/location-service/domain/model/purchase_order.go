@@ -0,0 +1,85 @@
+package model
+
+import "time"
+
+type PurchaseOrderStatus string
+
+const (
+	PurchaseDraft             PurchaseOrderStatus = "DRAFT"
+	PurchaseSent              PurchaseOrderStatus = "SENT"
+	PurchasePartiallyReceived PurchaseOrderStatus = "PARTIALLY_RECEIVED"
+	PurchaseReceived          PurchaseOrderStatus = "RECEIVED"
+	// Encerrado, sem mais recebimentos; o que faltou receber fica como divergência
+	PurchaseClosed   PurchaseOrderStatus = "CLOSED"
+	PurchaseCanceled PurchaseOrderStatus = "CANCELED"
+)
+
+// PurchaseOrder é o pedido de compra a um fornecedor, recebido em uma localização.
+// Cada recebimento dá entrada no estoque da localização e registra as divergências
+// de quantidade e de preço em relação ao pedido.
+type PurchaseOrder struct {
+	ID           string              `json:"id" bson:"_id"`
+	SupplierID   string              `json:"supplierId" bson:"supplierId"`
+	SupplierName string              `json:"supplierName" bson:"supplierName"`
+	Location     Location            `json:"location" bson:"location"`
+	Status       PurchaseOrderStatus `json:"status" bson:"status"`
+	Lines        []PurchaseOrderLine `json:"lines" bson:"lines"`
+	Total        float64             `json:"total" bson:"total"`
+	Notes        string              `json:"notes,omitempty" bson:"notes,omitempty"`
+	// Previsão de entrega calculada no envio pelo maior prazo entre os itens
+	ExpectedAt *time.Time           `json:"expectedAt,omitempty" bson:"expectedAt,omitempty"`
+	Receipts   []PurchaseReceipt    `json:"receipts" bson:"receipts"`
+	History    []PurchaseOrderEvent `json:"history" bson:"history"`
+	// Controle de concorrência: cada alteração exige a versão lida
+	Version   int       `json:"-" bson:"version"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// PurchaseOrderLine é um item do pedido. QuantityVariance é o recebido menos o
+// pedido (negativa para falta, positiva para sobra) e PriceVariance é a soma da
+// diferença de custo de cada unidade recebida em relação ao custo do pedido.
+type PurchaseOrderLine struct {
+	ProductID        string  `json:"productId" bson:"productId"`
+	SKU              string  `json:"sku,omitempty" bson:"sku"`
+	Quantity         int     `json:"quantity" bson:"quantity"`
+	UnitCost         float64 `json:"unitCost" bson:"unitCost"`
+	Received         int     `json:"received" bson:"received"`
+	QuantityVariance int     `json:"quantityVariance" bson:"quantityVariance"`
+	PriceVariance    float64 `json:"priceVariance" bson:"priceVariance"`
+}
+
+// PurchaseReceipt é uma entrega recebida contra o pedido. Pending indica que as
+// entradas no estoque ainda não foram todas lançadas; o lançamento pode ser repetido.
+type PurchaseReceipt struct {
+	ID         string                `json:"id" bson:"id"`
+	Lines      []PurchaseReceiptLine `json:"lines" bson:"lines"`
+	Notes      string                `json:"notes,omitempty" bson:"notes,omitempty"`
+	UserID     string                `json:"userId,omitempty" bson:"userId,omitempty"`
+	ReceivedAt time.Time             `json:"receivedAt" bson:"receivedAt"`
+	Pending    bool                  `json:"pending,omitempty" bson:"pending,omitempty"`
+}
+
+// PurchaseReceiptLine é a quantidade recebida de um item e o custo faturado
+type PurchaseReceiptLine struct {
+	ProductID     string  `json:"productId" bson:"productId"`
+	SKU           string  `json:"sku,omitempty" bson:"sku"`
+	Quantity      int     `json:"quantity" bson:"quantity"`
+	UnitCost      float64 `json:"unitCost" bson:"unitCost"`
+	PriceVariance float64 `json:"priceVariance" bson:"priceVariance"`
+}
+
+// PurchaseOrderEvent registra cada mudança de status do pedido
+type PurchaseOrderEvent struct {
+	Status PurchaseOrderStatus `json:"status" bson:"status"`
+	UserID string              `json:"userId,omitempty" bson:"userId,omitempty"`
+	Notes  string              `json:"notes,omitempty" bson:"notes,omitempty"`
+	At     time.Time           `json:"at" bson:"at"`
+}
+
+// PurchaseOrderFilter filtra a listagem de pedidos de compra; campos vazios não filtram
+type PurchaseOrderFilter struct {
+	SupplierID string
+	LocationID string
+	Status     PurchaseOrderStatus
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCNPJ = errors.New("CNPJ inválido")
+
+type SupplierStatus string
+
+const (
+	SupplierActive   SupplierStatus = "ACTIVE"
+	SupplierInactive SupplierStatus = "INACTIVE"
+)
+
+// Supplier é um fornecedor de mercadorias. LeadTimeDays é o prazo de entrega padrão,
+// usado quando o item da tabela de preços não define o próprio.
+type Supplier struct {
+	ID           string            `json:"id" bson:"_id"`
+	CNPJ         string            `json:"cnpj" bson:"cnpj"`
+	LegalName    string            `json:"legalName" bson:"legalName"`
+	TradeName    string            `json:"tradeName,omitempty" bson:"tradeName,omitempty"`
+	Contacts     []SupplierContact `json:"contacts" bson:"contacts"`
+	LeadTimeDays int               `json:"leadTimeDays" bson:"leadTimeDays"`
+	Prices       []SupplierPrice   `json:"prices" bson:"prices"`
+	Status       SupplierStatus    `json:"status" bson:"status"`
+	CreatedAt    time.Time         `json:"createdAt" bson:"createdAt"`
+	UpdatedAt    time.Time         `json:"updatedAt" bson:"updatedAt"`
+}
+
+type SupplierContact struct {
+	Name  string `json:"name" bson:"name"`
+	Role  string `json:"role,omitempty" bson:"role,omitempty"`
+	Email string `json:"email,omitempty" bson:"email,omitempty"`
+	Phone string `json:"phone,omitempty" bson:"phone,omitempty"`
+}
+
+// SupplierPrice é o custo de um produto (ou variante) na tabela do fornecedor
+type SupplierPrice struct {
+	ProductID string `json:"productId" bson:"productId"`
+	SKU       string `json:"sku,omitempty" bson:"sku"`
+	// Código do item no catálogo do fornecedor
+	SupplierCode string  `json:"supplierCode,omitempty" bson:"supplierCode,omitempty"`
+	UnitCost     float64 `json:"unitCost" bson:"unitCost"`
+	// Quantidade mínima por pedido e prazo de entrega do item; zero usa o padrão
+	MinQuantity  int       `json:"minQuantity,omitempty" bson:"minQuantity,omitempty"`
+	LeadTimeDays int       `json:"leadTimeDays,omitempty" bson:"leadTimeDays,omitempty"`
+	UpdatedAt    time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// FindPrice retorna o item da tabela de preços do produto ou da variante
+func (s *Supplier) FindPrice(productID, sku string) *SupplierPrice {
+	for i := range s.Prices {
+		if s.Prices[i].ProductID == productID && s.Prices[i].SKU == sku {
+			return &s.Prices[i]
+		}
+	}
+	return nil
+}
+
+// LeadTimeFor retorna o prazo de entrega do item, ou o padrão do fornecedor
+func (s *Supplier) LeadTimeFor(productID, sku string) int {
+	if price := s.FindPrice(productID, sku); price != nil && price.LeadTimeDays > 0 {
+		return price.LeadTimeDays
+	}
+	return s.LeadTimeDays
+}
+
+// NormalizeCNPJ remove a pontuação e valida o tamanho e os dígitos verificadores,
+// retornando os 14 dígitos como devem ser gravados
+func NormalizeCNPJ(cnpj string) (string, error) {
+	cnpj = strings.NewReplacer(".", "", "/", "", "-", "", " ", "").Replace(cnpj)
+
+	if len(cnpj) != 14 {
+		return "", fmt.Errorf("%w: %q deve ter 14 dígitos", ErrInvalidCNPJ, cnpj)
+	}
+	for _, r := range cnpj {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("%w: %q deve conter apenas dígitos", ErrInvalidCNPJ, cnpj)
+		}
+	}
+	// Sequências repetidas passam no cálculo, mas não são CNPJs válidos
+	if strings.Count(cnpj, cnpj[:1]) == len(cnpj) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidCNPJ, cnpj)
+	}
+
+	if cnpjCheckDigit(cnpj[:12]) != int(cnpj[12]-'0') || cnpjCheckDigit(cnpj[:13]) != int(cnpj[13]-'0') {
+		return "", fmt.Errorf("%w: dígitos verificadores de %s não conferem", ErrInvalidCNPJ, cnpj)
+	}
+	return cnpj, nil
+}
+
+// Dígito verificador (módulo 11) dos 12 ou 13 primeiros dígitos: da direita para a
+// esquerda, os pesos vão de 2 a 9 e recomeçam em 2
+func cnpjCheckDigit(body string) int {
+	sum := 0
+	for i := 0; i < len(body); i++ {
+		sum += int(body[len(body)-1-i]-'0') * (2 + i%8)
+	}
+	if rest := sum % 11; rest >= 2 {
+		return 11 - rest
+	}
+	return 0
+}
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+type TransferStatus string
+
+const (
+	TransferRequested TransferStatus = "REQUESTED"
+	TransferPicked    TransferStatus = "PICKED"
+	// Os lançamentos do despacho estão sendo feitos; um despacho interrompido pode ser repetido
+	TransferDispatching TransferStatus = "DISPATCHING"
+	TransferInTransit   TransferStatus = "IN_TRANSIT"
+	// Os lançamentos do recebimento estão sendo feitos; um recebimento interrompido pode ser repetido
+	TransferReceiving TransferStatus = "RECEIVING"
+	TransferReceived  TransferStatus = "RECEIVED"
+	// Recebida com quantidades diferentes das enviadas
+	TransferDiscrepancy TransferStatus = "DISCREPANCY"
+	TransferCanceled    TransferStatus = "CANCELED"
+)
+
+// Transfer é o documento de transferência de estoque entre localizações. As unidades
+// separadas ficam reservadas na origem, saem do estoque da origem no despacho, ficam
+// em trânsito no destino e entram no estoque do destino no recebimento.
+type Transfer struct {
+	ID          string          `json:"id" bson:"_id"`
+	Source      Location        `json:"source" bson:"source"`
+	Destination Location        `json:"destination" bson:"destination"`
+	Status      TransferStatus  `json:"status" bson:"status"`
+	Lines       []TransferLine  `json:"lines" bson:"lines"`
+	Notes       string          `json:"notes,omitempty" bson:"notes,omitempty"`
+	History     []TransferEvent `json:"history" bson:"history"`
+	CreatedAt   time.Time       `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt" bson:"updatedAt"`
+}
+
+// TransferLine é um item da transferência. Discrepancy é a diferença entre o
+// recebido e o enviado: negativa para falta, positiva para sobra.
+type TransferLine struct {
+	ProductID   string `json:"productId" bson:"productId"`
+	SKU         string `json:"sku,omitempty" bson:"sku"`
+	Requested   int    `json:"requested" bson:"requested"`
+	Picked      int    `json:"picked" bson:"picked"`
+	Received    int    `json:"received" bson:"received"`
+	Discrepancy int    `json:"discrepancy" bson:"discrepancy"`
+}
+
+// TransferEvent registra cada mudança de status da transferência
+type TransferEvent struct {
+	Status TransferStatus `json:"status" bson:"status"`
+	UserID string         `json:"userId,omitempty" bson:"userId,omitempty"`
+	Notes  string         `json:"notes,omitempty" bson:"notes,omitempty"`
+	At     time.Time      `json:"at" bson:"at"`
+}
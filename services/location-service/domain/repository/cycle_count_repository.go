@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"Varejo-Golang-Microservices/services/location-service/infra/db"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrCycleCountClosed indica que a contagem já foi concluída ou cancelada
+var ErrCycleCountClosed = errors.New("a contagem não está aberta")
+
+type MongoCycleCountRepository struct {
+	client *mongo.Client
+}
+
+func NewMongoCycleCountRepository(mongoURI string) *MongoCycleCountRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	collection := client.Database("locationDB").Collection("cycle_counts")
+	_, err = collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "locationId", Value: 1}, {Key: "status", Value: 1}, {Key: "createdAt", Value: -1}},
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índice de contagens: %v\n", err)
+	}
+
+	return &MongoCycleCountRepository{
+		client: client,
+	}
+}
+
+func (r *MongoCycleCountRepository) Save(count *model.CycleCount) error {
+	collection := r.client.Database("locationDB").Collection("cycle_counts")
+
+	_, err := collection.InsertOne(context.TODO(), count)
+	return err
+}
+
+func (r *MongoCycleCountRepository) FindByID(id string) (*model.CycleCount, error) {
+	collection := r.client.Database("locationDB").Collection("cycle_counts")
+
+	var count model.CycleCount
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&count)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &count, nil
+}
+
+// List retorna as contagens da localização, das mais recentes para as mais antigas
+func (r *MongoCycleCountRepository) List(locationID string, status model.CycleCountStatus) ([]*model.CycleCount, error) {
+	collection := r.client.Database("locationDB").Collection("cycle_counts")
+
+	filter := bson.M{}
+	if locationID != "" {
+		filter["locationId"] = locationID
+	}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	counts := make([]*model.CycleCount, 0)
+	if err := cursor.All(context.TODO(), &counts); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// RecordLine grava a contagem de um item, atualizando a linha existente ou incluindo
+// uma nova. Cada linha é atualizada isoladamente para que várias pessoas possam
+// contar a mesma localização ao mesmo tempo.
+func (r *MongoCycleCountRepository) RecordLine(id string, line model.CycleCountLine) error {
+	collection := r.client.Database("locationDB").Collection("cycle_counts")
+
+	item := bson.M{"productId": line.ProductID, "sku": line.SKU}
+	result, err := collection.UpdateOne(context.TODO(),
+		bson.M{"_id": id, "status": model.CycleCountOpen, "lines": bson.M{"$elemMatch": item}},
+		bson.M{"$set": bson.M{"lines.$[line]": line}},
+		options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+			bson.M{"line.productId": line.ProductID, "line.sku": line.SKU},
+		}}),
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	result, err = collection.UpdateOne(context.TODO(),
+		bson.M{"_id": id, "status": model.CycleCountOpen, "lines": bson.M{"$not": bson.M{"$elemMatch": item}}},
+		bson.M{"$push": bson.M{"lines": line}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrCycleCountClosed
+	}
+	return nil
+}
+
+// Transition muda o status da contagem se ela estiver em um dos status de origem
+func (r *MongoCycleCountRepository) Transition(id string, from []model.CycleCountStatus, to model.CycleCountStatus, userID string) error {
+	collection := r.client.Database("locationDB").Collection("cycle_counts")
+
+	update := bson.M{"status": to}
+	if to == model.CycleCountCompleted {
+		update["completedBy"] = userID
+		update["completedAt"] = time.Now()
+	}
+
+	result, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id, "status": bson.M{"$in": from}}, bson.M{"$set": update})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrCycleCountClosed
+	}
+	return nil
+}
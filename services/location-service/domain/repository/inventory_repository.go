@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"Varejo-Golang-Microservices/services/location-service/infra/db"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrInsufficientStock indica que a localização não tem unidades suficientes para a operação
+var ErrInsufficientStock = errors.New("estoque insuficiente na localização")
+
+// Quantidade de lançamentos recentes guardados no registro. A conciliação resolve os
+// lançamentos pendentes em poucos minutos, bem antes de saírem desta janela.
+const maxAppliedMovements = 100
+
+type MongoInventoryRepository struct {
+	client *mongo.Client
+}
+
+func NewMongoInventoryRepository(mongoURI string) *MongoInventoryRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	collection := client.Database("locationDB").Collection("inventory")
+	migrateInventorySKU(collection)
+
+	// Um único registro por produto, SKU e localização
+	_, err = collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "productId", Value: 1}, {Key: "sku", Value: 1}, {Key: "locationId", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índice de estoque: %v\n", err)
+	}
+
+	return &MongoInventoryRepository{
+		client: client,
+	}
+}
+
+// Registros anteriores às variantes não têm o campo sku e ainda estão sob o índice
+// único (productId, locationId). Sem o backfill, os filtros por {"sku": ""} não
+// os encontram e o upsert de Adjust cria um registro duplicado. A migração é
+// idempotente e roda a cada inicialização.
+func migrateInventorySKU(collection *mongo.Collection) {
+	result, err := collection.UpdateMany(context.TODO(),
+		bson.M{"sku": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"sku": ""}},
+	)
+	if err != nil {
+		log.Printf("Erro ao preencher o SKU dos registros de estoque: %v\n", err)
+	} else if result.ModifiedCount > 0 {
+		log.Printf("SKU preenchido em %d registros de estoque\n", result.ModifiedCount)
+	}
+
+	_, err = collection.Indexes().DropOne(context.TODO(), "productId_1_locationId_1")
+	if err != nil {
+		var cmdErr mongo.CommandError
+		// IndexNotFound: o índice antigo já foi removido
+		if !errors.As(err, &cmdErr) || cmdErr.Code != 27 {
+			log.Printf("Erro ao remover o índice antigo de estoque: %v\n", err)
+		}
+	}
+}
+
+func (r *MongoInventoryRepository) Find(productID, sku, locationID string) (*model.InventoryRecord, error) {
+	collection := r.client.Database("locationDB").Collection("inventory")
+
+	var record model.InventoryRecord
+	err := collection.FindOne(context.TODO(), bson.M{"productId": productID, "sku": sku, "locationId": locationID}).Decode(&record)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (r *MongoInventoryRepository) ListAll() ([]*model.InventoryRecord, error) {
+	return r.find(bson.M{})
+}
+
+func (r *MongoInventoryRepository) ListByProduct(productID string) ([]*model.InventoryRecord, error) {
+	return r.find(bson.M{"productId": productID})
+}
+
+func (r *MongoInventoryRepository) ListByLocation(locationID string) ([]*model.InventoryRecord, error) {
+	return r.find(bson.M{"locationId": locationID})
+}
+
+// Lista os registros dos produtos informados nas localizações informadas
+func (r *MongoInventoryRepository) ListByProductsAndLocations(productIDs, locationIDs []string) ([]*model.InventoryRecord, error) {
+	return r.find(bson.M{
+		"productId":  bson.M{"$in": productIDs},
+		"locationId": bson.M{"$in": locationIDs},
+	})
+}
+
+func (r *MongoInventoryRepository) find(filter bson.M) ([]*model.InventoryRecord, error) {
+	collection := r.client.Database("locationDB").Collection("inventory")
+
+	cursor, err := collection.Find(context.TODO(), filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var records []*model.InventoryRecord
+	for cursor.Next(context.TODO()) {
+		var record model.InventoryRecord
+		if err := cursor.Decode(&record); err != nil {
+			return nil, err
+		}
+		records = append(records, &record)
+	}
+
+	return records, cursor.Err()
+}
+
+// Adjust soma os deltas aos contadores do registro de forma atômica. Deltas
+// negativos só são aplicados se o saldo resultante não ficar negativo; o registro
+// é criado na primeira entrada de mercadoria.
+func (r *MongoInventoryRepository) Adjust(productID, sku, locationID string, onHandDelta, reservedDelta, inTransitDelta int) (*model.InventoryRecord, error) {
+	filter, update, upsert := counterUpdate(productID, sku, locationID, onHandDelta, reservedDelta, inTransitDelta)
+	return r.findOneAndUpdate(filter, update, upsert)
+}
+
+// ApplyMovement soma ao registro os deltas de um lançamento do livro uma única vez:
+// o ID do lançamento fica no registro, e aplicar de novo só retorna o registro atual.
+func (r *MongoInventoryRepository) ApplyMovement(movementID, productID, sku, locationID string, onHandDelta, reservedDelta, inTransitDelta int) (*model.InventoryRecord, error) {
+	filter, update, upsert := counterUpdate(productID, sku, locationID, onHandDelta, reservedDelta, inTransitDelta)
+	filter["appliedMovements"] = bson.M{"$ne": movementID}
+	update["$push"] = bson.M{"appliedMovements": bson.M{"$each": bson.A{movementID}, "$slice": -maxAppliedMovements}}
+
+	record, err := r.findOneAndUpdate(filter, update, upsert)
+	// Sem correspondência (ou, no upsert, com o registro já existente) o lançamento
+	// pode já ter sido aplicado
+	if errors.Is(err, ErrInsufficientStock) || mongo.IsDuplicateKeyError(err) {
+		current, findErr := r.Find(productID, sku, locationID)
+		if findErr != nil {
+			return nil, findErr
+		}
+		if current != nil && current.HasApplied(movementID) {
+			return current, nil
+		}
+	}
+	return record, err
+}
+
+func counterUpdate(productID, sku, locationID string, onHandDelta, reservedDelta, inTransitDelta int) (bson.M, bson.M, bool) {
+	availableDelta := onHandDelta - reservedDelta
+	filter := bson.M{"productId": productID, "sku": sku, "locationId": locationID}
+	if onHandDelta < 0 {
+		filter["onHand"] = bson.M{"$gte": -onHandDelta}
+	}
+	if reservedDelta < 0 {
+		filter["reserved"] = bson.M{"$gte": -reservedDelta}
+	}
+	if availableDelta < 0 {
+		filter["available"] = bson.M{"$gte": -availableDelta}
+	}
+	if inTransitDelta < 0 {
+		filter["inTransit"] = bson.M{"$gte": -inTransitDelta}
+	}
+	// Só cria o registro quando nenhum contador diminui
+	upsert := onHandDelta >= 0 && reservedDelta >= 0 && availableDelta >= 0 && inTransitDelta >= 0
+
+	update := bson.M{
+		"$inc": bson.M{
+			"onHand":    onHandDelta,
+			"reserved":  reservedDelta,
+			"available": availableDelta,
+			"inTransit": inTransitDelta,
+		},
+		"$set":         bson.M{"updatedAt": time.Now()},
+		"$setOnInsert": bson.M{"_id": uuid.New().String()},
+	}
+
+	return filter, update, upsert
+}
+
+func (r *MongoInventoryRepository) findOneAndUpdate(filter, update bson.M, upsert bool) (*model.InventoryRecord, error) {
+	collection := r.client.Database("locationDB").Collection("inventory")
+	opts := options.FindOneAndUpdate().SetUpsert(upsert).SetReturnDocument(options.After)
+
+	var record model.InventoryRecord
+	err := collection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&record)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrInsufficientStock
+		}
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// ClaimOpening reserva o saldo atual do registro para o lançamento de abertura. Só
+// registros que nunca receberam lançamentos do livro podem ser reivindicados, e o
+// saldo é copiado no mesmo update, então movimentos e outras instâncias não se
+// intercalam com a abertura. Retorna nil se o registro não pôde ser reivindicado.
+func (r *MongoInventoryRepository) ClaimOpening(id, movementID string) (*model.InventoryRecord, error) {
+	collection := r.client.Database("locationDB").Collection("inventory")
+
+	filter := bson.M{"_id": id, "appliedMovements": bson.M{"$exists": false}}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"openingBalance":   "$onHand",
+		"appliedMovements": bson.A{movementID},
+	}}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var record model.InventoryRecord
+	err := collection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&record)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// ListOpening lista os registros reivindicados cuja abertura ainda não foi lançada
+func (r *MongoInventoryRepository) ListOpening() ([]*model.InventoryRecord, error) {
+	return r.find(bson.M{"openingBalance": bson.M{"$exists": true}})
+}
+
+// ClearOpening encerra a abertura do registro depois do lançamento gravado
+func (r *MongoInventoryRepository) ClearOpening(id string) error {
+	collection := r.client.Database("locationDB").Collection("inventory")
+
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$unset": bson.M{"openingBalance": ""}})
+	return err
+}
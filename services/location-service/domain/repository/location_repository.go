@@ -10,7 +10,6 @@ import (
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -42,14 +41,10 @@ func NewMongoLocationRepository(mongoURI string, kafkaBroker string) *MongoLocat
 func (r *MongoLocationRepository) FindByID(id string) (*model.Location, error) {
 	collection := r.client.Database("locationDB").Collection("locations")
 
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return nil, err
-	}
-
-	filter := bson.M{"_id": objID}
+	// Os IDs das localizações são UUIDs gravados como string
+	filter := bson.M{"_id": id}
 	var location model.Location
-	err = collection.FindOne(context.TODO(), filter).Decode(&location)
+	err := collection.FindOne(context.TODO(), filter).Decode(&location)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil
@@ -143,6 +138,8 @@ func (r *MongoLocationRepository) Update(location *model.Location) error {
 			{Key: "description", Value: location.Description},
 			{Key: "address", Value: location.Address},
 			{Key: "data", Value: location.Data},
+			{Key: "region", Value: location.Region},
+			{Key: "type", Value: location.Type},
 			{Key: "createdDate", Value: location.CreatedDate},
 			{Key: "status", Value: location.Status},
 		}},
@@ -172,3 +169,30 @@ func (r *MongoLocationRepository) Delete(id string) error {
 
 	return nil
 }
+
+// Lista as localizações ativas, opcionalmente filtrando pela região
+func (r *MongoLocationRepository) ListActive(region string) ([]*model.Location, error) {
+	collection := r.client.Database("locationDB").Collection("locations")
+
+	filter := bson.M{"status": model.Active}
+	if region != "" {
+		filter["region"] = region
+	}
+
+	cursor, err := collection.Find(context.TODO(), filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var locations []*model.Location
+	for cursor.Next(context.TODO()) {
+		var location model.Location
+		if err := cursor.Decode(&location); err != nil {
+			return nil, err
+		}
+		locations = append(locations, &location)
+	}
+
+	return locations, cursor.Err()
+}
@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"Varejo-Golang-Microservices/services/location-service/infra/db"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrDuplicateMovement indica que já existe um lançamento com o mesmo ID
+var ErrDuplicateMovement = errors.New("lançamento já registrado no livro")
+
+// Filtro dos lançamentos que contam no saldo; os anteriores ao status não têm o campo
+var appliedMovement = bson.M{"$nin": bson.A{model.MovementPending, model.MovementApplying, model.MovementDiscarded}}
+
+// MongoMovementRepository é o livro de movimentos de estoque. Os lançamentos
+// aplicados são imutáveis: correções são novos lançamentos. Um lançamento que não
+// chegou ao registro de estoque é marcado como descartado, nunca apagado.
+type MongoMovementRepository struct {
+	client *mongo.Client
+}
+
+func NewMongoMovementRepository(mongoURI string) *MongoMovementRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	collection := client.Database("locationDB").Collection("stock_movements")
+	_, err = collection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "productId", Value: 1}, {Key: "sku", Value: 1}, {Key: "locationId", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "locationId", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "reference", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índices de movimentos de estoque: %v\n", err)
+	}
+
+	return &MongoMovementRepository{
+		client: client,
+	}
+}
+
+func (r *MongoMovementRepository) Insert(movement *model.StockMovement) error {
+	collection := r.client.Database("locationDB").Collection("stock_movements")
+
+	if movement.ID == "" {
+		movement.ID = uuid.New().String()
+	}
+	_, err := collection.InsertOne(context.TODO(), movement)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicateMovement
+	}
+	return err
+}
+
+func (r *MongoMovementRepository) FindByID(id string) (*model.StockMovement, error) {
+	collection := r.client.Database("locationDB").Collection("stock_movements")
+
+	var movement model.StockMovement
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&movement)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &movement, nil
+}
+
+// Claim marca o lançamento como em aplicação antes de alterar o registro de estoque.
+// Um lançamento descartado pode ser retomado pelo mesmo ID, pois nunca chegou ao
+// registro. Retorna false se o lançamento já foi aplicado.
+func (r *MongoMovementRepository) Claim(movement *model.StockMovement) (bool, error) {
+	collection := r.client.Database("locationDB").Collection("stock_movements")
+
+	movement.CreatedAt = time.Now()
+	filter := bson.M{
+		"_id":    movement.ID,
+		"status": bson.M{"$in": bson.A{model.MovementPending, model.MovementApplying, model.MovementDiscarded}},
+	}
+	update := bson.M{"$set": bson.M{"status": model.MovementApplying, "createdAt": movement.CreatedAt}}
+	result, err := collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return false, err
+	}
+	movement.Status = model.MovementApplying
+	return result.MatchedCount > 0, nil
+}
+
+// MarkApplied grava o lançamento como aplicado. O lançamento é gravado inteiro, pois a
+// conciliação pode tê-lo descartado enquanto a aplicação estava parada.
+func (r *MongoMovementRepository) MarkApplied(movement *model.StockMovement) error {
+	collection := r.client.Database("locationDB").Collection("stock_movements")
+
+	movement.Status = model.MovementApplied
+	_, err := collection.ReplaceOne(context.TODO(), bson.M{"_id": movement.ID}, movement, options.Replace().SetUpsert(true))
+	return err
+}
+
+// Discard marca como descartado um lançamento que não foi aplicado ao registro de
+// estoque, desde que ele ainda esteja no status informado
+func (r *MongoMovementRepository) Discard(id string, from model.MovementStatus) error {
+	collection := r.client.Database("locationDB").Collection("stock_movements")
+
+	filter := bson.M{"_id": id, "status": from}
+	_, err := collection.UpdateOne(context.TODO(), filter, bson.M{"$set": bson.M{"status": model.MovementDiscarded}})
+	return err
+}
+
+// ListUnapplied lista os lançamentos pendentes ou em aplicação desde antes do instante informado
+func (r *MongoMovementRepository) ListUnapplied(before time.Time) ([]*model.StockMovement, error) {
+	collection := r.client.Database("locationDB").Collection("stock_movements")
+
+	filter := bson.M{
+		"status":    bson.M{"$in": bson.A{model.MovementPending, model.MovementApplying}},
+		"createdAt": bson.M{"$lt": before},
+	}
+	cursor, err := collection.Find(context.TODO(), filter, options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var movements []*model.StockMovement
+	if err := cursor.All(context.TODO(), &movements); err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// List retorna os movimentos do filtro, do mais recente para o mais antigo
+func (r *MongoMovementRepository) List(filter model.MovementFilter, skip, limit int64) ([]*model.StockMovement, int64, error) {
+	collection := r.client.Database("locationDB").Collection("stock_movements")
+
+	query := movementQuery(filter)
+	total, err := collection.CountDocuments(context.TODO(), query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetSkip(skip).SetLimit(limit)
+	cursor, err := collection.Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(context.TODO())
+
+	movements := make([]*model.StockMovement, 0)
+	if err := cursor.All(context.TODO(), &movements); err != nil {
+		return nil, 0, err
+	}
+	return movements, total, nil
+}
+
+func movementQuery(filter model.MovementFilter) bson.M {
+	query := bson.M{}
+	if filter.ProductID != "" {
+		query["productId"] = filter.ProductID
+	}
+	if filter.SKU != nil {
+		query["sku"] = *filter.SKU
+	}
+	if filter.LocationID != "" {
+		query["locationId"] = filter.LocationID
+	}
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+	if filter.Reference != "" {
+		query["reference"] = filter.Reference
+	}
+	createdAt := bson.M{}
+	if !filter.From.IsZero() {
+		createdAt["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		createdAt["$lt"] = filter.To
+	}
+	if len(createdAt) > 0 {
+		query["createdAt"] = createdAt
+	}
+	return query
+}
+
+// Balance soma os lançamentos aplicados do produto na localização
+func (r *MongoMovementRepository) Balance(productID, sku, locationID string) (int, int, error) {
+	collection := r.client.Database("locationDB").Collection("stock_movements")
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"productId": productID, "sku": sku, "locationId": locationID, "status": appliedMovement}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     nil,
+			"balance": bson.M{"$sum": "$quantity"},
+			"count":   bson.M{"$sum": 1},
+		}}},
+	}
+	cursor, err := collection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var result struct {
+		Balance int `bson:"balance"`
+		Count   int `bson:"count"`
+	}
+	if cursor.Next(context.TODO()) {
+		if err := cursor.Decode(&result); err != nil {
+			return 0, 0, err
+		}
+	}
+	return result.Balance, result.Count, cursor.Err()
+}
+
+// LedgerKeys retorna as chaves produto/SKU/localização que já têm lançamentos
+func (r *MongoMovementRepository) LedgerKeys() (map[[3]string]bool, error) {
+	collection := r.client.Database("locationDB").Collection("stock_movements")
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"productId": "$productId", "sku": "$sku", "locationId": "$locationId"}}}},
+	}
+	cursor, err := collection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	keys := make(map[[3]string]bool)
+	for cursor.Next(context.TODO()) {
+		var doc struct {
+			ID struct {
+				ProductID  string `bson:"productId"`
+				SKU        string `bson:"sku"`
+				LocationID string `bson:"locationId"`
+			} `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		keys[[3]string{doc.ID.ProductID, doc.ID.SKU, doc.ID.LocationID}] = true
+	}
+	return keys, cursor.Err()
+}
+
+// Shrinkage consolida por produto e localização as quebras declaradas, as
+// diferenças das contagens cíclicas e as vendas do período
+func (r *MongoMovementRepository) Shrinkage(locationID string, from, to time.Time) ([]*model.ShrinkageLine, error) {
+	collection := r.client.Database("locationDB").Collection("stock_movements")
+
+	match := bson.M{
+		"createdAt": bson.M{"$gte": from, "$lt": to},
+		"status":    appliedMovement,
+		"$or": bson.A{
+			bson.M{"type": bson.M{"$in": bson.A{model.ShrinkageMovement, model.SaleMovement}}},
+			bson.M{"type": model.AdjustmentMovement, "referenceType": model.CycleCountReference},
+		},
+	}
+	if locationID != "" {
+		match["locationId"] = locationID
+	}
+
+	isType := func(movementType model.MovementType) bson.M {
+		return bson.M{"$eq": bson.A{"$type", movementType}}
+	}
+	countAdjustment := bson.M{"$eq": bson.A{"$referenceType", model.CycleCountReference}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"locationId": "$locationId", "productId": "$productId", "sku": "$sku"},
+			"declared": bson.M{"$sum": bson.M{"$cond": bson.A{
+				isType(model.ShrinkageMovement), bson.M{"$multiply": bson.A{"$quantity", -1}}, 0,
+			}}},
+			"countLosses": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$and": bson.A{countAdjustment, bson.M{"$lt": bson.A{"$quantity", 0}}}}, bson.M{"$multiply": bson.A{"$quantity", -1}}, 0,
+			}}},
+			"countGains": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$and": bson.A{countAdjustment, bson.M{"$gt": bson.A{"$quantity", 0}}}}, "$quantity", 0,
+			}}},
+			"sold": bson.M{"$sum": bson.M{"$cond": bson.A{
+				isType(model.SaleMovement), bson.M{"$multiply": bson.A{"$quantity", -1}}, 0,
+			}}},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":         0,
+			"locationId":  "$_id.locationId",
+			"productId":   "$_id.productId",
+			"sku":         "$_id.sku",
+			"declared":    1,
+			"countLosses": 1,
+			"countGains":  1,
+			"sold":        1,
+			"netLoss":     bson.M{"$subtract": bson.A{bson.M{"$add": bson.A{"$declared", "$countLosses"}}, "$countGains"}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "netLoss", Value: -1}, {Key: "locationId", Value: 1}, {Key: "productId", Value: 1}}}},
+	}
+
+	cursor, err := collection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var lines []*model.ShrinkageLine
+	if err := cursor.All(context.TODO(), &lines); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"Varejo-Golang-Microservices/services/location-service/infra/db"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrPurchaseOrderChanged indica que o pedido de compra mudou desde a leitura ou não
+// está no status exigido pela operação
+var ErrPurchaseOrderChanged = errors.New("o pedido de compra foi alterado ou não está no status exigido pela operação")
+
+type MongoPurchaseOrderRepository struct {
+	client *mongo.Client
+}
+
+func NewMongoPurchaseOrderRepository(mongoURI string) *MongoPurchaseOrderRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	collection := client.Database("locationDB").Collection("purchase_orders")
+	_, err = collection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "supplierId", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "location._id", Value: 1}, {Key: "createdAt", Value: -1}}},
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índices de pedidos de compra: %v\n", err)
+	}
+
+	return &MongoPurchaseOrderRepository{
+		client: client,
+	}
+}
+
+func (r *MongoPurchaseOrderRepository) Save(order *model.PurchaseOrder) error {
+	collection := r.client.Database("locationDB").Collection("purchase_orders")
+
+	_, err := collection.InsertOne(context.TODO(), order)
+	return err
+}
+
+func (r *MongoPurchaseOrderRepository) FindByID(id string) (*model.PurchaseOrder, error) {
+	collection := r.client.Database("locationDB").Collection("purchase_orders")
+
+	var order model.PurchaseOrder
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&order)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (r *MongoPurchaseOrderRepository) List(filter model.PurchaseOrderFilter) ([]*model.PurchaseOrder, error) {
+	collection := r.client.Database("locationDB").Collection("purchase_orders")
+
+	query := bson.M{}
+	if filter.SupplierID != "" {
+		query["supplierId"] = filter.SupplierID
+	}
+	if filter.LocationID != "" {
+		query["location._id"] = filter.LocationID
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := collection.Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	orders := make([]*model.PurchaseOrder, 0)
+	if err := cursor.All(context.TODO(), &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// MarkReceiptPosted registra que as entradas do recebimento foram lançadas no estoque,
+// incrementando a versão para que uma gravação feita com a versão anterior não desfaça a marcação
+func (r *MongoPurchaseOrderRepository) MarkReceiptPosted(orderID, receiptID string) error {
+	collection := r.client.Database("locationDB").Collection("purchase_orders")
+
+	_, err := collection.UpdateOne(context.TODO(),
+		bson.M{"_id": orderID, "receipts.id": receiptID},
+		bson.M{
+			"$unset": bson.M{"receipts.$.pending": ""},
+			"$set":   bson.M{"updatedAt": time.Now()},
+			"$inc":   bson.M{"version": 1},
+		},
+	)
+	return err
+}
+
+// Update grava o pedido se ele ainda estiver na versão lida, incrementando a versão.
+// Com event, registra o novo status no histórico.
+func (r *MongoPurchaseOrderRepository) Update(order *model.PurchaseOrder, event *model.PurchaseOrderEvent) error {
+	collection := r.client.Database("locationDB").Collection("purchase_orders")
+
+	version := order.Version
+	order.Version++
+	order.UpdatedAt = time.Now()
+	if event != nil {
+		order.Status = event.Status
+		order.History = append(order.History, *event)
+	}
+
+	result, err := collection.ReplaceOne(context.TODO(), bson.M{"_id": order.ID, "version": version}, order)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrPurchaseOrderChanged
+	}
+	return nil
+}
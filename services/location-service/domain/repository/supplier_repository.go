@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"Varejo-Golang-Microservices/services/location-service/infra/db"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrDuplicateCNPJ indica que já existe um fornecedor com o CNPJ
+	ErrDuplicateCNPJ = errors.New("já existe um fornecedor com este CNPJ")
+	// ErrPriceNotFound indica que o item não está na tabela de preços do fornecedor
+	ErrPriceNotFound = errors.New("item não encontrado na tabela de preços do fornecedor")
+)
+
+type MongoSupplierRepository struct {
+	client *mongo.Client
+}
+
+func NewMongoSupplierRepository(mongoURI string) *MongoSupplierRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	collection := client.Database("locationDB").Collection("suppliers")
+	_, err = collection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "cnpj", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "prices.productId", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índices de fornecedores: %v\n", err)
+	}
+
+	return &MongoSupplierRepository{
+		client: client,
+	}
+}
+
+func (r *MongoSupplierRepository) Save(supplier *model.Supplier) error {
+	collection := r.client.Database("locationDB").Collection("suppliers")
+
+	_, err := collection.InsertOne(context.TODO(), supplier)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicateCNPJ
+	}
+	return err
+}
+
+func (r *MongoSupplierRepository) FindByID(id string) (*model.Supplier, error) {
+	collection := r.client.Database("locationDB").Collection("suppliers")
+
+	var supplier model.Supplier
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&supplier)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &supplier, nil
+}
+
+// List retorna os fornecedores pelo status e, com productID, só os que têm o
+// produto na tabela de preços
+func (r *MongoSupplierRepository) List(status model.SupplierStatus, productID string) ([]*model.Supplier, error) {
+	collection := r.client.Database("locationDB").Collection("suppliers")
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	if productID != "" {
+		filter["prices.productId"] = productID
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "legalName", Value: 1}})
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	suppliers := make([]*model.Supplier, 0)
+	if err := cursor.All(context.TODO(), &suppliers); err != nil {
+		return nil, err
+	}
+	return suppliers, nil
+}
+
+// Update grava os dados cadastrais do fornecedor, sem alterar a tabela de preços
+func (r *MongoSupplierRepository) Update(supplier *model.Supplier) error {
+	collection := r.client.Database("locationDB").Collection("suppliers")
+
+	supplier.UpdatedAt = time.Now()
+	update := bson.M{"$set": bson.M{
+		"cnpj":         supplier.CNPJ,
+		"legalName":    supplier.LegalName,
+		"tradeName":    supplier.TradeName,
+		"contacts":     supplier.Contacts,
+		"leadTimeDays": supplier.LeadTimeDays,
+		"status":       supplier.Status,
+		"updatedAt":    supplier.UpdatedAt,
+	}}
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": supplier.ID}, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicateCNPJ
+	}
+	return err
+}
+
+// SetPrice grava o preço de um item, atualizando o existente ou incluindo um novo.
+// Cada item é atualizado isoladamente para não sobrescrever alterações simultâneas da tabela.
+func (r *MongoSupplierRepository) SetPrice(id string, price model.SupplierPrice) error {
+	collection := r.client.Database("locationDB").Collection("suppliers")
+
+	price.UpdatedAt = time.Now()
+	item := bson.M{"productId": price.ProductID, "sku": price.SKU}
+	result, err := collection.UpdateOne(context.TODO(),
+		bson.M{"_id": id, "prices": bson.M{"$elemMatch": item}},
+		bson.M{"$set": bson.M{"prices.$[price]": price, "updatedAt": price.UpdatedAt}},
+		options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+			bson.M{"price.productId": price.ProductID, "price.sku": price.SKU},
+		}}),
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	_, err = collection.UpdateOne(context.TODO(),
+		bson.M{"_id": id, "prices": bson.M{"$not": bson.M{"$elemMatch": item}}},
+		bson.M{"$push": bson.M{"prices": price}, "$set": bson.M{"updatedAt": price.UpdatedAt}},
+	)
+	return err
+}
+
+func (r *MongoSupplierRepository) RemovePrice(id, productID, sku string) error {
+	collection := r.client.Database("locationDB").Collection("suppliers")
+
+	item := bson.M{"productId": productID, "sku": sku}
+	result, err := collection.UpdateOne(context.TODO(),
+		bson.M{"_id": id},
+		bson.M{"$pull": bson.M{"prices": item}, "$set": bson.M{"updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return ErrPriceNotFound
+	}
+	return nil
+}
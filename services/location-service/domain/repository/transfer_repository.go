@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"Varejo-Golang-Microservices/services/location-service/infra/db"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrTransferStateChanged indica que a transferência não está no status exigido pela operação
+var ErrTransferStateChanged = errors.New("a transferência não está no status exigido pela operação")
+
+type MongoTransferRepository struct {
+	client *mongo.Client
+}
+
+func NewMongoTransferRepository(mongoURI string) *MongoTransferRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	collection := client.Database("locationDB").Collection("transfers")
+	_, err = collection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "source._id", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "destination._id", Value: 1}, {Key: "createdAt", Value: -1}}},
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índices de transferências: %v\n", err)
+	}
+
+	return &MongoTransferRepository{
+		client: client,
+	}
+}
+
+func (r *MongoTransferRepository) Save(transfer *model.Transfer) error {
+	collection := r.client.Database("locationDB").Collection("transfers")
+
+	_, err := collection.InsertOne(context.TODO(), transfer)
+	return err
+}
+
+func (r *MongoTransferRepository) FindByID(id string) (*model.Transfer, error) {
+	collection := r.client.Database("locationDB").Collection("transfers")
+
+	var transfer model.Transfer
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&transfer)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+// List retorna as transferências em que a localização é origem ou destino
+func (r *MongoTransferRepository) List(locationID string, status model.TransferStatus) ([]*model.Transfer, error) {
+	collection := r.client.Database("locationDB").Collection("transfers")
+
+	filter := bson.M{}
+	if locationID != "" {
+		filter["$or"] = bson.A{bson.M{"source._id": locationID}, bson.M{"destination._id": locationID}}
+	}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	transfers := make([]*model.Transfer, 0)
+	if err := cursor.All(context.TODO(), &transfers); err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// Transition muda o status se a transferência estiver em um dos status de origem,
+// gravando as linhas atualizadas e o evento no histórico
+func (r *MongoTransferRepository) Transition(id string, from []model.TransferStatus, event model.TransferEvent, lines []model.TransferLine) error {
+	collection := r.client.Database("locationDB").Collection("transfers")
+
+	set := bson.M{"status": event.Status, "updatedAt": time.Now()}
+	if lines != nil {
+		set["lines"] = lines
+	}
+
+	result, err := collection.UpdateOne(context.TODO(),
+		bson.M{"_id": id, "status": bson.M{"$in": from}},
+		bson.M{"$set": set, "$push": bson.M{"history": event}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrTransferStateChanged
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"Varejo-Golang-Microservices/services/location-service/domain/repository"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCycleCountNotFound = errors.New("contagem não encontrada")
+	ErrInvalidCycleCount  = errors.New("contagem inválida")
+)
+
+type CycleCountService interface {
+	StartCount(locationID string, items []model.AllocationItem, notes, userID string) (*model.CycleCount, error)
+	GetCount(id string) (*model.CycleCount, error)
+	ListCounts(locationID string, status model.CycleCountStatus) ([]*model.CycleCount, error)
+	RecordCounts(id string, entries []model.CycleCountEntry, userID string) (*model.CycleCount, error)
+	CompleteCount(id, userID string) (*model.CycleCount, error)
+	CancelCount(id string) (*model.CycleCount, error)
+}
+
+type CycleCountServiceImpl struct {
+	cycleCountRepo   *repository.MongoCycleCountRepository
+	inventoryRepo    *repository.MongoInventoryRepository
+	movementRepo     *repository.MongoMovementRepository
+	locationRepo     *repository.MongoLocationRepository
+	inventoryService InventoryService
+}
+
+func NewCycleCountService(cycleCountRepo *repository.MongoCycleCountRepository, inventoryRepo *repository.MongoInventoryRepository, movementRepo *repository.MongoMovementRepository, locationRepo *repository.MongoLocationRepository, inventoryService InventoryService) CycleCountService {
+	return &CycleCountServiceImpl{
+		cycleCountRepo:   cycleCountRepo,
+		inventoryRepo:    inventoryRepo,
+		movementRepo:     movementRepo,
+		locationRepo:     locationRepo,
+		inventoryService: inventoryService,
+	}
+}
+
+// StartCount abre uma contagem com os itens informados ou, sem itens, com todos
+// os registros de estoque da localização. Itens fora da lista podem ser incluídos
+// ao registrar a contagem.
+func (s *CycleCountServiceImpl) StartCount(locationID string, items []model.AllocationItem, notes, userID string) (*model.CycleCount, error) {
+	location, err := s.locationRepo.FindByID(locationID)
+	if err != nil {
+		return nil, err
+	}
+	if location == nil {
+		return nil, ErrLocationNotFound
+	}
+
+	count := &model.CycleCount{
+		ID:         uuid.New().String(),
+		LocationID: locationID,
+		Status:     model.CycleCountOpen,
+		Lines:      make([]model.CycleCountLine, 0),
+		Notes:      notes,
+		CreatedBy:  userID,
+		CreatedAt:  time.Now(),
+	}
+
+	if len(items) == 0 {
+		records, err := s.inventoryRepo.ListByLocation(locationID)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			count.Lines = append(count.Lines, model.CycleCountLine{ProductID: record.ProductID, SKU: record.SKU, Expected: record.OnHand})
+		}
+	}
+
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if item.ProductID == "" {
+			return nil, fmt.Errorf("%w: o ID do produto é obrigatório", ErrInvalidCycleCount)
+		}
+		key := stockKey(item.ProductID, item.SKU)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		expected, err := s.onHand(item.ProductID, item.SKU, locationID)
+		if err != nil {
+			return nil, err
+		}
+		count.Lines = append(count.Lines, model.CycleCountLine{ProductID: item.ProductID, SKU: item.SKU, Expected: expected})
+	}
+
+	if err := s.cycleCountRepo.Save(count); err != nil {
+		return nil, err
+	}
+	return count, nil
+}
+
+func (s *CycleCountServiceImpl) GetCount(id string) (*model.CycleCount, error) {
+	count, err := s.cycleCountRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if count == nil {
+		return nil, ErrCycleCountNotFound
+	}
+	return count, nil
+}
+
+func (s *CycleCountServiceImpl) ListCounts(locationID string, status model.CycleCountStatus) ([]*model.CycleCount, error) {
+	return s.cycleCountRepo.List(locationID, status)
+}
+
+// RecordCounts registra as quantidades contadas. O esperado é o OnHand no momento
+// do registro, de modo que a diferença não inclui as vendas feitas antes da contagem.
+func (s *CycleCountServiceImpl) RecordCounts(id string, entries []model.CycleCountEntry, userID string) (*model.CycleCount, error) {
+	count, err := s.GetCount(id)
+	if err != nil {
+		return nil, err
+	}
+	if count.Status != model.CycleCountOpen {
+		return nil, repository.ErrCycleCountClosed
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: informe ao menos um item contado", ErrInvalidCycleCount)
+	}
+	for _, entry := range entries {
+		if entry.ProductID == "" {
+			return nil, fmt.Errorf("%w: o ID do produto é obrigatório", ErrInvalidCycleCount)
+		}
+		if entry.Counted < 0 {
+			return nil, fmt.Errorf("%w: a quantidade contada de %s não pode ser negativa", ErrInvalidCycleCount, stockKey(entry.ProductID, entry.SKU))
+		}
+	}
+
+	for _, entry := range entries {
+		expected, err := s.onHand(entry.ProductID, entry.SKU, count.LocationID)
+		if err != nil {
+			return nil, err
+		}
+
+		counted := entry.Counted
+		countedAt := time.Now()
+		line := model.CycleCountLine{
+			ProductID: entry.ProductID,
+			SKU:       entry.SKU,
+			Expected:  expected,
+			Counted:   &counted,
+			Variance:  counted - expected,
+			CountedBy: userID,
+			CountedAt: &countedAt,
+		}
+		if err := s.cycleCountRepo.RecordLine(id, line); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetCount(id)
+}
+
+// CompleteCount lança um ajuste para cada item contado com diferença e encerra a
+// contagem. Os ajustes referenciam a contagem e têm ID fixo por item; se a conclusão
+// for interrompida, ela pode ser repetida e os itens já ajustados não são lançados de novo.
+func (s *CycleCountServiceImpl) CompleteCount(id, userID string) (*model.CycleCount, error) {
+	count, err := s.GetCount(id)
+	if err != nil {
+		return nil, err
+	}
+
+	open := []model.CycleCountStatus{model.CycleCountOpen, model.CycleCountCompleting}
+	if err := s.cycleCountRepo.Transition(id, open, model.CycleCountCompleting, userID); err != nil {
+		return nil, err
+	}
+
+	applied, _, err := s.movementRepo.List(model.MovementFilter{Reference: id, LocationID: count.LocationID}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	adjusted := make(map[string]bool, len(applied))
+	for _, movement := range applied {
+		// Um ajuste pendente ou descartado é retomado pelo mesmo ID
+		if movement.IsApplied() {
+			adjusted[stockKey(movement.ProductID, movement.SKU)] = true
+		}
+	}
+
+	for _, line := range count.Lines {
+		if line.Counted == nil || line.Variance == 0 || adjusted[stockKey(line.ProductID, line.SKU)] {
+			continue
+		}
+
+		_, err := s.inventoryService.RecordMovement(&model.StockMovement{
+			ID:            fmt.Sprintf("cycle-count:%s:%s", id, stockKey(line.ProductID, line.SKU)),
+			ProductID:     line.ProductID,
+			SKU:           line.SKU,
+			LocationID:    count.LocationID,
+			Type:          model.AdjustmentMovement,
+			Quantity:      line.Variance,
+			Reason:        fmt.Sprintf("Contagem cíclica: contado %d, esperado %d", *line.Counted, line.Expected),
+			UserID:        userID,
+			ReferenceType: model.CycleCountReference,
+			Reference:     id,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ajustar %s: %w", stockKey(line.ProductID, line.SKU), err)
+		}
+	}
+
+	if err := s.cycleCountRepo.Transition(id, []model.CycleCountStatus{model.CycleCountCompleting}, model.CycleCountCompleted, userID); err != nil {
+		return nil, err
+	}
+	return s.GetCount(id)
+}
+
+func (s *CycleCountServiceImpl) CancelCount(id string) (*model.CycleCount, error) {
+	if _, err := s.GetCount(id); err != nil {
+		return nil, err
+	}
+	if err := s.cycleCountRepo.Transition(id, []model.CycleCountStatus{model.CycleCountOpen}, model.CycleCountCanceled, ""); err != nil {
+		return nil, err
+	}
+	return s.GetCount(id)
+}
+
+func (s *CycleCountServiceImpl) onHand(productID, sku, locationID string) (int, error) {
+	record, err := s.inventoryRepo.Find(productID, sku, locationID)
+	if err != nil || record == nil {
+		return 0, err
+	}
+	return record.OnHand, nil
+}
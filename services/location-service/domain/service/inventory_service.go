@@ -0,0 +1,606 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"Varejo-Golang-Microservices/services/location-service/domain/repository"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+)
+
+var (
+	ErrLocationNotFound     = errors.New("localização não encontrada")
+	ErrNoFulfillingLocation = errors.New("nenhuma localização tem estoque para atender o pedido")
+	ErrInvalidMovement      = errors.New("movimento de estoque inválido")
+	ErrAllocationOrigin     = errors.New("informe as coordenadas de entrega ou a região")
+)
+
+// Lançamentos pendentes há mais tempo que isso são resolvidos pela conciliação
+const pendingMovementTimeout = 2 * time.Minute
+
+// Sinal exigido da quantidade de cada tipo de movimento: -1 baixa, 1 entrada, 0 qualquer
+var movementSigns = map[model.MovementType]int{
+	model.SaleMovement:       -1,
+	model.ShrinkageMovement:  -1,
+	model.ReturnMovement:     1,
+	model.ReceiptMovement:    1,
+	model.AdjustmentMovement: 0,
+	model.TransferMovement:   0,
+}
+
+type InventoryService interface {
+	GetProductInventory(productID string) ([]*model.InventoryRecord, error)
+	GetLocationInventory(locationID string) ([]*model.InventoryRecord, error)
+	GetRegionAvailability(productID, region string) ([]*model.RegionAvailability, error)
+	AdjustInventory(movement *model.StockMovement, inTransitDelta int) (*model.InventoryRecord, error)
+	RecordMovement(movement *model.StockMovement) (*model.InventoryRecord, error)
+	ApplyMovement(movement *model.StockMovement, reservedDelta, inTransitDelta int) (*model.InventoryRecord, error)
+	ListMovements(filter model.MovementFilter, page, pageSize int) ([]*model.StockMovement, int64, error)
+	GetLedgerBalance(productID, sku, locationID string) (*model.LedgerBalance, error)
+	GetShrinkageReport(locationID string, from, to time.Time) (*model.ShrinkageReport, error)
+	OpenLedger() error
+	ReconcileLedger() error
+	RunLedgerReconciliation(interval time.Duration)
+	Reserve(productID, sku, locationID string, quantity int) (*model.InventoryRecord, error)
+	Release(productID, sku, locationID string, quantity int) (*model.InventoryRecord, error)
+	Commit(productID, sku, locationID string, quantity int, reference, userID string) (*model.InventoryRecord, error)
+	Allocate(items []model.AllocationItem, origin *model.GeoPoint, region string) (*model.Allocation, error)
+}
+
+type InventoryServiceImpl struct {
+	inventoryRepo *repository.MongoInventoryRepository
+	locationRepo  *repository.MongoLocationRepository
+	movementRepo  *repository.MongoMovementRepository
+}
+
+func NewInventoryService(inventoryRepo *repository.MongoInventoryRepository, locationRepo *repository.MongoLocationRepository, movementRepo *repository.MongoMovementRepository) InventoryService {
+	return &InventoryServiceImpl{
+		inventoryRepo: inventoryRepo,
+		locationRepo:  locationRepo,
+		movementRepo:  movementRepo,
+	}
+}
+
+func (s *InventoryServiceImpl) GetProductInventory(productID string) ([]*model.InventoryRecord, error) {
+	return s.inventoryRepo.ListByProduct(productID)
+}
+
+func (s *InventoryServiceImpl) GetLocationInventory(locationID string) ([]*model.InventoryRecord, error) {
+	if _, err := s.getLocation(locationID); err != nil {
+		return nil, err
+	}
+	return s.inventoryRepo.ListByLocation(locationID)
+}
+
+// GetRegionAvailability soma o estoque do produto nas localizações ativas de cada região.
+// Sem região informada, retorna todas as regiões.
+func (s *InventoryServiceImpl) GetRegionAvailability(productID, region string) ([]*model.RegionAvailability, error) {
+	locations, err := s.locationRepo.ListActive(region)
+	if err != nil {
+		return nil, err
+	}
+
+	regionByLocation := make(map[string]string, len(locations))
+	locationIDs := make([]string, 0, len(locations))
+	for _, location := range locations {
+		regionByLocation[location.ID] = location.Region
+		locationIDs = append(locationIDs, location.ID)
+	}
+
+	records, err := s.inventoryRepo.ListByProductsAndLocations([]string{productID}, locationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byRegion := make(map[string]*model.RegionAvailability)
+	for _, record := range records {
+		name := regionByLocation[record.LocationID]
+		aggregate, ok := byRegion[name]
+		if !ok {
+			aggregate = &model.RegionAvailability{Region: name, ProductID: productID}
+			byRegion[name] = aggregate
+		}
+		aggregate.OnHand += record.OnHand
+		aggregate.Reserved += record.Reserved
+		aggregate.Available += record.Available
+		aggregate.InTransit += record.InTransit
+		aggregate.Locations++
+	}
+
+	result := make([]*model.RegionAvailability, 0, len(byRegion))
+	for _, aggregate := range byRegion {
+		result = append(result, aggregate)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Region < result[j].Region })
+
+	return result, nil
+}
+
+// AdjustInventory lança o movimento no estoque físico e ajusta o saldo em trânsito.
+// O saldo em trânsito não faz parte do livro, então pode ser ajustado sem movimento;
+// nesse caso, um ID no movimento torna o ajuste idempotente como os lançamentos.
+func (s *InventoryServiceImpl) AdjustInventory(movement *model.StockMovement, inTransitDelta int) (*model.InventoryRecord, error) {
+	if movement.Type == "" {
+		movement.Type = model.AdjustmentMovement
+	}
+	if movement.Quantity == 0 && inTransitDelta != 0 {
+		if movement.ProductID == "" {
+			return nil, fmt.Errorf("%w: o ID do produto é obrigatório", ErrInvalidMovement)
+		}
+		if _, err := s.getLocation(movement.LocationID); err != nil {
+			return nil, err
+		}
+		if movement.ID != "" {
+			return s.inventoryRepo.ApplyMovement(movement.ID, movement.ProductID, movement.SKU, movement.LocationID, 0, 0, inTransitDelta)
+		}
+		return s.inventoryRepo.Adjust(movement.ProductID, movement.SKU, movement.LocationID, 0, 0, inTransitDelta)
+	}
+
+	if err := s.validateMovement(movement); err != nil {
+		return nil, err
+	}
+	return s.apply(movement, 0, inTransitDelta)
+}
+
+// RecordMovement lança um movimento no livro e aplica a quantidade ao estoque físico
+func (s *InventoryServiceImpl) RecordMovement(movement *model.StockMovement) (*model.InventoryRecord, error) {
+	return s.ApplyMovement(movement, 0, 0)
+}
+
+// ApplyMovement lança o movimento ajustando no mesmo update as unidades reservadas
+// e em trânsito, como na baixa de unidades separadas para uma transferência
+func (s *InventoryServiceImpl) ApplyMovement(movement *model.StockMovement, reservedDelta, inTransitDelta int) (*model.InventoryRecord, error) {
+	if err := s.validateMovement(movement); err != nil {
+		return nil, err
+	}
+	return s.apply(movement, reservedDelta, inTransitDelta)
+}
+
+func (s *InventoryServiceImpl) validateMovement(movement *model.StockMovement) error {
+	if movement.ProductID == "" {
+		return fmt.Errorf("%w: o ID do produto é obrigatório", ErrInvalidMovement)
+	}
+	sign, ok := movementSigns[movement.Type]
+	if !ok {
+		return fmt.Errorf("%w: tipo %s desconhecido", ErrInvalidMovement, movement.Type)
+	}
+	switch {
+	case movement.Quantity == 0:
+		return fmt.Errorf("%w: a quantidade não pode ser zero", ErrInvalidMovement)
+	case sign < 0 && movement.Quantity > 0:
+		return fmt.Errorf("%w: movimentos do tipo %s baixam o estoque e exigem quantidade negativa", ErrInvalidMovement, movement.Type)
+	case sign > 0 && movement.Quantity < 0:
+		return fmt.Errorf("%w: movimentos do tipo %s dão entrada no estoque e exigem quantidade positiva", ErrInvalidMovement, movement.Type)
+	}
+
+	_, err := s.getLocation(movement.LocationID)
+	return err
+}
+
+// Grava o lançamento como pendente, marca-o como em aplicação, aplica a quantidade ao
+// registro e marca o lançamento como aplicado com o saldo resultante. O registro
+// guarda o ID do lançamento, então repetir um lançamento com o mesmo ID não altera o
+// estoque de novo; os chamadores que precisam retomar uma operação usam IDs fixos. O
+// lançamento nunca sai do livro: se o processo parar no meio, a conciliação resolve
+// o lançamento pelo registro.
+func (s *InventoryServiceImpl) apply(movement *model.StockMovement, reservedDelta, inTransitDelta int) (*model.InventoryRecord, error) {
+	movement.Status = model.MovementPending
+	movement.CreatedAt = time.Now()
+	if err := s.movementRepo.Insert(movement); err != nil && !errors.Is(err, repository.ErrDuplicateMovement) {
+		return nil, err
+	}
+
+	claimed, err := s.movementRepo.Claim(movement)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		// Já aplicado por uma tentativa anterior com o mesmo ID
+		existing, err := s.movementRepo.FindByID(movement.ID)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			*movement = *existing
+		}
+		return s.inventoryRepo.Find(movement.ProductID, movement.SKU, movement.LocationID)
+	}
+
+	record, err := s.inventoryRepo.ApplyMovement(movement.ID, movement.ProductID, movement.SKU, movement.LocationID, movement.Quantity, reservedDelta, inTransitDelta)
+	if err != nil {
+		// Sem estoque, o lançamento não chegou ao registro e é descartado
+		if errors.Is(err, repository.ErrInsufficientStock) {
+			if discardErr := s.movementRepo.Discard(movement.ID, model.MovementApplying); discardErr != nil {
+				return nil, fmt.Errorf("%w (o lançamento %s fica pendente até a conciliação: %v)", err, movement.ID, discardErr)
+			}
+		}
+		return nil, err
+	}
+
+	// O estoque já mudou; se a marcação falhar, a conciliação encontra o ID no registro
+	// e marca o lançamento, então a operação não deve ser repetida pelo chamador
+	movement.BalanceAfter = record.OnHand
+	if err := s.movementRepo.MarkApplied(movement); err != nil {
+		log.Printf("Lançamento %s aplicado ao estoque e pendente até a conciliação: %v\n", movement.ID, err)
+	}
+	return record, nil
+}
+
+// ReconcileLedger resolve os lançamentos parados: os que chegaram ao registro de
+// estoque são marcados como aplicados, com o saldo atual, e os demais são marcados
+// como descartados, como se a operação tivesse falhado. O descarte só vale se o
+// lançamento continuar no status lido, e uma aplicação atrasada que ainda alcance o
+// registro grava o lançamento inteiro como aplicado ao terminar.
+func (s *InventoryServiceImpl) ReconcileLedger() error {
+	pending, err := s.movementRepo.ListUnapplied(time.Now().Add(-pendingMovementTimeout))
+	if err != nil {
+		return err
+	}
+
+	for _, movement := range pending {
+		record, err := s.inventoryRepo.Find(movement.ProductID, movement.SKU, movement.LocationID)
+		if err != nil {
+			return err
+		}
+		if record != nil && record.HasApplied(movement.ID) {
+			movement.BalanceAfter = record.OnHand
+			err = s.movementRepo.MarkApplied(movement)
+		} else {
+			err = s.movementRepo.Discard(movement.ID, movement.Status)
+		}
+		if err != nil {
+			return fmt.Errorf("erro ao conciliar o lançamento %s: %w", movement.ID, err)
+		}
+	}
+
+	if len(pending) > 0 {
+		log.Printf("%d lançamentos pendentes do livro de estoque conciliados\n", len(pending))
+	}
+	return nil
+}
+
+// RunLedgerReconciliation concilia os lançamentos pendentes periodicamente
+func (s *InventoryServiceImpl) RunLedgerReconciliation(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.ReconcileLedger(); err != nil {
+			log.Printf("Erro ao conciliar o livro de estoque: %v\n", err)
+		}
+	}
+}
+
+// ListMovements consulta o livro, do lançamento mais recente para o mais antigo
+func (s *InventoryServiceImpl) ListMovements(filter model.MovementFilter, page, pageSize int) ([]*model.StockMovement, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 200 {
+		pageSize = 50
+	}
+	return s.movementRepo.List(filter, int64((page-1)*pageSize), int64(pageSize))
+}
+
+// GetLedgerBalance confere o estoque físico do registro com a soma do livro
+func (s *InventoryServiceImpl) GetLedgerBalance(productID, sku, locationID string) (*model.LedgerBalance, error) {
+	if _, err := s.getLocation(locationID); err != nil {
+		return nil, err
+	}
+
+	record, err := s.inventoryRepo.Find(productID, sku, locationID)
+	if err != nil {
+		return nil, err
+	}
+	balance, count, err := s.movementRepo.Balance(productID, sku, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.LedgerBalance{
+		ProductID:     productID,
+		SKU:           sku,
+		LocationID:    locationID,
+		LedgerBalance: balance,
+		Movements:     count,
+	}
+	if record != nil {
+		result.OnHand = record.OnHand
+	}
+	result.Difference = result.OnHand - result.LedgerBalance
+	return result, nil
+}
+
+// GetShrinkageReport consolida as quebras declaradas e as diferenças das contagens
+// do período. A taxa de perda é a perda líquida sobre as unidades vendidas.
+func (s *InventoryServiceImpl) GetShrinkageReport(locationID string, from, to time.Time) (*model.ShrinkageReport, error) {
+	if locationID != "" {
+		if _, err := s.getLocation(locationID); err != nil {
+			return nil, err
+		}
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -30)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: o início do período deve ser anterior ao fim", ErrInvalidMovement)
+	}
+
+	lines, err := s.movementRepo.Shrinkage(locationID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.ShrinkageReport{LocationID: locationID, From: from, To: to, Lines: make([]*model.ShrinkageLine, 0)}
+	for _, line := range lines {
+		report.Sold += line.Sold
+		// Itens só com vendas entram na taxa geral, mas não são listados
+		if line.Declared == 0 && line.CountLosses == 0 && line.CountGains == 0 {
+			continue
+		}
+		report.Declared += line.Declared
+		report.CountLosses += line.CountLosses
+		report.CountGains += line.CountGains
+		report.NetLoss += line.NetLoss
+		line.LossRate = lossRate(line.NetLoss, line.Sold)
+		report.Lines = append(report.Lines, line)
+	}
+	report.LossRate = lossRate(report.NetLoss, report.Sold)
+
+	return report, nil
+}
+
+func lossRate(netLoss, sold int) float64 {
+	if sold <= 0 {
+		return 0
+	}
+	return math.Round(float64(netLoss)/float64(sold)*10000) / 10000
+}
+
+// OpenLedger lança o saldo de abertura dos registros que ainda não têm movimentos,
+// para que o estoque existente antes do livro também seja a soma dos lançamentos.
+// Cada registro é reivindicado com um update condicional que copia o saldo, o que
+// serve de trava contra outras instâncias e contra movimentos simultâneos. O
+// lançamento tem ID fixo por registro, então uma abertura interrompida é retomada
+// na próxima inicialização sem duplicar saldos.
+func (s *InventoryServiceImpl) OpenLedger() error {
+	claimed, err := s.inventoryRepo.ListOpening()
+	if err != nil {
+		return err
+	}
+	for _, record := range claimed {
+		if err := s.postOpening(record); err != nil {
+			return err
+		}
+	}
+
+	keys, err := s.movementRepo.LedgerKeys()
+	if err != nil {
+		return err
+	}
+	records, err := s.inventoryRepo.ListAll()
+	if err != nil {
+		return err
+	}
+
+	opened := len(claimed)
+	for _, record := range records {
+		if record.OnHand == 0 || record.AppliedMovements != nil || keys[[3]string{record.ProductID, record.SKU, record.LocationID}] {
+			continue
+		}
+		record, err := s.inventoryRepo.ClaimOpening(record.ID, openingMovementID(record))
+		if err != nil {
+			return err
+		}
+		// Outra instância ou um movimento chegou antes ao registro
+		if record == nil {
+			continue
+		}
+		if err := s.postOpening(record); err != nil {
+			return err
+		}
+		opened++
+	}
+
+	if opened > 0 {
+		log.Printf("Saldo de abertura lançado para %d registros de estoque\n", opened)
+	}
+	return nil
+}
+
+func openingMovementID(record *model.InventoryRecord) string {
+	return "opening:" + record.ID
+}
+
+// Grava o lançamento de abertura com o saldo reivindicado e encerra a abertura
+func (s *InventoryServiceImpl) postOpening(record *model.InventoryRecord) error {
+	if record.OpeningBalance == nil {
+		return nil
+	}
+	if *record.OpeningBalance != 0 {
+		err := s.movementRepo.Insert(&model.StockMovement{
+			ID:            openingMovementID(record),
+			ProductID:     record.ProductID,
+			SKU:           record.SKU,
+			LocationID:    record.LocationID,
+			Type:          model.AdjustmentMovement,
+			Quantity:      *record.OpeningBalance,
+			BalanceAfter:  *record.OpeningBalance,
+			Reason:        "Saldo de abertura do livro de movimentos",
+			ReferenceType: model.OpeningReference,
+			Status:        model.MovementApplied,
+			CreatedAt:     time.Now(),
+		})
+		if err != nil && !errors.Is(err, repository.ErrDuplicateMovement) {
+			return err
+		}
+	}
+	return s.inventoryRepo.ClearOpening(record.ID)
+}
+
+// Reserve separa unidades disponíveis na localização para um pedido
+func (s *InventoryServiceImpl) Reserve(productID, sku, locationID string, quantity int) (*model.InventoryRecord, error) {
+	if quantity <= 0 {
+		return nil, errors.New("a quantidade deve ser maior que zero")
+	}
+	return s.inventoryRepo.Adjust(productID, sku, locationID, 0, quantity, 0)
+}
+
+// Release devolve unidades reservadas ao disponível
+func (s *InventoryServiceImpl) Release(productID, sku, locationID string, quantity int) (*model.InventoryRecord, error) {
+	if quantity <= 0 {
+		return nil, errors.New("a quantidade deve ser maior que zero")
+	}
+	return s.inventoryRepo.Adjust(productID, sku, locationID, 0, -quantity, 0)
+}
+
+// Commit baixa do estoque físico unidades que estavam reservadas, lançando a venda no livro
+func (s *InventoryServiceImpl) Commit(productID, sku, locationID string, quantity int, reference, userID string) (*model.InventoryRecord, error) {
+	if quantity <= 0 {
+		return nil, errors.New("a quantidade deve ser maior que zero")
+	}
+
+	movement := &model.StockMovement{
+		ProductID:  productID,
+		SKU:        sku,
+		LocationID: locationID,
+		Type:       model.SaleMovement,
+		Quantity:   -quantity,
+		UserID:     userID,
+		Reference:  reference,
+	}
+	if reference != "" {
+		movement.ReferenceType = model.OrderReference
+	}
+	return s.apply(movement, -quantity, 0)
+}
+
+// Allocate escolhe a localização que atenderá o pedido: entre as localizações ativas
+// com estoque disponível para todos os itens, vence a mais próxima do destino;
+// em caso de empate na distância, a que tiver mais unidades disponíveis.
+// Sem coordenadas, a região é obrigatória e vale apenas o estoque disponível.
+func (s *InventoryServiceImpl) Allocate(items []model.AllocationItem, origin *model.GeoPoint, region string) (*model.Allocation, error) {
+	if len(items) == 0 {
+		return nil, errors.New("informe ao menos um item para alocar")
+	}
+	if origin == nil && region == "" {
+		return nil, ErrAllocationOrigin
+	}
+
+	// Quantidade exigida por produto/SKU
+	required := make(map[string]int)
+	productIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, errors.New("a quantidade deve ser maior que zero")
+		}
+		productIDs = append(productIDs, item.ProductID)
+		required[stockKey(item.ProductID, item.SKU)] += item.Quantity
+	}
+
+	locations, err := s.locationRepo.ListActive(region)
+	if err != nil {
+		return nil, err
+	}
+
+	locationIDs := make([]string, 0, len(locations))
+	for _, location := range locations {
+		locationIDs = append(locationIDs, location.ID)
+	}
+
+	records, err := s.inventoryRepo.ListByProductsAndLocations(productIDs, locationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	available := make(map[string]map[string]int)
+	for _, record := range records {
+		if available[record.LocationID] == nil {
+			available[record.LocationID] = make(map[string]int)
+		}
+		available[record.LocationID][stockKey(record.ProductID, record.SKU)] = record.Available
+	}
+
+	type candidate struct {
+		location *model.Location
+		distance float64
+		units    int
+	}
+
+	var candidates []candidate
+	for _, location := range locations {
+		stock := available[location.ID]
+		fulfills := true
+		units := 0
+		for key, quantity := range required {
+			if stock[key] < quantity {
+				fulfills = false
+				break
+			}
+			units += stock[key]
+		}
+		if !fulfills {
+			continue
+		}
+
+		c := candidate{location: location, units: units}
+		if origin != nil {
+			c.distance = haversineKm(origin.Latitude, origin.Longitude, location.Latitude, location.Longitude)
+		}
+		candidates = append(candidates, c)
+	}
+
+	if len(candidates) == 0 {
+		return nil, ErrNoFulfillingLocation
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].units > candidates[j].units
+	})
+
+	best := candidates[0]
+	allocation := &model.Allocation{Location: best.location, Items: items}
+	if origin != nil {
+		distance := math.Round(best.distance*100) / 100
+		allocation.DistanceKm = &distance
+	}
+	return allocation, nil
+}
+
+func (s *InventoryServiceImpl) getLocation(locationID string) (*model.Location, error) {
+	location, err := s.locationRepo.FindByID(locationID)
+	if err != nil {
+		return nil, err
+	}
+	if location == nil {
+		return nil, ErrLocationNotFound
+	}
+	return location, nil
+}
+
+func stockKey(productID, sku string) string {
+	return productID + "/" + sku
+}
+
+// Distância em quilômetros entre duas coordenadas pela fórmula de haversine
+func haversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371.0
+
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return earthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
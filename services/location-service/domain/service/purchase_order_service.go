@@ -0,0 +1,410 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"Varejo-Golang-Microservices/services/location-service/domain/repository"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPurchaseOrderNotFound = errors.New("pedido de compra não encontrado")
+	ErrInvalidPurchaseOrder  = errors.New("pedido de compra inválido")
+	ErrReceiptNotFound       = errors.New("recebimento não encontrado no pedido de compra")
+	ErrReceiptPending        = errors.New("o pedido tem recebimentos ainda não lançados no estoque")
+)
+
+type PurchaseOrderService interface {
+	CreatePurchaseOrder(supplierID, locationID string, lines []model.PurchaseOrderLine, notes, userID string) (*model.PurchaseOrder, error)
+	GetPurchaseOrder(id string) (*model.PurchaseOrder, error)
+	ListPurchaseOrders(filter model.PurchaseOrderFilter) ([]*model.PurchaseOrder, error)
+	UpdateDraft(id string, lines []model.PurchaseOrderLine, notes string) (*model.PurchaseOrder, error)
+	SendPurchaseOrder(id, userID string) (*model.PurchaseOrder, error)
+	ReceivePurchaseOrder(id string, lines []model.PurchaseReceiptLine, notes, userID string) (*model.PurchaseOrder, error)
+	PostReceipt(id, receiptID string) (*model.PurchaseOrder, error)
+	ClosePurchaseOrder(id, notes, userID string) (*model.PurchaseOrder, error)
+	CancelPurchaseOrder(id, notes, userID string) (*model.PurchaseOrder, error)
+}
+
+type PurchaseOrderServiceImpl struct {
+	purchaseOrderRepo *repository.MongoPurchaseOrderRepository
+	supplierRepo      *repository.MongoSupplierRepository
+	locationRepo      *repository.MongoLocationRepository
+	inventoryService  InventoryService
+}
+
+func NewPurchaseOrderService(purchaseOrderRepo *repository.MongoPurchaseOrderRepository, supplierRepo *repository.MongoSupplierRepository, locationRepo *repository.MongoLocationRepository, inventoryService InventoryService) PurchaseOrderService {
+	return &PurchaseOrderServiceImpl{
+		purchaseOrderRepo: purchaseOrderRepo,
+		supplierRepo:      supplierRepo,
+		locationRepo:      locationRepo,
+		inventoryService:  inventoryService,
+	}
+}
+
+// CreatePurchaseOrder abre o pedido em rascunho para um fornecedor ativo, a ser
+// recebido em uma localização ativa
+func (s *PurchaseOrderServiceImpl) CreatePurchaseOrder(supplierID, locationID string, lines []model.PurchaseOrderLine, notes, userID string) (*model.PurchaseOrder, error) {
+	supplier, err := s.activeSupplier(supplierID)
+	if err != nil {
+		return nil, err
+	}
+	location, err := s.locationRepo.FindByID(locationID)
+	if err != nil {
+		return nil, err
+	}
+	if location == nil {
+		return nil, ErrLocationNotFound
+	}
+	if location.Status != model.Active {
+		return nil, fmt.Errorf("%w: a localização %s não está ativa", ErrInvalidPurchaseOrder, locationID)
+	}
+
+	orderLines, err := purchaseLines(supplier, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	order := &model.PurchaseOrder{
+		ID:           uuid.New().String(),
+		SupplierID:   supplier.ID,
+		SupplierName: supplier.LegalName,
+		Location:     *location,
+		Status:       model.PurchaseDraft,
+		Lines:        orderLines,
+		Total:        purchaseTotal(orderLines),
+		Notes:        notes,
+		Receipts:     make([]model.PurchaseReceipt, 0),
+		History:      []model.PurchaseOrderEvent{{Status: model.PurchaseDraft, UserID: userID, Notes: notes, At: now}},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.purchaseOrderRepo.Save(order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *PurchaseOrderServiceImpl) GetPurchaseOrder(id string) (*model.PurchaseOrder, error) {
+	order, err := s.purchaseOrderRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrPurchaseOrderNotFound
+	}
+	return order, nil
+}
+
+func (s *PurchaseOrderServiceImpl) ListPurchaseOrders(filter model.PurchaseOrderFilter) ([]*model.PurchaseOrder, error) {
+	return s.purchaseOrderRepo.List(filter)
+}
+
+// UpdateDraft substitui os itens e as observações de um pedido ainda em rascunho
+func (s *PurchaseOrderServiceImpl) UpdateDraft(id string, lines []model.PurchaseOrderLine, notes string) (*model.PurchaseOrder, error) {
+	order, err := s.orderIn(id, model.PurchaseDraft)
+	if err != nil {
+		return nil, err
+	}
+	supplier, err := s.activeSupplier(order.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	orderLines, err := purchaseLines(supplier, lines)
+	if err != nil {
+		return nil, err
+	}
+	order.Lines = orderLines
+	order.Total = purchaseTotal(orderLines)
+	order.Notes = notes
+	if err := s.purchaseOrderRepo.Update(order, nil); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// SendPurchaseOrder marca o pedido como enviado ao fornecedor e calcula a previsão
+// de entrega pelo maior prazo entre os itens
+func (s *PurchaseOrderServiceImpl) SendPurchaseOrder(id, userID string) (*model.PurchaseOrder, error) {
+	order, err := s.orderIn(id, model.PurchaseDraft)
+	if err != nil {
+		return nil, err
+	}
+	supplier, err := s.activeSupplier(order.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	leadTime := 0
+	for _, line := range order.Lines {
+		if days := supplier.LeadTimeFor(line.ProductID, line.SKU); days > leadTime {
+			leadTime = days
+		}
+	}
+	now := time.Now()
+	expectedAt := now.AddDate(0, 0, leadTime)
+	order.ExpectedAt = &expectedAt
+
+	event := &model.PurchaseOrderEvent{Status: model.PurchaseSent, UserID: userID, At: now}
+	if err := s.purchaseOrderRepo.Update(order, event); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// ReceivePurchaseOrder registra uma entrega e dá entrada das quantidades no estoque da
+// localização do pedido. Itens sem custo informado são faturados pelo custo do pedido;
+// a diferença de custo e as quantidades acima do pedido ficam registradas como
+// divergência. O pedido fica recebido quando todos os itens forem entregues.
+//
+// O recebimento é gravado pendente e as entradas têm ID fixo por linha; se o
+// lançamento for interrompido, PostReceipt o completa sem repetir as entradas feitas.
+func (s *PurchaseOrderServiceImpl) ReceivePurchaseOrder(id string, lines []model.PurchaseReceiptLine, notes, userID string) (*model.PurchaseOrder, error) {
+	order, err := s.orderIn(id, model.PurchaseSent, model.PurchasePartiallyReceived)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%w: informe ao menos um item recebido", ErrInvalidPurchaseOrder)
+	}
+
+	index := make(map[string]int, len(order.Lines))
+	for i, line := range order.Lines {
+		index[stockKey(line.ProductID, line.SKU)] = i
+	}
+
+	receipt := model.PurchaseReceipt{
+		ID:         uuid.New().String(),
+		Notes:      notes,
+		UserID:     userID,
+		ReceivedAt: time.Now(),
+		Pending:    true,
+	}
+	for _, line := range lines {
+		key := stockKey(line.ProductID, line.SKU)
+		i, ok := index[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: o item %s não faz parte do pedido", ErrInvalidPurchaseOrder, key)
+		}
+		if line.Quantity <= 0 || line.UnitCost < 0 {
+			return nil, fmt.Errorf("%w: o item %s precisa de quantidade maior que zero e custo não negativo", ErrInvalidPurchaseOrder, key)
+		}
+
+		receipt.Lines = append(receipt.Lines, receiveLine(&order.Lines[i], line))
+	}
+
+	status := receiptStatus(order.Lines)
+	order.Receipts = append(order.Receipts, receipt)
+
+	// O pedido é gravado antes da entrada no estoque para que a mesma entrega não seja
+	// lançada duas vezes por requisições simultâneas
+	event := &model.PurchaseOrderEvent{Status: status, UserID: userID, Notes: notes, At: receipt.ReceivedAt}
+	if err := s.purchaseOrderRepo.Update(order, event); err != nil {
+		return nil, err
+	}
+
+	return s.postReceipt(order, &order.Receipts[len(order.Receipts)-1])
+}
+
+// PostReceipt completa o lançamento no estoque de um recebimento interrompido. Um
+// recebimento já lançado não é alterado.
+func (s *PurchaseOrderServiceImpl) PostReceipt(id, receiptID string) (*model.PurchaseOrder, error) {
+	order, err := s.GetPurchaseOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	for i := range order.Receipts {
+		if order.Receipts[i].ID != receiptID {
+			continue
+		}
+		if !order.Receipts[i].Pending {
+			return order, nil
+		}
+		return s.postReceipt(order, &order.Receipts[i])
+	}
+	return nil, ErrReceiptNotFound
+}
+
+// Dá entrada das linhas do recebimento, com ID fixo por linha, e marca o recebimento
+// como lançado
+func (s *PurchaseOrderServiceImpl) postReceipt(order *model.PurchaseOrder, receipt *model.PurchaseReceipt) (*model.PurchaseOrder, error) {
+	for i, line := range receipt.Lines {
+		_, err := s.inventoryService.RecordMovement(&model.StockMovement{
+			ID:            fmt.Sprintf("receipt:%s:%d", receipt.ID, i),
+			ProductID:     line.ProductID,
+			SKU:           line.SKU,
+			LocationID:    order.Location.ID,
+			Type:          model.ReceiptMovement,
+			Quantity:      line.Quantity,
+			Reason:        "Recebimento do pedido de compra de " + order.SupplierName,
+			UserID:        receipt.UserID,
+			ReferenceType: model.PurchaseReference,
+			Reference:     order.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao dar entrada de %s no estoque; repita o lançamento do recebimento %s: %w", stockKey(line.ProductID, line.SKU), receipt.ID, err)
+		}
+	}
+
+	if err := s.purchaseOrderRepo.MarkReceiptPosted(order.ID, receipt.ID); err != nil {
+		return nil, err
+	}
+	receipt.Pending = false
+	order.Version++
+	return order, nil
+}
+
+// Soma uma linha do recebimento ao item do pedido. Sem custo informado, vale o custo
+// do pedido; a diferença de custo e a quantidade acima do pedido ficam como
+// divergência. Retorna a linha com o custo e a divergência de preço calculados.
+func receiveLine(orderLine *model.PurchaseOrderLine, line model.PurchaseReceiptLine) model.PurchaseReceiptLine {
+	if line.UnitCost == 0 {
+		line.UnitCost = orderLine.UnitCost
+	}
+	line.PriceVariance = roundCost((line.UnitCost - orderLine.UnitCost) * float64(line.Quantity))
+
+	orderLine.Received += line.Quantity
+	orderLine.PriceVariance = roundCost(orderLine.PriceVariance + line.PriceVariance)
+	if orderLine.Received > orderLine.Quantity {
+		orderLine.QuantityVariance = orderLine.Received - orderLine.Quantity
+	}
+	return line
+}
+
+// O pedido fica recebido quando todos os itens forem entregues
+func receiptStatus(lines []model.PurchaseOrderLine) model.PurchaseOrderStatus {
+	for _, line := range lines {
+		if line.Received < line.Quantity {
+			return model.PurchasePartiallyReceived
+		}
+	}
+	return model.PurchaseReceived
+}
+
+// ClosePurchaseOrder encerra um pedido recebido, total ou parcialmente, depois que
+// todos os recebimentos foram lançados. O que não foi entregue fica registrado como
+// divergência negativa de quantidade.
+func (s *PurchaseOrderServiceImpl) ClosePurchaseOrder(id, notes, userID string) (*model.PurchaseOrder, error) {
+	order, err := s.orderIn(id, model.PurchasePartiallyReceived, model.PurchaseReceived)
+	if err != nil {
+		return nil, err
+	}
+	// Um recebimento pendente ainda não entrou no estoque; encerrar o pedido o deixaria
+	// registrado como entregue sem a entrada correspondente
+	for _, receipt := range order.Receipts {
+		if receipt.Pending {
+			return nil, fmt.Errorf("%w: lance o recebimento %s antes de encerrar", ErrReceiptPending, receipt.ID)
+		}
+	}
+
+	for i := range order.Lines {
+		order.Lines[i].QuantityVariance = order.Lines[i].Received - order.Lines[i].Quantity
+	}
+
+	event := &model.PurchaseOrderEvent{Status: model.PurchaseClosed, UserID: userID, Notes: notes, At: time.Now()}
+	if err := s.purchaseOrderRepo.Update(order, event); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// CancelPurchaseOrder cancela pedidos ainda sem recebimentos
+func (s *PurchaseOrderServiceImpl) CancelPurchaseOrder(id, notes, userID string) (*model.PurchaseOrder, error) {
+	order, err := s.orderIn(id, model.PurchaseDraft, model.PurchaseSent)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &model.PurchaseOrderEvent{Status: model.PurchaseCanceled, UserID: userID, Notes: notes, At: time.Now()}
+	if err := s.purchaseOrderRepo.Update(order, event); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// Busca o pedido e confirma que ele está em um dos status aceitos pela operação
+func (s *PurchaseOrderServiceImpl) orderIn(id string, statuses ...model.PurchaseOrderStatus) (*model.PurchaseOrder, error) {
+	order, err := s.GetPurchaseOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range statuses {
+		if order.Status == status {
+			return order, nil
+		}
+	}
+	return nil, repository.ErrPurchaseOrderChanged
+}
+
+func (s *PurchaseOrderServiceImpl) activeSupplier(id string) (*model.Supplier, error) {
+	supplier, err := s.supplierRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, ErrSupplierNotFound
+	}
+	if supplier.Status != model.SupplierActive {
+		return nil, fmt.Errorf("%w: o fornecedor %s não está ativo", ErrInvalidPurchaseOrder, id)
+	}
+	return supplier, nil
+}
+
+// Monta os itens do pedido somando os repetidos. Sem custo informado, vale o da
+// tabela do fornecedor; itens fora da tabela precisam do custo.
+func purchaseLines(supplier *model.Supplier, lines []model.PurchaseOrderLine) ([]model.PurchaseOrderLine, error) {
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%w: informe ao menos um item", ErrInvalidPurchaseOrder)
+	}
+
+	orderLines := make([]model.PurchaseOrderLine, 0, len(lines))
+	index := make(map[string]int, len(lines))
+	for _, line := range lines {
+		key := stockKey(line.ProductID, line.SKU)
+		if line.ProductID == "" || line.Quantity <= 0 || line.UnitCost < 0 {
+			return nil, fmt.Errorf("%w: cada item precisa do ID do produto, de quantidade maior que zero e de custo não negativo", ErrInvalidPurchaseOrder)
+		}
+		if i, ok := index[key]; ok {
+			orderLines[i].Quantity += line.Quantity
+			continue
+		}
+
+		price := supplier.FindPrice(line.ProductID, line.SKU)
+		unitCost := line.UnitCost
+		if unitCost == 0 {
+			if price == nil {
+				return nil, fmt.Errorf("%w: o item %s não está na tabela de preços do fornecedor; informe o custo", ErrInvalidPurchaseOrder, key)
+			}
+			unitCost = price.UnitCost
+		}
+
+		index[key] = len(orderLines)
+		orderLines = append(orderLines, model.PurchaseOrderLine{ProductID: line.ProductID, SKU: line.SKU, Quantity: line.Quantity, UnitCost: unitCost})
+	}
+
+	for _, line := range orderLines {
+		price := supplier.FindPrice(line.ProductID, line.SKU)
+		if price != nil && line.Quantity < price.MinQuantity {
+			return nil, fmt.Errorf("%w: o fornecedor exige ao menos %d unidades de %s", ErrInvalidPurchaseOrder, price.MinQuantity, stockKey(line.ProductID, line.SKU))
+		}
+	}
+	return orderLines, nil
+}
+
+func purchaseTotal(lines []model.PurchaseOrderLine) float64 {
+	total := 0.0
+	for _, line := range lines {
+		total += float64(line.Quantity) * line.UnitCost
+	}
+	return roundCost(total)
+}
+
+func roundCost(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"testing"
+)
+
+func TestReceiveLine(t *testing.T) {
+	tests := []struct {
+		name                  string
+		orderLine             model.PurchaseOrderLine
+		received              model.PurchaseReceiptLine
+		wantUnitCost          float64
+		wantPriceVariance     float64
+		wantReceived          int
+		wantQuantityVariance  int
+		wantLinePriceVariance float64
+	}{
+		{"entrega parcial sem custo usa o custo do pedido",
+			model.PurchaseOrderLine{Quantity: 10, UnitCost: 5},
+			model.PurchaseReceiptLine{Quantity: 4},
+			5, 0, 4, 0, 0},
+		{"custo acima do pedido registra a diferença",
+			model.PurchaseOrderLine{Quantity: 10, UnitCost: 5},
+			model.PurchaseReceiptLine{Quantity: 10, UnitCost: 5.5},
+			5.5, 5, 10, 0, 5},
+		{"custo abaixo do pedido registra diferença negativa",
+			model.PurchaseOrderLine{Quantity: 10, UnitCost: 5},
+			model.PurchaseReceiptLine{Quantity: 3, UnitCost: 4.9},
+			4.9, -0.3, 3, 0, -0.3},
+		{"segunda entrega soma ao recebido e à divergência",
+			model.PurchaseOrderLine{Quantity: 10, UnitCost: 5, Received: 6, PriceVariance: 1.2},
+			model.PurchaseReceiptLine{Quantity: 4, UnitCost: 5.1},
+			5.1, 0.4, 10, 0, 1.6},
+		{"sobra vira divergência de quantidade",
+			model.PurchaseOrderLine{Quantity: 10, UnitCost: 5, Received: 8},
+			model.PurchaseReceiptLine{Quantity: 4},
+			5, 0, 12, 2, 0},
+		{"centavos arredondados",
+			model.PurchaseOrderLine{Quantity: 3, UnitCost: 0.1},
+			model.PurchaseReceiptLine{Quantity: 3, UnitCost: 0.2},
+			0.2, 0.3, 3, 0, 0.3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderLine := tt.orderLine
+			line := receiveLine(&orderLine, tt.received)
+			if line.UnitCost != tt.wantUnitCost {
+				t.Errorf("custo = %.2f, esperado %.2f", line.UnitCost, tt.wantUnitCost)
+			}
+			if line.PriceVariance != tt.wantPriceVariance {
+				t.Errorf("divergência de preço da entrega = %.2f, esperada %.2f", line.PriceVariance, tt.wantPriceVariance)
+			}
+			if orderLine.Received != tt.wantReceived {
+				t.Errorf("recebido = %d, esperado %d", orderLine.Received, tt.wantReceived)
+			}
+			if orderLine.QuantityVariance != tt.wantQuantityVariance {
+				t.Errorf("divergência de quantidade = %d, esperada %d", orderLine.QuantityVariance, tt.wantQuantityVariance)
+			}
+			if orderLine.PriceVariance != tt.wantLinePriceVariance {
+				t.Errorf("divergência de preço do item = %.2f, esperada %.2f", orderLine.PriceVariance, tt.wantLinePriceVariance)
+			}
+		})
+	}
+}
+
+func TestReceiptStatus(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []model.PurchaseOrderLine
+		want  model.PurchaseOrderStatus
+	}{
+		{"todos os itens entregues", []model.PurchaseOrderLine{{Quantity: 10, Received: 10}, {Quantity: 5, Received: 5}}, model.PurchaseReceived},
+		{"sobra conta como entregue", []model.PurchaseOrderLine{{Quantity: 10, Received: 12}}, model.PurchaseReceived},
+		{"um item com falta", []model.PurchaseOrderLine{{Quantity: 10, Received: 10}, {Quantity: 5, Received: 4}}, model.PurchasePartiallyReceived},
+		{"item ainda não entregue", []model.PurchaseOrderLine{{Quantity: 10, Received: 10}, {Quantity: 5}}, model.PurchasePartiallyReceived},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := receiptStatus(tt.lines); got != tt.want {
+				t.Errorf("receiptStatus = %s, esperado %s", got, tt.want)
+			}
+		})
+	}
+}
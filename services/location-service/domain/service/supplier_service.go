@@ -0,0 +1,158 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"Varejo-Golang-Microservices/services/location-service/domain/repository"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSupplierNotFound = errors.New("fornecedor não encontrado")
+	ErrInvalidSupplier  = errors.New("fornecedor inválido")
+)
+
+type SupplierService interface {
+	CreateSupplier(supplier *model.Supplier) (*model.Supplier, error)
+	GetSupplier(id string) (*model.Supplier, error)
+	ListSuppliers(status model.SupplierStatus, productID string) ([]*model.Supplier, error)
+	UpdateSupplier(id string, supplier *model.Supplier) (*model.Supplier, error)
+	SetPrice(id string, price model.SupplierPrice) (*model.Supplier, error)
+	RemovePrice(id, productID, sku string) (*model.Supplier, error)
+}
+
+type SupplierServiceImpl struct {
+	supplierRepo *repository.MongoSupplierRepository
+}
+
+func NewSupplierService(supplierRepo *repository.MongoSupplierRepository) SupplierService {
+	return &SupplierServiceImpl{
+		supplierRepo: supplierRepo,
+	}
+}
+
+// CreateSupplier cadastra o fornecedor ativo, com a tabela de preços vazia; os
+// preços são mantidos item a item
+func (s *SupplierServiceImpl) CreateSupplier(supplier *model.Supplier) (*model.Supplier, error) {
+	if err := validateSupplier(supplier); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	supplier.ID = uuid.New().String()
+	supplier.Prices = make([]model.SupplierPrice, 0)
+	supplier.CreatedAt = now
+	supplier.UpdatedAt = now
+	if supplier.Status == "" {
+		supplier.Status = model.SupplierActive
+	}
+	if err := s.supplierRepo.Save(supplier); err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
+
+func (s *SupplierServiceImpl) GetSupplier(id string) (*model.Supplier, error) {
+	supplier, err := s.supplierRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, ErrSupplierNotFound
+	}
+	return supplier, nil
+}
+
+func (s *SupplierServiceImpl) ListSuppliers(status model.SupplierStatus, productID string) ([]*model.Supplier, error) {
+	return s.supplierRepo.List(status, productID)
+}
+
+// UpdateSupplier altera os dados cadastrais, mantendo a tabela de preços
+func (s *SupplierServiceImpl) UpdateSupplier(id string, supplier *model.Supplier) (*model.Supplier, error) {
+	existing, err := s.GetSupplier(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSupplier(supplier); err != nil {
+		return nil, err
+	}
+
+	existing.CNPJ = supplier.CNPJ
+	existing.LegalName = supplier.LegalName
+	existing.TradeName = supplier.TradeName
+	existing.Contacts = supplier.Contacts
+	existing.LeadTimeDays = supplier.LeadTimeDays
+	if supplier.Status != "" {
+		existing.Status = supplier.Status
+	}
+	if err := s.supplierRepo.Update(existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// SetPrice inclui ou altera o preço de um item na tabela do fornecedor
+func (s *SupplierServiceImpl) SetPrice(id string, price model.SupplierPrice) (*model.Supplier, error) {
+	if price.ProductID == "" {
+		return nil, fmt.Errorf("%w: o ID do produto é obrigatório", ErrInvalidSupplier)
+	}
+	if price.UnitCost <= 0 {
+		return nil, fmt.Errorf("%w: o custo unitário deve ser maior que zero", ErrInvalidSupplier)
+	}
+	if price.MinQuantity < 0 || price.LeadTimeDays < 0 {
+		return nil, fmt.Errorf("%w: quantidade mínima e prazo de entrega não podem ser negativos", ErrInvalidSupplier)
+	}
+	if _, err := s.GetSupplier(id); err != nil {
+		return nil, err
+	}
+
+	if err := s.supplierRepo.SetPrice(id, price); err != nil {
+		return nil, err
+	}
+	return s.GetSupplier(id)
+}
+
+func (s *SupplierServiceImpl) RemovePrice(id, productID, sku string) (*model.Supplier, error) {
+	if _, err := s.GetSupplier(id); err != nil {
+		return nil, err
+	}
+	if err := s.supplierRepo.RemovePrice(id, productID, sku); err != nil {
+		return nil, err
+	}
+	return s.GetSupplier(id)
+}
+
+// Valida e normaliza o CNPJ e os campos obrigatórios do cadastro
+func validateSupplier(supplier *model.Supplier) error {
+	cnpj, err := model.NormalizeCNPJ(supplier.CNPJ)
+	if err != nil {
+		return err
+	}
+	supplier.CNPJ = cnpj
+
+	supplier.LegalName = strings.TrimSpace(supplier.LegalName)
+	if supplier.LegalName == "" {
+		return fmt.Errorf("%w: a razão social é obrigatória", ErrInvalidSupplier)
+	}
+	if supplier.LeadTimeDays < 0 {
+		return fmt.Errorf("%w: o prazo de entrega não pode ser negativo", ErrInvalidSupplier)
+	}
+	switch supplier.Status {
+	case "", model.SupplierActive, model.SupplierInactive:
+	default:
+		return fmt.Errorf("%w: status %s desconhecido", ErrInvalidSupplier, supplier.Status)
+	}
+	for _, contact := range supplier.Contacts {
+		if strings.TrimSpace(contact.Name) == "" {
+			return fmt.Errorf("%w: todo contato precisa de nome", ErrInvalidSupplier)
+		}
+	}
+	if supplier.Contacts == nil {
+		supplier.Contacts = make([]model.SupplierContact, 0)
+	}
+	return nil
+}
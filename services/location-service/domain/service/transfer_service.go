@@ -0,0 +1,419 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"Varejo-Golang-Microservices/services/location-service/domain/repository"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTransferNotFound = errors.New("transferência não encontrada")
+	ErrInvalidTransfer  = errors.New("transferência inválida")
+)
+
+type TransferService interface {
+	CreateTransfer(sourceID, destinationID string, items []model.AllocationItem, notes, userID string) (*model.Transfer, error)
+	GetTransfer(id string) (*model.Transfer, error)
+	ListTransfers(locationID string, status model.TransferStatus) ([]*model.Transfer, error)
+	PickTransfer(id string, picked []model.AllocationItem, userID string) (*model.Transfer, error)
+	DispatchTransfer(id, userID string) (*model.Transfer, error)
+	ReceiveTransfer(id string, received []model.AllocationItem, notes, userID string) (*model.Transfer, error)
+	CancelTransfer(id, userID string) (*model.Transfer, error)
+}
+
+type TransferServiceImpl struct {
+	transferRepo     *repository.MongoTransferRepository
+	locationRepo     *repository.MongoLocationRepository
+	inventoryService InventoryService
+}
+
+func NewTransferService(transferRepo *repository.MongoTransferRepository, locationRepo *repository.MongoLocationRepository, inventoryService InventoryService) TransferService {
+	return &TransferServiceImpl{
+		transferRepo:     transferRepo,
+		locationRepo:     locationRepo,
+		inventoryService: inventoryService,
+	}
+}
+
+// CreateTransfer abre a solicitação entre duas localizações ativas. Itens repetidos
+// são somados em uma única linha.
+func (s *TransferServiceImpl) CreateTransfer(sourceID, destinationID string, items []model.AllocationItem, notes, userID string) (*model.Transfer, error) {
+	if sourceID == destinationID {
+		return nil, fmt.Errorf("%w: origem e destino devem ser diferentes", ErrInvalidTransfer)
+	}
+	source, err := s.activeLocation(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	destination, err := s.activeLocation(destinationID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: informe ao menos um item", ErrInvalidTransfer)
+	}
+
+	lines := make([]model.TransferLine, 0, len(items))
+	index := make(map[string]int, len(items))
+	for _, item := range items {
+		if item.ProductID == "" || item.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: cada item precisa do ID do produto e de quantidade maior que zero", ErrInvalidTransfer)
+		}
+		key := stockKey(item.ProductID, item.SKU)
+		if i, ok := index[key]; ok {
+			lines[i].Requested += item.Quantity
+			continue
+		}
+		index[key] = len(lines)
+		lines = append(lines, model.TransferLine{ProductID: item.ProductID, SKU: item.SKU, Requested: item.Quantity})
+	}
+
+	now := time.Now()
+	transfer := &model.Transfer{
+		ID:          uuid.New().String(),
+		Source:      *source,
+		Destination: *destination,
+		Status:      model.TransferRequested,
+		Lines:       lines,
+		Notes:       notes,
+		History:     []model.TransferEvent{{Status: model.TransferRequested, UserID: userID, Notes: notes, At: now}},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.transferRepo.Save(transfer); err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+func (s *TransferServiceImpl) GetTransfer(id string) (*model.Transfer, error) {
+	transfer, err := s.transferRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if transfer == nil {
+		return nil, ErrTransferNotFound
+	}
+	return transfer, nil
+}
+
+func (s *TransferServiceImpl) ListTransfers(locationID string, status model.TransferStatus) ([]*model.Transfer, error) {
+	return s.transferRepo.List(locationID, status)
+}
+
+// PickTransfer registra as quantidades separadas e as reserva na origem. Sem itens
+// informados, tudo o que foi solicitado é separado.
+func (s *TransferServiceImpl) PickTransfer(id string, picked []model.AllocationItem, userID string) (*model.Transfer, error) {
+	transfer, err := s.GetTransfer(id)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.Status != model.TransferRequested {
+		return nil, repository.ErrTransferStateChanged
+	}
+
+	lines := transfer.Lines
+	if len(picked) == 0 {
+		for i := range lines {
+			lines[i].Picked = lines[i].Requested
+		}
+	} else {
+		quantities, err := lineQuantities(lines, picked)
+		if err != nil {
+			return nil, err
+		}
+		for i := range lines {
+			quantity := quantities[stockKey(lines[i].ProductID, lines[i].SKU)]
+			if quantity > lines[i].Requested {
+				return nil, fmt.Errorf("%w: separado mais que o solicitado de %s", ErrInvalidTransfer, stockKey(lines[i].ProductID, lines[i].SKU))
+			}
+			lines[i].Picked = quantity
+		}
+	}
+
+	total := 0
+	for _, line := range lines {
+		total += line.Picked
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("%w: nenhuma unidade separada", ErrInvalidTransfer)
+	}
+
+	// Reserva antes de mudar o status; se algo falhar, as reservas feitas são desfeitas
+	var reserved []model.TransferLine
+	for _, line := range lines {
+		if line.Picked == 0 {
+			continue
+		}
+		if _, err := s.inventoryService.Reserve(line.ProductID, line.SKU, transfer.Source.ID, line.Picked); err != nil {
+			s.releasePicked(transfer.Source.ID, reserved)
+			return nil, fmt.Errorf("erro ao reservar %s na origem: %w", stockKey(line.ProductID, line.SKU), err)
+		}
+		reserved = append(reserved, line)
+	}
+
+	event := model.TransferEvent{Status: model.TransferPicked, UserID: userID, At: time.Now()}
+	if err := s.transferRepo.Transition(id, []model.TransferStatus{model.TransferRequested}, event, lines); err != nil {
+		s.releasePicked(transfer.Source.ID, reserved)
+		return nil, err
+	}
+	return s.GetTransfer(id)
+}
+
+// DispatchTransfer baixa da origem as unidades separadas e as coloca em trânsito no
+// destino. Os lançamentos têm ID fixo por item; se o despacho for interrompido, a
+// transferência fica em DISPATCHING e o despacho pode ser repetido sem baixar de novo.
+func (s *TransferServiceImpl) DispatchTransfer(id, userID string) (*model.Transfer, error) {
+	transfer, err := s.GetTransfer(id)
+	if err != nil {
+		return nil, err
+	}
+
+	event := model.TransferEvent{Status: model.TransferDispatching, UserID: userID, At: time.Now()}
+	from := []model.TransferStatus{model.TransferPicked, model.TransferDispatching}
+	if err := s.transferRepo.Transition(id, from, event, nil); err != nil {
+		return nil, err
+	}
+
+	// As unidades estão reservadas para a transferência, então a baixa não falha por falta de estoque
+	for _, line := range transfer.Lines {
+		if line.Picked == 0 {
+			continue
+		}
+		key := stockKey(line.ProductID, line.SKU)
+		_, err := s.inventoryService.ApplyMovement(&model.StockMovement{
+			ID:            transferMovementID(id, "dispatch", key),
+			ProductID:     line.ProductID,
+			SKU:           line.SKU,
+			LocationID:    transfer.Source.ID,
+			Type:          model.TransferMovement,
+			Quantity:      -line.Picked,
+			Reason:        "Despacho para " + transfer.Destination.Description,
+			UserID:        userID,
+			ReferenceType: model.TransferReference,
+			Reference:     id,
+		}, -line.Picked, 0)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao baixar %s da origem: %w", key, err)
+		}
+
+		_, err = s.inventoryService.AdjustInventory(&model.StockMovement{
+			ID:         transferMovementID(id, "in-transit", key),
+			ProductID:  line.ProductID,
+			SKU:        line.SKU,
+			LocationID: transfer.Destination.ID,
+		}, line.Picked)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao registrar %s em trânsito no destino: %w", key, err)
+		}
+	}
+
+	event = model.TransferEvent{Status: model.TransferInTransit, UserID: userID, At: time.Now()}
+	if err := s.transferRepo.Transition(id, []model.TransferStatus{model.TransferDispatching}, event, nil); err != nil {
+		return nil, err
+	}
+	return s.GetTransfer(id)
+}
+
+// ReceiveTransfer dá entrada no destino das quantidades recebidas e retira as unidades
+// do trânsito. Sem itens informados, considera recebido tudo o que foi enviado; com
+// itens, os não informados são considerados não recebidos. Qualquer diferença deixa a
+// transferência em DISCREPANCY, com a diferença registrada em cada linha; a falta é
+// lançada como quebra no destino.
+//
+// As quantidades ficam gravadas na transferência em RECEIVING antes dos lançamentos,
+// que têm ID fixo por item. Um recebimento interrompido é repetido com as quantidades
+// gravadas, ignorando os itens informados na repetição.
+func (s *TransferServiceImpl) ReceiveTransfer(id string, received []model.AllocationItem, notes, userID string) (*model.Transfer, error) {
+	transfer, err := s.GetTransfer(id)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := transfer.Lines
+	switch transfer.Status {
+	case model.TransferInTransit:
+		var quantities map[string]int
+		if len(received) > 0 {
+			if quantities, err = lineQuantities(lines, received); err != nil {
+				return nil, err
+			}
+		}
+		setReceived(lines, quantities)
+
+		event := model.TransferEvent{Status: model.TransferReceiving, UserID: userID, Notes: notes, At: time.Now()}
+		if err := s.transferRepo.Transition(id, []model.TransferStatus{model.TransferInTransit}, event, lines); err != nil {
+			return nil, err
+		}
+	case model.TransferReceiving:
+	default:
+		return nil, repository.ErrTransferStateChanged
+	}
+
+	status := model.TransferReceived
+	for _, line := range lines {
+		if line.Discrepancy != 0 {
+			status = model.TransferDiscrepancy
+		}
+		if err := s.receiveLine(transfer, line, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	event := model.TransferEvent{Status: status, UserID: userID, At: time.Now()}
+	if err := s.transferRepo.Transition(id, []model.TransferStatus{model.TransferReceiving}, event, nil); err != nil {
+		return nil, err
+	}
+	return s.GetTransfer(id)
+}
+
+// Dá entrada no destino de tudo o que foi enviado, retirando do trânsito, e lança a
+// falta como quebra, para que o livro mostre a perda no transporte. Uma sobra entra
+// junto com o enviado.
+func (s *TransferServiceImpl) receiveLine(transfer *model.Transfer, line model.TransferLine, userID string) error {
+	key := stockKey(line.ProductID, line.SKU)
+	quantity, inTransitDelta, shrinkage := receiptQuantities(line)
+	if quantity == 0 {
+		return nil
+	}
+
+	_, err := s.inventoryService.ApplyMovement(&model.StockMovement{
+		ID:            transferMovementID(transfer.ID, "receive", key),
+		ProductID:     line.ProductID,
+		SKU:           line.SKU,
+		LocationID:    transfer.Destination.ID,
+		Type:          model.TransferMovement,
+		Quantity:      quantity,
+		Reason:        "Recebimento de " + transfer.Source.Description,
+		UserID:        userID,
+		ReferenceType: model.TransferReference,
+		Reference:     transfer.ID,
+	}, 0, inTransitDelta)
+	if err != nil {
+		return fmt.Errorf("erro ao dar entrada de %s no destino: %w", key, err)
+	}
+
+	if shrinkage == 0 {
+		return nil
+	}
+	_, err = s.inventoryService.RecordMovement(&model.StockMovement{
+		ID:            transferMovementID(transfer.ID, "shrinkage", key),
+		ProductID:     line.ProductID,
+		SKU:           line.SKU,
+		LocationID:    transfer.Destination.ID,
+		Type:          model.ShrinkageMovement,
+		Quantity:      shrinkage,
+		Reason:        fmt.Sprintf("Falta no recebimento de %s: enviado %d, recebido %d", transfer.Source.Description, line.Picked, line.Received),
+		UserID:        userID,
+		ReferenceType: model.TransferReference,
+		Reference:     transfer.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao lançar a falta de %s no destino: %w", key, err)
+	}
+	return nil
+}
+
+// Grava nas linhas as quantidades recebidas e a diferença para o separado. Sem
+// quantidades informadas, tudo o que foi separado é considerado recebido.
+func setReceived(lines []model.TransferLine, quantities map[string]int) {
+	for i := range lines {
+		lines[i].Received = lines[i].Picked
+		if quantities != nil {
+			lines[i].Received = quantities[stockKey(lines[i].ProductID, lines[i].SKU)]
+		}
+		lines[i].Discrepancy = lines[i].Received - lines[i].Picked
+	}
+}
+
+// Quantidades lançadas no recebimento de uma linha: a entrada no destino (o enviado,
+// ou o recebido quando há sobra), a baixa do trânsito e a falta, negativa, lançada
+// como quebra
+func receiptQuantities(line model.TransferLine) (quantity, inTransitDelta, shrinkage int) {
+	quantity = line.Picked
+	if line.Received > quantity {
+		quantity = line.Received
+	}
+	if line.Discrepancy < 0 {
+		shrinkage = line.Discrepancy
+	}
+	return quantity, -line.Picked, shrinkage
+}
+
+// ID fixo do lançamento de uma etapa da transferência para um item
+func transferMovementID(transferID, step, key string) string {
+	return fmt.Sprintf("transfer:%s:%s:%s", transferID, step, key)
+}
+
+// CancelTransfer cancela transferências ainda não despachadas, liberando as reservas da separação
+func (s *TransferServiceImpl) CancelTransfer(id, userID string) (*model.Transfer, error) {
+	transfer, err := s.GetTransfer(id)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.Status != model.TransferRequested && transfer.Status != model.TransferPicked {
+		return nil, repository.ErrTransferStateChanged
+	}
+
+	event := model.TransferEvent{Status: model.TransferCanceled, UserID: userID, At: time.Now()}
+	if err := s.transferRepo.Transition(id, []model.TransferStatus{transfer.Status}, event, nil); err != nil {
+		return nil, err
+	}
+	if transfer.Status == model.TransferPicked {
+		s.releasePicked(transfer.Source.ID, transfer.Lines)
+	}
+
+	return s.GetTransfer(id)
+}
+
+// Libera na origem as unidades reservadas na separação. Falhas são apenas registradas.
+func (s *TransferServiceImpl) releasePicked(locationID string, lines []model.TransferLine) {
+	for _, line := range lines {
+		if line.Picked == 0 {
+			continue
+		}
+		if _, err := s.inventoryService.Release(line.ProductID, line.SKU, locationID, line.Picked); err != nil {
+			log.Printf("Erro ao liberar a reserva de %s na localização %s: %v\n", stockKey(line.ProductID, line.SKU), locationID, err)
+		}
+	}
+}
+
+func (s *TransferServiceImpl) activeLocation(id string) (*model.Location, error) {
+	location, err := s.locationRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if location == nil {
+		return nil, ErrLocationNotFound
+	}
+	if location.Status != model.Active {
+		return nil, fmt.Errorf("%w: a localização %s não está ativa", ErrInvalidTransfer, id)
+	}
+	return location, nil
+}
+
+// Soma as quantidades informadas por item, recusando itens fora da transferência
+func lineQuantities(lines []model.TransferLine, items []model.AllocationItem) (map[string]int, error) {
+	known := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		known[stockKey(line.ProductID, line.SKU)] = true
+	}
+
+	quantities := make(map[string]int, len(items))
+	for _, item := range items {
+		key := stockKey(item.ProductID, item.SKU)
+		if !known[key] {
+			return nil, fmt.Errorf("%w: o item %s não faz parte da transferência", ErrInvalidTransfer, key)
+		}
+		if item.Quantity < 0 {
+			return nil, fmt.Errorf("%w: a quantidade de %s não pode ser negativa", ErrInvalidTransfer, key)
+		}
+		quantities[key] += item.Quantity
+	}
+	return quantities, nil
+}
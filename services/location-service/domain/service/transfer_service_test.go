@@ -0,0 +1,69 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/location-service/domain/model"
+	"testing"
+)
+
+func TestSetReceived(t *testing.T) {
+	tests := []struct {
+		name            string
+		picked          int
+		quantities      map[string]int
+		wantReceived    int
+		wantDiscrepancy int
+	}{
+		{"sem quantidades recebe o separado", 10, nil, 10, 0},
+		{"recebido igual ao separado", 10, map[string]int{"p1/azul": 10}, 10, 0},
+		{"falta no recebimento", 10, map[string]int{"p1/azul": 7}, 7, -3},
+		{"sobra no recebimento", 10, map[string]int{"p1/azul": 12}, 12, 2},
+		{"item não informado conta como não recebido", 10, map[string]int{"p2/": 4}, 0, -10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := []model.TransferLine{{ProductID: "p1", SKU: "azul", Requested: 10, Picked: tt.picked}}
+			setReceived(lines, tt.quantities)
+			if lines[0].Received != tt.wantReceived {
+				t.Errorf("recebido = %d, esperado %d", lines[0].Received, tt.wantReceived)
+			}
+			if lines[0].Discrepancy != tt.wantDiscrepancy {
+				t.Errorf("diferença = %d, esperada %d", lines[0].Discrepancy, tt.wantDiscrepancy)
+			}
+		})
+	}
+}
+
+func TestReceiptQuantities(t *testing.T) {
+	tests := []struct {
+		name          string
+		picked        int
+		received      int
+		wantQuantity  int
+		wantInTransit int
+		wantShrinkage int
+	}{
+		{"recebido completo", 10, 10, 10, -10, 0},
+		{"falta entra o enviado e lança a quebra", 10, 7, 10, -10, -3},
+		{"nada recebido lança tudo como quebra", 10, 0, 10, -10, -10},
+		{"sobra entra junto com o enviado", 10, 12, 12, -10, 0},
+		{"sobra de item não separado", 0, 3, 3, 0, 0},
+		{"linha sem separação nem recebimento", 0, 0, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := model.TransferLine{Picked: tt.picked, Received: tt.received, Discrepancy: tt.received - tt.picked}
+			quantity, inTransit, shrinkage := receiptQuantities(line)
+			if quantity != tt.wantQuantity {
+				t.Errorf("entrada = %d, esperada %d", quantity, tt.wantQuantity)
+			}
+			if inTransit != tt.wantInTransit {
+				t.Errorf("baixa do trânsito = %d, esperada %d", inTransit, tt.wantInTransit)
+			}
+			if shrinkage != tt.wantShrinkage {
+				t.Errorf("quebra = %d, esperada %d", shrinkage, tt.wantShrinkage)
+			}
+		})
+	}
+}
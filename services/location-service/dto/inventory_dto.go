@@ -0,0 +1,77 @@
+package dto
+
+import "Varejo-Golang-Microservices/services/location-service/domain/model"
+
+type InventoryAdjustmentDTO struct {
+	ProductID      string             `json:"productId"`
+	SKU            string             `json:"sku"`
+	LocationID     string             `json:"locationId"`
+	OnHandDelta    int                `json:"onHandDelta"`
+	InTransitDelta int                `json:"inTransitDelta"`
+	Type           model.MovementType `json:"type"`
+	Reason         string             `json:"reason"`
+	Reference      string             `json:"reference"`
+}
+
+type InventoryQuantityDTO struct {
+	ProductID  string `json:"productId"`
+	SKU        string `json:"sku"`
+	LocationID string `json:"locationId"`
+	Quantity   int    `json:"quantity"`
+	Reference  string `json:"reference"`
+}
+
+type StockMovementDTO struct {
+	ProductID     string              `json:"productId"`
+	SKU           string              `json:"sku"`
+	LocationID    string              `json:"locationId"`
+	Type          model.MovementType  `json:"type"`
+	Quantity      int                 `json:"quantity"`
+	Reason        string              `json:"reason"`
+	ReferenceType model.ReferenceType `json:"referenceType"`
+	Reference     string              `json:"reference"`
+}
+
+type CycleCountDTO struct {
+	LocationID string              `json:"locationId"`
+	Items      []AllocationItemDTO `json:"items"`
+	Notes      string              `json:"notes"`
+}
+
+type CycleCountEntriesDTO struct {
+	Entries []CycleCountEntryDTO `json:"entries"`
+}
+
+type CycleCountEntryDTO struct {
+	ProductID string `json:"productId"`
+	SKU       string `json:"sku"`
+	Counted   int    `json:"counted"`
+}
+
+// AllocationDTO exige as coordenadas de entrega ou a região; sem coordenadas,
+// as localizações da região são ordenadas só pelo estoque disponível
+type AllocationDTO struct {
+	Items     []AllocationItemDTO `json:"items"`
+	Latitude  *float64            `json:"latitude"`
+	Longitude *float64            `json:"longitude"`
+	Region    string              `json:"region"`
+}
+
+type AllocationItemDTO struct {
+	ProductID string `json:"productId"`
+	SKU       string `json:"sku"`
+	Quantity  int    `json:"quantity"`
+}
+
+type TransferDTO struct {
+	SourceID      string              `json:"sourceId"`
+	DestinationID string              `json:"destinationId"`
+	Lines         []AllocationItemDTO `json:"lines"`
+	Notes         string              `json:"notes"`
+}
+
+// TransferLinesDTO informa as quantidades separadas ou recebidas; sem linhas, vale o total
+type TransferLinesDTO struct {
+	Lines []AllocationItemDTO `json:"lines"`
+	Notes string              `json:"notes"`
+}
@@ -14,6 +14,8 @@ type LocationDTO struct {
 	PostalCode  string                 `json:"postalCode,omitempty"`
 	Description string                 `json:"description,omitempty"`
 	Data        string                 `json:"data,omitempty"`
+	Region      string                 `json:"region,omitempty"`
+	Type        model.LocationType     `json:"type,omitempty"`
 	CreatedDate time.Time              `json:"createdDate"`
 	Coordinates LocationCoordinatesDTO `json:"coordinates"`
 	Latitude    float64                `json:"latitude"`
@@ -0,0 +1,46 @@
+package dto
+
+import "Varejo-Golang-Microservices/services/location-service/domain/model"
+
+type SupplierDTO struct {
+	CNPJ         string                  `json:"cnpj"`
+	LegalName    string                  `json:"legalName"`
+	TradeName    string                  `json:"tradeName"`
+	Contacts     []model.SupplierContact `json:"contacts"`
+	LeadTimeDays int                     `json:"leadTimeDays"`
+	Status       model.SupplierStatus    `json:"status"`
+}
+
+type SupplierPriceDTO struct {
+	ProductID    string  `json:"productId"`
+	SKU          string  `json:"sku"`
+	SupplierCode string  `json:"supplierCode"`
+	UnitCost     float64 `json:"unitCost"`
+	MinQuantity  int     `json:"minQuantity"`
+	LeadTimeDays int     `json:"leadTimeDays"`
+}
+
+// PurchaseItemDTO é um item pedido ou recebido; sem custo, vale o da tabela do
+// fornecedor (no pedido) ou o do pedido (no recebimento)
+type PurchaseItemDTO struct {
+	ProductID string  `json:"productId"`
+	SKU       string  `json:"sku"`
+	Quantity  int     `json:"quantity"`
+	UnitCost  float64 `json:"unitCost"`
+}
+
+type PurchaseOrderDTO struct {
+	SupplierID string            `json:"supplierId"`
+	LocationID string            `json:"locationId"`
+	Lines      []PurchaseItemDTO `json:"lines"`
+	Notes      string            `json:"notes"`
+}
+
+type PurchaseReceiptDTO struct {
+	Lines []PurchaseItemDTO `json:"lines"`
+	Notes string            `json:"notes"`
+}
+
+type PurchaseOrderNotesDTO struct {
+	Notes string `json:"notes"`
+}
@@ -4,6 +4,7 @@ import (
 	"Varejo-Golang-Microservices/services/order-service/domain/model"
 	"Varejo-Golang-Microservices/services/order-service/domain/service"
 	"Varejo-Golang-Microservices/services/order-service/dto"
+	"errors"
 	"log"
 	"net/http"
 
@@ -51,10 +52,21 @@ func convertDTOItemsToOrderProducts(items []dto.OrderItemDTO) []model.OrderProdu
 	for _, item := range items {
 		product := model.OrderProduct{
 			ProductID:   item.ProductID,
+			SKU:         item.SKU,
 			ProductName: item.ProductName,
 			Quantity:    item.Quantity,
 			Price:       item.Price, // Use UnitPrice aqui
 		}
+		for _, component := range item.Components {
+			product.Components = append(product.Components, model.OrderComponent{
+				ProductID:   component.ProductID,
+				SKU:         component.SKU,
+				ProductName: component.ProductName,
+				Quantity:    component.Quantity,
+				UnitPrice:   component.UnitPrice,
+				Total:       component.Total,
+			})
+		}
 		products = append(products, product)
 	}
 
@@ -124,6 +136,10 @@ func (h *OrderHandler) AddOrder(c *gin.Context) {
 
 	// Salva o pedido usando o serviço
 	err := h.Service.SaveOrder(&order)
+	if errors.Is(err, service.ErrInvalidComponents) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		log.Printf("Detalhes do Erro ao salvar pedido: %v\n", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao adicionar pedido. Detalhes: " + err.Error()})
@@ -187,3 +203,24 @@ func (h *OrderHandler) DeleteOrder(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Pedido deletado com sucesso"})
 }
+
+// Informa se o cliente comprou o produto; usado pelo serviço de produtos para marcar avaliações como compra verificada
+func (h *OrderHandler) VerifyPurchase(c *gin.Context) {
+	customerID, productID := c.Query("customerId"), c.Query("productId")
+	if customerID == "" || productID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Os parâmetros customerId e productId são obrigatórios"})
+		return
+	}
+
+	order, err := h.Service.FindPurchase(customerID, productID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar pedidos"})
+		return
+	}
+	if order == nil {
+		c.JSON(http.StatusOK, gin.H{"verified": false})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"verified": true, "orderId": order.ID.Hex(), "purchasedAt": order.OrderDate})
+}
@@ -38,8 +38,13 @@ func main() {
 	orderService := service.NewOrderService(orderRepo)
 	orderHandler := handler.NewOrderHandler(orderService)
 
+	// Marca os pedidos como pagos a partir dos eventos do payment-service
+	go service.ListenPaymentIntents(kafkaBroker, orderService)
+
 	// Setting up the routes
 	r.GET("/order", orderHandler.GetAllOrders)
+	// Consulta interna do serviço de produtos, que se autentica com o token do serviço
+	authorized.GET("/orders/verify-purchase", orderHandler.VerifyPurchase)
 	r.GET("/orders/:id", orderHandler.GetOrderByID)
 	r.POST("/order", orderHandler.AddOrder)
 	r.PUT("/order/:id", orderHandler.UpdateOrderStatus)
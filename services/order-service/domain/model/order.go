@@ -19,9 +19,21 @@ type Order struct {
 
 type OrderProduct struct {
 	ProductID   string  `json:"productId" bson:"productId"`
+	SKU         string  `json:"sku,omitempty" bson:"sku,omitempty"`
 	ProductName string  `json:"productName" bson:"productName"`
 	Quantity    int     `json:"quantity" bson:"quantity"`
 	Price       float64 `json:"price" bson:"price"`
+	// Em kits, o preço rateado entre os componentes, para faturamento e devolução por item
+	Components []OrderComponent `json:"components,omitempty" bson:"components,omitempty"`
+}
+
+type OrderComponent struct {
+	ProductID   string  `json:"productId" bson:"productId"`
+	SKU         string  `json:"sku,omitempty" bson:"sku,omitempty"`
+	ProductName string  `json:"productName" bson:"productName"`
+	Quantity    int     `json:"quantity" bson:"quantity"`
+	UnitPrice   float64 `json:"unitPrice" bson:"unitPrice"`
+	Total       float64 `json:"total" bson:"total"`
 }
 
 type Address struct {
@@ -36,6 +48,7 @@ type OrderStatus string
 
 const (
 	Pending   OrderStatus = "PENDING"
+	Paid      OrderStatus = "PAID"
 	Shipped   OrderStatus = "SHIPPED"
 	Delivered OrderStatus = "DELIVERED"
 	Canceled  OrderStatus = "CANCELED"
@@ -12,6 +12,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type MongoOrderRepository struct {
@@ -66,13 +67,17 @@ func (r *MongoOrderRepository) GetAll() ([]*model.Order, error) {
 
 func (r *MongoOrderRepository) FindByID(id string) (*model.Order, error) {
 	collection := r.client.Database("orderDB").Collection("orders")
-	filter := bson.M{"_id": id}
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("ID do pedido inválido")
+	}
 
 	var order model.Order
-	err := collection.FindOne(context.TODO(), filter).Decode(&order)
+	err = collection.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&order)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("pedido não encontrado")
+			return nil, nil
 		}
 		return nil, err
 	}
@@ -80,6 +85,32 @@ func (r *MongoOrderRepository) FindByID(id string) (*model.Order, error) {
 	return &order, nil
 }
 
+// FindPurchase retorna o pedido mais recente do cliente, com o status informado,
+// que contém o produto diretamente ou como componente de um kit
+func (r *MongoOrderRepository) FindPurchase(customerID, productID string, statuses []model.OrderStatus) (*model.Order, error) {
+	collection := r.client.Database("orderDB").Collection("orders")
+	filter := bson.M{
+		"customerId": customerID,
+		"status":     bson.M{"$in": statuses},
+		"$or": []bson.M{
+			{"products.productId": productID},
+			{"products.components.productId": productID},
+		},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "orderDate", Value: -1}})
+
+	var order model.Order
+	err := collection.FindOne(context.TODO(), filter, opts).Decode(&order)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
 func (r *MongoOrderRepository) Save(order *model.Order) error {
 	orderCollection := r.client.Database("orderDB").Collection("orders")
 
@@ -90,6 +121,12 @@ func (r *MongoOrderRepository) Save(order *model.Order) error {
 		return err
 	}
 
+	return r.Publish(order)
+}
+
+// Publish envia o pedido ao tópico de pedidos. Além da criação, os cancelamentos são
+// publicados para que os consumidores desfaçam o que contabilizaram.
+func (r *MongoOrderRepository) Publish(order *model.Order) error {
 	// Converte o pedido para JSON para enviar ao Kafka
 	orderJSON, err := json.Marshal(order)
 	if err != nil {
@@ -154,6 +191,41 @@ func (r *MongoOrderRepository) Update(order *model.Order) error {
 	return nil
 }
 
+// Atualiza apenas o status do pedido
+func (r *MongoOrderRepository) UpdateStatus(id string, status model.OrderStatus) error {
+	collection := r.client.Database("orderDB").Collection("orders")
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := collection.UpdateOne(context.TODO(), bson.M{"_id": objID}, bson.M{"$set": bson.M{"status": status}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("pedido não encontrado")
+	}
+
+	return nil
+}
+
+// TransitionStatus altera o status somente se o pedido ainda estiver no status
+// esperado. Retorna false quando o pedido não existe ou já mudou de status.
+func (r *MongoOrderRepository) TransitionStatus(id primitive.ObjectID, from, to model.OrderStatus) (bool, error) {
+	collection := r.client.Database("orderDB").Collection("orders")
+
+	filter := bson.M{"_id": id, "status": from}
+	result, err := collection.UpdateOne(context.TODO(), filter, bson.M{"$set": bson.M{"status": to}})
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
 func (r *MongoOrderRepository) Delete(id string) error {
 	collection := r.client.Database("orderDB").Collection("orders")
 
@@ -3,16 +3,23 @@ package service
 import (
 	"Varejo-Golang-Microservices/services/order-service/domain/model"
 	"Varejo-Golang-Microservices/services/order-service/domain/repository"
+	"errors"
+	"fmt"
+	"math"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+var ErrInvalidComponents = errors.New("rateio do kit inválido")
+
 type OrderService interface {
 	GetOrderByID(id string) (*model.Order, error)
 	SaveOrder(order *model.Order) error
 	GetAllOrders() ([]*model.Order, error)
 	UpdateOrderStatus(id string, status model.OrderStatus) error
 	DeleteOrder(id string) error
+	MarkOrderPaid(id string, amount float64) error
+	FindPurchase(customerID, productID string) (*model.Order, error)
 }
 
 type OrderServiceImpl struct {
@@ -30,9 +37,33 @@ func (s *OrderServiceImpl) GetOrderByID(id string) (*model.Order, error) {
 }
 
 func (s *OrderServiceImpl) SaveOrder(order *model.Order) error {
+	for _, product := range order.Products {
+		if err := validateComponents(product); err != nil {
+			return err
+		}
+	}
 	return s.orderRepo.Save(order)
 }
 
+// O rateio de um kit precisa fechar com o valor do item, com tolerância de um centavo
+func validateComponents(product model.OrderProduct) error {
+	if len(product.Components) == 0 {
+		return nil
+	}
+
+	total := 0.0
+	for _, component := range product.Components {
+		if component.Quantity <= 0 {
+			return fmt.Errorf("%w: quantidade inválida para o componente %s", ErrInvalidComponents, component.ProductID)
+		}
+		total += component.Total
+	}
+	if math.Abs(total-product.Price*float64(product.Quantity)) > 0.01 {
+		return fmt.Errorf("%w: a soma dos componentes (%.2f) difere do total do kit %s", ErrInvalidComponents, total, product.ProductID)
+	}
+	return nil
+}
+
 func (s *OrderServiceImpl) GetAllOrders() ([]*model.Order, error) {
 	return s.orderRepo.GetAll()
 }
@@ -44,7 +75,13 @@ func (s *OrderServiceImpl) UpdateOrderStatus(id string, status model.OrderStatus
 	}
 
 	orderToUpdate := &model.Order{ID: objID, Status: status}
-	return s.orderRepo.Update(orderToUpdate)
+	if err := s.orderRepo.Update(orderToUpdate); err != nil {
+		return err
+	}
+	if status == model.Canceled {
+		return s.orderRepo.Publish(orderToUpdate)
+	}
+	return nil
 }
 
 func (s *OrderServiceImpl) UpdateOrder(order *model.Order) error {
@@ -54,3 +91,35 @@ func (s *OrderServiceImpl) UpdateOrder(order *model.Order) error {
 func (s *OrderServiceImpl) DeleteOrder(id string) error {
 	return s.orderRepo.Delete(id)
 }
+
+// MarkOrderPaid marca como pago um pedido pendente cujo total é igual ao valor
+// capturado. Pedidos cancelados ou já pagos não mudam de status.
+func (s *OrderServiceImpl) MarkOrderPaid(id string, amount float64) error {
+	order, err := s.orderRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		return errors.New("pedido não encontrado")
+	}
+	if order.Status != model.Pending {
+		return fmt.Errorf("o pedido não está pendente (%s)", order.Status)
+	}
+	if math.Abs(order.TotalPrice-amount) >= 0.01 {
+		return fmt.Errorf("valor pago %.2f difere do total do pedido %.2f", amount, order.TotalPrice)
+	}
+
+	changed, err := s.orderRepo.TransitionStatus(order.ID, model.Pending, model.Paid)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return errors.New("o pedido mudou de status durante o pagamento")
+	}
+	return nil
+}
+
+// FindPurchase busca uma compra efetivada (paga, enviada ou entregue) do produto pelo cliente
+func (s *OrderServiceImpl) FindPurchase(customerID, productID string) (*model.Order, error) {
+	return s.orderRepo.FindPurchase(customerID, productID, []model.OrderStatus{model.Paid, model.Shipped, model.Delivered})
+}
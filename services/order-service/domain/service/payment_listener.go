@@ -0,0 +1,45 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/order-service/infra/event"
+	"encoding/json"
+	"log"
+)
+
+const paymentIntentTopic = "Payment_Intent_Topic"
+
+// paymentIntentEvent contém os campos usados do evento publicado pelo payment-service
+type paymentIntentEvent struct {
+	OrderID        string  `json:"orderId"`
+	CapturedAmount float64 `json:"capturedAmount"`
+	Status         string  `json:"status"`
+}
+
+// ListenPaymentIntents consome os eventos de intents de pagamento e marca o
+// pedido como pago quando a soma dos tenders capturados cobre o total. Só pedidos
+// pendentes passam a pagos.
+func ListenPaymentIntents(kafkaBroker string, orderService OrderService) {
+	messages := make(chan string)
+
+	go func() {
+		if err := event.ConsumeMessage(kafkaBroker, paymentIntentTopic, "order-service", messages); err != nil {
+			log.Printf("Erro ao consumir o tópico %s: %v\n", paymentIntentTopic, err)
+		}
+	}()
+
+	for message := range messages {
+		var intent paymentIntentEvent
+		if err := json.Unmarshal([]byte(message), &intent); err != nil {
+			log.Printf("Erro ao ler evento de pagamento: %v\n", err)
+			continue
+		}
+
+		if intent.Status != "PAID" {
+			continue
+		}
+
+		if err := orderService.MarkOrderPaid(intent.OrderID, intent.CapturedAmount); err != nil {
+			log.Printf("Erro ao marcar o pedido %s como pago: %v\n", intent.OrderID, err)
+		}
+	}
+}
@@ -18,10 +18,22 @@ type OrderDTO struct {
 
 type OrderItemDTO struct {
 	ProductID   string  `json:"productId"`
+	SKU         string  `json:"sku,omitempty"`
 	ProductName string  `json:"productName"`
 	Quantity    int     `json:"quantity"`
 	Price       float64 `json:"Price"`
 	Total       float64 `json:"total"`
+	// Rateio do kit, obtido em GET /products/:id/bundle?quantity=N do serviço de produtos
+	Components []OrderComponentDTO `json:"components,omitempty"`
+}
+
+type OrderComponentDTO struct {
+	ProductID   string  `json:"productId"`
+	SKU         string  `json:"sku,omitempty"`
+	ProductName string  `json:"productName"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unitPrice"`
+	Total       float64 `json:"total"`
 }
 
 type Address struct {
@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"Varejo-Golang-Microservices/services/payment-service/domain/service"
+	"Varejo-Golang-Microservices/services/payment-service/dto"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DisputeHandler struct {
+	Service       service.DisputeService
+	webhookSecret string
+}
+
+// Inicializa um novo manipulador de disputas. Os webhooks precisam trazer a
+// assinatura HMAC-SHA256 do corpo no cabeçalho X-Signature; sem o segredo
+// configurado, o webhook fica indisponível.
+func NewDisputeHandler(s service.DisputeService, webhookSecret string) *DisputeHandler {
+	if webhookSecret == "" {
+		log.Println("DISPUTE_WEBHOOK_SECRET não definida: o webhook de disputas responderá 503")
+	}
+	return &DisputeHandler{
+		Service:       s,
+		webhookSecret: webhookSecret,
+	}
+}
+
+func disputeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrDisputeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrDisputeTransition), errors.Is(err, service.ErrDisputeAlreadyOpen):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrPaymentNotDisputable):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// Abre manualmente uma disputa para um pagamento
+func (h *DisputeHandler) OpenDispute(c *gin.Context) {
+	var disputeDTO dto.DisputeDTO
+	if err := c.ShouldBindJSON(&disputeDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao processar os dados da disputa."})
+		return
+	}
+
+	paymentID, err := primitive.ObjectIDFromHex(disputeDTO.PaymentID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do pagamento inválido"})
+		return
+	}
+
+	dispute := &model.Dispute{
+		PaymentID:     paymentID,
+		ReasonCode:    disputeDTO.ReasonCode,
+		Amount:        disputeDTO.Amount,
+		Fee:           disputeDTO.Fee,
+		EvidenceDueAt: disputeDTO.EvidenceDueAt,
+	}
+
+	if err := h.Service.OpenDispute(dispute, c.GetString("userID")); err != nil {
+		log.Printf("Erro ao abrir disputa: %v\n", err)
+		c.JSON(disputeErrorStatus(err), gin.H{"error": "Erro ao abrir disputa. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Disputa aberta com sucesso.", "data": dispute})
+}
+
+func (h *DisputeHandler) ListDisputes(c *gin.Context) {
+	disputes, err := h.Service.ListDisputes(model.DisputeStatus(c.Query("status")), c.Query("paymentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao buscar disputas. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, disputes)
+}
+
+// Lista as disputas com prazo de evidências vencendo nos próximos dias (padrão: 2)
+func (h *DisputeHandler) ListDueSoon(c *gin.Context) {
+	days := 2
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro days inválido"})
+			return
+		}
+		days = parsed
+	}
+
+	disputes, err := h.Service.ListDueSoon(time.Duration(days) * 24 * time.Hour)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar disputas com prazo próximo"})
+		return
+	}
+
+	c.JSON(http.StatusOK, disputes)
+}
+
+func (h *DisputeHandler) GetDisputeByID(c *gin.Context) {
+	dispute, err := h.Service.GetDisputeByID(c.Param("id"))
+	if err != nil {
+		c.JSON(disputeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dispute)
+}
+
+func (h *DisputeHandler) AddEvidence(c *gin.Context) {
+	var evidenceDTO dto.EvidenceDTO
+	if err := c.ShouldBindJSON(&evidenceDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	evidence := &model.Evidence{
+		Type:        evidenceDTO.Type,
+		FileName:    evidenceDTO.FileName,
+		ContentType: evidenceDTO.ContentType,
+		URL:         evidenceDTO.URL,
+		Description: evidenceDTO.Description,
+	}
+
+	dispute, err := h.Service.AddEvidence(c.Param("id"), evidence)
+	if err != nil {
+		c.JSON(disputeErrorStatus(err), gin.H{"error": "Erro ao anexar evidência. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Evidência anexada com sucesso.", "data": dispute})
+}
+
+func (h *DisputeHandler) SubmitEvidence(c *gin.Context) {
+	var actionDTO dto.DisputeActionDTO
+	// O corpo é opcional
+	_ = c.ShouldBindJSON(&actionDTO)
+
+	dispute, err := h.Service.SubmitEvidence(c.Param("id"), c.GetString("userID"), actionDTO.Note)
+	if err != nil {
+		c.JSON(disputeErrorStatus(err), gin.H{"error": "Erro ao enviar evidências. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Evidências enviadas ao emissor.", "data": dispute})
+}
+
+// Encerra a disputa como WON ou LOST
+func (h *DisputeHandler) Resolve(c *gin.Context) {
+	var actionDTO dto.DisputeActionDTO
+	if err := c.ShouldBindJSON(&actionDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dispute, err := h.Service.Resolve(c.Param("id"), actionDTO.Status, c.GetString("userID"), actionDTO.Note)
+	if err != nil {
+		c.JSON(disputeErrorStatus(err), gin.H{"error": "Erro ao resolver disputa. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Disputa resolvida.", "data": dispute})
+}
+
+func (h *DisputeHandler) ListLedgerEntries(c *gin.Context) {
+	entries, err := h.Service.ListLedgerEntries(c.Param("id"))
+	if err != nil {
+		c.JSON(disputeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// Recebe os eventos de disputa enviados pelo provedor de pagamento
+func (h *DisputeHandler) ProviderWebhook(c *gin.Context) {
+	if h.webhookSecret == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Webhook de disputas desativado: DISPUTE_WEBHOOK_SECRET não configurado"})
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao ler o corpo da requisição"})
+		return
+	}
+
+	if !h.validSignature(body, c.GetHeader("X-Signature")) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Assinatura do webhook inválida"})
+		return
+	}
+
+	var webhookDTO dto.DisputeWebhookDTO
+	if err := json.Unmarshal(body, &webhookDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao processar o evento de disputa."})
+		return
+	}
+
+	event := &model.DisputeProviderEvent{
+		Type:              webhookDTO.Type,
+		ProviderDisputeID: webhookDTO.DisputeID,
+		PaymentID:         webhookDTO.PaymentID,
+		ReasonCode:        webhookDTO.ReasonCode,
+		Amount:            webhookDTO.Amount,
+		Fee:               webhookDTO.Fee,
+		EvidenceDueAt:     webhookDTO.EvidenceDueAt,
+	}
+
+	dispute, err := h.Service.HandleProviderEvent(c.Param("provider"), event)
+	if err != nil {
+		log.Printf("Erro ao processar webhook de disputa: %v\n", err)
+		c.JSON(disputeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Evento de disputa processado.", "data": dispute})
+}
+
+func (h *DisputeHandler) validSignature(body []byte, signature string) bool {
+	mac := hmac.New(sha256.New, []byte(h.webhookSecret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
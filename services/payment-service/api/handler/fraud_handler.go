@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"Varejo-Golang-Microservices/services/payment-service/domain/service"
+	"Varejo-Golang-Microservices/services/payment-service/dto"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FraudHandler struct {
+	Service service.FraudService
+}
+
+// Inicializa um novo manipulador de fraude com o serviço fornecido
+func NewFraudHandler(s service.FraudService) *FraudHandler {
+	return &FraudHandler{
+		Service: s,
+	}
+}
+
+// Busca as regras de pontuação em uso
+func (h *FraudHandler) GetRules(c *gin.Context) {
+	rules, err := h.Service.GetRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar regras de fraude"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// Atualiza as regras de pontuação
+func (h *FraudHandler) UpdateRules(c *gin.Context) {
+	var rules model.FraudRules
+	if err := c.ShouldBindJSON(&rules); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.UpdateRules(&rules); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao atualizar regras de fraude. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Regras de fraude atualizadas com sucesso", "data": rules})
+}
+
+// Lista as decisões registradas para auditoria
+func (h *FraudHandler) ListAssessments(c *gin.Context) {
+	assessments, err := h.Service.ListAssessments(c.Query("paymentId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar decisões de fraude. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, assessments)
+}
+
+// Lista a fila de revisão manual; por padrão apenas os itens pendentes
+func (h *FraudHandler) ListReviews(c *gin.Context) {
+	status := model.ReviewStatus(c.DefaultQuery("status", string(model.ReviewPending)))
+
+	reviews, err := h.Service.ListReviews(status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar fila de revisão"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+func (h *FraudHandler) ApproveReview(c *gin.Context) {
+	h.resolveReview(c, h.Service.ApproveReview)
+}
+
+func (h *FraudHandler) DenyReview(c *gin.Context) {
+	h.resolveReview(c, h.Service.DenyReview)
+}
+
+func (h *FraudHandler) resolveReview(c *gin.Context, resolve func(id, reviewer, note string) (*model.FraudReview, error)) {
+	reviewID := c.Param("id")
+	if reviewID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "O ID da revisão é obrigatório"})
+		return
+	}
+
+	var decisionDTO dto.ReviewDecisionDTO
+	if err := c.ShouldBindJSON(&decisionDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// O revisor é sempre o usuário autenticado, nunca um valor do corpo da requisição
+	reviewer := c.GetString("userID")
+	if reviewer == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário autenticado é obrigatório para resolver revisões"})
+		return
+	}
+
+	review, err := resolve(reviewID, reviewer, decisionDTO.Note)
+	if errors.Is(err, service.ErrReviewNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revisão não encontrada"})
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao resolver revisão de fraude: %v\n", err)
+		c.JSON(http.StatusConflict, gin.H{"error": "Erro ao resolver revisão. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Revisão resolvida com sucesso", "data": review})
+}
+
+func (h *FraudHandler) ListBlacklist(c *gin.Context) {
+	entries, err := h.Service.ListBlacklist()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar lista negra"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+func (h *FraudHandler) AddBlacklistEntry(c *gin.Context) {
+	var entryDTO dto.BlacklistEntryDTO
+	if err := c.ShouldBindJSON(&entryDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry := &model.BlacklistEntry{
+		Type:   entryDTO.Type,
+		Value:  entryDTO.Value,
+		Reason: entryDTO.Reason,
+	}
+
+	if err := h.Service.AddBlacklistEntry(entry); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao adicionar à lista negra. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Entrada adicionada à lista negra", "data": entry})
+}
+
+func (h *FraudHandler) DeleteBlacklistEntry(c *gin.Context) {
+	entryID := c.Param("id")
+	if entryID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "O ID da entrada é obrigatório"})
+		return
+	}
+
+	if err := h.Service.DeleteBlacklistEntry(entryID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Erro ao excluir entrada. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Entrada removida da lista negra"})
+}
@@ -2,8 +2,10 @@ package handler
 
 import (
 	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"Varejo-Golang-Microservices/services/payment-service/domain/repository"
 	"Varejo-Golang-Microservices/services/payment-service/domain/service"
 	"Varejo-Golang-Microservices/services/payment-service/dto"
+	"errors"
 	"log"
 	"net/http"
 
@@ -44,9 +46,23 @@ func convertDTOPayment(paymentDTO dto.PaymentDTO) (model.Payment, error) {
 		Method:      convertDTOPaymentMethod(paymentDTO.Method),
 		Status:      paymentDTO.Status,
 		PaymentDate: paymentDTO.PaymentDate,
+
+		BillingAddress:  convertDTOAddress(paymentDTO.BillingAddress),
+		ShippingAddress: convertDTOAddress(paymentDTO.ShippingAddress),
 	}, nil
 }
 
+// Função auxiliar para converter AddressDTO em model.Address
+func convertDTOAddress(addressDTO dto.AddressDTO) model.Address {
+	return model.Address{
+		Street:     addressDTO.Street,
+		City:       addressDTO.City,
+		State:      addressDTO.State,
+		PostalCode: addressDTO.PostalCode,
+		Country:    addressDTO.Country,
+	}
+}
+
 // Função auxiliar para converter PaymentMethodDTO em model.PaymentMethod
 func convertDTOPaymentMethod(methodDTO dto.PaymentMethodDTO) model.PaymentMethod {
 	return model.PaymentMethod{
@@ -54,6 +70,9 @@ func convertDTOPaymentMethod(methodDTO dto.PaymentMethodDTO) model.PaymentMethod
 		CardNumber: methodDTO.CardNumber,
 		Expiry:     methodDTO.Expiry,
 		CVV:        methodDTO.CVV,
+
+		GiftCardCode: methodDTO.GiftCardCode,
+		GiftCardPIN:  methodDTO.GiftCardPIN,
 	}
 }
 
@@ -122,6 +141,14 @@ func (h *PaymentHandler) AddPayment(c *gin.Context) {
 
 	// Salva o pagamento usando o serviço
 	err = h.Service.SavePayment(&payment)
+	if errors.Is(err, service.ErrInvalidCard) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrPaymentDenied) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "data": payment})
+		return
+	}
 	if err != nil {
 		log.Printf("Detalhes do Erro ao salvar pagamento: %v\n", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao adicionar pagamento. Detalhes: " + err.Error()})
@@ -129,6 +156,11 @@ func (h *PaymentHandler) AddPayment(c *gin.Context) {
 	}
 
 	// Envia a resposta ao cliente
+	if payment.Status == model.UnderReview {
+		c.JSON(http.StatusAccepted, gin.H{"message": "Pagamento enviado para revisão manual.", "data": payment})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Pagamento efetuado com sucesso.", "data": payment})
 }
 
@@ -160,14 +192,31 @@ func (h *PaymentHandler) UpdatePayment(c *gin.Context) {
 	payment.ID = objID
 
 	err = h.Service.UpdatePayment(&payment)
+	if errors.Is(err, service.ErrInvalidCard) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrPaymentDenied) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "data": payment})
+		return
+	}
+	if errors.Is(err, service.ErrPaymentUnderReview) || errors.Is(err, repository.ErrPaymentConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar Pagamento. Detalhes: " + err.Error()})
 		return
 	}
 
+	if payment.Status == model.UnderReview {
+		c.JSON(http.StatusAccepted, gin.H{"message": "Pagamento enviado para revisão manual.", "data": payment})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Cliente atualizado com sucesso",
-		"data":    paymentDTO,
+		"data":    payment,
 	})
 }
 
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"Varejo-Golang-Microservices/services/payment-service/domain/repository"
+	"Varejo-Golang-Microservices/services/payment-service/domain/service"
+	"Varejo-Golang-Microservices/services/payment-service/dto"
+	"Varejo-Golang-Microservices/services/payment-service/infra/orders"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentIntentHandler struct {
+	Service service.PaymentIntentService
+}
+
+// Inicializa um novo manipulador de intents de pagamento com o serviço fornecido
+func NewPaymentIntentHandler(s service.PaymentIntentService) *PaymentIntentHandler {
+	return &PaymentIntentHandler{
+		Service: s,
+	}
+}
+
+// Converte os erros do serviço para o status HTTP correspondente
+func intentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrIntentNotFound), errors.Is(err, service.ErrTenderNotFound), errors.Is(err, service.ErrOrderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrIntentStatus), errors.Is(err, repository.ErrIntentConflict):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrTenderDeclined), errors.Is(err, service.ErrIntentDenied):
+		return http.StatusPaymentRequired
+	case errors.Is(err, service.ErrIntentReview):
+		return http.StatusAccepted
+	case errors.Is(err, orders.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+func (h *PaymentIntentHandler) CreateIntent(c *gin.Context) {
+	var intentDTO dto.PaymentIntentDTO
+	if err := c.ShouldBindJSON(&intentDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao processar os dados do intent."})
+		return
+	}
+
+	intent := &model.PaymentIntent{
+		OrderID: intentDTO.OrderID,
+	}
+
+	if err := h.Service.CreateIntent(intent); err != nil {
+		log.Printf("Erro ao criar intent de pagamento: %v\n", err)
+		c.JSON(intentErrorStatus(err), gin.H{"error": "Erro ao criar intent de pagamento. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Intent de pagamento criado com sucesso.", "data": intent})
+}
+
+func (h *PaymentIntentHandler) GetIntentByID(c *gin.Context) {
+	intent, err := h.Service.GetIntentByID(c.Param("id"))
+	if err != nil {
+		c.JSON(intentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, intent)
+}
+
+func (h *PaymentIntentHandler) GetIntentByOrderID(c *gin.Context) {
+	intent, err := h.Service.GetIntentByOrderID(c.Param("orderId"))
+	if err != nil {
+		c.JSON(intentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, intent)
+}
+
+func (h *PaymentIntentHandler) AddTender(c *gin.Context) {
+	var tenderDTO dto.TenderDTO
+	if err := c.ShouldBindJSON(&tenderDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao processar os dados do tender."})
+		return
+	}
+
+	tender := &model.Tender{
+		Type:   tenderDTO.Type,
+		Amount: tenderDTO.Amount,
+		Method: convertDTOPaymentMethod(tenderDTO.Method),
+	}
+
+	intent, err := h.Service.AddTender(c.Param("id"), tender)
+	if err != nil {
+		c.JSON(intentErrorStatus(err), gin.H{"error": "Erro ao adicionar tender. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Tender adicionado com sucesso.", "data": intent})
+}
+
+func (h *PaymentIntentHandler) RemoveTender(c *gin.Context) {
+	intent, err := h.Service.RemoveTender(c.Param("id"), c.Param("tenderId"))
+	if err != nil {
+		c.JSON(intentErrorStatus(err), gin.H{"error": "Erro ao remover tender. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tender removido com sucesso.", "data": intent})
+}
+
+func (h *PaymentIntentHandler) Authorize(c *gin.Context) {
+	intent, err := h.Service.Authorize(c.Param("id"))
+	if err != nil {
+		log.Printf("Erro ao autorizar intent de pagamento: %v\n", err)
+		c.JSON(intentErrorStatus(err), gin.H{"error": "Erro ao autorizar pagamento. Detalhes: " + err.Error(), "data": intent})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pagamento autorizado com sucesso.", "data": intent})
+}
+
+func (h *PaymentIntentHandler) Capture(c *gin.Context) {
+	intent, err := h.Service.Capture(c.Param("id"))
+	if err != nil {
+		log.Printf("Erro ao capturar intent de pagamento: %v\n", err)
+		c.JSON(intentErrorStatus(err), gin.H{"error": "Erro ao capturar pagamento. Detalhes: " + err.Error(), "data": intent})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pagamento capturado com sucesso.", "data": intent})
+}
+
+func (h *PaymentIntentHandler) Cancel(c *gin.Context) {
+	intent, err := h.Service.Cancel(c.Param("id"))
+	if err != nil {
+		c.JSON(intentErrorStatus(err), gin.H{"error": "Erro ao cancelar intent. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Intent de pagamento cancelado.", "data": intent})
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/service"
+	"Varejo-Golang-Microservices/services/payment-service/dto"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WalletHandler struct {
+	Service service.WalletService
+}
+
+// Inicializa um novo manipulador de cartões-presente e crédito em loja com o serviço fornecido
+func NewWalletHandler(s service.WalletService) *WalletHandler {
+	return &WalletHandler{
+		Service: s,
+	}
+}
+
+// Converte os erros de PIN do cartão-presente para o status HTTP correspondente
+func giftCardPINErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, service.ErrInvalidGiftCard):
+		return http.StatusUnauthorized, true
+	case errors.Is(err, service.ErrGiftCardLocked):
+		return http.StatusTooManyRequests, true
+	default:
+		return 0, false
+	}
+}
+
+// Emite um cartão-presente. O PIN é retornado somente nesta resposta.
+func (h *WalletHandler) IssueGiftCard(c *gin.Context) {
+	var issueDTO dto.IssueGiftCardDTO
+	if err := c.ShouldBindJSON(&issueDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao processar os dados do cartão-presente."})
+		return
+	}
+
+	card, pin, err := h.Service.IssueGiftCard(issueDTO.Amount, issueDTO.ExpiresAt, issueDTO.Channel, issueDTO.Activate)
+	if err != nil {
+		log.Printf("Erro ao emitir cartão-presente: %v\n", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao emitir cartão-presente. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Cartão-presente emitido com sucesso.", "data": card, "pin": pin})
+}
+
+func (h *WalletHandler) ActivateGiftCard(c *gin.Context) {
+	var channelDTO dto.GiftCardChannelDTO
+	if err := c.ShouldBindJSON(&channelDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	card, err := h.Service.ActivateGiftCard(c.Param("code"), channelDTO.Channel)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao ativar cartão-presente. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Cartão-presente ativado com sucesso.", "data": card})
+}
+
+func (h *WalletHandler) BlockGiftCard(c *gin.Context) {
+	card, err := h.Service.BlockGiftCard(c.Param("code"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Erro ao bloquear cartão-presente. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Cartão-presente bloqueado.", "data": card})
+}
+
+// Consulta o saldo; exige o PIN no corpo para não expô-lo na URL
+func (h *WalletHandler) GetGiftCardBalance(c *gin.Context) {
+	var pinDTO dto.GiftCardPINDTO
+	if err := c.ShouldBindJSON(&pinDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	card, err := h.Service.VerifyGiftCard(c.Param("code"), pinDTO.PIN)
+	if status, ok := giftCardPINErrorStatus(err); ok {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"code": card.Code, "balance": card.Balance, "expiresAt": card.ExpiresAt})
+}
+
+// Lista o histórico do cartão; assim como o saldo, exige o PIN no corpo
+func (h *WalletHandler) ListGiftCardTransactions(c *gin.Context) {
+	var pinDTO dto.GiftCardPINDTO
+	if err := c.ShouldBindJSON(&pinDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transactions, err := h.Service.ListGiftCardTransactions(c.Param("code"), pinDTO.PIN)
+	if status, ok := giftCardPINErrorStatus(err); ok {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar transações do cartão-presente"})
+		return
+	}
+
+	c.JSON(http.StatusOK, transactions)
+}
+
+func (h *WalletHandler) GetStoreCredit(c *gin.Context) {
+	credit, err := h.Service.GetStoreCredit(c.Param("customerId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar crédito em loja"})
+		return
+	}
+
+	c.JSON(http.StatusOK, credit)
+}
+
+func (h *WalletHandler) CreditStoreCredit(c *gin.Context) {
+	var creditDTO dto.StoreCreditDTO
+	if err := c.ShouldBindJSON(&creditDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	credit, err := h.Service.CreditStoreCredit(c.Param("customerId"), creditDTO.Amount, creditDTO.Reference, creditDTO.Note)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao creditar cliente. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Crédito em loja adicionado com sucesso.", "data": credit})
+}
+
+func (h *WalletHandler) ListStoreCreditTransactions(c *gin.Context) {
+	transactions, err := h.Service.ListStoreCreditTransactions(c.Param("customerId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar transações de crédito em loja"})
+		return
+	}
+
+	c.JSON(http.StatusOK, transactions)
+}
@@ -3,16 +3,21 @@ package main
 import (
 	"Varejo-Golang-Microservices/middleware"
 	"Varejo-Golang-Microservices/services/payment-service/api/handler"
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
 	"Varejo-Golang-Microservices/services/payment-service/domain/repository"
 	"Varejo-Golang-Microservices/services/payment-service/domain/service"
+	"Varejo-Golang-Microservices/services/payment-service/infra/orders"
+	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 const defaultMongoURI = "mongodb://localhost:27017"
 const defaultKafkaBroker = "localhost:9092"
+const defaultOrderServiceURL = "http://localhost:8084"
 
 func main() {
 	r := gin.Default()
@@ -28,15 +33,60 @@ func main() {
 		kafkaBroker = defaultKafkaBroker
 	}
 
+	// Chave do HMAC dos tokens de cartão; sem ela os tokens seriam reversíveis
+	cardTokenKey := os.Getenv("CARD_TOKEN_KEY")
+	if cardTokenKey == "" {
+		log.Fatal("CARD_TOKEN_KEY não definida: a chave é obrigatória para tokenizar cartões")
+	}
+	model.SetCardTokenKey(cardTokenKey)
+
+	// Chave do HMAC dos PINs de cartão-presente; sem ela o espaço de 6 dígitos seria testado offline
+	giftCardPINKey := os.Getenv("GIFT_CARD_PIN_KEY")
+	if giftCardPINKey == "" {
+		log.Fatal("GIFT_CARD_PIN_KEY não definida: a chave é obrigatória para os PINs de cartão-presente")
+	}
+	model.SetGiftCardPINKey(giftCardPINKey)
+
+	// O total cobrado nos intents vem do serviço de pedidos
+	orderServiceURL := os.Getenv("ORDER_SERVICE_URL")
+	if orderServiceURL == "" {
+		orderServiceURL = defaultOrderServiceURL
+	}
+
 	r.POST("/login", authenticate)
 
 	authorized := r.Group("/")
 	authorized.Use(middleware.AuthMiddleware())
 
+	// Operações da equipe da loja (emissão de cartões-presente, crédito em loja)
+	staff := authorized.Group("/")
+	staff.Use(middleware.StaffMiddleware())
+
 	// Inicialize as conexões de banco de dados, repositórios, serviços.
 	paymentRepo := repository.NewMongoPaymentRepository(mongoURI, kafkaBroker)
-	paymentService := service.NewPaymentService(paymentRepo)
+	intentRepo := repository.NewMongoPaymentIntentRepository(mongoURI, kafkaBroker)
+	fraudRepo := repository.NewMongoFraudRepository(mongoURI)
+	fraudService := service.NewFraudService(fraudRepo, paymentRepo, intentRepo)
+	paymentService := service.NewPaymentService(paymentRepo, fraudService)
 	paymentHandler := handler.NewPaymentHandler(paymentService)
+	fraudHandler := handler.NewFraudHandler(fraudService)
+
+	walletRepo := repository.NewMongoWalletRepository(mongoURI)
+	walletService := service.NewWalletService(walletRepo)
+	walletHandler := handler.NewWalletHandler(walletService)
+
+	// Cartões-presente e crédito em loja são resgatados como tenders
+	processors := service.DefaultTenderProcessors()
+	processors[model.GiftCardPayment] = service.NewGiftCardTenderProcessor(walletService)
+	processors[model.StoreCreditPayment] = service.NewStoreCreditTenderProcessor(walletService)
+
+	intentService := service.NewPaymentIntentService(intentRepo, processors, fraudService, orders.NewClient(orderServiceURL))
+	intentHandler := handler.NewPaymentIntentHandler(intentService)
+	go intentService.RunRecovery(time.Minute)
+
+	disputeRepo := repository.NewMongoDisputeRepository(mongoURI, kafkaBroker)
+	disputeService := service.NewDisputeService(disputeRepo, paymentRepo)
+	disputeHandler := handler.NewDisputeHandler(disputeService, os.Getenv("DISPUTE_WEBHOOK_SECRET"))
 
 	// Configurando as rotas
 	r.GET("/payment", paymentHandler.GetAllPayments)
@@ -45,6 +95,48 @@ func main() {
 	r.PUT("/payment/:id", paymentHandler.UpdatePayment)
 	r.DELETE("/payment/:id", paymentHandler.DeletePayment)
 
+	// Rotas do motor de fraude
+	r.GET("/fraud/rules", fraudHandler.GetRules)
+	authorized.PUT("/fraud/rules", fraudHandler.UpdateRules)
+	r.GET("/fraud/assessments", fraudHandler.ListAssessments)
+	r.GET("/fraud/reviews", fraudHandler.ListReviews)
+	authorized.POST("/fraud/reviews/:id/approve", fraudHandler.ApproveReview)
+	authorized.POST("/fraud/reviews/:id/deny", fraudHandler.DenyReview)
+	r.GET("/fraud/blacklist", fraudHandler.ListBlacklist)
+	authorized.POST("/fraud/blacklist", fraudHandler.AddBlacklistEntry)
+	authorized.DELETE("/fraud/blacklist/:id", fraudHandler.DeleteBlacklistEntry)
+
+	// Rotas de pagamento dividido (split tender)
+	r.POST("/payment-intents", intentHandler.CreateIntent)
+	r.GET("/payment-intents/:id", intentHandler.GetIntentByID)
+	r.GET("/payment-intents/order/:orderId", intentHandler.GetIntentByOrderID)
+	r.POST("/payment-intents/:id/tenders", intentHandler.AddTender)
+	r.DELETE("/payment-intents/:id/tenders/:tenderId", intentHandler.RemoveTender)
+	r.POST("/payment-intents/:id/authorize", intentHandler.Authorize)
+	r.POST("/payment-intents/:id/capture", intentHandler.Capture)
+	r.POST("/payment-intents/:id/cancel", intentHandler.Cancel)
+
+	// Rotas de cartões-presente e crédito em loja
+	staff.POST("/gift-cards", walletHandler.IssueGiftCard)
+	staff.POST("/gift-cards/:code/activate", walletHandler.ActivateGiftCard)
+	staff.POST("/gift-cards/:code/block", walletHandler.BlockGiftCard)
+	r.POST("/gift-cards/:code/balance", walletHandler.GetGiftCardBalance)
+	r.POST("/gift-cards/:code/transactions", walletHandler.ListGiftCardTransactions)
+	r.GET("/store-credit/:customerId", walletHandler.GetStoreCredit)
+	staff.POST("/store-credit/:customerId/credit", walletHandler.CreditStoreCredit)
+	r.GET("/store-credit/:customerId/transactions", walletHandler.ListStoreCreditTransactions)
+
+	// Rotas de disputas (chargebacks)
+	authorized.POST("/disputes", disputeHandler.OpenDispute)
+	r.GET("/disputes", disputeHandler.ListDisputes)
+	r.GET("/disputes/due", disputeHandler.ListDueSoon)
+	r.GET("/disputes/:id", disputeHandler.GetDisputeByID)
+	authorized.POST("/disputes/:id/evidence", disputeHandler.AddEvidence)
+	authorized.POST("/disputes/:id/submit", disputeHandler.SubmitEvidence)
+	authorized.POST("/disputes/:id/resolve", disputeHandler.Resolve)
+	r.GET("/disputes/:id/ledger", disputeHandler.ListLedgerEntries)
+	r.POST("/webhooks/disputes/:provider", disputeHandler.ProviderWebhook)
+
 	// Starting the server
 	r.Run(":8085")
 }
@@ -0,0 +1,63 @@
+package model
+
+type CardBrand string
+
+const (
+	Visa      CardBrand = "VISA"
+	Master    CardBrand = "MASTER"
+	Elo       CardBrand = "ELO"
+	Hipercard CardBrand = "HIPERCARD"
+	Amex      CardBrand = "AMEX"
+)
+
+// CardBrandRule define as faixas de BIN, os comprimentos válidos do número e
+// o tamanho do CVV de uma bandeira.
+type CardBrandRule struct {
+	Brand     CardBrand
+	BINRanges [][2]int
+	Lengths   []int
+	CVVLength int
+}
+
+// CardBrandRules é avaliada em ordem: Elo e Hipercard vêm antes de Visa e Master
+// porque parte de suas faixas fica dentro dos prefixos 4 e 5.
+// As faixas usam os 6 primeiros dígitos do cartão.
+var CardBrandRules = []CardBrandRule{
+	{
+		Brand: Elo,
+		BINRanges: [][2]int{
+			{401178, 401179}, {431274, 431274}, {438935, 438935}, {451416, 451416},
+			{457393, 457393}, {457631, 457632}, {504175, 504175}, {506699, 506778},
+			{509000, 509999}, {627780, 627780}, {636297, 636297}, {636368, 636368},
+			{650031, 650033}, {650035, 650051}, {650405, 650439}, {650485, 650538},
+			{650541, 650598}, {650700, 650718}, {650720, 650727}, {650901, 650920},
+			{651652, 651679}, {655000, 655019}, {655021, 655058},
+		},
+		Lengths:   []int{16},
+		CVVLength: 3,
+	},
+	{
+		Brand:     Hipercard,
+		BINRanges: [][2]int{{606282, 606282}, {384100, 384100}, {384140, 384140}, {384160, 384160}},
+		Lengths:   []int{13, 16, 19},
+		CVVLength: 3,
+	},
+	{
+		Brand:     Amex,
+		BINRanges: [][2]int{{340000, 349999}, {370000, 379999}},
+		Lengths:   []int{15},
+		CVVLength: 4,
+	},
+	{
+		Brand:     Master,
+		BINRanges: [][2]int{{510000, 559999}, {222100, 272099}},
+		Lengths:   []int{16},
+		CVVLength: 3,
+	},
+	{
+		Brand:     Visa,
+		BINRanges: [][2]int{{400000, 499999}},
+		Lengths:   []int{13, 16, 19},
+		CVVLength: 3,
+	},
+}
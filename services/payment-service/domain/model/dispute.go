@@ -0,0 +1,172 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Dispute representa um chargeback aberto pelo emissor sobre um pagamento.
+// OpenPaymentID repete o pagamento enquanto a disputa está aberta; o índice único
+// sobre ele impede duas disputas abertas para o mesmo pagamento.
+type Dispute struct {
+	ID                primitive.ObjectID  `json:"id" bson:"_id"`
+	PaymentID         primitive.ObjectID  `json:"paymentId" bson:"paymentId"`
+	OrderID           string              `json:"orderId" bson:"orderId"`
+	Provider          string              `json:"provider,omitempty" bson:"provider,omitempty"`
+	ProviderDisputeID string              `json:"providerDisputeId,omitempty" bson:"providerDisputeId,omitempty"`
+	ReasonCode        string              `json:"reasonCode" bson:"reasonCode"`
+	ReasonCategory    ReasonCategory      `json:"reasonCategory" bson:"reasonCategory"`
+	Amount            float64             `json:"amount" bson:"amount"`
+	Fee               float64             `json:"fee" bson:"fee"`
+	Status            DisputeStatus       `json:"status" bson:"status"`
+	Source            DisputeSource       `json:"source" bson:"source"`
+	EvidenceDueAt     time.Time           `json:"evidenceDueAt" bson:"evidenceDueAt"`
+	Evidence          []Evidence          `json:"evidence" bson:"evidence"`
+	History           []DisputeEvent      `json:"history" bson:"history"`
+	OpenedAt          time.Time           `json:"openedAt" bson:"openedAt"`
+	ResolvedAt        *time.Time          `json:"resolvedAt,omitempty" bson:"resolvedAt,omitempty"`
+	OpenPaymentID     *primitive.ObjectID `json:"-" bson:"openPaymentId,omitempty"`
+}
+
+type DisputeStatus string
+
+const (
+	DisputeOpened            DisputeStatus = "OPENED"
+	DisputeEvidenceSubmitted DisputeStatus = "EVIDENCE_SUBMITTED"
+	DisputeWon               DisputeStatus = "WON"
+	DisputeLost              DisputeStatus = "LOST"
+)
+
+// disputeTransitions lista os status de destino permitidos a partir de cada status.
+var disputeTransitions = map[DisputeStatus][]DisputeStatus{
+	DisputeOpened:            {DisputeEvidenceSubmitted, DisputeWon, DisputeLost},
+	DisputeEvidenceSubmitted: {DisputeWon, DisputeLost},
+}
+
+// CanTransition informa se a disputa pode passar para o status informado.
+func (d *Dispute) CanTransition(to DisputeStatus) bool {
+	for _, allowed := range disputeTransitions[d.Status] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpen informa se a disputa ainda aguarda a decisão do emissor
+func (d *Dispute) IsOpen() bool {
+	return d.Status == DisputeOpened || d.Status == DisputeEvidenceSubmitted
+}
+
+type DisputeSource string
+
+const (
+	DisputeSourceWebhook DisputeSource = "WEBHOOK"
+	DisputeSourceManual  DisputeSource = "MANUAL"
+)
+
+// DisputeEvent registra cada mudança de status da disputa.
+type DisputeEvent struct {
+	Status DisputeStatus `json:"status" bson:"status"`
+	Actor  string        `json:"actor,omitempty" bson:"actor,omitempty"`
+	Note   string        `json:"note,omitempty" bson:"note,omitempty"`
+	At     time.Time     `json:"at" bson:"at"`
+}
+
+// Evidence é um anexo enviado para contestar a disputa.
+type Evidence struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id"`
+	Type        EvidenceType       `json:"type" bson:"type"`
+	FileName    string             `json:"fileName" bson:"fileName"`
+	ContentType string             `json:"contentType" bson:"contentType"`
+	URL         string             `json:"url" bson:"url"`
+	Description string             `json:"description,omitempty" bson:"description,omitempty"`
+	UploadedAt  time.Time          `json:"uploadedAt" bson:"uploadedAt"`
+}
+
+type EvidenceType string
+
+const (
+	EvidenceReceipt       EvidenceType = "RECEIPT"
+	EvidenceShippingProof EvidenceType = "SHIPPING_PROOF"
+	EvidenceCommunication EvidenceType = "CUSTOMER_COMMUNICATION"
+	EvidenceRefundPolicy  EvidenceType = "REFUND_POLICY"
+	EvidenceOther         EvidenceType = "OTHER"
+)
+
+type ReasonCategory string
+
+const (
+	ReasonFraud           ReasonCategory = "FRAUD"
+	ReasonNotReceived     ReasonCategory = "PRODUCT_NOT_RECEIVED"
+	ReasonNotAsDescribed  ReasonCategory = "PRODUCT_NOT_AS_DESCRIBED"
+	ReasonDuplicate       ReasonCategory = "DUPLICATE"
+	ReasonCanceled        ReasonCategory = "CANCELED_RECURRING"
+	ReasonProcessingError ReasonCategory = "PROCESSING_ERROR"
+	ReasonOther           ReasonCategory = "OTHER"
+)
+
+// ReasonCodes mapeia os códigos de motivo das bandeiras para as categorias internas.
+var ReasonCodes = map[string]ReasonCategory{
+	// Visa
+	"10.4":   ReasonFraud,
+	"10.5":   ReasonFraud,
+	"13.1":   ReasonNotReceived,
+	"13.3":   ReasonNotAsDescribed,
+	"13.2":   ReasonCanceled,
+	"12.6.1": ReasonDuplicate,
+	"12.5":   ReasonProcessingError,
+	// Mastercard
+	"4837": ReasonFraud,
+	"4863": ReasonFraud,
+	"4855": ReasonNotReceived,
+	"4853": ReasonNotAsDescribed,
+	"4841": ReasonCanceled,
+	"4834": ReasonDuplicate,
+	"4831": ReasonProcessingError,
+}
+
+// ReasonCategoryFor retorna a categoria do código de motivo, ou OTHER se desconhecido.
+func ReasonCategoryFor(code string) ReasonCategory {
+	if category, ok := ReasonCodes[code]; ok {
+		return category
+	}
+	return ReasonOther
+}
+
+// LedgerEntry é um lançamento financeiro gerado pela resolução de disputas.
+type LedgerEntry struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	PaymentID primitive.ObjectID `json:"paymentId" bson:"paymentId"`
+	DisputeID primitive.ObjectID `json:"disputeId" bson:"disputeId"`
+	Type      LedgerEntryType    `json:"type" bson:"type"`
+	Amount    float64            `json:"amount" bson:"amount"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+type LedgerEntryType string
+
+const (
+	LedgerChargeback    LedgerEntryType = "CHARGEBACK"
+	LedgerChargebackFee LedgerEntryType = "CHARGEBACK_FEE"
+)
+
+// DisputeProviderEvent é o evento normalizado recebido pelo webhook do provedor de pagamento.
+type DisputeProviderEvent struct {
+	Type              DisputeEventType
+	ProviderDisputeID string
+	PaymentID         string
+	ReasonCode        string
+	Amount            float64
+	Fee               float64
+	EvidenceDueAt     time.Time
+}
+
+type DisputeEventType string
+
+const (
+	DisputeEventOpened DisputeEventType = "dispute.opened"
+	DisputeEventWon    DisputeEventType = "dispute.won"
+	DisputeEventLost   DisputeEventType = "dispute.lost"
+)
@@ -0,0 +1,170 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FraudRules guarda a configuração das regras de pontuação de fraude.
+// Cada regra tem um peso; a pontuação final é a soma dos pesos das regras acionadas.
+type FraudRules struct {
+	ID primitive.ObjectID `json:"id" bson:"_id"`
+
+	// Velocidade: número máximo de pagamentos dentro da janela
+	VelocityWindowMinutes   int `json:"velocityWindowMinutes" bson:"velocityWindowMinutes"`
+	MaxPaymentsPerCustomer  int `json:"maxPaymentsPerCustomer" bson:"maxPaymentsPerCustomer"`
+	MaxPaymentsPerCardToken int `json:"maxPaymentsPerCardToken" bson:"maxPaymentsPerCardToken"`
+	CustomerVelocityWeight  int `json:"customerVelocityWeight" bson:"customerVelocityWeight"`
+	CardVelocityWeight      int `json:"cardVelocityWeight" bson:"cardVelocityWeight"`
+
+	// Valor fora do padrão em relação ao histórico do cliente
+	AmountOutlierFactor float64 `json:"amountOutlierFactor" bson:"amountOutlierFactor"`
+	MinHistorySize      int     `json:"minHistorySize" bson:"minHistorySize"`
+	AmountOutlierWeight int     `json:"amountOutlierWeight" bson:"amountOutlierWeight"`
+
+	// Divergência entre endereço de cobrança e de entrega
+	AddressMismatchWeight int `json:"addressMismatchWeight" bson:"addressMismatchWeight"`
+
+	// Conta recém-criada
+	NewAccountDays   int `json:"newAccountDays" bson:"newAccountDays"`
+	NewAccountWeight int `json:"newAccountWeight" bson:"newAccountWeight"`
+
+	// Ocorrência em lista negra
+	BlacklistWeight int `json:"blacklistWeight" bson:"blacklistWeight"`
+
+	// Limites de decisão
+	ReviewThreshold int `json:"reviewThreshold" bson:"reviewThreshold"`
+	DenyThreshold   int `json:"denyThreshold" bson:"denyThreshold"`
+
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// DefaultFraudRules retorna a configuração usada enquanto nenhuma regra for cadastrada.
+func DefaultFraudRules() FraudRules {
+	return FraudRules{
+		VelocityWindowMinutes:   60,
+		MaxPaymentsPerCustomer:  5,
+		MaxPaymentsPerCardToken: 3,
+		CustomerVelocityWeight:  25,
+		CardVelocityWeight:      30,
+		AmountOutlierFactor:     3,
+		MinHistorySize:          3,
+		AmountOutlierWeight:     25,
+		AddressMismatchWeight:   15,
+		NewAccountDays:          7,
+		NewAccountWeight:        15,
+		BlacklistWeight:         100,
+		ReviewThreshold:         40,
+		DenyThreshold:           80,
+	}
+}
+
+type FraudDecision string
+
+const (
+	DecisionApproved FraudDecision = "APPROVED"
+	DecisionReview   FraudDecision = "REVIEW"
+	DecisionDenied   FraudDecision = "DENIED"
+)
+
+// FraudRuleHit descreve uma regra acionada durante a avaliação.
+type FraudRuleHit struct {
+	Rule   string `json:"rule" bson:"rule"`
+	Weight int    `json:"weight" bson:"weight"`
+	Detail string `json:"detail" bson:"detail"`
+}
+
+// FraudAssessment é o registro de auditoria de cada decisão do motor de fraude,
+// inclusive as decisões manuais tomadas na fila de revisão.
+type FraudAssessment struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id"`
+	PaymentID  primitive.ObjectID `json:"paymentId" bson:"paymentId"`
+	OrderID    string             `json:"orderId" bson:"orderId"`
+	CustomerID string             `json:"customerId" bson:"customerId"`
+	Amount     float64            `json:"amount" bson:"amount"`
+	Score      int                `json:"score" bson:"score"`
+	Hits       []FraudRuleHit     `json:"hits" bson:"hits"`
+	Decision   FraudDecision      `json:"decision" bson:"decision"`
+	Manual     bool               `json:"manual" bson:"manual"`
+	Reviewer   string             `json:"reviewer,omitempty" bson:"reviewer,omitempty"`
+	Note       string             `json:"note,omitempty" bson:"note,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+type ReviewStatus string
+
+const (
+	ReviewPending  ReviewStatus = "PENDING"
+	ReviewApproved ReviewStatus = "APPROVED"
+	ReviewDenied   ReviewStatus = "DENIED"
+	ReviewCanceled ReviewStatus = "CANCELED"
+)
+
+// FraudReview é um item da fila de revisão manual. IntentID é preenchido quando
+// a revisão é de um intent de pagamento dividido em vez de um pagamento avulso.
+type FraudReview struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id"`
+	PaymentID       primitive.ObjectID `json:"paymentId" bson:"paymentId"`
+	IntentID        string             `json:"intentId,omitempty" bson:"intentId,omitempty"`
+	OrderID         string             `json:"orderId" bson:"orderId"`
+	CustomerID      string             `json:"customerId" bson:"customerId"`
+	Amount          float64            `json:"amount" bson:"amount"`
+	Score           int                `json:"score" bson:"score"`
+	Hits            []FraudRuleHit     `json:"hits" bson:"hits"`
+	RequestedStatus PaymentStatus      `json:"requestedStatus" bson:"requestedStatus"`
+	Status          ReviewStatus       `json:"status" bson:"status"`
+	Reviewer        string             `json:"reviewer,omitempty" bson:"reviewer,omitempty"`
+	Note            string             `json:"note,omitempty" bson:"note,omitempty"`
+	CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
+	ResolvedAt      *time.Time         `json:"resolvedAt,omitempty" bson:"resolvedAt,omitempty"`
+}
+
+type BlacklistType string
+
+const (
+	BlacklistCustomer   BlacklistType = "CUSTOMER"
+	BlacklistCardToken  BlacklistType = "CARD_TOKEN"
+	BlacklistPostalCode BlacklistType = "POSTAL_CODE"
+)
+
+// BlacklistEntry é um valor bloqueado pelo motor de fraude.
+type BlacklistEntry struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	Type      BlacklistType      `json:"type" bson:"type"`
+	Value     string             `json:"value" bson:"value"`
+	Reason    string             `json:"reason" bson:"reason"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// Chave secreta do HMAC dos tokens de cartão, definida na inicialização do serviço
+var cardTokenKey []byte
+
+// SetCardTokenKey define a chave usada por TokenizeCard. Trocar a chave invalida os
+// tokens já gravados nos pagamentos e na lista negra.
+func SetCardTokenKey(key string) {
+	cardTokenKey = []byte(key)
+}
+
+// TokenizeCard gera um token irreversível do número do cartão para as regras de
+// velocidade e lista negra, sem depender do número em claro. O HMAC com chave
+// secreta impede recuperar o número testando o espaço pequeno de PANs.
+func TokenizeCard(cardNumber string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, cardNumber)
+	if digits == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, cardTokenKey)
+	mac.Write([]byte(digits))
+	return hex.EncodeToString(mac.Sum(nil))
+}
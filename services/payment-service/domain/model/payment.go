@@ -1,6 +1,7 @@
 package model
 
 import (
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,6 +15,20 @@ type Payment struct {
 	Method      PaymentMethod      `json:"method" bson:"method"`
 	Status      PaymentStatus      `json:"status" bson:"status"`
 	PaymentDate time.Time          `json:"paymentDate" bson:"paymentDate"`
+
+	// Dados usados pelo motor de fraude; CustomerSince é preenchido pelo serviço a partir do ID do cliente
+	BillingAddress  Address   `json:"billingAddress" bson:"billingAddress"`
+	ShippingAddress Address   `json:"shippingAddress" bson:"shippingAddress"`
+	CustomerSince   time.Time `json:"customerSince" bson:"customerSince"`
+	FraudScore      int       `json:"fraudScore" bson:"fraudScore"`
+}
+
+type Address struct {
+	Street     string `json:"street" bson:"street"`
+	City       string `json:"city" bson:"city"`
+	State      string `json:"state" bson:"state"`
+	PostalCode string `json:"postalCode" bson:"postalCode"`
+	Country    string `json:"country" bson:"country"`
 }
 
 type PaymentMethod struct {
@@ -21,21 +36,45 @@ type PaymentMethod struct {
 	CardNumber string      `json:"cardNumber,omitempty" bson:"cardNumber,omitempty"`
 	Expiry     string      `json:"expiry,omitempty" bson:"expiry,omitempty"`
 	CVV        string      `json:"cvv,omitempty" bson:"cvv,omitempty"`
+	CardToken  string      `json:"cardToken,omitempty" bson:"cardToken,omitempty"`
+	Brand      CardBrand   `json:"brand,omitempty" bson:"brand,omitempty"`
+	BIN        string      `json:"bin,omitempty" bson:"bin,omitempty"`
+	LastFour   string      `json:"lastFour,omitempty" bson:"lastFour,omitempty"`
+
+	// Dados de resgate de cartão-presente
+	GiftCardCode string `json:"giftCardCode,omitempty" bson:"giftCardCode,omitempty"`
+	GiftCardPIN  string `json:"-" bson:"-"`
+}
+
+// RedactCard mascara o número e descarta o CVV depois da validação. Ficam apenas
+// o token, a bandeira, o BIN e os últimos dígitos (ex.: 411111******1111).
+func (m *PaymentMethod) RedactCard() {
+	if len(m.CardNumber) > 10 {
+		m.CardNumber = m.CardNumber[:6] + strings.Repeat("*", len(m.CardNumber)-10) + m.CardNumber[len(m.CardNumber)-4:]
+	} else {
+		m.CardNumber = ""
+	}
+	m.CVV = ""
 }
 
 type PaymentType string
 
 const (
-	CreditCard PaymentType = "CREDIT_CARD"
-	DebitCard  PaymentType = "DEBIT_CARD"
-	PayPal     PaymentType = "PAYPAL"
+	CreditCard         PaymentType = "CREDIT_CARD"
+	DebitCard          PaymentType = "DEBIT_CARD"
+	PayPal             PaymentType = "PAYPAL"
+	Pix                PaymentType = "PIX"
+	GiftCardPayment    PaymentType = "GIFT_CARD"
+	StoreCreditPayment PaymentType = "STORE_CREDIT"
 )
 
 type PaymentStatus string
 
 const (
-	Unpaid    PaymentStatus = "UNPAID"
-	Processed PaymentStatus = "PROCESSED"
-	Failed    PaymentStatus = "FAILED"
-	Refunded  PaymentStatus = "REFUNDED"
+	Unpaid      PaymentStatus = "UNPAID"
+	Processed   PaymentStatus = "PROCESSED"
+	Failed      PaymentStatus = "FAILED"
+	Refunded    PaymentStatus = "REFUNDED"
+	UnderReview PaymentStatus = "UNDER_REVIEW"
+	ChargedBack PaymentStatus = "CHARGED_BACK"
 )
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PaymentIntent agrupa as formas de pagamento (tenders) usadas para pagar um pedido.
+// O pedido só é considerado pago quando a soma dos tenders capturados é igual ao total.
+// FraudApproved indica que os tenders atuais já passaram pelo motor de fraude.
+// Canceling marca o intent em PROCESSING por um cancelamento, que é concluído pela
+// recuperação se for interrompido.
+type PaymentIntent struct {
+	ID             primitive.ObjectID  `json:"id" bson:"_id"`
+	OrderID        string              `json:"orderId" bson:"orderId"`
+	CustomerID     string              `json:"customerId" bson:"customerId"`
+	OrderTotal     float64             `json:"orderTotal" bson:"orderTotal"`
+	Tenders        []Tender            `json:"tenders" bson:"tenders"`
+	Status         PaymentIntentStatus `json:"status" bson:"status"`
+	CapturedAmount float64             `json:"capturedAmount" bson:"capturedAmount"`
+	LastError      string              `json:"lastError,omitempty" bson:"lastError,omitempty"`
+	FraudApproved  bool                `json:"fraudApproved" bson:"fraudApproved"`
+	Canceling      bool                `json:"canceling,omitempty" bson:"canceling,omitempty"`
+	Version        int                 `json:"version" bson:"version"`
+	CreatedAt      time.Time           `json:"createdAt" bson:"createdAt"`
+	UpdatedAt      time.Time           `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Tender é uma das formas de pagamento de um PaymentIntent, autorizada separadamente.
+type Tender struct {
+	ID                primitive.ObjectID `json:"id" bson:"_id"`
+	Type              PaymentType        `json:"type" bson:"type"`
+	Amount            float64            `json:"amount" bson:"amount"`
+	Method            PaymentMethod      `json:"method" bson:"method"`
+	Status            TenderStatus       `json:"status" bson:"status"`
+	AuthorizationCode string             `json:"authorizationCode,omitempty" bson:"authorizationCode,omitempty"`
+	FailureReason     string             `json:"failureReason,omitempty" bson:"failureReason,omitempty"`
+	UpdatedAt         time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+type PaymentIntentStatus string
+
+const (
+	IntentOpen       PaymentIntentStatus = "OPEN"
+	IntentProcessing PaymentIntentStatus = "PROCESSING"
+	IntentReview     PaymentIntentStatus = "UNDER_REVIEW"
+	IntentAuthorized PaymentIntentStatus = "AUTHORIZED"
+	IntentPaid       PaymentIntentStatus = "PAID"
+	IntentCanceled   PaymentIntentStatus = "CANCELED"
+)
+
+type TenderStatus string
+
+const (
+	TenderPending    TenderStatus = "PENDING"
+	TenderAuthorized TenderStatus = "AUTHORIZED"
+	TenderCaptured   TenderStatus = "CAPTURED"
+	TenderFailed     TenderStatus = "FAILED"
+	TenderVoided     TenderStatus = "VOIDED"
+	TenderRefunded   TenderStatus = "REFUNDED"
+)
+
+// PaymentIntentEvent é o evento publicado para os demais serviços. Leva apenas
+// identificadores, valores e status; os dados dos tenders ficam no payment-service.
+type PaymentIntentEvent struct {
+	ID             string              `json:"id"`
+	OrderID        string              `json:"orderId"`
+	CustomerID     string              `json:"customerId"`
+	OrderTotal     float64             `json:"orderTotal"`
+	CapturedAmount float64             `json:"capturedAmount"`
+	Status         PaymentIntentStatus `json:"status"`
+}
+
+// Event monta o evento publicado com o estado atual do intent.
+func (p *PaymentIntent) Event() PaymentIntentEvent {
+	return PaymentIntentEvent{
+		ID:             p.ID.Hex(),
+		OrderID:        p.OrderID,
+		CustomerID:     p.CustomerID,
+		OrderTotal:     p.OrderTotal,
+		CapturedAmount: p.CapturedAmount,
+		Status:         p.Status,
+	}
+}
+
+// AllocatedAmount soma os tenders que ainda contam para o pagamento do pedido.
+func (p *PaymentIntent) AllocatedAmount() float64 {
+	var total float64
+	for _, t := range p.Tenders {
+		if t.Status != TenderFailed && t.Status != TenderVoided && t.Status != TenderRefunded {
+			total += t.Amount
+		}
+	}
+	return RoundMoney(total)
+}
+
+// RemainingAmount é o valor do pedido ainda sem tender associado.
+func (p *PaymentIntent) RemainingAmount() float64 {
+	return RoundMoney(p.OrderTotal - p.AllocatedAmount())
+}
+
+// RoundMoney arredonda um valor monetário para centavos.
+func RoundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
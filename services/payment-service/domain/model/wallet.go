@@ -0,0 +1,123 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GiftCard é um cartão-presente com saldo próprio, resgatável como tender.
+// Após várias tentativas com PIN errado o cartão fica travado até LockedUntil.
+type GiftCard struct {
+	ID               primitive.ObjectID `json:"id" bson:"_id"`
+	Code             string             `json:"code" bson:"code"`
+	PINHash          string             `json:"-" bson:"pinHash"`
+	InitialAmount    float64            `json:"initialAmount" bson:"initialAmount"`
+	Balance          float64            `json:"balance" bson:"balance"`
+	Status           GiftCardStatus     `json:"status" bson:"status"`
+	IssuedChannel    SalesChannel       `json:"issuedChannel" bson:"issuedChannel"`
+	ActivatedChannel SalesChannel       `json:"activatedChannel,omitempty" bson:"activatedChannel,omitempty"`
+	ActivatedAt      *time.Time         `json:"activatedAt,omitempty" bson:"activatedAt,omitempty"`
+	ExpiresAt        time.Time          `json:"expiresAt" bson:"expiresAt"`
+	FailedPINs       int                `json:"-" bson:"failedPins"`
+	LockedUntil      *time.Time         `json:"lockedUntil,omitempty" bson:"lockedUntil,omitempty"`
+	CreatedAt        time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+type GiftCardStatus string
+
+const (
+	GiftCardInactive GiftCardStatus = "INACTIVE"
+	GiftCardActive   GiftCardStatus = "ACTIVE"
+	GiftCardBlocked  GiftCardStatus = "BLOCKED"
+)
+
+type SalesChannel string
+
+const (
+	ChannelStore  SalesChannel = "STORE"
+	ChannelOnline SalesChannel = "ONLINE"
+	ChannelApp    SalesChannel = "APP"
+)
+
+// IsExpired informa se o cartão-presente passou da data de validade.
+func (g *GiftCard) IsExpired(now time.Time) bool {
+	return !g.ExpiresAt.IsZero() && now.After(g.ExpiresAt)
+}
+
+// CanDebit informa se o valor pode ser debitado: cartão ativo, dentro da validade e
+// com saldo suficiente. É a mesma regra do débito atômico no repositório.
+func (g *GiftCard) CanDebit(amount float64, now time.Time) bool {
+	return g.Status == GiftCardActive && !g.IsExpired(now) && g.Balance >= amount
+}
+
+// IsLocked informa se o cartão está travado por tentativas de PIN erradas.
+func (g *GiftCard) IsLocked(now time.Time) bool {
+	return g.LockedUntil != nil && now.Before(*g.LockedUntil)
+}
+
+var giftCardPINKey []byte
+
+// SetGiftCardPINKey define a chave usada por HashGiftCardPIN. Trocar a chave invalida
+// os PINs dos cartões já emitidos.
+func SetGiftCardPINKey(key string) {
+	giftCardPINKey = []byte(key)
+}
+
+// HashGiftCardPIN gera o HMAC do PIN com o código do cartão. Como o PIN tem só 6
+// dígitos, sem a chave secreta o hash seria revertido testando todas as combinações.
+func HashGiftCardPIN(code, pin string) string {
+	mac := hmac.New(sha256.New, giftCardPINKey)
+	mac.Write([]byte(code + ":" + pin))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ShouldLock informa se as tentativas erradas já contadas atingiram o limite que trava o cartão.
+func (g *GiftCard) ShouldLock(maxAttempts int) bool {
+	return g.FailedPINs >= maxAttempts
+}
+
+// StoreCredit é o saldo de crédito em loja de um cliente (ex.: devoluções).
+type StoreCredit struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id"`
+	CustomerID string             `json:"customerId" bson:"customerId"`
+	Balance    float64            `json:"balance" bson:"balance"`
+	UpdatedAt  time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+type WalletAccountType string
+
+const (
+	AccountGiftCard    WalletAccountType = "GIFT_CARD"
+	AccountStoreCredit WalletAccountType = "STORE_CREDIT"
+)
+
+type WalletTransactionType string
+
+const (
+	TxIssue    WalletTransactionType = "ISSUE"
+	TxActivate WalletTransactionType = "ACTIVATE"
+	TxCredit   WalletTransactionType = "CREDIT"
+	TxHold     WalletTransactionType = "HOLD"
+	TxCapture  WalletTransactionType = "CAPTURE"
+	TxRelease  WalletTransactionType = "RELEASE"
+	TxBlock    WalletTransactionType = "BLOCK"
+)
+
+// WalletTransaction é um lançamento imutável do log de saldo. Amount é negativo
+// nos débitos; BalanceAfter registra o saldo resultante.
+type WalletTransaction struct {
+	ID           primitive.ObjectID    `json:"id" bson:"_id"`
+	AccountType  WalletAccountType     `json:"accountType" bson:"accountType"`
+	AccountKey   string                `json:"accountKey" bson:"accountKey"`
+	Type         WalletTransactionType `json:"type" bson:"type"`
+	Amount       float64               `json:"amount" bson:"amount"`
+	BalanceAfter float64               `json:"balanceAfter" bson:"balanceAfter"`
+	Reference    string                `json:"reference,omitempty" bson:"reference,omitempty"`
+	Channel      SalesChannel          `json:"channel,omitempty" bson:"channel,omitempty"`
+	Note         string                `json:"note,omitempty" bson:"note,omitempty"`
+	CreatedAt    time.Time             `json:"createdAt" bson:"createdAt"`
+}
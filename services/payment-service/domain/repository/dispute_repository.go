@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"Varejo-Golang-Microservices/services/payment-service/infra/db"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoDisputeRepository struct {
+	client *mongo.Client
+	kafka  *kafka.Producer
+}
+
+func NewMongoDisputeRepository(mongoURI string, kafkaBroker string) *MongoDisputeRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	config := &kafka.ConfigMap{
+		"bootstrap.servers": kafkaBroker,
+	}
+	producer, err := kafka.NewProducer(config)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao Kafka: %v", err)
+	}
+
+	r := &MongoDisputeRepository{
+		client: client,
+		kafka:  producer,
+	}
+
+	// Uma única disputa aberta por pagamento; as encerradas não têm o campo
+	collection := client.Database("paymentDB").Collection("disputes")
+	_, err = collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "openPaymentId", Value: 1}},
+		Options: options.Index().SetUnique(true).SetSparse(true),
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índice disputes.openPaymentId: %v\n", err)
+	}
+
+	return r
+}
+
+func (r *MongoDisputeRepository) Save(dispute *model.Dispute) error {
+	collection := r.client.Database("paymentDB").Collection("disputes")
+
+	_, err := collection.InsertOne(context.TODO(), dispute)
+	if err != nil {
+		log.Printf("Erro ao inserir disputa no MongoDB: %v\n", err)
+		return err
+	}
+
+	return r.publish(dispute)
+}
+
+func (r *MongoDisputeRepository) FindByID(id string) (*model.Dispute, error) {
+	collection := r.client.Database("paymentDB").Collection("disputes")
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("ID da disputa inválido")
+	}
+
+	return r.findOne(collection, bson.M{"_id": objID})
+}
+
+// Busca a disputa pelo identificador do provedor, usado para tornar os webhooks idempotentes
+func (r *MongoDisputeRepository) FindByProviderID(provider, providerDisputeID string) (*model.Dispute, error) {
+	collection := r.client.Database("paymentDB").Collection("disputes")
+
+	return r.findOne(collection, bson.M{"provider": provider, "providerDisputeId": providerDisputeID})
+}
+
+func (r *MongoDisputeRepository) findOne(collection *mongo.Collection, filter bson.M) (*model.Dispute, error) {
+	var dispute model.Dispute
+	err := collection.FindOne(context.TODO(), filter).Decode(&dispute)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &dispute, nil
+}
+
+// Lista disputas filtrando por status e pagamento quando informados
+func (r *MongoDisputeRepository) List(status model.DisputeStatus, paymentID string) ([]*model.Dispute, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	if paymentID != "" {
+		objID, err := primitive.ObjectIDFromHex(paymentID)
+		if err != nil {
+			return nil, errors.New("ID do pagamento inválido")
+		}
+		filter["paymentId"] = objID
+	}
+
+	return r.find(filter, options.Find().SetSort(bson.M{"openedAt": -1}))
+}
+
+// Lista as disputas ainda em aberto cujo prazo de evidências vence até a data informada
+func (r *MongoDisputeRepository) ListDueBefore(limit time.Time) ([]*model.Dispute, error) {
+	filter := bson.M{
+		"status":        model.DisputeOpened,
+		"evidenceDueAt": bson.M{"$lte": limit},
+	}
+
+	return r.find(filter, options.Find().SetSort(bson.M{"evidenceDueAt": 1}))
+}
+
+func (r *MongoDisputeRepository) find(filter bson.M, opts *options.FindOptions) ([]*model.Dispute, error) {
+	collection := r.client.Database("paymentDB").Collection("disputes")
+
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var disputes []*model.Dispute
+	for cursor.Next(context.TODO()) {
+		var dispute model.Dispute
+		if err := cursor.Decode(&dispute); err != nil {
+			return nil, err
+		}
+		disputes = append(disputes, &dispute)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return disputes, nil
+}
+
+// Update grava a disputa somente se o status não mudou desde a leitura,
+// evitando que duas resoluções concorrentes sejam aplicadas.
+func (r *MongoDisputeRepository) Update(dispute *model.Dispute, previousStatus model.DisputeStatus) error {
+	collection := r.client.Database("paymentDB").Collection("disputes")
+
+	filter := bson.M{"_id": dispute.ID, "status": previousStatus}
+	updateData := bson.M{
+		"status":        dispute.Status,
+		"evidence":      dispute.Evidence,
+		"history":       dispute.History,
+		"evidenceDueAt": dispute.EvidenceDueAt,
+		"resolvedAt":    dispute.ResolvedAt,
+	}
+
+	update := bson.M{"$set": updateData}
+	if !dispute.IsOpen() {
+		dispute.OpenPaymentID = nil
+		update["$unset"] = bson.M{"openPaymentId": ""}
+	}
+
+	result, err := collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("disputa alterada por outra operação")
+	}
+
+	if dispute.Status != previousStatus {
+		return r.publish(dispute)
+	}
+
+	return nil
+}
+
+// InsertLedgerEntry grava o lançamento uma única vez por disputa e tipo, de modo
+// que repetir o encerramento de uma disputa não duplica o estorno nem a tarifa
+func (r *MongoDisputeRepository) InsertLedgerEntry(entry *model.LedgerEntry) error {
+	collection := r.client.Database("paymentDB").Collection("ledger_entries")
+
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+
+	filter := bson.M{"disputeId": entry.DisputeID, "type": entry.Type}
+	opts := options.Update().SetUpsert(true)
+	_, err := collection.UpdateOne(context.TODO(), filter, bson.M{"$setOnInsert": entry}, opts)
+	return err
+}
+
+func (r *MongoDisputeRepository) ListLedgerEntries(disputeID primitive.ObjectID) ([]*model.LedgerEntry, error) {
+	collection := r.client.Database("paymentDB").Collection("ledger_entries")
+
+	cursor, err := collection.Find(context.TODO(), bson.M{"disputeId": disputeID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var entries []*model.LedgerEntry
+	for cursor.Next(context.TODO()) {
+		var entry model.LedgerEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, cursor.Err()
+}
+
+// Publica a disputa no tópico de disputas a cada abertura ou mudança de status
+func (r *MongoDisputeRepository) publish(dispute *model.Dispute) error {
+	disputeJSON, err := json.Marshal(dispute)
+	if err != nil {
+		log.Printf("Erro ao organizar a disputa: %v", err)
+		return err
+	}
+
+	topic := "Dispute_Topic"
+	message := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Value:          disputeJSON,
+	}
+
+	deliveryChan := make(chan kafka.Event)
+	defer close(deliveryChan)
+
+	err = r.kafka.Produce(message, deliveryChan)
+	if err != nil {
+		log.Printf("Erro ao produzir mensagem para Kafka: %v\n", err)
+		return err
+	}
+
+	e := <-deliveryChan
+	if ev, ok := e.(*kafka.Message); ok && ev.TopicPartition.Error != nil {
+		log.Printf("Erro ao enviar a mensagem ao Kafka: %v\n", ev.TopicPartition.Error)
+		return ev.TopicPartition.Error
+	}
+
+	return nil
+}
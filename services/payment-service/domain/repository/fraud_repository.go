@@ -0,0 +1,329 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"Varejo-Golang-Microservices/services/payment-service/infra/db"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoFraudRepository struct {
+	client *mongo.Client
+}
+
+func NewMongoFraudRepository(mongoURI string) *MongoFraudRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	return &MongoFraudRepository{
+		client: client,
+	}
+}
+
+// Busca as regras cadastradas; retorna as regras padrão quando não houver nenhuma
+func (r *MongoFraudRepository) GetRules() (*model.FraudRules, error) {
+	collection := r.client.Database("paymentDB").Collection("fraud_rules")
+
+	var rules model.FraudRules
+	err := collection.FindOne(context.TODO(), bson.M{}).Decode(&rules)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			defaults := model.DefaultFraudRules()
+			return &defaults, nil
+		}
+		return nil, err
+	}
+
+	return &rules, nil
+}
+
+// Substitui as regras cadastradas, mantendo um único documento de configuração
+func (r *MongoFraudRepository) SaveRules(rules *model.FraudRules) error {
+	collection := r.client.Database("paymentDB").Collection("fraud_rules")
+
+	existing, err := r.GetRules()
+	if err != nil {
+		return err
+	}
+
+	if existing.ID.IsZero() {
+		rules.ID = primitive.NewObjectID()
+	} else {
+		rules.ID = existing.ID
+	}
+	rules.UpdatedAt = time.Now()
+
+	opts := options.Replace().SetUpsert(true)
+	_, err = collection.ReplaceOne(context.TODO(), bson.M{"_id": rules.ID}, rules, opts)
+	return err
+}
+
+// Registra uma decisão do motor de fraude para auditoria
+func (r *MongoFraudRepository) SaveAssessment(assessment *model.FraudAssessment) error {
+	collection := r.client.Database("paymentDB").Collection("fraud_assessments")
+
+	if assessment.ID.IsZero() {
+		assessment.ID = primitive.NewObjectID()
+	}
+
+	_, err := collection.InsertOne(context.TODO(), assessment)
+	if err != nil {
+		log.Printf("Erro ao registrar decisão de fraude no MongoDB: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// Lista as decisões registradas, opcionalmente filtradas por pagamento
+func (r *MongoFraudRepository) ListAssessments(paymentID string) ([]*model.FraudAssessment, error) {
+	collection := r.client.Database("paymentDB").Collection("fraud_assessments")
+
+	filter := bson.M{}
+	if paymentID != "" {
+		objID, err := primitive.ObjectIDFromHex(paymentID)
+		if err != nil {
+			return nil, errors.New("ID do pagamento inválido")
+		}
+		filter["paymentId"] = objID
+	}
+
+	opts := options.Find().SetSort(bson.M{"createdAt": -1})
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var assessments []*model.FraudAssessment
+	for cursor.Next(context.TODO()) {
+		var assessment model.FraudAssessment
+		if err := cursor.Decode(&assessment); err != nil {
+			return nil, err
+		}
+		assessments = append(assessments, &assessment)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return assessments, nil
+}
+
+// Adiciona um pagamento à fila de revisão manual
+func (r *MongoFraudRepository) SaveReview(review *model.FraudReview) error {
+	collection := r.client.Database("paymentDB").Collection("fraud_reviews")
+
+	if review.ID.IsZero() {
+		review.ID = primitive.NewObjectID()
+	}
+
+	_, err := collection.InsertOne(context.TODO(), review)
+	return err
+}
+
+func (r *MongoFraudRepository) FindReviewByID(id string) (*model.FraudReview, error) {
+	collection := r.client.Database("paymentDB").Collection("fraud_reviews")
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("ID da revisão inválido")
+	}
+
+	var review model.FraudReview
+	err = collection.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&review)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+// Lista a fila de revisão pelo status informado
+func (r *MongoFraudRepository) ListReviews(status model.ReviewStatus) ([]*model.FraudReview, error) {
+	collection := r.client.Database("paymentDB").Collection("fraud_reviews")
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.M{"createdAt": 1})
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var reviews []*model.FraudReview
+	for cursor.Next(context.TODO()) {
+		var review model.FraudReview
+		if err := cursor.Decode(&review); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, &review)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// Resolve uma revisão pendente. A condição sobre o status impede que duas
+// decisões concorrentes sejam aplicadas à mesma revisão.
+func (r *MongoFraudRepository) ResolveReview(review *model.FraudReview) error {
+	collection := r.client.Database("paymentDB").Collection("fraud_reviews")
+
+	filter := bson.M{"_id": review.ID, "status": model.ReviewPending}
+	update := bson.M{"$set": bson.M{
+		"status":     review.Status,
+		"reviewer":   review.Reviewer,
+		"note":       review.Note,
+		"resolvedAt": review.ResolvedAt,
+	}}
+
+	result, err := collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("revisão já foi resolvida")
+	}
+
+	return nil
+}
+
+// Devolve à fila uma revisão resolvida quando a decisão não pôde ser aplicada ao
+// pagamento ou ao intent. Só reabre se a revisão ainda tiver a decisão gravada.
+func (r *MongoFraudRepository) ReopenReview(review *model.FraudReview) error {
+	collection := r.client.Database("paymentDB").Collection("fraud_reviews")
+
+	filter := bson.M{"_id": review.ID, "status": review.Status}
+	update := bson.M{
+		"$set":   bson.M{"status": model.ReviewPending},
+		"$unset": bson.M{"reviewer": "", "note": "", "resolvedAt": ""},
+	}
+
+	_, err := collection.UpdateOne(context.TODO(), filter, update)
+	return err
+}
+
+// Encerra a revisão pendente de um intent cancelado antes da decisão manual
+func (r *MongoFraudRepository) CancelIntentReview(intentID string, note string, at time.Time) error {
+	collection := r.client.Database("paymentDB").Collection("fraud_reviews")
+
+	filter := bson.M{"intentId": intentID, "status": model.ReviewPending}
+	update := bson.M{"$set": bson.M{
+		"status":     model.ReviewCanceled,
+		"note":       note,
+		"resolvedAt": at,
+	}}
+
+	_, err := collection.UpdateMany(context.TODO(), filter, update)
+	return err
+}
+
+func (r *MongoFraudRepository) SaveBlacklistEntry(entry *model.BlacklistEntry) error {
+	collection := r.client.Database("paymentDB").Collection("fraud_blacklist")
+
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+
+	_, err := collection.InsertOne(context.TODO(), entry)
+	return err
+}
+
+func (r *MongoFraudRepository) ListBlacklist() ([]*model.BlacklistEntry, error) {
+	collection := r.client.Database("paymentDB").Collection("fraud_blacklist")
+
+	cursor, err := collection.Find(context.TODO(), bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var entries []*model.BlacklistEntry
+	for cursor.Next(context.TODO()) {
+		var entry model.BlacklistEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Busca as entradas da lista negra que correspondem a algum dos valores informados
+func (r *MongoFraudRepository) FindBlacklistHits(values map[model.BlacklistType]string) ([]*model.BlacklistEntry, error) {
+	collection := r.client.Database("paymentDB").Collection("fraud_blacklist")
+
+	var conditions bson.A
+	for t, v := range values {
+		if v == "" {
+			continue
+		}
+		conditions = append(conditions, bson.M{"type": t, "value": v})
+	}
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := collection.Find(context.TODO(), bson.M{"$or": conditions})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var entries []*model.BlacklistEntry
+	for cursor.Next(context.TODO()) {
+		var entry model.BlacklistEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, cursor.Err()
+}
+
+func (r *MongoFraudRepository) DeleteBlacklistEntry(id string) error {
+	collection := r.client.Database("paymentDB").Collection("fraud_blacklist")
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := collection.DeleteOne(context.TODO(), bson.M{"_id": objID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("nenhuma entrada encontrada com o ID fornecido")
+	}
+
+	return nil
+}
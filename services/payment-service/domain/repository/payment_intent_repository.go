@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"Varejo-Golang-Microservices/services/payment-service/infra/db"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrIntentConflict indica que o intent foi alterado por outra requisição desde a leitura.
+var ErrIntentConflict = errors.New("intent de pagamento alterado por outra operação")
+
+type MongoPaymentIntentRepository struct {
+	client *mongo.Client
+	kafka  *kafka.Producer
+}
+
+func NewMongoPaymentIntentRepository(mongoURI string, kafkaBroker string) *MongoPaymentIntentRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	config := &kafka.ConfigMap{
+		"bootstrap.servers": kafkaBroker,
+	}
+	producer, err := kafka.NewProducer(config)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao Kafka: %v", err)
+	}
+
+	return &MongoPaymentIntentRepository{
+		client: client,
+		kafka:  producer,
+	}
+}
+
+func (r *MongoPaymentIntentRepository) Save(intent *model.PaymentIntent) error {
+	collection := r.client.Database("paymentDB").Collection("payment_intents")
+
+	_, err := collection.InsertOne(context.TODO(), intent)
+	if err != nil {
+		log.Printf("Erro ao inserir intent de pagamento no MongoDB: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *MongoPaymentIntentRepository) FindByID(id string) (*model.PaymentIntent, error) {
+	collection := r.client.Database("paymentDB").Collection("payment_intents")
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("ID do intent inválido")
+	}
+
+	var intent model.PaymentIntent
+	err = collection.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&intent)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &intent, nil
+}
+
+// Busca o intent ainda não cancelado de um pedido
+func (r *MongoPaymentIntentRepository) FindActiveByOrderID(orderID string) (*model.PaymentIntent, error) {
+	collection := r.client.Database("paymentDB").Collection("payment_intents")
+
+	filter := bson.M{"orderId": orderID, "status": bson.M{"$ne": model.IntentCanceled}}
+
+	var intent model.PaymentIntent
+	err := collection.FindOne(context.TODO(), filter).Decode(&intent)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &intent, nil
+}
+
+// Update grava o intent somente se a versão não mudou desde a leitura
+// e incrementa a versão em caso de sucesso.
+func (r *MongoPaymentIntentRepository) Update(intent *model.PaymentIntent) error {
+	collection := r.client.Database("paymentDB").Collection("payment_intents")
+
+	filter := bson.M{"_id": intent.ID, "version": intent.Version}
+	updateData := bson.M{
+		"tenders":        intent.Tenders,
+		"status":         intent.Status,
+		"capturedAmount": intent.CapturedAmount,
+		"lastError":      intent.LastError,
+		"fraudApproved":  intent.FraudApproved,
+		"canceling":      intent.Canceling,
+		"updatedAt":      intent.UpdatedAt,
+		"version":        intent.Version + 1,
+	}
+
+	result, err := collection.UpdateOne(context.TODO(), filter, bson.M{"$set": updateData})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrIntentConflict
+	}
+
+	intent.Version++
+	return nil
+}
+
+// FindStaleProcessing busca os intents que estão em PROCESSING desde antes do
+// instante informado, ou seja, cuja autorização ou captura foi interrompida
+func (r *MongoPaymentIntentRepository) FindStaleProcessing(before time.Time) ([]*model.PaymentIntent, error) {
+	collection := r.client.Database("paymentDB").Collection("payment_intents")
+
+	filter := bson.M{"status": model.IntentProcessing, "updatedAt": bson.M{"$lt": before}}
+	cursor, err := collection.Find(context.TODO(), filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var intents []*model.PaymentIntent
+	if err := cursor.All(context.TODO(), &intents); err != nil {
+		return nil, err
+	}
+
+	return intents, nil
+}
+
+// ResolveReview aplica a decisão da revisão de fraude a um intent em UNDER_REVIEW:
+// aprovado, volta a OPEN liberado para autorização; recusado, é cancelado
+func (r *MongoPaymentIntentRepository) ResolveReview(id primitive.ObjectID, approved bool, reason string) error {
+	collection := r.client.Database("paymentDB").Collection("payment_intents")
+
+	updateData := bson.M{"updatedAt": time.Now()}
+	if approved {
+		updateData["status"] = model.IntentOpen
+		updateData["fraudApproved"] = true
+		updateData["lastError"] = ""
+	} else {
+		updateData["status"] = model.IntentCanceled
+		updateData["lastError"] = reason
+	}
+
+	filter := bson.M{"_id": id, "status": model.IntentReview}
+	result, err := collection.UpdateOne(context.TODO(), filter, bson.M{"$set": updateData, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("intent de pagamento não está em revisão")
+	}
+
+	return nil
+}
+
+// Publica o estado do intent para os demais serviços (ex.: order-service marca o pedido como pago).
+// Os tenders não são publicados: o evento leva apenas IDs, valores e status.
+func (r *MongoPaymentIntentRepository) Publish(intent *model.PaymentIntent) error {
+	intentJSON, err := json.Marshal(intent.Event())
+	if err != nil {
+		log.Printf("Erro ao organizar o intent de pagamento: %v", err)
+		return err
+	}
+
+	topic := "Payment_Intent_Topic"
+	message := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Value:          intentJSON,
+	}
+
+	deliveryChan := make(chan kafka.Event)
+	defer close(deliveryChan)
+
+	err = r.kafka.Produce(message, deliveryChan)
+	if err != nil {
+		log.Printf("Erro ao produzir mensagem para Kafka: %v\n", err)
+		return err
+	}
+
+	e := <-deliveryChan
+	if ev, ok := e.(*kafka.Message); ok && ev.TopicPartition.Error != nil {
+		log.Printf("Erro ao enviar a mensagem ao Kafka: %v\n", ev.TopicPartition.Error)
+		return ev.TopicPartition.Error
+	}
+
+	return nil
+}
@@ -7,13 +7,18 @@ import (
 	"encoding/json"
 	"errors"
 	"log"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// ErrPaymentConflict indica que o status do pagamento mudou entre a leitura e a gravação
+var ErrPaymentConflict = errors.New("pagamento alterado por outra operação")
+
 type MongoPaymentRepository struct {
 	client *mongo.Client
 	kafka  *kafka.Producer
@@ -136,26 +141,32 @@ func (r *MongoPaymentRepository) Save(payment *model.Payment) error {
 	return nil
 }
 
-func (r *MongoPaymentRepository) Update(payment *model.Payment) error {
+// Update grava o valor, o método e o resultado da reavaliação de fraude. A gravação só
+// acontece se o status ainda for from; caso contrário retorna ErrPaymentConflict.
+// A data do pagamento é a do servidor na criação e não vem da atualização.
+func (r *MongoPaymentRepository) Update(payment *model.Payment, from model.PaymentStatus) error {
 	collection := r.client.Database("paymentDB").Collection("payments")
 
-	// Usei o ID para filtrar
-	filter := bson.M{"_id": payment.ID}
+	// Usei o ID e o status lido para filtrar
+	filter := bson.M{"_id": payment.ID, "status": from}
 
-	// Dados de atualização, mencionando todos os campos no objeto Payment
 	updateData := bson.M{
-		"amount":      payment.Amount,
-		"method":      payment.Method,
-		"status":      payment.Status,
-		"paymentDate": payment.PaymentDate,
+		"amount":     payment.Amount,
+		"method":     payment.Method,
+		"status":     payment.Status,
+		"fraudScore": payment.FraudScore,
 	}
 
 	// Atualiza o documento
-	_, err := collection.UpdateOne(context.TODO(), filter, bson.M{"$set": updateData})
+	result, err := collection.UpdateOne(context.TODO(), filter, bson.M{"$set": updateData})
 	if err != nil {
 		return err
 	}
 
+	if result.MatchedCount == 0 {
+		return ErrPaymentConflict
+	}
+
 	return nil
 }
 
@@ -180,3 +191,67 @@ func (r *MongoPaymentRepository) Delete(id string) error {
 
 	return nil
 }
+
+// Atualiza apenas o status do pagamento
+func (r *MongoPaymentRepository) UpdateStatus(id primitive.ObjectID, status model.PaymentStatus) error {
+	collection := r.client.Database("paymentDB").Collection("payments")
+
+	result, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": bson.M{"status": status}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("pagamento não encontrado")
+	}
+
+	return nil
+}
+
+// Conta os pagamentos de um cliente a partir de uma data, sem contar o pagamento
+// exclude (o próprio pagamento quando ele é reavaliado)
+func (r *MongoPaymentRepository) CountByCustomerSince(customerID string, since time.Time, exclude primitive.ObjectID) (int64, error) {
+	collection := r.client.Database("paymentDB").Collection("payments")
+
+	filter := bson.M{"customerId": customerID, "paymentDate": bson.M{"$gte": since}, "_id": bson.M{"$ne": exclude}}
+	return collection.CountDocuments(context.TODO(), filter)
+}
+
+// Conta os pagamentos feitos com o mesmo token de cartão a partir de uma data, sem
+// contar o pagamento exclude
+func (r *MongoPaymentRepository) CountByCardTokenSince(cardToken string, since time.Time, exclude primitive.ObjectID) (int64, error) {
+	collection := r.client.Database("paymentDB").Collection("payments")
+
+	filter := bson.M{"method.cardToken": cardToken, "paymentDate": bson.M{"$gte": since}, "_id": bson.M{"$ne": exclude}}
+	return collection.CountDocuments(context.TODO(), filter)
+}
+
+// Retorna os valores dos últimos pagamentos processados de um cliente, sem o pagamento exclude
+func (r *MongoPaymentRepository) AmountHistory(customerID string, limit int64, exclude primitive.ObjectID) ([]float64, error) {
+	collection := r.client.Database("paymentDB").Collection("payments")
+
+	filter := bson.M{"customerId": customerID, "status": model.Processed, "_id": bson.M{"$ne": exclude}}
+	opts := options.Find().
+		SetSort(bson.M{"paymentDate": -1}).
+		SetLimit(limit).
+		SetProjection(bson.M{"amount": 1})
+
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var amounts []float64
+	for cursor.Next(context.TODO()) {
+		var doc struct {
+			Amount float64 `bson:"amount"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		amounts = append(amounts, doc.Amount)
+	}
+
+	return amounts, cursor.Err()
+}
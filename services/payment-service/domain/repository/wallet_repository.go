@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"Varejo-Golang-Microservices/services/payment-service/infra/db"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrInsufficientBalance indica que o débito foi recusado por falta de saldo
+// ou porque a conta não está apta a ser debitada.
+var ErrInsufficientBalance = errors.New("saldo insuficiente")
+
+type MongoWalletRepository struct {
+	client *mongo.Client
+}
+
+func NewMongoWalletRepository(mongoURI string) *MongoWalletRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	r := &MongoWalletRepository{
+		client: client,
+	}
+
+	// Códigos de cartão-presente e contas de crédito são únicos
+	r.ensureUniqueIndex("gift_cards", "code")
+	r.ensureUniqueIndex("store_credits", "customerId")
+
+	return r
+}
+
+func (r *MongoWalletRepository) ensureUniqueIndex(collectionName, field string) {
+	collection := r.client.Database("paymentDB").Collection(collectionName)
+
+	_, err := collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: field, Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índice %s.%s: %v\n", collectionName, field, err)
+	}
+}
+
+func (r *MongoWalletRepository) SaveGiftCard(card *model.GiftCard) error {
+	collection := r.client.Database("paymentDB").Collection("gift_cards")
+
+	_, err := collection.InsertOne(context.TODO(), card)
+	return err
+}
+
+// DeleteGiftCard remove um cartão recém-emitido cuja emissão não pôde ser registrada no log
+func (r *MongoWalletRepository) DeleteGiftCard(id primitive.ObjectID) error {
+	collection := r.client.Database("paymentDB").Collection("gift_cards")
+
+	_, err := collection.DeleteOne(context.TODO(), bson.M{"_id": id})
+	return err
+}
+
+func (r *MongoWalletRepository) FindGiftCardByCode(code string) (*model.GiftCard, error) {
+	collection := r.client.Database("paymentDB").Collection("gift_cards")
+
+	var card model.GiftCard
+	err := collection.FindOne(context.TODO(), bson.M{"code": code}).Decode(&card)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &card, nil
+}
+
+// Ativa um cartão ainda inativo no canal informado
+func (r *MongoWalletRepository) ActivateGiftCard(code string, channel model.SalesChannel, at time.Time) (*model.GiftCard, error) {
+	collection := r.client.Database("paymentDB").Collection("gift_cards")
+
+	filter := bson.M{"code": code, "status": model.GiftCardInactive}
+	update := bson.M{"$set": bson.M{
+		"status":           model.GiftCardActive,
+		"activatedChannel": channel,
+		"activatedAt":      at,
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var card model.GiftCard
+	err := collection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&card)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("cartão-presente não encontrado ou já ativado")
+		}
+		return nil, err
+	}
+
+	return &card, nil
+}
+
+// RegisterFailedPIN conta uma tentativa com PIN errado. Ao atingir maxAttempts o
+// cartão fica travado até lockedUntil e a contagem recomeça.
+func (r *MongoWalletRepository) RegisterFailedPIN(code string, maxAttempts int, lockedUntil time.Time) error {
+	collection := r.client.Database("paymentDB").Collection("gift_cards")
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var card model.GiftCard
+	err := collection.FindOneAndUpdate(context.TODO(), bson.M{"code": code}, bson.M{"$inc": bson.M{"failedPins": 1}}, opts).Decode(&card)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return err
+	}
+
+	if !card.ShouldLock(maxAttempts) {
+		return nil
+	}
+
+	update := bson.M{"$set": bson.M{"failedPins": 0, "lockedUntil": lockedUntil}}
+	_, err = collection.UpdateOne(context.TODO(), bson.M{"code": code}, update)
+	return err
+}
+
+// ResetFailedPINs zera a contagem de tentativas erradas após um PIN correto
+func (r *MongoWalletRepository) ResetFailedPINs(code string) error {
+	collection := r.client.Database("paymentDB").Collection("gift_cards")
+
+	update := bson.M{"$set": bson.M{"failedPins": 0}, "$unset": bson.M{"lockedUntil": ""}}
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"code": code}, update)
+	return err
+}
+
+func (r *MongoWalletRepository) BlockGiftCard(code string) (*model.GiftCard, error) {
+	collection := r.client.Database("paymentDB").Collection("gift_cards")
+
+	update := bson.M{"$set": bson.M{"status": model.GiftCardBlocked}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var card model.GiftCard
+	err := collection.FindOneAndUpdate(context.TODO(), bson.M{"code": code}, update, opts).Decode(&card)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("cartão-presente não encontrado")
+		}
+		return nil, err
+	}
+
+	return &card, nil
+}
+
+// AdjustGiftCardBalance altera o saldo de forma atômica. Débitos só são aplicados
+// em cartões ativos, dentro da validade e com saldo suficiente.
+func (r *MongoWalletRepository) AdjustGiftCardBalance(code string, delta float64, now time.Time) (*model.GiftCard, error) {
+	collection := r.client.Database("paymentDB").Collection("gift_cards")
+
+	filter := bson.M{"code": code}
+	if delta < 0 {
+		filter["status"] = model.GiftCardActive
+		filter["balance"] = bson.M{"$gte": -delta}
+		filter["expiresAt"] = bson.M{"$gt": now}
+	}
+
+	update := bson.M{"$inc": bson.M{"balance": delta}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var card model.GiftCard
+	err := collection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&card)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrInsufficientBalance
+		}
+		return nil, err
+	}
+
+	return &card, nil
+}
+
+func (r *MongoWalletRepository) FindStoreCredit(customerID string) (*model.StoreCredit, error) {
+	collection := r.client.Database("paymentDB").Collection("store_credits")
+
+	var credit model.StoreCredit
+	err := collection.FindOne(context.TODO(), bson.M{"customerId": customerID}).Decode(&credit)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &credit, nil
+}
+
+// AdjustStoreCredit altera o saldo de crédito do cliente de forma atômica.
+// Créditos criam a conta se necessário; débitos exigem saldo suficiente.
+func (r *MongoWalletRepository) AdjustStoreCredit(customerID string, delta float64, now time.Time) (*model.StoreCredit, error) {
+	collection := r.client.Database("paymentDB").Collection("store_credits")
+
+	filter := bson.M{"customerId": customerID}
+	update := bson.M{
+		"$inc": bson.M{"balance": delta},
+		"$set": bson.M{"updatedAt": now},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	if delta < 0 {
+		filter["balance"] = bson.M{"$gte": -delta}
+	} else {
+		update["$setOnInsert"] = bson.M{"_id": primitive.NewObjectID()}
+		opts.SetUpsert(true)
+	}
+
+	var credit model.StoreCredit
+	err := collection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&credit)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrInsufficientBalance
+		}
+		return nil, err
+	}
+
+	return &credit, nil
+}
+
+// RevertBalance desfaz uma alteração de saldo cujo lançamento não pôde ser gravado.
+// Ao contrário dos ajustes normais, não verifica status, validade nem saldo.
+func (r *MongoWalletRepository) RevertBalance(accountType model.WalletAccountType, key string, delta float64) error {
+	collection := r.client.Database("paymentDB").Collection("store_credits")
+	filter := bson.M{"customerId": key}
+	if accountType == model.AccountGiftCard {
+		collection = r.client.Database("paymentDB").Collection("gift_cards")
+		filter = bson.M{"code": key}
+	}
+
+	_, err := collection.UpdateOne(context.TODO(), filter, bson.M{"$inc": bson.M{"balance": -delta}})
+	return err
+}
+
+// Grava um lançamento no log de transações. Lançamentos nunca são alterados ou removidos.
+func (r *MongoWalletRepository) InsertTransaction(tx *model.WalletTransaction) error {
+	collection := r.client.Database("paymentDB").Collection("wallet_transactions")
+
+	if tx.ID.IsZero() {
+		tx.ID = primitive.NewObjectID()
+	}
+
+	_, err := collection.InsertOne(context.TODO(), tx)
+	if err != nil {
+		log.Printf("Erro ao gravar transação de carteira no MongoDB: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *MongoWalletRepository) ListTransactions(accountType model.WalletAccountType, accountKey string) ([]*model.WalletTransaction, error) {
+	collection := r.client.Database("paymentDB").Collection("wallet_transactions")
+
+	filter := bson.M{"accountType": accountType, "accountKey": accountKey}
+	opts := options.Find().SetSort(bson.M{"createdAt": 1})
+
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var transactions []*model.WalletTransaction
+	for cursor.Next(context.TODO()) {
+		var tx model.WalletTransaction
+		if err := cursor.Decode(&tx); err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, &tx)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCard = errors.New("cartão inválido")
+
+// ValidateCard valida o número (Luhn), a bandeira pelo BIN, o comprimento,
+// o CVV e a validade do cartão. Em caso de sucesso preenche a bandeira, o BIN
+// e os últimos dígitos no próprio método de pagamento.
+func ValidateCard(method *model.PaymentMethod, now time.Time) error {
+	number := strings.NewReplacer(" ", "", "-", "").Replace(method.CardNumber)
+	if number == "" {
+		return fmt.Errorf("%w: número do cartão é obrigatório", ErrInvalidCard)
+	}
+	if !isDigits(number) {
+		return fmt.Errorf("%w: número do cartão deve conter apenas dígitos", ErrInvalidCard)
+	}
+	if !luhnValid(number) {
+		return fmt.Errorf("%w: número do cartão não passa na verificação de Luhn", ErrInvalidCard)
+	}
+
+	rule := detectBrand(number)
+	if rule == nil {
+		return fmt.Errorf("%w: bandeira não suportada", ErrInvalidCard)
+	}
+
+	if !containsInt(rule.Lengths, len(number)) {
+		return fmt.Errorf("%w: comprimento inválido para a bandeira %s", ErrInvalidCard, rule.Brand)
+	}
+
+	cvv := strings.TrimSpace(method.CVV)
+	if len(cvv) != rule.CVVLength || !isDigits(cvv) {
+		return fmt.Errorf("%w: o CVV da bandeira %s deve ter %d dígitos", ErrInvalidCard, rule.Brand, rule.CVVLength)
+	}
+
+	expiresAt, err := parseExpiry(method.Expiry)
+	if err != nil {
+		return err
+	}
+	if !now.Before(expiresAt) {
+		return fmt.Errorf("%w: cartão expirado", ErrInvalidCard)
+	}
+
+	method.CardNumber = number
+	method.Brand = rule.Brand
+	method.BIN = number[:6]
+	method.LastFour = number[len(number)-4:]
+	return nil
+}
+
+// IsCardPayment informa se o tipo de pagamento usa dados de cartão.
+func IsCardPayment(paymentType model.PaymentType) bool {
+	return paymentType == model.CreditCard || paymentType == model.DebitCard
+}
+
+func detectBrand(number string) *model.CardBrandRule {
+	if len(number) < 6 {
+		return nil
+	}
+
+	bin, err := strconv.Atoi(number[:6])
+	if err != nil {
+		return nil
+	}
+
+	for i := range model.CardBrandRules {
+		rule := &model.CardBrandRules[i]
+		for _, r := range rule.BINRanges {
+			if bin >= r[0] && bin <= r[1] {
+				return rule
+			}
+		}
+	}
+
+	return nil
+}
+
+func luhnValid(number string) bool {
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		digit := int(number[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// Aceita validade nos formatos MM/AA e MM/AAAA. O cartão vale até o fim do mês informado.
+func parseExpiry(expiry string) (time.Time, error) {
+	parts := strings.Split(strings.TrimSpace(expiry), "/")
+	if len(parts) != 2 {
+		return time.Time{}, fmt.Errorf("%w: validade deve estar no formato MM/AA", ErrInvalidCard)
+	}
+
+	month, err := strconv.Atoi(parts[0])
+	if err != nil || month < 1 || month > 12 {
+		return time.Time{}, fmt.Errorf("%w: mês de validade inválido", ErrInvalidCard)
+	}
+
+	year, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: ano de validade inválido", ErrInvalidCard)
+	}
+	switch len(parts[1]) {
+	case 2:
+		year += 2000
+	case 4:
+	default:
+		return time.Time{}, fmt.Errorf("%w: ano de validade inválido", ErrInvalidCard)
+	}
+
+	// Primeiro instante do mês seguinte ao da validade
+	return time.Date(year, time.Month(month)+1, 1, 0, 0, 0, 0, time.UTC), nil
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return value != ""
+}
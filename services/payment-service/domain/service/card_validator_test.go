@@ -0,0 +1,148 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLuhnValid(t *testing.T) {
+	tests := []struct {
+		name   string
+		number string
+		want   bool
+	}{
+		{"visa válido", "4111111111111111", true},
+		{"master válido", "5555555555554444", true},
+		{"amex válido", "378282246310005", true},
+		{"dígito verificador errado", "4111111111111112", false},
+		{"dígitos trocados", "4111111111111161", false},
+		{"todos zeros", "0000000000000000", true},
+		{"um dígito", "0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := luhnValid(tt.number); got != tt.want {
+				t.Errorf("luhnValid(%q) = %v, esperado %v", tt.number, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectBrand(t *testing.T) {
+	tests := []struct {
+		name   string
+		number string
+		want   model.CardBrand
+	}{
+		{"visa", "4111111111111111", model.Visa},
+		{"elo dentro do prefixo 4", "4389350000000002", model.Elo},
+		{"elo faixa 509", "5090000000000000", model.Elo},
+		{"hipercard", "6062820000000003", model.Hipercard},
+		{"amex 34", "340000000000009", model.Amex},
+		{"amex 37", "378282246310005", model.Amex},
+		{"master 5x", "5555555555554444", model.Master},
+		{"master 2x", "2221000000000009", model.Master},
+		{"bin desconhecido", "9999990000000000", ""},
+		{"número curto", "41111", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := detectBrand(tt.number)
+			var got model.CardBrand
+			if rule != nil {
+				got = rule.Brand
+			}
+			if got != tt.want {
+				t.Errorf("detectBrand(%q) = %q, esperado %q", tt.number, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCard(t *testing.T) {
+	now := time.Date(2026, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		method    model.PaymentMethod
+		wantErr   bool
+		wantBrand model.CardBrand
+		wantBIN   string
+		wantLast  string
+	}{
+		{
+			name:      "visa com espaços",
+			method:    model.PaymentMethod{CardNumber: "4111 1111 1111 1111", CVV: "123", Expiry: "12/30"},
+			wantBrand: model.Visa, wantBIN: "411111", wantLast: "1111",
+		},
+		{
+			name:      "amex com CVV de 4 dígitos",
+			method:    model.PaymentMethod{CardNumber: "378282246310005", CVV: "1234", Expiry: "01/2030"},
+			wantBrand: model.Amex, wantBIN: "378282", wantLast: "0005",
+		},
+		{
+			name:      "válido até o fim do mês da validade",
+			method:    model.PaymentMethod{CardNumber: "5555555555554444", CVV: "123", Expiry: "03/26"},
+			wantBrand: model.Master, wantBIN: "555555", wantLast: "4444",
+		},
+		{
+			name:    "falha no Luhn",
+			method:  model.PaymentMethod{CardNumber: "4111111111111112", CVV: "123", Expiry: "12/30"},
+			wantErr: true,
+		},
+		{
+			name:    "bandeira não suportada",
+			method:  model.PaymentMethod{CardNumber: "9999990000000006", CVV: "123", Expiry: "12/30"},
+			wantErr: true,
+		},
+		{
+			name:    "comprimento inválido para a bandeira",
+			method:  model.PaymentMethod{CardNumber: "5090000000000000006", CVV: "123", Expiry: "12/30"},
+			wantErr: true,
+		},
+		{
+			name:    "CVV de 3 dígitos no amex",
+			method:  model.PaymentMethod{CardNumber: "378282246310005", CVV: "123", Expiry: "12/30"},
+			wantErr: true,
+		},
+		{
+			name:    "cartão expirado",
+			method:  model.PaymentMethod{CardNumber: "4111111111111111", CVV: "123", Expiry: "02/26"},
+			wantErr: true,
+		},
+		{
+			name:    "validade em formato inválido",
+			method:  model.PaymentMethod{CardNumber: "4111111111111111", CVV: "123", Expiry: "2030-12"},
+			wantErr: true,
+		},
+		{
+			name:    "número com letras",
+			method:  model.PaymentMethod{CardNumber: "4111a11111111111", CVV: "123", Expiry: "12/30"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			err := ValidateCard(&method, now)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCard) {
+					t.Fatalf("esperado ErrInvalidCard, obtido %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if method.Brand != tt.wantBrand || method.BIN != tt.wantBIN || method.LastFour != tt.wantLast {
+				t.Errorf("obtido bandeira %q, BIN %q, final %q; esperado %q, %q, %q",
+					method.Brand, method.BIN, method.LastFour, tt.wantBrand, tt.wantBIN, tt.wantLast)
+			}
+		})
+	}
+}
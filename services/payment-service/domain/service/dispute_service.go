@@ -0,0 +1,344 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"Varejo-Golang-Microservices/services/payment-service/domain/repository"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Prazo padrão para envio de evidências quando o emissor não informa
+const defaultEvidenceWindow = 7 * 24 * time.Hour
+
+var (
+	ErrDisputeNotFound      = errors.New("disputa não encontrada")
+	ErrDisputeTransition    = errors.New("transição de status da disputa não permitida")
+	ErrPaymentNotDisputable = errors.New("apenas pagamentos processados podem ser contestados")
+	ErrDisputeAlreadyOpen   = errors.New("o pagamento já possui uma disputa aberta")
+)
+
+type DisputeService interface {
+	OpenDispute(dispute *model.Dispute, actor string) error
+	HandleProviderEvent(provider string, event *model.DisputeProviderEvent) (*model.Dispute, error)
+	GetDisputeByID(id string) (*model.Dispute, error)
+	ListDisputes(status model.DisputeStatus, paymentID string) ([]*model.Dispute, error)
+	ListDueSoon(within time.Duration) ([]*model.Dispute, error)
+	AddEvidence(id string, evidence *model.Evidence) (*model.Dispute, error)
+	SubmitEvidence(id, actor, note string) (*model.Dispute, error)
+	Resolve(id string, status model.DisputeStatus, actor, note string) (*model.Dispute, error)
+	ListLedgerEntries(id string) ([]*model.LedgerEntry, error)
+}
+
+type DisputeServiceImpl struct {
+	disputeRepo *repository.MongoDisputeRepository
+	paymentRepo *repository.MongoPaymentRepository
+}
+
+func NewDisputeService(disputeRepo *repository.MongoDisputeRepository, paymentRepo *repository.MongoPaymentRepository) DisputeService {
+	return &DisputeServiceImpl{
+		disputeRepo: disputeRepo,
+		paymentRepo: paymentRepo,
+	}
+}
+
+// OpenDispute abre uma disputa para um pagamento processado. Sem valor informado,
+// a disputa cobre o valor total do pagamento. Um pagamento tem no máximo uma
+// disputa aberta, para que as disputas não somem mais que o valor pago.
+func (s *DisputeServiceImpl) OpenDispute(dispute *model.Dispute, actor string) error {
+	payment, err := s.paymentRepo.FindByID(dispute.PaymentID.Hex())
+	if err != nil {
+		return err
+	}
+	if payment.Status != model.Processed {
+		return fmt.Errorf("%w: o pagamento está %s", ErrPaymentNotDisputable, payment.Status)
+	}
+
+	if dispute.Amount == 0 {
+		dispute.Amount = payment.Amount
+	}
+	dispute.Amount = model.RoundMoney(dispute.Amount)
+	if dispute.Amount < 0 || dispute.Amount > payment.Amount {
+		return fmt.Errorf("o valor da disputa deve estar entre 0 e %.2f", payment.Amount)
+	}
+	if dispute.ReasonCode == "" {
+		return errors.New("o código de motivo é obrigatório")
+	}
+
+	now := time.Now()
+	dispute.ID = primitive.NewObjectID()
+	dispute.OrderID = payment.OrderID
+	dispute.ReasonCategory = model.ReasonCategoryFor(dispute.ReasonCode)
+	dispute.Status = model.DisputeOpened
+	dispute.OpenedAt = now
+	dispute.Evidence = []model.Evidence{}
+	dispute.History = []model.DisputeEvent{{Status: model.DisputeOpened, Actor: actor, At: now}}
+	if dispute.Source == "" {
+		dispute.Source = model.DisputeSourceManual
+	}
+	if dispute.EvidenceDueAt.IsZero() {
+		dispute.EvidenceDueAt = now.Add(defaultEvidenceWindow)
+	}
+	dispute.OpenPaymentID = &dispute.PaymentID
+
+	if err := s.disputeRepo.Save(dispute); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrDisputeAlreadyOpen
+		}
+		return err
+	}
+	return nil
+}
+
+// HandleProviderEvent aplica um evento do webhook do provedor. Eventos repetidos
+// para a mesma disputa são ignorados, o que torna o webhook idempotente.
+func (s *DisputeServiceImpl) HandleProviderEvent(provider string, event *model.DisputeProviderEvent) (*model.Dispute, error) {
+	if event.ProviderDisputeID == "" {
+		return nil, errors.New("o ID da disputa no provedor é obrigatório")
+	}
+
+	dispute, err := s.disputeRepo.FindByProviderID(provider, event.ProviderDisputeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dispute == nil {
+		paymentID, err := primitive.ObjectIDFromHex(event.PaymentID)
+		if err != nil {
+			return nil, errors.New("ID do pagamento inválido")
+		}
+
+		dispute = &model.Dispute{
+			PaymentID:         paymentID,
+			Provider:          provider,
+			ProviderDisputeID: event.ProviderDisputeID,
+			ReasonCode:        event.ReasonCode,
+			Amount:            event.Amount,
+			Fee:               event.Fee,
+			Source:            model.DisputeSourceWebhook,
+			EvidenceDueAt:     event.EvidenceDueAt,
+		}
+		if err := s.OpenDispute(dispute, provider); err != nil {
+			return nil, err
+		}
+	}
+
+	var target model.DisputeStatus
+	switch event.Type {
+	case model.DisputeEventOpened:
+		return dispute, nil
+	case model.DisputeEventWon:
+		target = model.DisputeWon
+	case model.DisputeEventLost:
+		target = model.DisputeLost
+	default:
+		return nil, fmt.Errorf("tipo de evento desconhecido: %s", event.Type)
+	}
+
+	// Evento repetido: reaplica os lançamentos da perda, caso a entrega anterior
+	// tenha falhado depois de encerrar a disputa
+	if dispute.Status == target {
+		if target == model.DisputeLost {
+			if err := s.settleLoss(dispute); err != nil {
+				return nil, err
+			}
+		}
+		return dispute, nil
+	}
+	if event.Fee > 0 {
+		dispute.Fee = event.Fee
+	}
+
+	return s.resolve(dispute, target, provider, "")
+}
+
+func (s *DisputeServiceImpl) GetDisputeByID(id string) (*model.Dispute, error) {
+	dispute, err := s.disputeRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if dispute == nil {
+		return nil, ErrDisputeNotFound
+	}
+	return dispute, nil
+}
+
+func (s *DisputeServiceImpl) ListDisputes(status model.DisputeStatus, paymentID string) ([]*model.Dispute, error) {
+	return s.disputeRepo.List(status, paymentID)
+}
+
+// ListDueSoon lista as disputas sem evidências cujo prazo vence dentro do intervalo informado,
+// incluindo as que já estão vencidas.
+func (s *DisputeServiceImpl) ListDueSoon(within time.Duration) ([]*model.Dispute, error) {
+	return s.disputeRepo.ListDueBefore(time.Now().Add(within))
+}
+
+func (s *DisputeServiceImpl) AddEvidence(id string, evidence *model.Evidence) (*model.Dispute, error) {
+	dispute, err := s.GetDisputeByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Status != model.DisputeOpened {
+		return nil, fmt.Errorf("%w: evidências só podem ser anexadas a disputas abertas", ErrDisputeTransition)
+	}
+	if evidence.URL == "" || evidence.FileName == "" {
+		return nil, errors.New("o nome do arquivo e a URL da evidência são obrigatórios")
+	}
+	if evidence.Type == "" {
+		evidence.Type = model.EvidenceOther
+	}
+
+	evidence.ID = primitive.NewObjectID()
+	evidence.UploadedAt = time.Now()
+	dispute.Evidence = append(dispute.Evidence, *evidence)
+
+	if err := s.disputeRepo.Update(dispute, dispute.Status); err != nil {
+		return nil, err
+	}
+
+	return dispute, nil
+}
+
+func (s *DisputeServiceImpl) SubmitEvidence(id, actor, note string) (*model.Dispute, error) {
+	dispute, err := s.GetDisputeByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSubmission(dispute, time.Now()); err != nil {
+		return nil, err
+	}
+
+	previous := dispute.Status
+	dispute.Status = model.DisputeEvidenceSubmitted
+	dispute.History = append(dispute.History, model.DisputeEvent{
+		Status: dispute.Status,
+		Actor:  actor,
+		Note:   note,
+		At:     time.Now(),
+	})
+
+	if err := s.disputeRepo.Update(dispute, previous); err != nil {
+		return nil, err
+	}
+
+	return dispute, nil
+}
+
+func (s *DisputeServiceImpl) Resolve(id string, status model.DisputeStatus, actor, note string) (*model.Dispute, error) {
+	dispute, err := s.GetDisputeByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Repetir a perda conclui os lançamentos de uma tentativa anterior que falhou
+	if dispute.Status == model.DisputeLost && status == model.DisputeLost {
+		if err := s.settleLoss(dispute); err != nil {
+			return nil, err
+		}
+		return dispute, nil
+	}
+
+	return s.resolve(dispute, status, actor, note)
+}
+
+// Encerra a disputa. Quando perdida, gera os lançamentos de estorno e tarifa
+// e marca o pagamento como CHARGED_BACK.
+func (s *DisputeServiceImpl) resolve(dispute *model.Dispute, status model.DisputeStatus, actor, note string) (*model.Dispute, error) {
+	if err := checkResolution(dispute, status); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	previous := dispute.Status
+	dispute.Status = status
+	dispute.ResolvedAt = &now
+	dispute.History = append(dispute.History, model.DisputeEvent{Status: status, Actor: actor, Note: note, At: now})
+
+	if err := s.disputeRepo.Update(dispute, previous); err != nil {
+		return nil, err
+	}
+
+	if status == model.DisputeLost {
+		if err := s.settleLoss(dispute); err != nil {
+			return nil, err
+		}
+	}
+
+	return dispute, nil
+}
+
+// As evidências só são enviadas com ao menos um anexo, dentro do prazo e a partir
+// de uma disputa aberta
+func checkSubmission(dispute *model.Dispute, now time.Time) error {
+	if len(dispute.Evidence) == 0 {
+		return errors.New("anexe ao menos uma evidência antes de enviar")
+	}
+	if !dispute.CanTransition(model.DisputeEvidenceSubmitted) {
+		return ErrDisputeTransition
+	}
+	if now.After(dispute.EvidenceDueAt) {
+		return errors.New("o prazo para envio de evidências expirou")
+	}
+	return nil
+}
+
+// A disputa só é encerrada como ganha ou perdida, e apenas enquanto está aberta
+func checkResolution(dispute *model.Dispute, status model.DisputeStatus) error {
+	if status != model.DisputeWon && status != model.DisputeLost {
+		return fmt.Errorf("%w: status de resolução inválido %s", ErrDisputeTransition, status)
+	}
+	if !dispute.CanTransition(status) {
+		return ErrDisputeTransition
+	}
+	return nil
+}
+
+// Gera os lançamentos de estorno e tarifa e marca o pagamento como CHARGED_BACK.
+// Os lançamentos são únicos por disputa e tipo, então a operação pode ser repetida
+// depois de uma falha parcial.
+func (s *DisputeServiceImpl) settleLoss(dispute *model.Dispute) error {
+	at := time.Now()
+	if dispute.ResolvedAt != nil {
+		at = *dispute.ResolvedAt
+	}
+
+	if err := s.recordLedger(dispute, model.LedgerChargeback, -dispute.Amount, at); err != nil {
+		return err
+	}
+	if dispute.Fee > 0 {
+		if err := s.recordLedger(dispute, model.LedgerChargebackFee, -dispute.Fee, at); err != nil {
+			return err
+		}
+	}
+
+	if err := s.paymentRepo.UpdateStatus(dispute.PaymentID, model.ChargedBack); err != nil {
+		return fmt.Errorf("erro ao marcar o pagamento %s como estornado: %w", dispute.PaymentID.Hex(), err)
+	}
+	return nil
+}
+
+func (s *DisputeServiceImpl) recordLedger(dispute *model.Dispute, entryType model.LedgerEntryType, amount float64, at time.Time) error {
+	err := s.disputeRepo.InsertLedgerEntry(&model.LedgerEntry{
+		PaymentID: dispute.PaymentID,
+		DisputeID: dispute.ID,
+		Type:      entryType,
+		Amount:    model.RoundMoney(amount),
+		CreatedAt: at,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao gravar lançamento %s da disputa %s: %w", entryType, dispute.ID.Hex(), err)
+	}
+	return nil
+}
+
+func (s *DisputeServiceImpl) ListLedgerEntries(id string) ([]*model.LedgerEntry, error) {
+	dispute, err := s.GetDisputeByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.disputeRepo.ListLedgerEntries(dispute.ID)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckResolution(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    model.DisputeStatus
+		to      model.DisputeStatus
+		wantErr bool
+	}{
+		{"aberta para ganha", model.DisputeOpened, model.DisputeWon, false},
+		{"aberta para perdida", model.DisputeOpened, model.DisputeLost, false},
+		{"evidências enviadas para ganha", model.DisputeEvidenceSubmitted, model.DisputeWon, false},
+		{"evidências enviadas para perdida", model.DisputeEvidenceSubmitted, model.DisputeLost, false},
+		{"ganha não muda para perdida", model.DisputeWon, model.DisputeLost, true},
+		{"perdida não muda para ganha", model.DisputeLost, model.DisputeWon, true},
+		{"resolução não volta para aberta", model.DisputeEvidenceSubmitted, model.DisputeOpened, true},
+		{"envio de evidências não é resolução", model.DisputeOpened, model.DisputeEvidenceSubmitted, true},
+		{"status desconhecido", model.DisputeOpened, "CANCELED", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkResolution(&model.Dispute{Status: tt.from}, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkResolution(%s -> %s) = %v, esperado erro: %v", tt.from, tt.to, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrDisputeTransition) {
+				t.Errorf("erro = %v, esperado ErrDisputeTransition", err)
+			}
+		})
+	}
+}
+
+func TestCheckSubmission(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	evidence := []model.Evidence{{FileName: "nota.pdf", URL: "https://exemplo/nota.pdf"}}
+
+	tests := []struct {
+		name    string
+		dispute model.Dispute
+		wantErr bool
+	}{
+		{"aberta com evidência no prazo", model.Dispute{Status: model.DisputeOpened, Evidence: evidence, EvidenceDueAt: now.Add(time.Hour)}, false},
+		{"sem evidência", model.Dispute{Status: model.DisputeOpened, EvidenceDueAt: now.Add(time.Hour)}, true},
+		{"prazo vencido", model.Dispute{Status: model.DisputeOpened, Evidence: evidence, EvidenceDueAt: now.Add(-time.Hour)}, true},
+		{"evidências já enviadas", model.Dispute{Status: model.DisputeEvidenceSubmitted, Evidence: evidence, EvidenceDueAt: now.Add(time.Hour)}, true},
+		{"disputa encerrada", model.Dispute{Status: model.DisputeLost, Evidence: evidence, EvidenceDueAt: now.Add(time.Hour)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkSubmission(&tt.dispute, now); (err != nil) != tt.wantErr {
+				t.Errorf("checkSubmission = %v, esperado erro: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDisputeIsOpen(t *testing.T) {
+	tests := []struct {
+		status model.DisputeStatus
+		want   bool
+	}{
+		{model.DisputeOpened, true},
+		{model.DisputeEvidenceSubmitted, true},
+		{model.DisputeWon, false},
+		{model.DisputeLost, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			dispute := &model.Dispute{Status: tt.status}
+			if got := dispute.IsOpen(); got != tt.want {
+				t.Errorf("IsOpen com %s = %v, esperado %v", tt.status, got, tt.want)
+			}
+		})
+	}
+}
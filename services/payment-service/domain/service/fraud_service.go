@@ -0,0 +1,413 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"Varejo-Golang-Microservices/services/payment-service/domain/repository"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Quantidade de pagamentos do histórico usada na regra de valor fora do padrão
+const amountHistorySize = 50
+
+var ErrReviewNotFound = errors.New("revisão não encontrada")
+
+type FraudService interface {
+	Assess(payment *model.Payment) (*model.FraudAssessment, error)
+	Record(payment *model.Payment, assessment *model.FraudAssessment, requestedStatus model.PaymentStatus) error
+	RecordIntent(intent *model.PaymentIntent, assessment *model.FraudAssessment) error
+	CancelIntentReview(intent *model.PaymentIntent) error
+	GetRules() (*model.FraudRules, error)
+	UpdateRules(rules *model.FraudRules) error
+	ListAssessments(paymentID string) ([]*model.FraudAssessment, error)
+	ListReviews(status model.ReviewStatus) ([]*model.FraudReview, error)
+	ApproveReview(id, reviewer, note string) (*model.FraudReview, error)
+	DenyReview(id, reviewer, note string) (*model.FraudReview, error)
+	ListBlacklist() ([]*model.BlacklistEntry, error)
+	AddBlacklistEntry(entry *model.BlacklistEntry) error
+	DeleteBlacklistEntry(id string) error
+}
+
+type FraudServiceImpl struct {
+	fraudRepo   *repository.MongoFraudRepository
+	paymentRepo *repository.MongoPaymentRepository
+	intentRepo  *repository.MongoPaymentIntentRepository
+}
+
+func NewFraudService(fraudRepo *repository.MongoFraudRepository, paymentRepo *repository.MongoPaymentRepository, intentRepo *repository.MongoPaymentIntentRepository) FraudService {
+	return &FraudServiceImpl{
+		fraudRepo:   fraudRepo,
+		paymentRepo: paymentRepo,
+		intentRepo:  intentRepo,
+	}
+}
+
+// Assess calcula a pontuação de fraude do pagamento aplicando as regras configuradas.
+// A avaliação não é persistida; use Record depois de salvar o pagamento. Quando o
+// pagamento já existe (reavaliação), ele não entra na própria velocidade nem no histórico.
+func (s *FraudServiceImpl) Assess(payment *model.Payment) (*model.FraudAssessment, error) {
+	rules, err := s.fraudRepo.GetRules()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var hits []model.FraudRuleHit
+
+	// Velocidade por cliente e por token do cartão
+	since := now.Add(-time.Duration(rules.VelocityWindowMinutes) * time.Minute)
+	if payment.CustomerID != "" && rules.MaxPaymentsPerCustomer > 0 {
+		count, err := s.paymentRepo.CountByCustomerSince(payment.CustomerID, since, payment.ID)
+		if err != nil {
+			return nil, err
+		}
+		if int(count) >= rules.MaxPaymentsPerCustomer {
+			hits = append(hits, model.FraudRuleHit{
+				Rule:   "CUSTOMER_VELOCITY",
+				Weight: rules.CustomerVelocityWeight,
+				Detail: fmt.Sprintf("%d pagamentos do cliente nos últimos %d minutos", count, rules.VelocityWindowMinutes),
+			})
+		}
+	}
+
+	cardToken := payment.Method.CardToken
+	if cardToken != "" && rules.MaxPaymentsPerCardToken > 0 {
+		count, err := s.paymentRepo.CountByCardTokenSince(cardToken, since, payment.ID)
+		if err != nil {
+			return nil, err
+		}
+		if int(count) >= rules.MaxPaymentsPerCardToken {
+			hits = append(hits, model.FraudRuleHit{
+				Rule:   "CARD_VELOCITY",
+				Weight: rules.CardVelocityWeight,
+				Detail: fmt.Sprintf("%d pagamentos com o mesmo cartão nos últimos %d minutos", count, rules.VelocityWindowMinutes),
+			})
+		}
+	}
+
+	// Valor fora do padrão do histórico do cliente
+	if payment.CustomerID != "" && rules.AmountOutlierFactor > 0 {
+		amounts, err := s.paymentRepo.AmountHistory(payment.CustomerID, amountHistorySize, payment.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(amounts) >= rules.MinHistorySize && len(amounts) > 0 {
+			limit := outlierLimit(amounts, rules.AmountOutlierFactor)
+			if payment.Amount > limit {
+				hits = append(hits, model.FraudRuleHit{
+					Rule:   "AMOUNT_OUTLIER",
+					Weight: rules.AmountOutlierWeight,
+					Detail: fmt.Sprintf("valor %.2f acima do limite %.2f do histórico do cliente", payment.Amount, limit),
+				})
+			}
+		}
+	}
+
+	// Endereço de cobrança diferente do endereço de entrega
+	if addressMismatch(payment.BillingAddress, payment.ShippingAddress) {
+		hits = append(hits, model.FraudRuleHit{
+			Rule:   "ADDRESS_MISMATCH",
+			Weight: rules.AddressMismatchWeight,
+			Detail: "endereço de cobrança diferente do endereço de entrega",
+		})
+	}
+
+	// Conta criada recentemente
+	if !payment.CustomerSince.IsZero() && rules.NewAccountDays > 0 {
+		age := now.Sub(payment.CustomerSince)
+		if age < time.Duration(rules.NewAccountDays)*24*time.Hour {
+			hits = append(hits, model.FraudRuleHit{
+				Rule:   "NEW_ACCOUNT",
+				Weight: rules.NewAccountWeight,
+				Detail: fmt.Sprintf("conta criada há %d dias", int(age.Hours()/24)),
+			})
+		}
+	}
+
+	// Lista negra
+	blacklisted, err := s.fraudRepo.FindBlacklistHits(map[model.BlacklistType]string{
+		model.BlacklistCustomer:   payment.CustomerID,
+		model.BlacklistCardToken:  cardToken,
+		model.BlacklistPostalCode: normalizePostalCode(payment.ShippingAddress.PostalCode),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range blacklisted {
+		hits = append(hits, model.FraudRuleHit{
+			Rule:   "BLACKLIST",
+			Weight: rules.BlacklistWeight,
+			Detail: fmt.Sprintf("%s na lista negra: %s", entry.Type, entry.Reason),
+		})
+	}
+
+	score, decision := scoreHits(hits, rules)
+
+	return &model.FraudAssessment{
+		PaymentID:  payment.ID,
+		OrderID:    payment.OrderID,
+		CustomerID: payment.CustomerID,
+		Amount:     payment.Amount,
+		Score:      score,
+		Hits:       hits,
+		Decision:   decision,
+		CreatedAt:  now,
+	}, nil
+}
+
+// Record grava a decisão para auditoria e, quando necessário, envia o pagamento para a fila de revisão.
+func (s *FraudServiceImpl) Record(payment *model.Payment, assessment *model.FraudAssessment, requestedStatus model.PaymentStatus) error {
+	assessment.PaymentID = payment.ID
+	if err := s.fraudRepo.SaveAssessment(assessment); err != nil {
+		return err
+	}
+
+	if assessment.Decision != model.DecisionReview {
+		return nil
+	}
+
+	return s.fraudRepo.SaveReview(&model.FraudReview{
+		PaymentID:       payment.ID,
+		OrderID:         payment.OrderID,
+		CustomerID:      payment.CustomerID,
+		Amount:          payment.Amount,
+		Score:           assessment.Score,
+		Hits:            assessment.Hits,
+		RequestedStatus: requestedStatus,
+		Status:          model.ReviewPending,
+		CreatedAt:       assessment.CreatedAt,
+	})
+}
+
+// RecordIntent grava a decisão sobre um intent de pagamento dividido e, quando
+// necessário, o envia para a fila de revisão. O intent já deve estar em UNDER_REVIEW.
+func (s *FraudServiceImpl) RecordIntent(intent *model.PaymentIntent, assessment *model.FraudAssessment) error {
+	assessment.PaymentID = intent.ID
+	if err := s.fraudRepo.SaveAssessment(assessment); err != nil {
+		return err
+	}
+
+	if assessment.Decision != model.DecisionReview {
+		return nil
+	}
+
+	return s.fraudRepo.SaveReview(&model.FraudReview{
+		PaymentID:  intent.ID,
+		IntentID:   intent.ID.Hex(),
+		OrderID:    intent.OrderID,
+		CustomerID: intent.CustomerID,
+		Amount:     intent.OrderTotal,
+		Score:      assessment.Score,
+		Hits:       assessment.Hits,
+		Status:     model.ReviewPending,
+		CreatedAt:  assessment.CreatedAt,
+	})
+}
+
+// CancelIntentReview tira da fila a revisão pendente de um intent que foi cancelado
+func (s *FraudServiceImpl) CancelIntentReview(intent *model.PaymentIntent) error {
+	return s.fraudRepo.CancelIntentReview(intent.ID.Hex(), "intent cancelado antes da revisão", time.Now())
+}
+
+func (s *FraudServiceImpl) GetRules() (*model.FraudRules, error) {
+	return s.fraudRepo.GetRules()
+}
+
+func (s *FraudServiceImpl) UpdateRules(rules *model.FraudRules) error {
+	if rules.ReviewThreshold <= 0 || rules.DenyThreshold <= 0 {
+		return errors.New("os limites de revisão e recusa devem ser maiores que zero")
+	}
+	if rules.ReviewThreshold > rules.DenyThreshold {
+		return errors.New("o limite de revisão não pode ser maior que o limite de recusa")
+	}
+
+	return s.fraudRepo.SaveRules(rules)
+}
+
+func (s *FraudServiceImpl) ListAssessments(paymentID string) ([]*model.FraudAssessment, error) {
+	return s.fraudRepo.ListAssessments(paymentID)
+}
+
+func (s *FraudServiceImpl) ListReviews(status model.ReviewStatus) ([]*model.FraudReview, error) {
+	return s.fraudRepo.ListReviews(status)
+}
+
+func (s *FraudServiceImpl) ApproveReview(id, reviewer, note string) (*model.FraudReview, error) {
+	return s.resolveReview(id, reviewer, note, model.ReviewApproved)
+}
+
+func (s *FraudServiceImpl) DenyReview(id, reviewer, note string) (*model.FraudReview, error) {
+	return s.resolveReview(id, reviewer, note, model.ReviewDenied)
+}
+
+// Aplica a decisão manual: atualiza a revisão, o status do pagamento e registra a auditoria
+func (s *FraudServiceImpl) resolveReview(id, reviewer, note string, status model.ReviewStatus) (*model.FraudReview, error) {
+	review, err := s.fraudRepo.FindReviewByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, ErrReviewNotFound
+	}
+	if review.Status != model.ReviewPending {
+		return nil, errors.New("revisão já foi resolvida")
+	}
+
+	now := time.Now()
+	review.Status = status
+	review.Reviewer = reviewer
+	review.Note = note
+	review.ResolvedAt = &now
+
+	// A revisão é resolvida primeiro para que duas decisões concorrentes não sejam
+	// aplicadas; se o pagamento ou o intent não puder ser atualizado, ela volta à fila
+	if err := s.fraudRepo.ResolveReview(review); err != nil {
+		return nil, err
+	}
+
+	if err := s.applyReviewDecision(review); err != nil {
+		if reopenErr := s.fraudRepo.ReopenReview(review); reopenErr != nil {
+			return nil, fmt.Errorf("%v; a revisão não pôde voltar à fila: %v", err, reopenErr)
+		}
+		return nil, err
+	}
+
+	decision := model.DecisionDenied
+	if status == model.ReviewApproved {
+		decision = model.DecisionApproved
+	}
+
+	err = s.fraudRepo.SaveAssessment(&model.FraudAssessment{
+		PaymentID:  review.PaymentID,
+		OrderID:    review.OrderID,
+		CustomerID: review.CustomerID,
+		Amount:     review.Amount,
+		Score:      review.Score,
+		Hits:       review.Hits,
+		Decision:   decision,
+		Manual:     true,
+		Reviewer:   reviewer,
+		Note:       note,
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// Aplica a decisão da revisão ao intent ou ao pagamento
+func (s *FraudServiceImpl) applyReviewDecision(review *model.FraudReview) error {
+	approved := review.Status == model.ReviewApproved
+	if review.IntentID != "" {
+		// Intent aprovado volta a OPEN para ser autorizado; recusado é cancelado
+		return s.intentRepo.ResolveReview(review.PaymentID, approved, "pagamento recusado na revisão de fraude")
+	}
+
+	paymentStatus := model.Failed
+	if approved {
+		paymentStatus = review.RequestedStatus
+		if paymentStatus == "" || paymentStatus == model.UnderReview {
+			paymentStatus = model.Processed
+		}
+	}
+	return s.paymentRepo.UpdateStatus(review.PaymentID, paymentStatus)
+}
+
+func (s *FraudServiceImpl) ListBlacklist() ([]*model.BlacklistEntry, error) {
+	return s.fraudRepo.ListBlacklist()
+}
+
+func (s *FraudServiceImpl) AddBlacklistEntry(entry *model.BlacklistEntry) error {
+	switch entry.Type {
+	case model.BlacklistCustomer:
+	case model.BlacklistCardToken:
+		// Aceita o número do cartão e guarda apenas o token
+		if len(entry.Value) < 64 {
+			entry.Value = model.TokenizeCard(entry.Value)
+		}
+	case model.BlacklistPostalCode:
+		entry.Value = normalizePostalCode(entry.Value)
+	default:
+		return fmt.Errorf("tipo de lista negra inválido: %s", entry.Type)
+	}
+
+	if entry.Value == "" {
+		return errors.New("o valor da lista negra é obrigatório")
+	}
+
+	entry.CreatedAt = time.Now()
+	return s.fraudRepo.SaveBlacklistEntry(entry)
+}
+
+func (s *FraudServiceImpl) DeleteBlacklistEntry(id string) error {
+	return s.fraudRepo.DeleteBlacklistEntry(id)
+}
+
+// Soma os pesos das regras acionadas, limitando a pontuação a 100, e decide pelos
+// limites de recusa e de revisão
+func scoreHits(hits []model.FraudRuleHit, rules *model.FraudRules) (int, model.FraudDecision) {
+	score := 0
+	for _, hit := range hits {
+		score += hit.Weight
+	}
+	if score > 100 {
+		score = 100
+	}
+
+	switch {
+	case score >= rules.DenyThreshold:
+		return score, model.DecisionDenied
+	case score >= rules.ReviewThreshold:
+		return score, model.DecisionReview
+	}
+	return score, model.DecisionApproved
+}
+
+// Limite acima do qual um valor é considerado fora do padrão: média mais
+// `factor` desvios padrão. O desvio tem piso de 25% da média para que
+// históricos com valores quase iguais não marquem qualquer variação.
+func outlierLimit(amounts []float64, factor float64) float64 {
+	var sum float64
+	for _, a := range amounts {
+		sum += a
+	}
+	mean := sum / float64(len(amounts))
+
+	var variance float64
+	for _, a := range amounts {
+		variance += (a - mean) * (a - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(amounts)))
+	if stddev < mean*0.25 {
+		stddev = mean * 0.25
+	}
+
+	return mean + factor*stddev
+}
+
+// Considera divergente quando ambos os endereços foram informados e diferem no CEP ou na cidade
+func addressMismatch(billing, shipping model.Address) bool {
+	if billing.PostalCode == "" || shipping.PostalCode == "" {
+		return false
+	}
+
+	if normalizePostalCode(billing.PostalCode) != normalizePostalCode(shipping.PostalCode) {
+		return true
+	}
+
+	return !strings.EqualFold(strings.TrimSpace(billing.City), strings.TrimSpace(shipping.City))
+}
+
+func normalizePostalCode(postalCode string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, postalCode)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"testing"
+)
+
+func TestScoreHits(t *testing.T) {
+	rules := model.DefaultFraudRules()
+
+	tests := []struct {
+		name         string
+		weights      []int
+		wantScore    int
+		wantDecision model.FraudDecision
+	}{
+		{"sem regras acionadas", nil, 0, model.DecisionApproved},
+		{"abaixo do limite de revisão", []int{15, 15}, 30, model.DecisionApproved},
+		{"no limite de revisão", []int{25, 15}, 40, model.DecisionReview},
+		{"entre revisão e recusa", []int{30, 25, 15}, 70, model.DecisionReview},
+		{"no limite de recusa", []int{30, 25, 25}, 80, model.DecisionDenied},
+		{"lista negra sozinha recusa", []int{100}, 100, model.DecisionDenied},
+		{"soma acima de 100 é limitada", []int{100, 30, 25}, 100, model.DecisionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits []model.FraudRuleHit
+			for _, weight := range tt.weights {
+				hits = append(hits, model.FraudRuleHit{Weight: weight})
+			}
+
+			score, decision := scoreHits(hits, &rules)
+			if score != tt.wantScore {
+				t.Errorf("pontuação = %d, esperada %d", score, tt.wantScore)
+			}
+			if decision != tt.wantDecision {
+				t.Errorf("decisão = %s, esperada %s", decision, tt.wantDecision)
+			}
+		})
+	}
+}
+
+func TestOutlierLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		amounts []float64
+		factor  float64
+		want    float64
+	}{
+		{"valores iguais usam o piso de 25% da média", []float64{100, 100, 100}, 3, 175},
+		{"desvio acima do piso", []float64{50, 150}, 2, 200},
+		{"fator zero fica na média", []float64{80, 120}, 0, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outlierLimit(tt.amounts, tt.factor); got != tt.want {
+				t.Errorf("outlierLimit(%v, %v) = %v, esperado %v", tt.amounts, tt.factor, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddressMismatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		billing  model.Address
+		shipping model.Address
+		want     bool
+	}{
+		{"mesmo endereço com formatação diferente", model.Address{PostalCode: "01310-100", City: "São Paulo"}, model.Address{PostalCode: "01310100", City: " são paulo "}, false},
+		{"CEP diferente", model.Address{PostalCode: "01310-100", City: "São Paulo"}, model.Address{PostalCode: "20040-002", City: "São Paulo"}, true},
+		{"cidade diferente", model.Address{PostalCode: "01310-100", City: "São Paulo"}, model.Address{PostalCode: "01310-100", City: "Campinas"}, true},
+		{"sem CEP de entrega", model.Address{PostalCode: "01310-100"}, model.Address{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := addressMismatch(tt.billing, tt.shipping); got != tt.want {
+				t.Errorf("addressMismatch = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,648 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"Varejo-Golang-Microservices/services/payment-service/domain/repository"
+	"Varejo-Golang-Microservices/services/payment-service/infra/orders"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrIntentNotFound  = errors.New("intent de pagamento não encontrado")
+	ErrTenderNotFound  = errors.New("tender não encontrado")
+	ErrTenderDeclined  = errors.New("tender recusado")
+	ErrIntentStatus    = errors.New("operação não permitida no status atual do intent")
+	ErrIntentUnbalance = errors.New("a soma dos tenders difere do total do pedido")
+	ErrOrderNotFound   = errors.New("pedido não encontrado")
+	ErrIntentDenied    = errors.New("pagamento recusado pelo motor de fraude")
+	ErrIntentReview    = errors.New("pagamento enviado para revisão manual de fraude")
+)
+
+// Tempo sem atualização a partir do qual um intent em PROCESSING é considerado
+// interrompido e recuperado por RecoverStale
+const staleProcessingAfter = 5 * time.Minute
+
+type PaymentIntentService interface {
+	CreateIntent(intent *model.PaymentIntent) error
+	GetIntentByID(id string) (*model.PaymentIntent, error)
+	GetIntentByOrderID(orderID string) (*model.PaymentIntent, error)
+	AddTender(intentID string, tender *model.Tender) (*model.PaymentIntent, error)
+	RemoveTender(intentID, tenderID string) (*model.PaymentIntent, error)
+	Authorize(intentID string) (*model.PaymentIntent, error)
+	Capture(intentID string) (*model.PaymentIntent, error)
+	Cancel(intentID string) (*model.PaymentIntent, error)
+	RecoverStale() int
+	RunRecovery(interval time.Duration)
+}
+
+type PaymentIntentServiceImpl struct {
+	intentRepo   *repository.MongoPaymentIntentRepository
+	processors   map[model.PaymentType]TenderProcessor
+	fraudService FraudService
+	orders       orders.OrderReader
+}
+
+func NewPaymentIntentService(intentRepo *repository.MongoPaymentIntentRepository, processors map[model.PaymentType]TenderProcessor, fraudService FraudService, orderReader orders.OrderReader) PaymentIntentService {
+	return &PaymentIntentServiceImpl{
+		intentRepo:   intentRepo,
+		processors:   processors,
+		fraudService: fraudService,
+		orders:       orderReader,
+	}
+}
+
+// CreateIntent abre o intent de um pedido pendente. O total e o cliente vêm do
+// serviço de pedidos, nunca da requisição.
+func (s *PaymentIntentServiceImpl) CreateIntent(intent *model.PaymentIntent) error {
+	if intent.OrderID == "" {
+		return errors.New("o ID do pedido é obrigatório")
+	}
+
+	order, err := s.orders.GetOrder(intent.OrderID)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		return ErrOrderNotFound
+	}
+	if order.Status != "PENDING" {
+		return fmt.Errorf("%w: o pedido está %s", ErrIntentStatus, order.Status)
+	}
+	if order.TotalPrice <= 0 {
+		return errors.New("o total do pedido deve ser maior que zero")
+	}
+	intent.CustomerID = order.CustomerID
+	intent.OrderTotal = order.TotalPrice
+
+	existing, err := s.intentRepo.FindActiveByOrderID(intent.OrderID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("%w: o pedido já possui o intent %s", ErrIntentStatus, existing.ID.Hex())
+	}
+
+	now := time.Now()
+	intent.ID = primitive.NewObjectID()
+	intent.OrderTotal = model.RoundMoney(intent.OrderTotal)
+	intent.Tenders = []model.Tender{}
+	intent.Status = model.IntentOpen
+	intent.CreatedAt = now
+	intent.UpdatedAt = now
+
+	return s.intentRepo.Save(intent)
+}
+
+func (s *PaymentIntentServiceImpl) GetIntentByID(id string) (*model.PaymentIntent, error) {
+	intent, err := s.intentRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if intent == nil {
+		return nil, ErrIntentNotFound
+	}
+	return intent, nil
+}
+
+func (s *PaymentIntentServiceImpl) GetIntentByOrderID(orderID string) (*model.PaymentIntent, error) {
+	intent, err := s.intentRepo.FindActiveByOrderID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if intent == nil {
+		return nil, ErrIntentNotFound
+	}
+	return intent, nil
+}
+
+// AddTender inclui uma forma de pagamento sem permitir que a soma ultrapasse o total do pedido.
+func (s *PaymentIntentServiceImpl) AddTender(intentID string, tender *model.Tender) (*model.PaymentIntent, error) {
+	intent, err := s.GetIntentByID(intentID)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Status != model.IntentOpen {
+		return nil, ErrIntentStatus
+	}
+
+	processor, ok := s.processors[tender.Type]
+	if !ok {
+		return nil, fmt.Errorf("forma de pagamento não suportada: %s", tender.Type)
+	}
+
+	tender.Amount = model.RoundMoney(tender.Amount)
+	if err := checkTenderAmount(intent, tender.Amount); err != nil {
+		return nil, err
+	}
+
+	if validator, ok := processor.(TenderValidator); ok {
+		if err := validator.Validate(intent, tender); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	tender.ID = primitive.NewObjectID()
+	tender.Status = model.TenderPending
+	tender.UpdatedAt = now
+	if tender.Method.Type == "" {
+		tender.Method.Type = tender.Type
+	}
+
+	intent.Tenders = append(intent.Tenders, *tender)
+	intent.FraudApproved = false
+	intent.UpdatedAt = now
+
+	if err := s.intentRepo.Update(intent); err != nil {
+		return nil, err
+	}
+
+	return intent, nil
+}
+
+func (s *PaymentIntentServiceImpl) RemoveTender(intentID, tenderID string) (*model.PaymentIntent, error) {
+	intent, err := s.GetIntentByID(intentID)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Status != model.IntentOpen {
+		return nil, ErrIntentStatus
+	}
+
+	index := findTender(intent, tenderID)
+	if index < 0 {
+		return nil, ErrTenderNotFound
+	}
+
+	intent.Tenders = append(intent.Tenders[:index], intent.Tenders[index+1:]...)
+	intent.FraudApproved = false
+	intent.UpdatedAt = time.Now()
+
+	if err := s.intentRepo.Update(intent); err != nil {
+		return nil, err
+	}
+
+	return intent, nil
+}
+
+// Authorize passa o intent pelo motor de fraude e autoriza cada tender separadamente.
+// Se algum for recusado, os tenders já autorizados nesta tentativa são estornados e
+// voltam a PENDING, de modo que o intent nunca fica parcialmente autorizado.
+func (s *PaymentIntentServiceImpl) Authorize(intentID string) (*model.PaymentIntent, error) {
+	intent, err := s.GetIntentByID(intentID)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Status != model.IntentOpen {
+		return nil, ErrIntentStatus
+	}
+	if intent.RemainingAmount() != 0 {
+		return nil, fmt.Errorf("%w: restam %.2f a pagar", ErrIntentUnbalance, intent.RemainingAmount())
+	}
+
+	if !intent.FraudApproved {
+		if err := s.screenFraud(intent); err != nil {
+			return intent, err
+		}
+	}
+
+	// Reserva o intent para esta operação; uma requisição concorrente recebe conflito
+	intent.Status = model.IntentProcessing
+	intent.UpdatedAt = time.Now()
+	if err := s.intentRepo.Update(intent); err != nil {
+		return nil, err
+	}
+
+	var authorized []int
+	var declineErr error
+	for i := range intent.Tenders {
+		tender := &intent.Tenders[i]
+		if tender.Status != model.TenderPending {
+			continue
+		}
+
+		code, err := s.processors[tender.Type].Authorize(intent, tender)
+		tender.UpdatedAt = time.Now()
+		if err != nil {
+			tender.Status = model.TenderFailed
+			tender.FailureReason = err.Error()
+			declineErr = fmt.Errorf("%w: %s (%s)", ErrTenderDeclined, tender.Type, err.Error())
+			break
+		}
+
+		tender.Status = model.TenderAuthorized
+		tender.AuthorizationCode = code
+		tender.FailureReason = ""
+		authorized = append(authorized, i)
+		s.saveProgress(intent)
+	}
+
+	if declineErr != nil {
+		for _, i := range authorized {
+			s.voidTender(intent, &intent.Tenders[i], model.TenderPending)
+		}
+		intent.Status = model.IntentOpen
+		intent.LastError = declineErr.Error()
+	} else {
+		intent.Status = model.IntentAuthorized
+		intent.LastError = ""
+	}
+
+	intent.UpdatedAt = time.Now()
+	if err := s.intentRepo.Update(intent); err != nil {
+		return nil, err
+	}
+
+	if declineErr != nil {
+		return intent, declineErr
+	}
+
+	return intent, nil
+}
+
+// Capture captura os tenders autorizados. O intent passa a PAID, e o evento é
+// publicado, apenas quando o valor capturado é igual ao total do pedido.
+func (s *PaymentIntentServiceImpl) Capture(intentID string) (*model.PaymentIntent, error) {
+	intent, err := s.GetIntentByID(intentID)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Status != model.IntentAuthorized {
+		return nil, ErrIntentStatus
+	}
+
+	intent.Status = model.IntentProcessing
+	intent.UpdatedAt = time.Now()
+	if err := s.intentRepo.Update(intent); err != nil {
+		return nil, err
+	}
+
+	var captureErr error
+	for i := range intent.Tenders {
+		tender := &intent.Tenders[i]
+		if tender.Status != model.TenderAuthorized {
+			continue
+		}
+
+		if err := s.processors[tender.Type].Capture(intent, tender); err != nil {
+			tender.FailureReason = err.Error()
+			captureErr = fmt.Errorf("erro ao capturar tender %s: %w", tender.ID.Hex(), err)
+			break
+		}
+
+		tender.Status = model.TenderCaptured
+		tender.UpdatedAt = time.Now()
+		s.saveProgress(intent)
+	}
+
+	intent.CapturedAmount = capturedAmount(intent)
+
+	if captureErr == nil && intent.CapturedAmount == intent.OrderTotal {
+		intent.Status = model.IntentPaid
+		intent.LastError = ""
+	} else {
+		// Mantém AUTHORIZED para que a captura dos tenders restantes possa ser repetida
+		intent.Status = model.IntentAuthorized
+		if captureErr != nil {
+			intent.LastError = captureErr.Error()
+		}
+	}
+
+	intent.UpdatedAt = time.Now()
+	if err := s.intentRepo.Update(intent); err != nil {
+		return nil, err
+	}
+
+	if intent.Status == model.IntentPaid {
+		s.publish(intent)
+	}
+
+	if captureErr != nil {
+		return intent, captureErr
+	}
+
+	return intent, nil
+}
+
+// Cancel encerra o intent: devolve os tenders já capturados numa captura parcial e
+// estorna os autorizados. O intent é reservado em PROCESSING antes de qualquer
+// devolução, para que uma captura concorrente receba conflito; a revisão de fraude
+// pendente de um intent em UNDER_REVIEW é encerrada. Se alguma devolução falhar, o
+// intent volta ao status anterior e a operação pode ser repetida. Intents pagos não
+// podem ser cancelados.
+func (s *PaymentIntentServiceImpl) Cancel(intentID string) (*model.PaymentIntent, error) {
+	intent, err := s.GetIntentByID(intentID)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Status != model.IntentOpen && intent.Status != model.IntentAuthorized && intent.Status != model.IntentReview {
+		return nil, ErrIntentStatus
+	}
+
+	previous := intent.Status
+	intent.Status = model.IntentProcessing
+	intent.Canceling = true
+	intent.UpdatedAt = time.Now()
+	if err := s.intentRepo.Update(intent); err != nil {
+		return nil, err
+	}
+
+	return s.finishCancel(intent, previous)
+}
+
+// Conclui o cancelamento de um intent já reservado. Se uma devolução falhar, o
+// intent volta a fallback com o erro registrado.
+func (s *PaymentIntentServiceImpl) finishCancel(intent *model.PaymentIntent, fallback model.PaymentIntentStatus) (*model.PaymentIntent, error) {
+	if err := s.fraudService.CancelIntentReview(intent); err != nil {
+		return s.abortCancel(intent, fallback, err)
+	}
+
+	for i := range intent.Tenders {
+		tender := &intent.Tenders[i]
+		if tender.Status != model.TenderCaptured {
+			continue
+		}
+
+		tender.UpdatedAt = time.Now()
+		if err := s.processors[tender.Type].Refund(intent, tender); err != nil {
+			tender.FailureReason = "falha na devolução: " + err.Error()
+			intent.CapturedAmount = capturedAmount(intent)
+			return s.abortCancel(intent, fallback, fmt.Errorf("erro ao devolver tender %s: %w", tender.ID.Hex(), err))
+		}
+		tender.Status = model.TenderRefunded
+		tender.FailureReason = ""
+		intent.CapturedAmount = capturedAmount(intent)
+		s.saveProgress(intent)
+	}
+
+	for i := range intent.Tenders {
+		if intent.Tenders[i].Status == model.TenderAuthorized {
+			s.voidTender(intent, &intent.Tenders[i], model.TenderVoided)
+		}
+	}
+
+	intent.Status = model.IntentCanceled
+	intent.Canceling = false
+	intent.UpdatedAt = time.Now()
+	if err := s.intentRepo.Update(intent); err != nil {
+		return nil, err
+	}
+
+	return intent, nil
+}
+
+// Libera a reserva de um cancelamento que não pôde ser concluído
+func (s *PaymentIntentServiceImpl) abortCancel(intent *model.PaymentIntent, fallback model.PaymentIntentStatus, cause error) (*model.PaymentIntent, error) {
+	intent.Status = fallback
+	intent.Canceling = false
+	intent.LastError = cause.Error()
+	intent.UpdatedAt = time.Now()
+	if err := s.intentRepo.Update(intent); err != nil {
+		return nil, err
+	}
+	return intent, cause
+}
+
+// RecoverStale retoma os intents que ficaram em PROCESSING por uma autorização ou
+// captura interrompida (ex.: queda do serviço no meio da operação). Retorna quantos
+// intents foram recuperados.
+func (s *PaymentIntentServiceImpl) RecoverStale() int {
+	intents, err := s.intentRepo.FindStaleProcessing(time.Now().Add(-staleProcessingAfter))
+	if err != nil {
+		log.Printf("Erro ao buscar intents de pagamento interrompidos: %v\n", err)
+		return 0
+	}
+
+	recovered := 0
+	for _, intent := range intents {
+		if err := s.recoverIntent(intent); err != nil {
+			log.Printf("Erro ao recuperar o intent %s: %v\n", intent.ID.Hex(), err)
+			continue
+		}
+		recovered++
+	}
+
+	return recovered
+}
+
+// RunRecovery verifica periodicamente os intents interrompidos. Deve ser executado em uma goroutine.
+func (s *PaymentIntentServiceImpl) RunRecovery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if n := s.RecoverStale(); n > 0 {
+			log.Printf("%d intents de pagamento interrompidos recuperados\n", n)
+		}
+	}
+}
+
+// Um cancelamento interrompido é concluído. Nos demais casos, vale recoveryStatus:
+// com tenders ainda pendentes os autorizados são estornados e o intent volta a OPEN;
+// sem pendentes, volta a AUTHORIZED para que a captura seja repetida, ou passa a
+// PAID se tudo já foi capturado.
+func (s *PaymentIntentServiceImpl) recoverIntent(intent *model.PaymentIntent) error {
+	if intent.Canceling {
+		// Só tenders capturados fazem a devolução falhar, e eles só existem em AUTHORIZED
+		_, err := s.finishCancel(intent, model.IntentAuthorized)
+		return err
+	}
+
+	intent.CapturedAmount = capturedAmount(intent)
+	intent.Status = recoveryStatus(intent)
+	switch intent.Status {
+	case model.IntentOpen:
+		for i := range intent.Tenders {
+			if intent.Tenders[i].Status == model.TenderAuthorized {
+				s.voidTender(intent, &intent.Tenders[i], model.TenderPending)
+			}
+		}
+		intent.LastError = "autorização interrompida; repita a operação"
+	case model.IntentAuthorized:
+		intent.LastError = "captura interrompida; repita a operação"
+	default:
+		intent.LastError = ""
+	}
+
+	intent.UpdatedAt = time.Now()
+	if err := s.intentRepo.Update(intent); err != nil {
+		return err
+	}
+
+	if intent.Status == model.IntentPaid {
+		s.publish(intent)
+	}
+	return nil
+}
+
+// Status para o qual volta um intent cuja autorização ou captura foi interrompida.
+// Tender ainda pendente indica autorização incompleta (OPEN, com os autorizados
+// estornados); sem pendentes, o intent é PAID se o capturado cobre o total e
+// AUTHORIZED caso contrário.
+func recoveryStatus(intent *model.PaymentIntent) model.PaymentIntentStatus {
+	for _, tender := range intent.Tenders {
+		if tender.Status == model.TenderPending {
+			return model.IntentOpen
+		}
+	}
+
+	if capturedAmount(intent) == intent.OrderTotal {
+		return model.IntentPaid
+	}
+	return model.IntentAuthorized
+}
+
+// Avalia o intent com as mesmas regras de SavePayment. Cada cartão é avaliado com
+// o valor do seu tender e, sem cartão, o intent é avaliado pelo total; vale a
+// decisão mais severa. Recusado, o intent é cancelado; em revisão, fica em
+// UNDER_REVIEW até a decisão manual.
+func (s *PaymentIntentServiceImpl) screenFraud(intent *model.PaymentIntent) error {
+	var worst *model.FraudAssessment
+	for _, payment := range fraudPayments(intent) {
+		assessment, err := s.fraudService.Assess(payment)
+		if err != nil {
+			return err
+		}
+		if worst == nil || decisionSeverity(assessment.Decision) > decisionSeverity(worst.Decision) {
+			worst = assessment
+		}
+	}
+
+	switch worst.Decision {
+	case model.DecisionApproved:
+		intent.FraudApproved = true
+		return s.fraudService.RecordIntent(intent, worst)
+	case model.DecisionDenied:
+		intent.Status = model.IntentCanceled
+		intent.LastError = ErrIntentDenied.Error()
+	default:
+		intent.Status = model.IntentReview
+		intent.LastError = ""
+	}
+
+	intent.UpdatedAt = time.Now()
+	if err := s.intentRepo.Update(intent); err != nil {
+		return err
+	}
+
+	if err := s.fraudService.RecordIntent(intent, worst); err != nil {
+		// Sem o item na fila, ninguém resolveria a revisão: o intent volta a OPEN
+		if intent.Status == model.IntentReview {
+			intent.Status = model.IntentOpen
+			intent.UpdatedAt = time.Now()
+			if revertErr := s.intentRepo.Update(intent); revertErr != nil {
+				log.Printf("Erro ao reabrir o intent %s: %v\n", intent.ID.Hex(), revertErr)
+			}
+		}
+		return err
+	}
+
+	if intent.Status == model.IntentCanceled {
+		return ErrIntentDenied
+	}
+	return ErrIntentReview
+}
+
+// Pagamentos avaliados pelo motor de fraude para um intent
+func fraudPayments(intent *model.PaymentIntent) []*model.Payment {
+	base := model.Payment{
+		ID:            intent.ID,
+		OrderID:       intent.OrderID,
+		CustomerID:    intent.CustomerID,
+		Amount:        intent.OrderTotal,
+		PaymentDate:   time.Now(),
+		CustomerSince: customerSince(intent.CustomerID),
+	}
+
+	var payments []*model.Payment
+	for _, tender := range intent.Tenders {
+		if tender.Status != model.TenderPending || !IsCardPayment(tender.Type) {
+			continue
+		}
+		payment := base
+		payment.Amount = tender.Amount
+		payment.Method = tender.Method
+		payments = append(payments, &payment)
+	}
+
+	if len(payments) == 0 {
+		return []*model.Payment{&base}
+	}
+	return payments
+}
+
+func decisionSeverity(decision model.FraudDecision) int {
+	switch decision {
+	case model.DecisionDenied:
+		return 2
+	case model.DecisionReview:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Grava o progresso a cada tender processado para que RecoverStale saiba o que
+// já foi feito. Uma falha aqui só é registrada: o estado final é gravado ao término.
+func (s *PaymentIntentServiceImpl) saveProgress(intent *model.PaymentIntent) {
+	intent.UpdatedAt = time.Now()
+	if err := s.intentRepo.Update(intent); err != nil {
+		log.Printf("Erro ao gravar o progresso do intent %s: %v\n", intent.ID.Hex(), err)
+	}
+}
+
+func (s *PaymentIntentServiceImpl) publish(intent *model.PaymentIntent) {
+	if err := s.intentRepo.Publish(intent); err != nil {
+		log.Printf("Erro ao publicar pagamento do pedido %s: %v\n", intent.OrderID, err)
+	}
+}
+
+func capturedAmount(intent *model.PaymentIntent) float64 {
+	var captured float64
+	for _, tender := range intent.Tenders {
+		if tender.Status == model.TenderCaptured {
+			captured += tender.Amount
+		}
+	}
+	return model.RoundMoney(captured)
+}
+
+// Estorna a autorização de um tender. Se o estorno falhar, o tender fica VOIDED
+// com o motivo registrado para conciliação manual.
+func (s *PaymentIntentServiceImpl) voidTender(intent *model.PaymentIntent, tender *model.Tender, next model.TenderStatus) {
+	tender.UpdatedAt = time.Now()
+	if err := s.processors[tender.Type].Void(intent, tender); err != nil {
+		log.Printf("Erro ao estornar tender %s do intent %s: %v\n", tender.ID.Hex(), intent.ID.Hex(), err)
+		tender.Status = model.TenderVoided
+		tender.FailureReason = "falha no estorno: " + err.Error()
+		return
+	}
+
+	tender.Status = next
+	tender.AuthorizationCode = ""
+}
+
+// O tender deve ter valor positivo e caber no que ainda falta pagar do pedido
+func checkTenderAmount(intent *model.PaymentIntent, amount float64) error {
+	if amount <= 0 {
+		return errors.New("o valor do tender deve ser maior que zero")
+	}
+	if amount > intent.RemainingAmount() {
+		return fmt.Errorf("%w: restam %.2f a pagar", ErrIntentUnbalance, intent.RemainingAmount())
+	}
+	return nil
+}
+
+func findTender(intent *model.PaymentIntent, tenderID string) int {
+	for i, tender := range intent.Tenders {
+		if tender.ID.Hex() == tenderID {
+			return i
+		}
+	}
+	return -1
+}
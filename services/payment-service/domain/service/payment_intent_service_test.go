@@ -0,0 +1,79 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"errors"
+	"testing"
+)
+
+func intentWithTenders(total float64, tenders ...model.Tender) *model.PaymentIntent {
+	return &model.PaymentIntent{OrderTotal: total, Tenders: tenders}
+}
+
+func testTender(amount float64, status model.TenderStatus) model.Tender {
+	return model.Tender{Amount: amount, Status: status}
+}
+
+func TestCheckTenderAmount(t *testing.T) {
+	tests := []struct {
+		name    string
+		intent  *model.PaymentIntent
+		amount  float64
+		wantErr bool
+		unbal   bool
+	}{
+		{"primeiro tender cobre o total", intentWithTenders(100), 100, false, false},
+		{"valor zero", intentWithTenders(100), 0, true, false},
+		{"valor negativo", intentWithTenders(100), -10, true, false},
+		{"acima do total", intentWithTenders(100), 100.01, true, true},
+		{"cabe no restante", intentWithTenders(100, testTender(60, model.TenderPending)), 40, false, false},
+		{"ultrapassa o restante", intentWithTenders(100, testTender(60, model.TenderAuthorized)), 40.01, true, true},
+		{"tender recusado não ocupa o total", intentWithTenders(100, testTender(60, model.TenderFailed)), 100, false, false},
+		{"tender estornado não ocupa o total", intentWithTenders(100, testTender(60, model.TenderVoided)), 100, false, false},
+		{"tender devolvido não ocupa o total", intentWithTenders(100, testTender(60, model.TenderRefunded)), 100, false, false},
+		{"centavos arredondados", intentWithTenders(0.3, testTender(0.1, model.TenderPending), testTender(0.1, model.TenderPending)), 0.1, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkTenderAmount(tt.intent, tt.amount)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkTenderAmount(%.2f) erro = %v, esperado erro: %v", tt.amount, err, tt.wantErr)
+			}
+			if tt.unbal && !errors.Is(err, ErrIntentUnbalance) {
+				t.Errorf("erro = %v, esperado ErrIntentUnbalance", err)
+			}
+		})
+	}
+}
+
+func TestRecoveryStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		intent *model.PaymentIntent
+		want   model.PaymentIntentStatus
+	}{
+		{"autorização interrompida com pendentes", intentWithTenders(100, testTender(60, model.TenderAuthorized), testTender(40, model.TenderPending)), model.IntentOpen},
+		{"nenhum tender processado", intentWithTenders(100, testTender(100, model.TenderPending)), model.IntentOpen},
+		{"todos autorizados", intentWithTenders(100, testTender(60, model.TenderAuthorized), testTender(40, model.TenderAuthorized)), model.IntentAuthorized},
+		{"captura parcial", intentWithTenders(100, testTender(60, model.TenderCaptured), testTender(40, model.TenderAuthorized)), model.IntentAuthorized},
+		{"tudo capturado", intentWithTenders(100, testTender(60, model.TenderCaptured), testTender(40, model.TenderCaptured)), model.IntentPaid},
+		{"capturado com centavos", intentWithTenders(0.3, testTender(0.1, model.TenderCaptured), testTender(0.2, model.TenderCaptured)), model.IntentPaid},
+		{"estorno falhou sem pendentes", intentWithTenders(100, testTender(60, model.TenderCaptured), testTender(40, model.TenderVoided)), model.IntentAuthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recoveryStatus(tt.intent); got != tt.want {
+				t.Errorf("recoveryStatus = %s, esperado %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecisionSeverity(t *testing.T) {
+	if !(decisionSeverity(model.DecisionDenied) > decisionSeverity(model.DecisionReview) &&
+		decisionSeverity(model.DecisionReview) > decisionSeverity(model.DecisionApproved)) {
+		t.Error("esperado recusa > revisão > aprovação")
+	}
+}
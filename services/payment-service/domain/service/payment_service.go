@@ -3,6 +3,15 @@ package service
 import (
 	"Varejo-Golang-Microservices/services/payment-service/domain/model"
 	"Varejo-Golang-Microservices/services/payment-service/domain/repository"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrPaymentDenied      = errors.New("pagamento recusado pela análise de fraude")
+	ErrPaymentUnderReview = errors.New("pagamento em revisão de fraude não pode ser alterado")
 )
 
 type PaymentService interface {
@@ -14,12 +23,14 @@ type PaymentService interface {
 }
 
 type PaymentServiceImpl struct {
-	paymentRepo *repository.MongoPaymentRepository
+	paymentRepo  *repository.MongoPaymentRepository
+	fraudService FraudService
 }
 
-func NewPaymentService(paymentRepo *repository.MongoPaymentRepository) PaymentService {
+func NewPaymentService(paymentRepo *repository.MongoPaymentRepository, fraudService FraudService) PaymentService {
 	return &PaymentServiceImpl{
-		paymentRepo: paymentRepo,
+		paymentRepo:  paymentRepo,
+		fraudService: fraudService,
 	}
 }
 
@@ -31,14 +42,125 @@ func (s *PaymentServiceImpl) GetPaymentByID(id string) (*model.Payment, error) {
 	return s.paymentRepo.FindByID(id)
 }
 
+// SavePayment pontua o pagamento no motor de fraude antes de gravá-lo.
+// Pagamentos recusados são gravados como FAILED e retornam ErrPaymentDenied;
+// pagamentos com pontuação intermediária ficam UNDER_REVIEW até a decisão manual.
+// O cartão é gravado apenas com o token e o número mascarado, sem o CVV.
 func (s *PaymentServiceImpl) SavePayment(payment *model.Payment) error {
-	return s.paymentRepo.Save(payment)
+	if IsCardPayment(payment.Method.Type) {
+		if err := ValidateCard(&payment.Method, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if payment.Method.CardNumber != "" {
+		payment.Method.CardToken = model.TokenizeCard(payment.Method.CardNumber)
+	}
+	payment.Method.RedactCard()
+
+	// As entradas das regras de velocidade e de conta nova não vêm do cliente
+	payment.PaymentDate = time.Now()
+	payment.CustomerSince = customerSince(payment.CustomerID)
+
+	assessment, err := s.fraudService.Assess(payment)
+	if err != nil {
+		return err
+	}
+
+	requestedStatus := payment.Status
+	payment.FraudScore = assessment.Score
+	switch assessment.Decision {
+	case model.DecisionDenied:
+		payment.Status = model.Failed
+	case model.DecisionReview:
+		payment.Status = model.UnderReview
+	}
+
+	if err := s.paymentRepo.Save(payment); err != nil {
+		return err
+	}
+
+	if err := s.fraudService.Record(payment, assessment, requestedStatus); err != nil {
+		return err
+	}
+
+	if assessment.Decision == model.DecisionDenied {
+		return ErrPaymentDenied
+	}
+
+	return nil
 }
 
+// UpdatePayment altera o valor e o método de pagamento. Quando um dos dois muda, o
+// pagamento é reavaliado no motor de fraude como em SavePayment: recusado vira FAILED
+// e retorna ErrPaymentDenied, e a pontuação intermediária o envia para a revisão.
+// Pagamentos que já estão em revisão não podem ser alterados.
 func (s *PaymentServiceImpl) UpdatePayment(payment *model.Payment) error {
-	return s.paymentRepo.Update(payment)
+	if IsCardPayment(payment.Method.Type) {
+		if err := ValidateCard(&payment.Method, time.Now()); err != nil {
+			return err
+		}
+		payment.Method.CardToken = model.TokenizeCard(payment.Method.CardNumber)
+	}
+	payment.Method.RedactCard()
+
+	current, err := s.paymentRepo.FindByID(payment.ID.Hex())
+	if err != nil {
+		return err
+	}
+	if current.Status == model.UnderReview {
+		return ErrPaymentUnderReview
+	}
+
+	// Os dados usados pelas regras vêm do pagamento gravado; só o valor e o método mudam
+	updated := *current
+	updated.Amount = payment.Amount
+	updated.Method = payment.Method
+
+	if updated.Amount == current.Amount && updated.Method == current.Method {
+		*payment = updated
+		return nil
+	}
+
+	assessment, err := s.fraudService.Assess(&updated)
+	if err != nil {
+		return err
+	}
+
+	updated.FraudScore = assessment.Score
+	switch assessment.Decision {
+	case model.DecisionDenied:
+		updated.Status = model.Failed
+	case model.DecisionReview:
+		updated.Status = model.UnderReview
+	}
+
+	if err := s.paymentRepo.Update(&updated, current.Status); err != nil {
+		return err
+	}
+	*payment = updated
+
+	if err := s.fraudService.Record(&updated, assessment, current.Status); err != nil {
+		return err
+	}
+
+	if assessment.Decision == model.DecisionDenied {
+		return ErrPaymentDenied
+	}
+
+	return nil
 }
 
 func (s *PaymentServiceImpl) DeletePayment(id string) error {
 	return s.paymentRepo.Delete(id)
 }
+
+// Data de cadastro do cliente, tirada do ObjectID gerado pelo customer-service na
+// criação do cadastro. IDs em outro formato não acionam a regra de conta nova.
+func customerSince(customerID string) time.Time {
+	id, err := primitive.ObjectIDFromHex(customerID)
+	if err != nil {
+		return time.Time{}
+	}
+	return id.Timestamp()
+}
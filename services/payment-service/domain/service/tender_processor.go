@@ -0,0 +1,155 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TenderProcessor autoriza, captura e estorna um tender junto ao meio de pagamento.
+// Void desfaz uma autorização; Refund devolve um valor já capturado.
+// Cada PaymentType pode ter seu próprio processador.
+type TenderProcessor interface {
+	Authorize(intent *model.PaymentIntent, tender *model.Tender) (string, error)
+	Capture(intent *model.PaymentIntent, tender *model.Tender) error
+	Void(intent *model.PaymentIntent, tender *model.Tender) error
+	Refund(intent *model.PaymentIntent, tender *model.Tender) error
+}
+
+// GatewayTenderProcessor atende cartões, PayPal e PIX. Ainda não há integração
+// com adquirente: a autorização valida os dados mínimos e gera um código local.
+type GatewayTenderProcessor struct{}
+
+func NewGatewayTenderProcessor() *GatewayTenderProcessor {
+	return &GatewayTenderProcessor{}
+}
+
+// Validate confere os dados do cartão, registra bandeira e BIN no tender e mascara
+// o número. O número completo e o CVV não chegam a ser gravados.
+func (p *GatewayTenderProcessor) Validate(intent *model.PaymentIntent, tender *model.Tender) error {
+	if !IsCardPayment(tender.Type) {
+		return nil
+	}
+
+	if err := ValidateCard(&tender.Method, time.Now()); err != nil {
+		return err
+	}
+	tender.Method.CardToken = model.TokenizeCard(tender.Method.CardNumber)
+	tender.Method.RedactCard()
+	return nil
+}
+
+// Authorize usa o token gravado em Validate; o cartão já foi validado na inclusão do tender
+func (p *GatewayTenderProcessor) Authorize(intent *model.PaymentIntent, tender *model.Tender) (string, error) {
+	if IsCardPayment(tender.Type) && tender.Method.CardToken == "" {
+		return "", errors.New("cartão sem token")
+	}
+
+	return uuid.New().String(), nil
+}
+
+func (p *GatewayTenderProcessor) Capture(intent *model.PaymentIntent, tender *model.Tender) error {
+	if tender.AuthorizationCode == "" {
+		return errors.New("tender sem autorização")
+	}
+	return nil
+}
+
+func (p *GatewayTenderProcessor) Void(intent *model.PaymentIntent, tender *model.Tender) error {
+	return nil
+}
+
+func (p *GatewayTenderProcessor) Refund(intent *model.PaymentIntent, tender *model.Tender) error {
+	return nil
+}
+
+// DefaultTenderProcessors associa cada forma de pagamento ao seu processador.
+func DefaultTenderProcessors() map[model.PaymentType]TenderProcessor {
+	gateway := NewGatewayTenderProcessor()
+	return map[model.PaymentType]TenderProcessor{
+		model.CreditCard: gateway,
+		model.DebitCard:  gateway,
+		model.PayPal:     gateway,
+		model.Pix:        gateway,
+	}
+}
+
+// TenderValidator é implementado pelos processadores que precisam validar o
+// tender no momento em que ele é incluído no intent (ex.: PIN do cartão-presente,
+// que não é armazenado).
+type TenderValidator interface {
+	Validate(intent *model.PaymentIntent, tender *model.Tender) error
+}
+
+// WalletTenderProcessor resgata cartões-presente e crédito em loja. A autorização
+// debita o saldo (hold), a captura confirma o débito e o estorno devolve o valor.
+type WalletTenderProcessor struct {
+	wallet      WalletService
+	accountType model.WalletAccountType
+}
+
+func NewGiftCardTenderProcessor(wallet WalletService) *WalletTenderProcessor {
+	return &WalletTenderProcessor{wallet: wallet, accountType: model.AccountGiftCard}
+}
+
+func NewStoreCreditTenderProcessor(wallet WalletService) *WalletTenderProcessor {
+	return &WalletTenderProcessor{wallet: wallet, accountType: model.AccountStoreCredit}
+}
+
+func (p *WalletTenderProcessor) Validate(intent *model.PaymentIntent, tender *model.Tender) error {
+	if p.accountType == model.AccountStoreCredit {
+		credit, err := p.wallet.GetStoreCredit(intent.CustomerID)
+		if err != nil {
+			return err
+		}
+		if credit.Balance < tender.Amount {
+			return fmt.Errorf("crédito em loja insuficiente: saldo %.2f", credit.Balance)
+		}
+		return nil
+	}
+
+	card, err := p.wallet.VerifyGiftCard(tender.Method.GiftCardCode, tender.Method.GiftCardPIN)
+	if err != nil {
+		return err
+	}
+	if !card.CanDebit(tender.Amount, time.Now()) {
+		return fmt.Errorf("saldo do cartão-presente insuficiente: saldo %.2f", card.Balance)
+	}
+	return nil
+}
+
+func (p *WalletTenderProcessor) Authorize(intent *model.PaymentIntent, tender *model.Tender) (string, error) {
+	tx, err := p.wallet.Hold(p.accountType, p.accountKey(intent, tender), tender.Amount, tenderReference(intent, tender))
+	if err != nil {
+		return "", err
+	}
+	return tx.ID.Hex(), nil
+}
+
+func (p *WalletTenderProcessor) Capture(intent *model.PaymentIntent, tender *model.Tender) error {
+	return p.wallet.Capture(p.accountType, p.accountKey(intent, tender), tender.Amount, tenderReference(intent, tender))
+}
+
+func (p *WalletTenderProcessor) Void(intent *model.PaymentIntent, tender *model.Tender) error {
+	return p.wallet.Release(p.accountType, p.accountKey(intent, tender), tender.Amount, tenderReference(intent, tender))
+}
+
+// Refund devolve à conta o valor capturado, como no estorno de uma autorização
+func (p *WalletTenderProcessor) Refund(intent *model.PaymentIntent, tender *model.Tender) error {
+	return p.wallet.Release(p.accountType, p.accountKey(intent, tender), tender.Amount, tenderReference(intent, tender))
+}
+
+func (p *WalletTenderProcessor) accountKey(intent *model.PaymentIntent, tender *model.Tender) string {
+	if p.accountType == model.AccountStoreCredit {
+		return intent.CustomerID
+	}
+	return tender.Method.GiftCardCode
+}
+
+// Referência gravada no log da carteira para ligar o lançamento ao pedido
+func tenderReference(intent *model.PaymentIntent, tender *model.Tender) string {
+	return intent.OrderID + "/" + tender.ID.Hex()
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"Varejo-Golang-Microservices/services/payment-service/domain/repository"
+	"crypto/hmac"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	giftCardCodeLength = 16
+	giftCardPINLength  = 6
+	// Validade padrão quando a emissão não informa a data de expiração
+	giftCardDefaultValidity = 365 * 24 * time.Hour
+	// Tentativas com PIN errado antes de travar o cartão, e por quanto tempo
+	giftCardMaxPINAttempts = 5
+	giftCardPINLockout     = 30 * time.Minute
+)
+
+var (
+	ErrGiftCardNotFound = errors.New("cartão-presente não encontrado")
+	ErrInvalidGiftCard  = errors.New("código ou PIN do cartão-presente inválido")
+	ErrGiftCardLocked   = errors.New("cartão-presente travado por excesso de tentativas com PIN errado")
+)
+
+type WalletService interface {
+	IssueGiftCard(amount float64, expiresAt time.Time, channel model.SalesChannel, activate bool) (*model.GiftCard, string, error)
+	ActivateGiftCard(code string, channel model.SalesChannel) (*model.GiftCard, error)
+	BlockGiftCard(code string) (*model.GiftCard, error)
+	VerifyGiftCard(code, pin string) (*model.GiftCard, error)
+	ListGiftCardTransactions(code, pin string) ([]*model.WalletTransaction, error)
+	GetStoreCredit(customerID string) (*model.StoreCredit, error)
+	CreditStoreCredit(customerID string, amount float64, reference, note string) (*model.StoreCredit, error)
+	ListStoreCreditTransactions(customerID string) ([]*model.WalletTransaction, error)
+	Hold(accountType model.WalletAccountType, key string, amount float64, reference string) (*model.WalletTransaction, error)
+	Capture(accountType model.WalletAccountType, key string, amount float64, reference string) error
+	Release(accountType model.WalletAccountType, key string, amount float64, reference string) error
+}
+
+type WalletServiceImpl struct {
+	walletRepo *repository.MongoWalletRepository
+}
+
+func NewWalletService(walletRepo *repository.MongoWalletRepository) WalletService {
+	return &WalletServiceImpl{
+		walletRepo: walletRepo,
+	}
+}
+
+// IssueGiftCard emite um cartão-presente com código único e PIN. O PIN só é
+// devolvido nesta chamada; no banco fica apenas o hash.
+func (s *WalletServiceImpl) IssueGiftCard(amount float64, expiresAt time.Time, channel model.SalesChannel, activate bool) (*model.GiftCard, string, error) {
+	amount = model.RoundMoney(amount)
+	if amount <= 0 {
+		return nil, "", errors.New("o valor do cartão-presente deve ser maior que zero")
+	}
+	if channel == "" {
+		return nil, "", errors.New("o canal de emissão é obrigatório")
+	}
+
+	now := time.Now()
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(giftCardDefaultValidity)
+	}
+	if !expiresAt.After(now) {
+		return nil, "", errors.New("a data de validade deve ser futura")
+	}
+
+	pin, err := randomDigits(giftCardPINLength)
+	if err != nil {
+		return nil, "", err
+	}
+
+	card := &model.GiftCard{
+		InitialAmount: amount,
+		Balance:       amount,
+		Status:        model.GiftCardInactive,
+		IssuedChannel: channel,
+		ExpiresAt:     expiresAt,
+		CreatedAt:     now,
+	}
+	if activate {
+		card.Status = model.GiftCardActive
+		card.ActivatedChannel = channel
+		card.ActivatedAt = &now
+	}
+
+	// Gera um novo código em caso de colisão com um cartão existente
+	for attempt := 0; attempt < 5; attempt++ {
+		card.ID = primitive.NewObjectID()
+		card.Code, err = randomDigits(giftCardCodeLength)
+		if err != nil {
+			return nil, "", err
+		}
+		card.PINHash = model.HashGiftCardPIN(card.Code, pin)
+
+		err = s.walletRepo.SaveGiftCard(card)
+		if err == nil {
+			break
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, "", err
+		}
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Sem o lançamento de emissão o saldo não teria origem no log: o cartão é descartado
+	if _, err := s.log(model.AccountGiftCard, card.Code, model.TxIssue, amount, card.Balance, "", channel, ""); err != nil {
+		if deleteErr := s.walletRepo.DeleteGiftCard(card.ID); deleteErr != nil {
+			log.Printf("Erro ao descartar o cartão-presente %s: %v\n", card.ID.Hex(), deleteErr)
+		}
+		return nil, "", err
+	}
+	if activate {
+		if _, err := s.log(model.AccountGiftCard, card.Code, model.TxActivate, 0, card.Balance, "", channel, ""); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return card, pin, nil
+}
+
+func (s *WalletServiceImpl) ActivateGiftCard(code string, channel model.SalesChannel) (*model.GiftCard, error) {
+	if channel == "" {
+		return nil, errors.New("o canal de ativação é obrigatório")
+	}
+
+	card, err := s.walletRepo.ActivateGiftCard(code, channel, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.log(model.AccountGiftCard, code, model.TxActivate, 0, card.Balance, "", channel, ""); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+func (s *WalletServiceImpl) BlockGiftCard(code string) (*model.GiftCard, error) {
+	card, err := s.walletRepo.BlockGiftCard(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.log(model.AccountGiftCard, code, model.TxBlock, 0, card.Balance, "", "", ""); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// VerifyGiftCard confere código e PIN e retorna o cartão quando ele pode ser usado.
+func (s *WalletServiceImpl) VerifyGiftCard(code, pin string) (*model.GiftCard, error) {
+	card, err := s.checkPIN(code, pin)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := giftCardUsable(card, time.Now()); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// O cartão só pode ser usado ativo e dentro da validade
+func giftCardUsable(card *model.GiftCard, now time.Time) error {
+	if card.Status != model.GiftCardActive {
+		return fmt.Errorf("cartão-presente não está ativo (%s)", card.Status)
+	}
+	if card.IsExpired(now) {
+		return errors.New("cartão-presente expirado")
+	}
+	return nil
+}
+
+// ListGiftCardTransactions exige o PIN, como a consulta de saldo, inclusive para
+// cartões bloqueados ou vencidos.
+func (s *WalletServiceImpl) ListGiftCardTransactions(code, pin string) ([]*model.WalletTransaction, error) {
+	if _, err := s.checkPIN(code, pin); err != nil {
+		return nil, err
+	}
+
+	return s.walletRepo.ListTransactions(model.AccountGiftCard, code)
+}
+
+// Confere o PIN contando as tentativas erradas; com giftCardMaxPINAttempts erros
+// seguidos o cartão fica travado por giftCardPINLockout, mesmo para o PIN certo.
+func (s *WalletServiceImpl) checkPIN(code, pin string) (*model.GiftCard, error) {
+	card, err := s.walletRepo.FindGiftCardByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if card == nil {
+		return nil, ErrInvalidGiftCard
+	}
+
+	now := time.Now()
+	if err := verifyPIN(card, pin, now); err != nil {
+		if errors.Is(err, ErrInvalidGiftCard) {
+			if err := s.walletRepo.RegisterFailedPIN(code, giftCardMaxPINAttempts, now.Add(giftCardPINLockout)); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	if card.FailedPINs > 0 || card.LockedUntil != nil {
+		if err := s.walletRepo.ResetFailedPINs(code); err != nil {
+			return nil, err
+		}
+	}
+	return card, nil
+}
+
+// Confere o PIN do cartão: travado, recusa até o PIN certo; fora da trava, compara
+// o HMAC do PIN informado com o gravado
+func verifyPIN(card *model.GiftCard, pin string, now time.Time) error {
+	if card.IsLocked(now) {
+		return ErrGiftCardLocked
+	}
+	if !hmac.Equal([]byte(card.PINHash), []byte(model.HashGiftCardPIN(card.Code, pin))) {
+		return ErrInvalidGiftCard
+	}
+	return nil
+}
+
+func (s *WalletServiceImpl) GetStoreCredit(customerID string) (*model.StoreCredit, error) {
+	credit, err := s.walletRepo.FindStoreCredit(customerID)
+	if err != nil {
+		return nil, err
+	}
+	if credit == nil {
+		return &model.StoreCredit{CustomerID: customerID}, nil
+	}
+	return credit, nil
+}
+
+// CreditStoreCredit adiciona crédito em loja ao cliente, por exemplo após uma devolução.
+func (s *WalletServiceImpl) CreditStoreCredit(customerID string, amount float64, reference, note string) (*model.StoreCredit, error) {
+	amount = model.RoundMoney(amount)
+	if customerID == "" {
+		return nil, errors.New("o ID do cliente é obrigatório")
+	}
+	if amount <= 0 {
+		return nil, errors.New("o valor do crédito deve ser maior que zero")
+	}
+
+	if _, _, err := s.post(model.AccountStoreCredit, customerID, model.TxCredit, amount, reference, note); err != nil {
+		return nil, err
+	}
+
+	return s.GetStoreCredit(customerID)
+}
+
+func (s *WalletServiceImpl) ListStoreCreditTransactions(customerID string) ([]*model.WalletTransaction, error) {
+	return s.walletRepo.ListTransactions(model.AccountStoreCredit, customerID)
+}
+
+// Hold debita o saldo no momento da autorização do tender. O valor volta para
+// a conta em Release ou é confirmado em Capture.
+func (s *WalletServiceImpl) Hold(accountType model.WalletAccountType, key string, amount float64, reference string) (*model.WalletTransaction, error) {
+	_, tx, err := s.post(accountType, key, model.TxHold, -amount, reference, "")
+	return tx, err
+}
+
+func (s *WalletServiceImpl) Capture(accountType model.WalletAccountType, key string, amount float64, reference string) error {
+	var balance float64
+	switch accountType {
+	case model.AccountGiftCard:
+		card, err := s.walletRepo.FindGiftCardByCode(key)
+		if err != nil {
+			return err
+		}
+		if card == nil {
+			return ErrGiftCardNotFound
+		}
+		balance = card.Balance
+	case model.AccountStoreCredit:
+		credit, err := s.GetStoreCredit(key)
+		if err != nil {
+			return err
+		}
+		balance = credit.Balance
+	}
+
+	// A captura não altera o saldo: o valor já foi debitado em Hold
+	_, err := s.log(accountType, key, model.TxCapture, 0, balance, reference, "", fmt.Sprintf("captura de %.2f", amount))
+	return err
+}
+
+func (s *WalletServiceImpl) Release(accountType model.WalletAccountType, key string, amount float64, reference string) error {
+	_, _, err := s.post(accountType, key, model.TxRelease, amount, reference, "")
+	return err
+}
+
+// Altera o saldo e grava o lançamento correspondente. Se o lançamento falhar, a
+// alteração de saldo é desfeita para que saldo e log não divirjam.
+func (s *WalletServiceImpl) post(accountType model.WalletAccountType, key string, txType model.WalletTransactionType, delta float64, reference, note string) (float64, *model.WalletTransaction, error) {
+	balance, err := s.adjust(accountType, key, delta)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	tx, err := s.log(accountType, key, txType, delta, balance, reference, "", note)
+	if err != nil {
+		if revertErr := s.walletRepo.RevertBalance(accountType, key, delta); revertErr != nil {
+			log.Printf("Erro ao desfazer %s de %.2f na conta %s %s sem lançamento: %v\n", txType, delta, accountType, key, revertErr)
+		}
+		return 0, nil, err
+	}
+
+	return balance, tx, nil
+}
+
+func (s *WalletServiceImpl) adjust(accountType model.WalletAccountType, key string, delta float64) (float64, error) {
+	now := time.Now()
+	switch accountType {
+	case model.AccountGiftCard:
+		card, err := s.walletRepo.AdjustGiftCardBalance(key, delta, now)
+		if err != nil {
+			return 0, err
+		}
+		return card.Balance, nil
+	case model.AccountStoreCredit:
+		credit, err := s.walletRepo.AdjustStoreCredit(key, delta, now)
+		if err != nil {
+			return 0, err
+		}
+		return credit.Balance, nil
+	default:
+		return 0, fmt.Errorf("tipo de conta inválido: %s", accountType)
+	}
+}
+
+// Registra a transação no log de saldo
+func (s *WalletServiceImpl) log(accountType model.WalletAccountType, key string, txType model.WalletTransactionType, amount, balance float64, reference string, channel model.SalesChannel, note string) (*model.WalletTransaction, error) {
+	tx := &model.WalletTransaction{
+		ID:           primitive.NewObjectID(),
+		AccountType:  accountType,
+		AccountKey:   key,
+		Type:         txType,
+		Amount:       model.RoundMoney(amount),
+		BalanceAfter: model.RoundMoney(balance),
+		Reference:    reference,
+		Channel:      channel,
+		Note:         note,
+		CreatedAt:    time.Now(),
+	}
+
+	if err := s.walletRepo.InsertTransaction(tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// Gera uma sequência de dígitos aleatórios criptograficamente segura
+func randomDigits(length int) (string, error) {
+	digits := make([]byte, length)
+	for i := range digits {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		digits[i] = byte('0' + n.Int64())
+	}
+	return string(digits), nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifyPIN(t *testing.T) {
+	model.SetGiftCardPINKey("chave-de-teste")
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	lockedUntil := now.Add(10 * time.Minute)
+	lockExpired := now.Add(-time.Minute)
+
+	card := func(lock *time.Time) *model.GiftCard {
+		return &model.GiftCard{
+			Code:        "1234567890123456",
+			PINHash:     model.HashGiftCardPIN("1234567890123456", "123456"),
+			LockedUntil: lock,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		card    *model.GiftCard
+		pin     string
+		wantErr error
+	}{
+		{"PIN correto", card(nil), "123456", nil},
+		{"PIN errado", card(nil), "654321", ErrInvalidGiftCard},
+		{"PIN vazio", card(nil), "", ErrInvalidGiftCard},
+		{"travado recusa o PIN correto", card(&lockedUntil), "123456", ErrGiftCardLocked},
+		{"travado recusa o PIN errado sem contar", card(&lockedUntil), "654321", ErrGiftCardLocked},
+		{"trava vencida aceita o PIN correto", card(&lockExpired), "123456", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifyPIN(tt.card, tt.pin, now); !errors.Is(err, tt.wantErr) {
+				t.Errorf("verifyPIN = %v, esperado %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHashGiftCardPINUsesKey(t *testing.T) {
+	model.SetGiftCardPINKey("chave-a")
+	first := model.HashGiftCardPIN("1234567890123456", "123456")
+	model.SetGiftCardPINKey("chave-b")
+	second := model.HashGiftCardPIN("1234567890123456", "123456")
+
+	if first == second {
+		t.Error("o hash do PIN deve depender da chave")
+	}
+	if first == model.HashGiftCardPIN("1234567890123457", "123456") {
+		t.Error("o hash do PIN deve depender do código do cartão")
+	}
+}
+
+func TestGiftCardShouldLock(t *testing.T) {
+	tests := []struct {
+		name   string
+		failed int
+		want   bool
+	}{
+		{"nenhuma tentativa errada", 0, false},
+		{"abaixo do limite", giftCardMaxPINAttempts - 1, false},
+		{"no limite", giftCardMaxPINAttempts, true},
+		{"acima do limite", giftCardMaxPINAttempts + 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := &model.GiftCard{FailedPINs: tt.failed}
+			if got := card.ShouldLock(giftCardMaxPINAttempts); got != tt.want {
+				t.Errorf("ShouldLock com %d erros = %v, esperado %v", tt.failed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGiftCardCanDebit(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	valid := now.Add(24 * time.Hour)
+	expired := now.Add(-24 * time.Hour)
+
+	tests := []struct {
+		name   string
+		card   model.GiftCard
+		amount float64
+		want   bool
+	}{
+		{"saldo suficiente", model.GiftCard{Status: model.GiftCardActive, Balance: 100, ExpiresAt: valid}, 60, true},
+		{"saldo exato", model.GiftCard{Status: model.GiftCardActive, Balance: 100, ExpiresAt: valid}, 100, true},
+		{"saldo insuficiente", model.GiftCard{Status: model.GiftCardActive, Balance: 50, ExpiresAt: valid}, 50.01, false},
+		{"cartão inativo", model.GiftCard{Status: model.GiftCardInactive, Balance: 100, ExpiresAt: valid}, 10, false},
+		{"cartão bloqueado", model.GiftCard{Status: model.GiftCardBlocked, Balance: 100, ExpiresAt: valid}, 10, false},
+		{"cartão vencido", model.GiftCard{Status: model.GiftCardActive, Balance: 100, ExpiresAt: expired}, 10, false},
+		{"sem validade", model.GiftCard{Status: model.GiftCardActive, Balance: 100}, 10, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.card.CanDebit(tt.amount, now); got != tt.want {
+				t.Errorf("CanDebit(%.2f) = %v, esperado %v", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGiftCardUsable(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		card    model.GiftCard
+		wantErr bool
+	}{
+		{"ativo e válido", model.GiftCard{Status: model.GiftCardActive, ExpiresAt: now.Add(time.Hour)}, false},
+		{"inativo", model.GiftCard{Status: model.GiftCardInactive, ExpiresAt: now.Add(time.Hour)}, true},
+		{"bloqueado", model.GiftCard{Status: model.GiftCardBlocked, ExpiresAt: now.Add(time.Hour)}, true},
+		{"vencido", model.GiftCard{Status: model.GiftCardActive, ExpiresAt: now.Add(-time.Hour)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := giftCardUsable(&tt.card, now); (err != nil) != tt.wantErr {
+				t.Errorf("giftCardUsable = %v, esperado erro: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"time"
+)
+
+type DisputeDTO struct {
+	PaymentID     string    `json:"paymentId"`
+	ReasonCode    string    `json:"reasonCode"`
+	Amount        float64   `json:"amount"`
+	Fee           float64   `json:"fee"`
+	EvidenceDueAt time.Time `json:"evidenceDueAt"`
+}
+
+type EvidenceDTO struct {
+	Type        model.EvidenceType `json:"type"`
+	FileName    string             `json:"fileName"`
+	ContentType string             `json:"contentType"`
+	URL         string             `json:"url"`
+	Description string             `json:"description"`
+}
+
+type DisputeActionDTO struct {
+	Status model.DisputeStatus `json:"status"`
+	Note   string              `json:"note"`
+}
+
+// DisputeWebhookDTO é o corpo enviado pelo provedor de pagamento
+type DisputeWebhookDTO struct {
+	Type          model.DisputeEventType `json:"type"`
+	DisputeID     string                 `json:"disputeId"`
+	PaymentID     string                 `json:"paymentId"`
+	ReasonCode    string                 `json:"reasonCode"`
+	Amount        float64                `json:"amount"`
+	Fee           float64                `json:"fee"`
+	EvidenceDueAt time.Time              `json:"evidenceDueAt"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "Varejo-Golang-Microservices/services/payment-service/domain/model"
+
+type ReviewDecisionDTO struct {
+	Note string `json:"note"`
+}
+
+type BlacklistEntryDTO struct {
+	Type   model.BlacklistType `json:"type"`
+	Value  string              `json:"value"`
+	Reason string              `json:"reason"`
+}
@@ -6,19 +6,32 @@ import (
 )
 
 type PaymentDTO struct {
-	ID        string             `json:"id"`
-	OrderID   string             `json:"orderId"`
-	CustomerID string            `json:"customerId"`
-	Amount    float64            `json:"amount"`
-	Method    PaymentMethodDTO   `json:"method"`
-	Status    model.PaymentStatus `json:"status"`
-	PaymentDate time.Time         `json:"paymentDate"`
-	UpdatedAt   time.Time         `json:"updatedAt"`
+	ID              string              `json:"id"`
+	OrderID         string              `json:"orderId"`
+	CustomerID      string              `json:"customerId"`
+	Amount          float64             `json:"amount"`
+	Method          PaymentMethodDTO    `json:"method"`
+	Status          model.PaymentStatus `json:"status"`
+	PaymentDate     time.Time           `json:"paymentDate"`
+	UpdatedAt       time.Time           `json:"updatedAt"`
+	BillingAddress  AddressDTO          `json:"billingAddress"`
+	ShippingAddress AddressDTO          `json:"shippingAddress"`
 }
 
 type PaymentMethodDTO struct {
-	Type        model.PaymentType `json:"type"`
-	CardNumber  string            `json:"cardNumber,omitempty"` 
-	Expiry      string            `json:"expiry,omitempty"`    
-	CVV         string            `json:"cvv,omitempty"`        
+	Type       model.PaymentType `json:"type"`
+	CardNumber string            `json:"cardNumber,omitempty"`
+	Expiry     string            `json:"expiry,omitempty"`
+	CVV        string            `json:"cvv,omitempty"`
+
+	GiftCardCode string `json:"giftCardCode,omitempty"`
+	GiftCardPIN  string `json:"giftCardPin,omitempty"`
+}
+
+type AddressDTO struct {
+	Street     string `json:"street"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	PostalCode string `json:"postalCode"`
+	Country    string `json:"country"`
 }
@@ -0,0 +1,14 @@
+package dto
+
+import "Varejo-Golang-Microservices/services/payment-service/domain/model"
+
+// O total e o cliente do intent são lidos do pedido no serviço de pedidos
+type PaymentIntentDTO struct {
+	OrderID string `json:"orderId"`
+}
+
+type TenderDTO struct {
+	Type   model.PaymentType `json:"type"`
+	Amount float64           `json:"amount"`
+	Method PaymentMethodDTO  `json:"method"`
+}
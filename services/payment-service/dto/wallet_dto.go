@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"Varejo-Golang-Microservices/services/payment-service/domain/model"
+	"time"
+)
+
+type IssueGiftCardDTO struct {
+	Amount    float64            `json:"amount"`
+	ExpiresAt time.Time          `json:"expiresAt"`
+	Channel   model.SalesChannel `json:"channel"`
+	Activate  bool               `json:"activate"`
+}
+
+type GiftCardChannelDTO struct {
+	Channel model.SalesChannel `json:"channel"`
+}
+
+type GiftCardPINDTO struct {
+	PIN string `json:"pin"`
+}
+
+type StoreCreditDTO struct {
+	Amount    float64 `json:"amount"`
+	Reference string  `json:"reference"`
+	Note      string  `json:"note"`
+}
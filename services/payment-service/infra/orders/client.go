@@ -0,0 +1,63 @@
+// Package orders consulta o serviço de pedidos a partir do serviço de pagamentos.
+package orders
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrUnavailable indica que o serviço de pedidos não respondeu
+var ErrUnavailable = errors.New("serviço de pedidos indisponível")
+
+// Order contém os campos do pedido usados na cobrança
+type Order struct {
+	ID         string  `json:"id"`
+	CustomerID string  `json:"customerId"`
+	TotalPrice float64 `json:"totalPrice"`
+	Status     string  `json:"status"`
+}
+
+// OrderReader busca o pedido que está sendo pago
+type OrderReader interface {
+	GetOrder(id string) (*Order, error)
+}
+
+// Client consulta o endpoint /orders/:id do serviço de pedidos
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// GetOrder retorna nil, sem erro, quando o pedido não existe
+func (c *Client) GetOrder(id string) (*Order, error) {
+	resp, err := c.http.Get(c.baseURL + "/orders/" + url.PathEscape(id))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrUnavailable, resp.StatusCode)
+	}
+
+	var order Order
+	if err := json.NewDecoder(resp.Body).Decode(&order); err != nil {
+		return nil, fmt.Errorf("%w: resposta inválida: %v", ErrUnavailable, err)
+	}
+	return &order, nil
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"Varejo-Golang-Microservices/services/product-service/domain/service"
+	"Varejo-Golang-Microservices/services/product-service/dto"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BarcodeHandler struct {
+	Service service.BarcodeService
+}
+
+// Inicializa um novo manipulador de códigos de barras com o serviço fornecido
+func NewBarcodeHandler(s service.BarcodeService) *BarcodeHandler {
+	return &BarcodeHandler{
+		Service: s,
+	}
+}
+
+func barcodeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrBarcodeNotFound), errors.Is(err, repository.ErrVariantNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrGTINAssigned), errors.Is(err, repository.ErrDuplicateGTIN),
+		errors.Is(err, service.ErrGTINRangeExhausted):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrGTINPrefixNotConfigured):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// Resolve o código lido pelo leitor (GTIN-8, 12, 13 ou 14) para o produto ou a variante
+func (h *BarcodeHandler) GetByBarcode(c *gin.Context) {
+	match, err := h.Service.FindByBarcode(c.Param("gtin"))
+	if err != nil {
+		c.JSON(barcodeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	match.Product = match.Product.Localized(requestLocale(c))
+	c.JSON(http.StatusOK, match)
+}
+
+// Gera um EAN-13 interno para o produto ou, com sku no corpo, para a variante
+func (h *BarcodeHandler) GenerateEAN13(c *gin.Context) {
+	var generationDTO dto.BarcodeGenerationDTO
+	// O corpo é opcional
+	_ = c.ShouldBindJSON(&generationDTO)
+
+	product, err := h.Service.GenerateEAN13(c.Param("id"), generationDTO.SKU)
+	if err != nil {
+		c.JSON(barcodeErrorStatus(err), gin.H{"error": "Erro ao gerar código de barras. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Código de barras gerado com sucesso.", "data": product})
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/service"
+	"Varejo-Golang-Microservices/services/product-service/dto"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type BundleHandler struct {
+	Service service.BundleService
+}
+
+// Inicializa um novo manipulador de kits com o serviço fornecido
+func NewBundleHandler(s service.BundleService) *BundleHandler {
+	return &BundleHandler{
+		Service: s,
+	}
+}
+
+// Fora as validações do kit, o erro vem da busca do produto
+func bundleErrorStatus(err error) int {
+	if errors.Is(err, service.ErrInvalidBundle) {
+		return http.StatusBadRequest
+	}
+	return http.StatusNotFound
+}
+
+// IDs inválidos viram ObjectID zero e são rejeitados como componente não encontrado
+func convertDTOBundle(bundleDTO *dto.BundleDTO) *model.Bundle {
+	if bundleDTO == nil {
+		return nil
+	}
+
+	bundle := &model.Bundle{
+		PricingMode:     bundleDTO.PricingMode,
+		DiscountPercent: bundleDTO.DiscountPercent,
+	}
+	for _, componentDTO := range bundleDTO.Components {
+		productID, _ := primitive.ObjectIDFromHex(componentDTO.ProductID)
+		bundle.Components = append(bundle.Components, model.BundleComponent{
+			ProductID: productID,
+			SKU:       componentDTO.SKU,
+			Quantity:  componentDTO.Quantity,
+		})
+	}
+	return bundle
+}
+
+// Lista os kits cadastrados com a disponibilidade calculada
+func (h *BundleHandler) ListBundles(c *gin.Context) {
+	bundles, err := h.Service.ListBundles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar kits"})
+		return
+	}
+
+	c.JSON(http.StatusOK, bundles)
+}
+
+// Substitui os componentes e a precificação de um kit
+func (h *BundleHandler) UpdateBundle(c *gin.Context) {
+	var bundleDTO dto.BundleDTO
+	if err := c.ShouldBindJSON(&bundleDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos. Detalhes: " + err.Error()})
+		return
+	}
+
+	product, err := h.Service.UpdateBundle(c.Param("id"), convertDTOBundle(&bundleDTO))
+	if err != nil {
+		c.JSON(bundleErrorStatus(err), gin.H{"error": "Erro ao atualizar kit. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Kit atualizado com sucesso.", "data": product})
+}
+
+// Rateio do preço do kit entre os componentes, usado pelo pedido (parâmetro quantity; padrão: 1)
+func (h *BundleHandler) GetBreakdown(c *gin.Context) {
+	quantity := 1
+	if value := c.Query("quantity"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro quantity inválido"})
+			return
+		}
+		quantity = parsed
+	}
+
+	breakdown, err := h.Service.GetBreakdown(c.Param("id"), quantity)
+	if err != nil {
+		c.JSON(bundleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, breakdown)
+}
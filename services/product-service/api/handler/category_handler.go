@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"Varejo-Golang-Microservices/services/product-service/domain/service"
+	"Varejo-Golang-Microservices/services/product-service/dto"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CategoryHandler struct {
+	Service service.CategoryService
+}
+
+// Inicializa um novo manipulador de categorias com o serviço fornecido
+func NewCategoryHandler(s service.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		Service: s,
+	}
+}
+
+func categoryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrCategoryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrDuplicateCategoryPath):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrInvalidCategory), errors.Is(err, service.ErrInvalidAttributes):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Converte o ID do pai; vazio significa categoria raiz
+func parseParentID(value string) (*primitive.ObjectID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := primitive.ObjectIDFromHex(value)
+	if err != nil {
+		return nil, errors.New("ID da categoria pai inválido")
+	}
+	return &id, nil
+}
+
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var categoryDTO dto.CategoryNodeDTO
+	if err := c.ShouldBindJSON(&categoryDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao processar os dados da categoria."})
+		return
+	}
+
+	parentID, err := parseParentID(categoryDTO.ParentID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category := &model.CategoryNode{
+		Name:        categoryDTO.Name,
+		Description: categoryDTO.Description,
+		ParentID:    parentID,
+	}
+	if categoryDTO.Position != nil {
+		category.Position = *categoryDTO.Position
+	}
+
+	if err := h.Service.CreateCategory(category); err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": "Erro ao criar categoria. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Categoria criada com sucesso.", "data": category})
+}
+
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	categories, err := h.Service.ListCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar categorias"})
+		return
+	}
+
+	locale := requestLocale(c)
+	localized := make([]*model.CategoryNode, 0, len(categories))
+	for _, category := range categories {
+		localized = append(localized, category.Localized(locale))
+	}
+	c.JSON(http.StatusOK, localized)
+}
+
+func (h *CategoryHandler) GetTree(c *gin.Context) {
+	tree, err := h.Service.GetTree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao montar a árvore de categorias"})
+		return
+	}
+
+	locale := requestLocale(c)
+	localized := make([]*model.CategoryTree, 0, len(tree))
+	for _, node := range tree {
+		localized = append(localized, node.Localized(locale))
+	}
+	c.JSON(http.StatusOK, localized)
+}
+
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	category, err := h.Service.GetCategory(c.Param("id"))
+	if err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, category.Localized(requestLocale(c)))
+}
+
+// Busca a categoria pelo caminho de slugs, ex.: /categories/path/eletronicos/tv/smart-tv
+func (h *CategoryHandler) GetCategoryByPath(c *gin.Context) {
+	category, err := h.Service.GetCategoryByPath(c.Param("path"))
+	if err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, category.Localized(requestLocale(c)))
+}
+
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	var categoryDTO dto.CategoryNodeDTO
+	if err := c.ShouldBindJSON(&categoryDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := h.Service.UpdateCategory(c.Param("id"), categoryDTO.Name, categoryDTO.Description, categoryDTO.Position)
+	if err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": "Erro ao atualizar categoria. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Categoria atualizada com sucesso", "data": category})
+}
+
+func (h *CategoryHandler) MoveCategory(c *gin.Context) {
+	var moveDTO dto.MoveCategoryDTO
+	if err := c.ShouldBindJSON(&moveDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	parentID, err := parseParentID(moveDTO.ParentID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := h.Service.MoveCategory(c.Param("id"), parentID, moveDTO.Position)
+	if err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": "Erro ao mover categoria. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Categoria movida com sucesso", "data": category})
+}
+
+// Mescla a categoria da rota na categoria de destino
+func (h *CategoryHandler) MergeCategory(c *gin.Context) {
+	var mergeDTO dto.MergeCategoryDTO
+	if err := c.ShouldBindJSON(&mergeDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	target, err := h.Service.MergeCategory(c.Param("id"), mergeDTO.TargetID)
+	if err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": "Erro ao mesclar categorias. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Categorias mescladas com sucesso", "data": target})
+}
+
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	if err := h.Service.DeleteCategory(c.Param("id")); err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": "Erro ao excluir categoria. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Categoria excluída com sucesso"})
+}
+
+// Lista os produtos da categoria, incluindo os das subcategorias
+func (h *CategoryHandler) ListProducts(c *gin.Context) {
+	products, err := h.Service.ListProducts(c.Param("id"))
+	if err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, localizeProducts(products, requestLocale(c)))
+}
+
+// Esquema efetivo de atributos da categoria, incluindo os herdados dos ancestrais
+func (h *CategoryHandler) GetAttributeSchema(c *gin.Context) {
+	schema, err := h.Service.GetAttributeSchema(c.Param("id"))
+	if err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attributes": schema})
+}
+
+// Substitui os atributos próprios da categoria
+func (h *CategoryHandler) SetAttributeSchema(c *gin.Context) {
+	var attributeDTOs []dto.AttributeDefinitionDTO
+	if err := c.ShouldBindJSON(&attributeDTOs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao processar os atributos da categoria."})
+		return
+	}
+
+	attributes := make([]model.AttributeDefinition, 0, len(attributeDTOs))
+	for _, attributeDTO := range attributeDTOs {
+		attributes = append(attributes, model.AttributeDefinition{
+			Key:           attributeDTO.Key,
+			Label:         attributeDTO.Label,
+			Type:          attributeDTO.Type,
+			Unit:          attributeDTO.Unit,
+			Required:      attributeDTO.Required,
+			AllowedValues: attributeDTO.AllowedValues,
+		})
+	}
+
+	category, err := h.Service.SetAttributeSchema(c.Param("id"), attributes)
+	if err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": "Erro ao atualizar atributos. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Atributos atualizados com sucesso.", "data": category})
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/service"
+	"Varejo-Golang-Microservices/services/product-service/dto"
+	"Varejo-Golang-Microservices/services/product-service/infra/feed"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedHandler struct {
+	Service service.FeedService
+}
+
+// Inicializa um novo manipulador de feeds com o serviço fornecido
+func NewFeedHandler(s service.FeedService) *FeedHandler {
+	return &FeedHandler{
+		Service: s,
+	}
+}
+
+// Serve o feed do Google Merchant em /feeds/google.xml ou /feeds/google.tsv.
+// Responde 304 quando o ETag enviado em If-None-Match ainda é o atual.
+func (h *FeedHandler) GetGoogleFeed(c *gin.Context) {
+	format, err := feed.ParseFormat(c.Param("file"))
+	if err != nil || !strings.HasPrefix(c.Param("file"), "google.") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed não encontrado"})
+		return
+	}
+
+	document, err := h.Service.GetFeed(format)
+	if err != nil {
+		c.Header("Retry-After", "30")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", document.ETag)
+	c.Header("Last-Modified", document.ModifiedAt.UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", "no-cache")
+
+	if etagMatches(c.GetHeader("If-None-Match"), document.ETag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, document.ContentType, document.Content)
+}
+
+// If-None-Match pode trazer vários ETags separados por vírgula, ou "*"
+func etagMatches(header, etag string) bool {
+	for _, value := range strings.Split(header, ",") {
+		value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+		if value == "*" || value == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *FeedHandler) SetCategoryMapping(c *gin.Context) {
+	var mappingDTO dto.GoogleCategoryDTO
+	if err := c.ShouldBindJSON(&mappingDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := h.Service.SetCategoryMapping(c.Param("id"), mappingDTO.GoogleCategory)
+	if err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": "Erro ao atualizar a categoria do Google. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Categoria do Google atualizada com sucesso", "data": category})
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ForecastHandler struct {
+	Service service.ForecastService
+}
+
+// Inicializa um novo manipulador de previsões de demanda com o serviço fornecido
+func NewForecastHandler(s service.ForecastService) *ForecastHandler {
+	return &ForecastHandler{
+		Service: s,
+	}
+}
+
+// Previsões semanais de demanda do produto (parâmetros sku e locationId; locationId
+// vazio retorna só a demanda total)
+func (h *ForecastHandler) GetProductForecasts(c *gin.Context) {
+	forecasts, err := h.Service.GetForecasts(c.Param("id"), c.Query("sku"), queryLocation(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, forecasts)
+}
+
+// Lista as previsões semanais de demanda (parâmetros productId, sku e locationId)
+func (h *ForecastHandler) ListForecasts(c *gin.Context) {
+	forecasts, err := h.Service.GetForecasts(c.Query("productId"), c.Query("sku"), queryLocation(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, forecasts)
+}
+
+// Recalcula as previsões a partir do histórico de pedidos, em segundo plano
+func (h *ForecastHandler) Refresh(c *gin.Context) {
+	if err := h.Service.StartRefresh(); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrForecastRunning) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Recálculo das previsões de demanda iniciado."})
+}
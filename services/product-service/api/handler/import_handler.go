@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/service"
+	"Varejo-Golang-Microservices/services/product-service/infra/catalog"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Tamanho máximo do arquivo de importação
+const maxImportFileSize = 50 << 20
+
+type ImportHandler struct {
+	Service service.ImportService
+}
+
+// Inicializa um novo manipulador de importação e exportação do catálogo
+func NewImportHandler(s service.ImportService) *ImportHandler {
+	return &ImportHandler{
+		Service: s,
+	}
+}
+
+func importErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrImportJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, catalog.ErrUnsupportedFormat):
+		return http.StatusUnsupportedMediaType
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// Recebe o arquivo (campo file) e inicia a importação assíncrona. O formato vem do
+// parâmetro format ou da extensão do arquivo; com dryRun=true nada é gravado.
+func (h *ImportHandler) StartImport(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Envie o arquivo no campo file"})
+		return
+	}
+	if header.Size > maxImportFileSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("O arquivo excede o limite de %d MB", maxImportFileSize>>20)})
+		return
+	}
+
+	formatName := c.DefaultPostForm("format", c.Query("format"))
+	if formatName == "" {
+		formatName = header.Filename
+	}
+	format, err := catalog.ParseFormat(formatName)
+	if err != nil {
+		c.JSON(importErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	dryRun := false
+	if value := c.DefaultPostForm("dryRun", c.Query("dryRun")); value != "" {
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro dryRun inválido"})
+			return
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao ler o arquivo enviado"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao ler o arquivo enviado"})
+		return
+	}
+
+	job, err := h.Service.StartImport(format, header.Filename, data, dryRun, c.GetString("userID"))
+	if err != nil {
+		c.JSON(importErrorStatus(err), gin.H{"error": "Erro ao iniciar importação. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.Header("Location", "/products/import/jobs/"+job.ID.Hex())
+	c.JSON(http.StatusAccepted, gin.H{"message": "Importação iniciada.", "data": job})
+}
+
+func (h *ImportHandler) ListJobs(c *gin.Context) {
+	jobs, err := h.Service.ListJobs()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar importações"})
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+func (h *ImportHandler) GetJob(c *gin.Context) {
+	job, err := h.Service.GetJob(c.Param("id"))
+	if err != nil {
+		c.JSON(importErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// Baixa o relatório das linhas rejeitadas em CSV
+func (h *ImportHandler) DownloadErrorReport(c *gin.Context) {
+	job, err := h.Service.GetJob(c.Param("id"))
+	if err != nil {
+		c.JSON(importErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=importacao-%s-erros.csv", job.ID.Hex()))
+	c.Status(http.StatusOK)
+	if err := catalog.WriteErrorReport(c.Writer, job.Errors); err != nil {
+		log.Printf("Erro ao gravar relatório da importação %s: %v\n", job.ID.Hex(), err)
+	}
+}
+
+// Exporta o catálogo no mesmo formato aceito pela importação (format=csv ou jsonl).
+// Produtos sem SKU são exportados, mas precisam de um SKU para serem reimportados.
+func (h *ImportHandler) ExportCatalog(c *gin.Context) {
+	format, err := catalog.ParseFormat(c.DefaultQuery("format", string(model.ImportCSV)))
+	if err != nil {
+		c.JSON(importErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == model.ImportJSONL {
+		contentType = "application/x-ndjson"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=catalogo-%s.%s", time.Now().Format("20060102"), format))
+	c.Status(http.StatusOK)
+
+	// Os dados já começaram a ser enviados, então só é possível registrar o erro
+	if err := h.Service.ExportCatalog(format, c.Writer); err != nil {
+		log.Printf("Erro ao exportar o catálogo: %v\n", err)
+	}
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"Varejo-Golang-Microservices/services/product-service/domain/service"
+	"Varejo-Golang-Microservices/services/product-service/dto"
+	"Varejo-Golang-Microservices/services/product-service/infra/storage"
+	"errors"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MediaHandler struct {
+	Service service.MediaService
+}
+
+// Inicializa um novo manipulador de mídias de produto com o serviço fornecido
+func NewMediaHandler(s service.MediaService) *MediaHandler {
+	return &MediaHandler{
+		Service: s,
+	}
+}
+
+func mediaErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrMediaNotFound), errors.Is(err, storage.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrUnsupportedMedia):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, service.ErrMediaTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, repository.ErrMediaLimit), errors.Is(err, repository.ErrMediaChanged):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// Recebe uma imagem ou vídeo no campo file (e opcionalmente altText) e o adiciona ao fim da galeria
+func (h *MediaHandler) UploadMedia(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxVideoSize+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": service.ErrMediaTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Envie o arquivo no campo file"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao ler o arquivo enviado"})
+		return
+	}
+	defer file.Close()
+
+	media, err := h.Service.UploadMedia(c.Param("id"), header.Filename, c.PostForm("altText"), file)
+	if err != nil {
+		log.Printf("Erro ao enviar mídia do produto %s: %v\n", c.Param("id"), err)
+		c.JSON(mediaErrorStatus(err), gin.H{"error": "Erro ao enviar mídia. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Mídia enviada com sucesso.", "data": media})
+}
+
+func (h *MediaHandler) ListMedia(c *gin.Context) {
+	media, err := h.Service.ListMedia(c.Param("id"))
+	if err != nil {
+		c.JSON(mediaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+func (h *MediaHandler) ReorderMedia(c *gin.Context) {
+	var orderDTO dto.MediaOrderDTO
+	if err := c.ShouldBindJSON(&orderDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	media, err := h.Service.ReorderMedia(c.Param("id"), orderDTO.MediaIDs)
+	if err != nil {
+		c.JSON(mediaErrorStatus(err), gin.H{"error": "Erro ao reordenar mídias. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Mídias reordenadas com sucesso.", "data": media})
+}
+
+func (h *MediaHandler) DeleteMedia(c *gin.Context) {
+	if err := h.Service.DeleteMedia(c.Param("id"), c.Param("mediaId")); err != nil {
+		c.JSON(mediaErrorStatus(err), gin.H{"error": "Erro ao excluir mídia. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Mídia excluída com sucesso"})
+}
+
+// Serve os arquivos do armazenamento local, com suporte a requisições parciais (vídeos)
+func (h *MediaHandler) ServeMedia(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	file, err := h.Service.OpenMedia(key)
+	if err != nil {
+		c.JSON(mediaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	// As chaves nunca são reaproveitadas, então o arquivo pode ficar em cache indefinidamente
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	http.ServeContent(c.Writer, c.Request, path.Base(key), time.Time{}, file)
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"Varejo-Golang-Microservices/services/product-service/domain/service"
+	"Varejo-Golang-Microservices/services/product-service/dto"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PriceHandler struct {
+	Service service.PriceService
+}
+
+// Inicializa um novo manipulador de preços com o serviço fornecido
+func NewPriceHandler(s service.PriceService) *PriceHandler {
+	return &PriceHandler{
+		Service: s,
+	}
+}
+
+func priceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrPriceChangeNotFound), errors.Is(err, repository.ErrVariantNotFound), errors.Is(err, service.ErrNoPriceAt):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrPriceChangeClosed):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// Lista todas as versões de preço do produto, incluindo as agendadas
+func (h *PriceHandler) GetPriceHistory(c *gin.Context) {
+	changes, err := h.Service.GetPriceHistory(c.Param("id"))
+	if err != nil {
+		c.JSON(priceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+// Agenda uma mudança de preço; sem effectiveAt o preço é aplicado na hora
+func (h *PriceHandler) SchedulePrice(c *gin.Context) {
+	var priceDTO dto.PriceChangeDTO
+	if err := c.ShouldBindJSON(&priceDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao processar os dados do preço."})
+		return
+	}
+
+	var change *model.PriceChange
+	var err error
+	if priceDTO.Inherit {
+		change, err = h.Service.ScheduleInherit(c.Param("id"), priceDTO.SKU, priceDTO.EffectiveAt, priceDTO.Reason, c.GetString("userID"))
+	} else {
+		change, err = h.Service.SchedulePrice(c.Param("id"), priceDTO.SKU, priceDTO.Price, priceDTO.EffectiveAt, priceDTO.Reason, c.GetString("userID"))
+	}
+	if err != nil {
+		c.JSON(priceErrorStatus(err), gin.H{"error": "Erro ao registrar mudança de preço. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Mudança de preço registrada com sucesso.", "data": change})
+}
+
+func (h *PriceHandler) CancelPriceChange(c *gin.Context) {
+	if err := h.Service.CancelPriceChange(c.Param("id"), c.Param("changeId")); err != nil {
+		c.JSON(priceErrorStatus(err), gin.H{"error": "Erro ao cancelar mudança de preço. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Mudança de preço cancelada"})
+}
+
+// Consulta o preço vigente em um instante (parâmetro at em RFC 3339; padrão: agora)
+func (h *PriceHandler) GetPriceAt(c *gin.Context) {
+	at := time.Now()
+	if value := c.Query("at"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro at inválido, use o formato RFC 3339"})
+			return
+		}
+		at = parsed
+	}
+
+	price, err := h.Service.GetPriceAt(c.Param("id"), c.Query("sku"), at)
+	if err != nil {
+		c.JSON(priceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, price)
+}
@@ -2,10 +2,16 @@ package handler
 
 import (
 	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
 	"Varejo-Golang-Microservices/services/product-service/domain/service"
 	"Varejo-Golang-Microservices/services/product-service/dto"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -25,34 +31,145 @@ func NewProductHandler(s service.ProductService) *ProductHandler {
 func convertDTOToProduct(dto dto.ProductDTO) *model.Product {
 	return &model.Product{
 		ID:          primitive.NewObjectID(),
+		SKU:         dto.SKU,
+		GTIN:        dto.GTIN,
+		Type:        dto.Type,
 		Name:        dto.Name,
 		Description: dto.Description,
 		Price:       dto.Price,
-		Category: model.Category{
-			Name:        dto.Category.Name,
-			Description: dto.Category.Description,
-		},
-		Stock:     dto.Stock,
-		AddedDate: dto.AddedDate,
-		Status:    dto.Status,
+		Category:    convertDTOCategory(dto.Category),
+		Stock:       dto.Stock,
+		AddedDate:   dto.AddedDate,
+		Status:      dto.Status,
+		Options:     convertDTOOptions(dto.Options),
+		Attributes:  dto.Attributes,
+		Variants:    convertDTOVariants(dto.Variants),
+		Bundle:      convertDTOBundle(dto.Bundle),
+	}
+}
+
+// A categoria é referenciada pelo ID; nome e descrição são preenchidos a partir do cadastro de categorias
+func convertDTOCategory(categoryDTO dto.CategoryDTO) model.Category {
+	id, _ := primitive.ObjectIDFromHex(categoryDTO.ID)
+	return model.Category{
+		ID:          id,
+		Name:        categoryDTO.Name,
+		Description: categoryDTO.Description,
+	}
+}
+
+func convertDTOOptions(optionDTOs []dto.ProductOptionDTO) []model.ProductOption {
+	var options []model.ProductOption
+	for _, optionDTO := range optionDTOs {
+		options = append(options, model.ProductOption{Name: optionDTO.Name, Values: optionDTO.Values})
+	}
+	return options
+}
+
+func convertDTOVariant(variantDTO dto.VariantDTO) model.Variant {
+	return model.Variant{
+		SKU:     variantDTO.SKU,
+		Options: variantDTO.Options,
+		GTIN:    variantDTO.GTIN,
+		Price:   variantDTO.Price,
+		Stock:   variantDTO.Stock,
+		Images:  variantDTO.Images,
+	}
+}
+
+func convertDTOVariants(variantDTOs []dto.VariantDTO) []model.Variant {
+	var variants []model.Variant
+	for _, variantDTO := range variantDTOs {
+		variants = append(variants, convertDTOVariant(variantDTO))
+	}
+	return variants
+}
+
+func productErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidVariant), errors.Is(err, model.ErrInvalidGTIN),
+		errors.Is(err, service.ErrInvalidBundle):
+		return http.StatusBadRequest
+	case errors.Is(err, repository.ErrDuplicateSKU), errors.Is(err, repository.ErrDuplicateGTIN):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrVariantNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrCategoryNotFound), errors.Is(err, service.ErrInvalidCategory),
+		errors.Is(err, service.ErrInvalidAttributes):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
 	}
 }
 
 func convertDTOToProductWithoutID(dto dto.ProductDTO) *model.Product {
 	return &model.Product{
+		SKU:         dto.SKU,
+		GTIN:        dto.GTIN,
 		Name:        dto.Name,
 		Description: dto.Description,
 		Price:       dto.Price,
-		Category: model.Category{
-			Name:        dto.Category.Name,
-			Description: dto.Category.Description,
-		},
+		Category:    convertDTOCategory(dto.Category),
+		Attributes:  dto.Attributes,
+	}
+}
+
+// Lê os filtros de atributo da query: attr.<chave>=<valor>, attr.<chave>.min e attr.<chave>.max
+func parseAttributeFilters(c *gin.Context) ([]model.AttributeFilter, error) {
+	filters := make(map[string]*model.AttributeFilter)
+	get := func(key string) *model.AttributeFilter {
+		if filters[key] == nil {
+			filters[key] = &model.AttributeFilter{Key: key}
+		}
+		return filters[key]
 	}
+
+	for param, values := range c.Request.URL.Query() {
+		if !strings.HasPrefix(param, "attr.") || len(values) == 0 {
+			continue
+		}
+		key := strings.TrimPrefix(param, "attr.")
+		switch {
+		case strings.HasSuffix(key, ".min"), strings.HasSuffix(key, ".max"):
+			bound, err := strconv.ParseFloat(values[0], 64)
+			if err != nil {
+				return nil, fmt.Errorf("parâmetro %s inválido", param)
+			}
+			if strings.HasSuffix(key, ".min") {
+				get(strings.TrimSuffix(key, ".min")).Min = &bound
+			} else {
+				get(strings.TrimSuffix(key, ".max")).Max = &bound
+			}
+		default:
+			get(key).Value = values[0]
+		}
+	}
+
+	result := make([]model.AttributeFilter, 0, len(filters))
+	for _, filter := range filters {
+		if filter.Key == "" {
+			return nil, fmt.Errorf("atributo %s inválido", filter.Key)
+		}
+		result = append(result, *filter)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result, nil
 }
 
-// Listar Produtos
+// Listar Produtos, com filtros opcionais por atributo (attr.voltagem=220, attr.tela.min=50)
 func (h *ProductHandler) ListProducts(c *gin.Context) {
-	products, err := h.Service.ListAllProducts()
+	filters, err := parseAttributeFilters(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var products []*model.Product
+	if len(filters) > 0 {
+		products, err = h.Service.ListByAttributes(filters)
+	} else {
+		products, err = h.Service.ListAllProducts()
+	}
 	if err != nil {
 		log.Printf("Erro ao buscar relatórios: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar relatórios"})
@@ -64,7 +181,7 @@ func (h *ProductHandler) ListProducts(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, products)
+	c.JSON(http.StatusOK, localizeProducts(products, requestLocale(c)))
 }
 
 // Busca um produto por seu ID.
@@ -90,8 +207,8 @@ func (h *ProductHandler) GetProductByID(c *gin.Context) {
 		return
 	}
 
-	// Retorna o produto encontrado
-	c.JSON(http.StatusOK, product)
+	// Retorna o produto encontrado no idioma pedido
+	c.JSON(http.StatusOK, product.Localized(requestLocale(c)))
 }
 
 func (h *ProductHandler) AddProduct(c *gin.Context) {
@@ -110,7 +227,7 @@ func (h *ProductHandler) AddProduct(c *gin.Context) {
 	err := h.Service.SaveProduct(product)
 	if err != nil {
 		log.Printf("Detalhes do Erro ao salvar produto: %v\n", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao adicionar produto. Detalhes: " + err.Error()})
+		c.JSON(productErrorStatus(err), gin.H{"error": "Erro ao adicionar produto. Detalhes: " + err.Error()})
 		return
 	}
 
@@ -146,7 +263,7 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	// Atualiza o produto
 	err = h.Service.UpdateProduct(product)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar o produto. Detalhes: " + err.Error()})
+		c.JSON(productErrorStatus(err), gin.H{"error": "Erro ao atualizar o produto. Detalhes: " + err.Error()})
 		return
 	}
 
@@ -164,6 +281,10 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	}
 
 	err := h.Service.DeleteProduct(productID)
+	if errors.Is(err, service.ErrInvalidBundle) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Erro ao excluir produto. Detalhes: " + err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Erro ao excluir produto. Detalhes: " + err.Error()})
 		return
@@ -171,3 +292,77 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Produto deletado com sucesso"})
 }
+
+// Busca o produto pai de uma variante pelo SKU
+func (h *ProductHandler) GetProductBySKU(c *gin.Context) {
+	product, err := h.Service.GetProductBySKU(c.Param("sku"))
+	if err != nil {
+		c.JSON(productErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, product.Localized(requestLocale(c)))
+}
+
+// Substitui as opções (tamanho, cor...) do produto
+func (h *ProductHandler) UpdateOptions(c *gin.Context) {
+	var optionDTOs []dto.ProductOptionDTO
+	if err := c.ShouldBindJSON(&optionDTOs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := h.Service.UpdateOptions(c.Param("id"), convertDTOOptions(optionDTOs))
+	if err != nil {
+		c.JSON(productErrorStatus(err), gin.H{"error": "Erro ao atualizar opções. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Opções atualizadas com sucesso", "data": product})
+}
+
+func (h *ProductHandler) AddVariant(c *gin.Context) {
+	var variantDTO dto.VariantDTO
+	if err := c.ShouldBindJSON(&variantDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	variant := convertDTOVariant(variantDTO)
+	product, err := h.Service.AddVariant(c.Param("id"), &variant)
+	if err != nil {
+		c.JSON(productErrorStatus(err), gin.H{"error": "Erro ao adicionar variante. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Variante cadastrada com sucesso.", "data": product})
+}
+
+// Atualiza os dados da variante; o estoque é alterado somente pelas rotas de estoque
+func (h *ProductHandler) UpdateVariant(c *gin.Context) {
+	var variantDTO dto.VariantDTO
+	if err := c.ShouldBindJSON(&variantDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	variant := convertDTOVariant(variantDTO)
+	variant.SKU = c.Param("sku")
+	product, err := h.Service.UpdateVariant(c.Param("id"), &variant)
+	if err != nil {
+		c.JSON(productErrorStatus(err), gin.H{"error": "Erro ao atualizar variante. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Variante atualizada com sucesso", "data": product})
+}
+
+func (h *ProductHandler) RemoveVariant(c *gin.Context) {
+	product, err := h.Service.RemoveVariant(c.Param("id"), c.Param("sku"))
+	if err != nil {
+		c.JSON(productErrorStatus(err), gin.H{"error": "Erro ao excluir variante. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Variante excluída com sucesso", "data": product})
+}
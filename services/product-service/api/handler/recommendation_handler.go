@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecommendationHandler struct {
+	Service service.RecommendationService
+}
+
+// Inicializa um novo manipulador de recomendações com o serviço fornecido
+func NewRecommendationHandler(s service.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{
+		Service: s,
+	}
+}
+
+// "Comprados juntos": produtos que aparecem nos mesmos pedidos (parâmetro limit; padrão: 10)
+func (h *RecommendationHandler) GetRecommendations(c *gin.Context) {
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro limit inválido"})
+		return
+	}
+
+	recommendations, err := h.Service.GetRecommendations(c.Param("id"), limit)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, recommendations)
+}
+
+// Recalcula as estatísticas de compras conjuntas a partir de todos os pedidos, em segundo plano
+func (h *RecommendationHandler) Recompute(c *gin.Context) {
+	if err := h.Service.StartRecompute(); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrRecomputeRunning) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Recálculo das recomendações iniciado."})
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"Varejo-Golang-Microservices/services/product-service/domain/service"
+	"Varejo-Golang-Microservices/services/product-service/dto"
+	"Varejo-Golang-Microservices/services/product-service/infra/locations"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReplenishmentHandler struct {
+	Service service.ReplenishmentService
+}
+
+// Inicializa um novo manipulador de reposição com o serviço fornecido
+func NewReplenishmentHandler(s service.ReplenishmentService) *ReplenishmentHandler {
+	return &ReplenishmentHandler{
+		Service: s,
+	}
+}
+
+// Fora as validações da política e a consulta às localizações, o erro vem da busca do produto
+func replenishmentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidPolicy), errors.Is(err, service.ErrInvalidVariant):
+		return http.StatusBadRequest
+	case errors.Is(err, locations.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusNotFound
+	}
+}
+
+// O parâmetro locationId filtra a localização; presente e vazio, só o estoque do catálogo
+func queryLocation(c *gin.Context) *string {
+	if locationID, ok := c.GetQuery("locationId"); ok {
+		return &locationID
+	}
+	return nil
+}
+
+// Define o ponto de pedido, o estoque de segurança, o prazo de entrega e os limites
+// mínimo/máximo do produto ou variante, no catálogo ou em uma localização
+func (h *ReplenishmentHandler) SetPolicy(c *gin.Context) {
+	var policyDTO dto.ReplenishmentPolicyDTO
+	if err := c.ShouldBindJSON(&policyDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos. Detalhes: " + err.Error()})
+		return
+	}
+
+	policy := &model.ReplenishmentPolicy{
+		ProductID:    c.Param("id"),
+		SKU:          policyDTO.SKU,
+		LocationID:   policyDTO.LocationID,
+		ReorderPoint: policyDTO.ReorderPoint,
+		SafetyStock:  policyDTO.SafetyStock,
+		LeadTimeDays: policyDTO.LeadTimeDays,
+		MinQuantity:  policyDTO.MinQuantity,
+		MaxQuantity:  policyDTO.MaxQuantity,
+	}
+	suggestion, err := h.Service.SetPolicy(policy)
+	if err != nil {
+		c.JSON(replenishmentErrorStatus(err), gin.H{"error": "Erro ao salvar política de reposição. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestion)
+}
+
+// Remove a política do produto (parâmetros sku e locationId)
+func (h *ReplenishmentHandler) DeletePolicy(c *gin.Context) {
+	err := h.Service.DeletePolicy(c.Param("id"), c.Query("sku"), c.Query("locationId"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, repository.ErrPolicyNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Política de reposição removida com sucesso."})
+}
+
+// Lista as políticas de reposição (parâmetros productId e locationId)
+func (h *ReplenishmentHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.Service.ListPolicies(c.Query("productId"), queryLocation(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, policies)
+}
+
+// Relatório de reposição com a quantidade sugerida de cada item (parâmetros
+// locationId e onlyBelow, que restringe aos itens no ponto de pedido ou abaixo)
+func (h *ReplenishmentHandler) GetReport(c *gin.Context) {
+	report, err := h.Service.GetReport(queryLocation(c), c.Query("onlyBelow") == "true")
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, locations.ErrUnavailable) {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/service"
+	"Varejo-Golang-Microservices/services/product-service/dto"
+	"Varejo-Golang-Microservices/services/product-service/infra/orders"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewHandler struct {
+	Service service.ReviewService
+}
+
+// Inicializa um novo manipulador de avaliações com o serviço fornecido
+func NewReviewHandler(s service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		Service: s,
+	}
+}
+
+func reviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrReviewNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrPurchaseNotVerified):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrDuplicateReview), errors.Is(err, service.ErrReviewAlreadyInState):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrUnsupportedMedia):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, service.ErrMediaTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, orders.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, service.ErrRatingNotUpdated):
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// O cliente é sempre o usuário do token; o corpo não pode indicar outro
+func reviewCustomer(c *gin.Context) (string, bool) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Autenticação obrigatória"})
+		return "", false
+	}
+	return userID, true
+}
+
+// Recebe a avaliação em multipart: rating, title, text e até 5 fotos no campo photos.
+// O cliente é o usuário autenticado.
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	customerID, ok := reviewCustomer(c)
+	if !ok {
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxReviewPhotos*service.MaxImageSize+1<<20)
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": service.ErrMediaTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Envie a avaliação como multipart/form-data"})
+		return
+	}
+
+	rating, err := strconv.Atoi(c.PostForm("rating"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Informe a nota no campo rating"})
+		return
+	}
+
+	var photos []service.ReviewPhoto
+	for _, header := range form.File["photos"] {
+		file, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao ler a foto enviada"})
+			return
+		}
+		defer file.Close()
+		photos = append(photos, service.ReviewPhoto{FileName: header.Filename, Body: file})
+	}
+
+	review, err := h.Service.CreateReview(c.Param("id"), customerID, rating, c.PostForm("title"), c.PostForm("text"), photos)
+	if err != nil {
+		log.Printf("Erro ao criar avaliação do produto %s: %v\n", c.Param("id"), err)
+		c.JSON(reviewErrorStatus(err), gin.H{"error": "Erro ao enviar avaliação. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Avaliação enviada para moderação.", "data": review})
+}
+
+// Lista as avaliações aprovadas do produto. Parâmetros: rating, sort (recent, helpful,
+// rating_desc, rating_asc), page e pageSize.
+func (h *ReviewHandler) ListReviews(c *gin.Context) {
+	rating, err := queryInt(c, "rating")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro rating inválido"})
+		return
+	}
+	page, err := queryInt(c, "page")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro page inválido"})
+		return
+	}
+	pageSize, err := queryInt(c, "pageSize")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro pageSize inválido"})
+		return
+	}
+
+	result, err := h.Service.ListReviews(c.Param("id"), rating, model.ReviewSort(c.Query("sort")), page, pageSize)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Fila de moderação: avaliações pendentes, das mais antigas para as mais novas
+func (h *ReviewHandler) ModerationQueue(c *gin.Context) {
+	reviews, err := h.Service.ModerationQueue()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar avaliações pendentes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+func (h *ReviewHandler) Approve(c *gin.Context) {
+	review, err := h.Service.Approve(c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), gin.H{"error": "Erro ao aprovar avaliação. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Avaliação aprovada.", "data": review})
+}
+
+func (h *ReviewHandler) Reject(c *gin.Context) {
+	var rejectionDTO dto.ReviewRejectionDTO
+	if err := c.ShouldBindJSON(&rejectionDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Informe o motivo da recusa no campo reason"})
+		return
+	}
+
+	review, err := h.Service.Reject(c.Param("id"), c.GetString("userID"), rejectionDTO.Reason)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), gin.H{"error": "Erro ao recusar avaliação. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Avaliação recusada.", "data": review})
+}
+
+// Registra se a avaliação foi útil para o usuário autenticado ({"helpful": true})
+func (h *ReviewHandler) Vote(c *gin.Context) {
+	customerID, ok := reviewCustomer(c)
+	if !ok {
+		return
+	}
+
+	var voteDTO dto.ReviewVoteDTO
+	if err := c.ShouldBindJSON(&voteDTO); err != nil || voteDTO.Helpful == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Informe o voto no campo helpful"})
+		return
+	}
+
+	review, err := h.Service.Vote(c.Param("id"), customerID, *voteDTO.Helpful)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), gin.H{"error": "Erro ao registrar voto. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Voto registrado.", "data": review})
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/service"
+	"Varejo-Golang-Microservices/services/product-service/infra/search"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	Service service.SearchService
+}
+
+// Inicializa um novo manipulador de busca com o serviço fornecido
+func NewSearchHandler(s service.SearchService) *SearchHandler {
+	return &SearchHandler{
+		Service: s,
+	}
+}
+
+// Busca produtos por texto com filtros e facets.
+// Parâmetros: q, category, status, minPrice, maxPrice, attr.<nome>=<valor>, attr.<nome>.min,
+// attr.<nome>.max, opt.<nome>=<valor>, sort, page, pageSize.
+func (h *SearchHandler) SearchProducts(c *gin.Context) {
+	query := search.Query{
+		Text:       c.Query("q"),
+		Category:   c.Query("category"),
+		Status:     model.ProductStatus(c.Query("status")),
+		Sort:       search.SortOrder(c.Query("sort")),
+		Attributes: make(map[string]string),
+		Options:    make(map[string]string),
+	}
+
+	var err error
+	if query.MinPrice, err = queryFloat(c, "minPrice"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro minPrice inválido"})
+		return
+	}
+	if query.MaxPrice, err = queryFloat(c, "maxPrice"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro maxPrice inválido"})
+		return
+	}
+	if query.Page, err = queryInt(c, "page"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro page inválido"})
+		return
+	}
+	if query.PageSize, err = queryInt(c, "pageSize"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro pageSize inválido"})
+		return
+	}
+
+	filters, err := parseAttributeFilters(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	query.AttributeRanges = make(map[string]search.Range)
+	for _, filter := range filters {
+		if filter.Value != "" {
+			query.Attributes[filter.Key] = filter.Value
+		}
+		if filter.Min != nil || filter.Max != nil {
+			query.AttributeRanges[filter.Key] = search.Range{Min: filter.Min, Max: filter.Max}
+		}
+	}
+
+	for param, values := range c.Request.URL.Query() {
+		if strings.HasPrefix(param, "opt.") && len(values) > 0 && values[0] != "" {
+			query.Options[strings.TrimPrefix(param, "opt.")] = values[0]
+		}
+	}
+
+	switch query.Sort {
+	case "", search.SortRelevance, search.SortPriceAsc, search.SortPriceDesc, search.SortName, search.SortNewest:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ordenação inválida: " + string(query.Sort)})
+		return
+	}
+
+	// O texto é analisado e pontuado nos campos do idioma pedido, e os resultados saem nele
+	query.Locale = requestLocale(c)
+	c.JSON(http.StatusOK, h.Service.Search(query))
+}
+
+func queryFloat(c *gin.Context, key string) (float64, error) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+func queryInt(c *gin.Context, key string) (int, error) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
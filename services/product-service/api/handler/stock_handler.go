@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"Varejo-Golang-Microservices/services/product-service/domain/service"
+	"Varejo-Golang-Microservices/services/product-service/dto"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type StockHandler struct {
+	Service service.StockService
+}
+
+// Inicializa um novo manipulador de estoque com o serviço fornecido
+func NewStockHandler(s service.StockService) *StockHandler {
+	return &StockHandler{
+		Service: s,
+	}
+}
+
+func stockErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrReservationNotFound), errors.Is(err, repository.ErrVariantNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrInsufficientStock), errors.Is(err, service.ErrReservationClosed):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// Reserva unidades de um ou mais produtos durante o checkout
+func (h *StockHandler) Reserve(c *gin.Context) {
+	var reservationDTO dto.ReservationDTO
+	if err := c.ShouldBindJSON(&reservationDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao processar os dados da reserva."})
+		return
+	}
+
+	items := make([]model.ReservationItem, 0, len(reservationDTO.Items))
+	for _, itemDTO := range reservationDTO.Items {
+		productID, err := primitive.ObjectIDFromHex(itemDTO.ProductID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID do produto inválido: " + itemDTO.ProductID})
+			return
+		}
+		items = append(items, model.ReservationItem{ProductID: productID, SKU: itemDTO.SKU, Quantity: itemDTO.Quantity})
+	}
+
+	ttl := time.Duration(reservationDTO.TTLSeconds) * time.Second
+	reservation, err := h.Service.Reserve(reservationDTO.OrderID, items, ttl)
+	if err != nil {
+		c.JSON(stockErrorStatus(err), gin.H{"error": "Erro ao reservar estoque. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Estoque reservado com sucesso.", "data": reservation})
+}
+
+func (h *StockHandler) GetReservation(c *gin.Context) {
+	reservation, err := h.Service.GetReservation(c.Param("id"))
+	if err != nil {
+		c.JSON(stockErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// Confirma a reserva após o checkout
+func (h *StockHandler) Commit(c *gin.Context) {
+	reservation, err := h.Service.Commit(c.Param("id"))
+	if err != nil {
+		c.JSON(stockErrorStatus(err), gin.H{"error": "Erro ao confirmar reserva. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reserva confirmada.", "data": reservation})
+}
+
+// Cancela a reserva devolvendo as unidades ao estoque
+func (h *StockHandler) Release(c *gin.Context) {
+	reservation, err := h.Service.Release(c.Param("id"))
+	if err != nil {
+		c.JSON(stockErrorStatus(err), gin.H{"error": "Erro ao liberar reserva. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reserva liberada.", "data": reservation})
+}
+
+// Ajusta o estoque disponível de um produto somando o delta informado
+func (h *StockHandler) AdjustStock(c *gin.Context) {
+	var adjustmentDTO dto.StockAdjustmentDTO
+	if err := c.ShouldBindJSON(&adjustmentDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := h.Service.AdjustStock(c.Param("id"), adjustmentDTO.SKU, adjustmentDTO.Delta)
+	if err != nil {
+		c.JSON(stockErrorStatus(err), gin.H{"error": "Erro ao ajustar estoque. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Estoque ajustado com sucesso.", "data": product})
+}
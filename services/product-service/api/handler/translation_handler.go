@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/service"
+	"Varejo-Golang-Microservices/services/product-service/dto"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TranslationHandler struct {
+	Service service.TranslationService
+}
+
+// Inicializa um novo manipulador de traduções com o serviço fornecido
+func NewTranslationHandler(s service.TranslationService) *TranslationHandler {
+	return &TranslationHandler{
+		Service: s,
+	}
+}
+
+// Fora as validações do idioma e do texto, o erro vem da busca do produto ou da categoria
+func translationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, model.ErrUnsupportedLocale), errors.Is(err, service.ErrInvalidTranslation):
+		return http.StatusBadRequest
+	default:
+		return http.StatusNotFound
+	}
+}
+
+// Idioma da resposta pelo parâmetro locale ou pelo cabeçalho Accept-Language,
+// informado de volta no cabeçalho Content-Language
+func requestLocale(c *gin.Context) string {
+	locale := model.NegotiateLocale(c.Query("locale"), c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", locale)
+	return locale
+}
+
+func localizeProducts(products []*model.Product, locale string) []*model.Product {
+	localized := make([]*model.Product, 0, len(products))
+	for _, product := range products {
+		localized = append(localized, product.Localized(locale))
+	}
+	return localized
+}
+
+// Traduções do produto e os campos que faltam traduzir em cada idioma
+func (h *TranslationHandler) GetProductTranslations(c *gin.Context) {
+	status, err := h.Service.GetProductTranslations(c.Param("id"))
+	if err != nil {
+		c.JSON(translationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func (h *TranslationHandler) SetProductTranslation(c *gin.Context) {
+	var translationDTO dto.TranslationDTO
+	if err := c.ShouldBindJSON(&translationDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos. Detalhes: " + err.Error()})
+		return
+	}
+
+	translation := model.Translation{Name: translationDTO.Name, Description: translationDTO.Description}
+	status, err := h.Service.SetProductTranslation(c.Param("id"), c.Param("locale"), translation)
+	if err != nil {
+		c.JSON(translationErrorStatus(err), gin.H{"error": "Erro ao salvar tradução. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tradução salva com sucesso", "data": status})
+}
+
+func (h *TranslationHandler) RemoveProductTranslation(c *gin.Context) {
+	status, err := h.Service.RemoveProductTranslation(c.Param("id"), c.Param("locale"))
+	if err != nil {
+		c.JSON(translationErrorStatus(err), gin.H{"error": "Erro ao remover tradução. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tradução removida com sucesso", "data": status})
+}
+
+func (h *TranslationHandler) GetCategoryTranslations(c *gin.Context) {
+	status, err := h.Service.GetCategoryTranslations(c.Param("id"))
+	if err != nil {
+		c.JSON(translationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// A tradução da categoria é copiada para os produtos dela
+func (h *TranslationHandler) SetCategoryTranslation(c *gin.Context) {
+	var translationDTO dto.TranslationDTO
+	if err := c.ShouldBindJSON(&translationDTO); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos. Detalhes: " + err.Error()})
+		return
+	}
+
+	translation := model.Translation{Name: translationDTO.Name, Description: translationDTO.Description}
+	status, err := h.Service.SetCategoryTranslation(c.Param("id"), c.Param("locale"), translation)
+	if err != nil {
+		c.JSON(translationErrorStatus(err), gin.H{"error": "Erro ao salvar tradução. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tradução salva com sucesso", "data": status})
+}
+
+func (h *TranslationHandler) RemoveCategoryTranslation(c *gin.Context) {
+	status, err := h.Service.RemoveCategoryTranslation(c.Param("id"), c.Param("locale"))
+	if err != nil {
+		c.JSON(translationErrorStatus(err), gin.H{"error": "Erro ao remover tradução. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tradução removida com sucesso", "data": status})
+}
+
+// Lista as categorias e os produtos com traduções incompletas; o parâmetro locale
+// restringe a um idioma
+func (h *TranslationHandler) ListIncomplete(c *gin.Context) {
+	statuses, err := h.Service.ListIncomplete(c.Query("locale"))
+	if errors.Is(err, model.ErrUnsupportedLocale) || errors.Is(err, service.ErrInvalidTranslation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar traduções incompletas. Detalhes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"supportedLocales": model.SupportedLocales, "data": statuses})
+}
@@ -5,14 +5,26 @@ import (
 	"Varejo-Golang-Microservices/services/product-service/api/handler"
 	"Varejo-Golang-Microservices/services/product-service/domain/repository"
 	"Varejo-Golang-Microservices/services/product-service/domain/service"
+	"Varejo-Golang-Microservices/services/product-service/infra/feed"
+	"Varejo-Golang-Microservices/services/product-service/infra/locations"
+	"Varejo-Golang-Microservices/services/product-service/infra/orders"
+	"Varejo-Golang-Microservices/services/product-service/infra/search"
+	"Varejo-Golang-Microservices/services/product-service/infra/storage"
+	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 const defaultMongoURI = "mongodb://localhost:27017"
 const defaultKafkaBroker = "localhost:9092"
+const defaultMediaDir = "./data/media"
+const defaultMediaBaseURL = "/media"
+const defaultOrderServiceURL = "http://localhost:8084"
+const defaultLocationServiceURL = "http://localhost:8083"
+const defaultFeedSiteURL = "http://localhost:8086"
 
 func main() {
 	r := gin.Default()
@@ -28,11 +40,123 @@ func main() {
 		kafkaBroker = defaultKafkaBroker
 	}
 
+	mediaDir := os.Getenv("MEDIA_DIR")
+	if mediaDir == "" {
+		mediaDir = defaultMediaDir
+	}
+
+	orderServiceURL := os.Getenv("ORDER_SERVICE_URL")
+	if orderServiceURL == "" {
+		orderServiceURL = defaultOrderServiceURL
+	}
+
+	locationServiceURL := os.Getenv("LOCATION_SERVICE_URL")
+	if locationServiceURL == "" {
+		locationServiceURL = defaultLocationServiceURL
+	}
+
+	mediaBaseURL := os.Getenv("MEDIA_BASE_URL")
+	if mediaBaseURL == "" {
+		mediaBaseURL = defaultMediaBaseURL
+	}
+
+	feedSiteURL := os.Getenv("FEED_SITE_URL")
+	if feedSiteURL == "" {
+		feedSiteURL = defaultFeedSiteURL
+	}
+
 	// Initialize database connections, repositories, services.
 	productRepo := repository.NewMongoProductRepository(mongoURI, kafkaBroker)
-	productService := service.NewProductService(productRepo)
+	categoryRepo := repository.NewMongoCategoryRepository(mongoURI)
+	categoryService := service.NewCategoryService(categoryRepo, productRepo)
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	// Produtos gravados só com o nome da categoria passam a apontar para o cadastro
+	if err := categoryService.BackfillProductCategories(); err != nil {
+		log.Printf("Erro ao vincular produtos às categorias: %v\n", err)
+	}
+	priceRepo := repository.NewMongoPriceRepository(mongoURI)
+	priceService := service.NewPriceService(priceRepo, productRepo)
+	priceHandler := handler.NewPriceHandler(priceService)
+	barcodeRepo := repository.NewMongoBarcodeRepository(mongoURI)
+	barcodeService := service.NewBarcodeService(barcodeRepo, productRepo, os.Getenv("GS1_COMPANY_PREFIX"))
+	barcodeHandler := handler.NewBarcodeHandler(barcodeService)
+	go barcodeService.SyncRegistry()
+	bundleService := service.NewBundleService(productRepo, priceService)
+	bundleHandler := handler.NewBundleHandler(bundleService)
+	productService := service.NewProductService(productRepo, categoryService, priceService, barcodeService, bundleService)
 	productHandler := handler.NewProductHandler(productService)
 
+	mediaStorage, err := storage.NewLocalStorage(mediaDir, mediaBaseURL)
+	if err != nil {
+		log.Fatalf("Erro ao inicializar o armazenamento de mídia: %v", err)
+	}
+	mediaService := service.NewMediaService(productRepo, mediaStorage)
+	mediaHandler := handler.NewMediaHandler(mediaService)
+
+	// As avaliações exigem compra verificada no serviço de pedidos
+	reviewRepo := repository.NewMongoReviewRepository(mongoURI)
+	reviewService := service.NewReviewService(reviewRepo, productRepo, orders.NewClient(orderServiceURL), mediaStorage)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+
+	reservationRepo := repository.NewMongoReservationRepository(mongoURI)
+	stockService := service.NewStockService(productRepo, reservationRepo)
+	stockHandler := handler.NewStockHandler(stockService)
+
+	importJobRepo := repository.NewMongoImportJobRepository(mongoURI)
+	importService := service.NewImportService(importJobRepo, productRepo, productService, stockService, categoryService)
+	importHandler := handler.NewImportHandler(importService)
+	importService.FailInterruptedJobs()
+
+	// Devolve ao estoque as reservas não confirmadas dentro do prazo
+	go stockService.RunExpiry(time.Minute)
+
+	// Aplica as mudanças de preço agendadas quando entram em vigor
+	go priceService.RunScheduler(time.Minute)
+
+	// O índice de busca fica em memória e é atualizado pelos eventos do catálogo
+	searchService := service.NewSearchService(productRepo, search.NewIndex())
+	searchHandler := handler.NewSearchHandler(searchService)
+	go searchService.ListenProductEvents(kafkaBroker)
+
+	// "Comprados juntos" a partir dos pedidos publicados pelo serviço de pedidos
+	recommendationRepo := repository.NewMongoRecommendationRepository(mongoURI)
+	recommendationService := service.NewRecommendationService(recommendationRepo, productRepo, service.DefaultRecommendationHalfLife)
+	recommendationHandler := handler.NewRecommendationHandler(recommendationService)
+	go recommendationService.ListenOrderEvents(kafkaBroker)
+
+	// Feed do Google Merchant, atualizado pelos eventos do catálogo e regenerado a cada hora
+	feedService := service.NewFeedService(productRepo, categoryService, feed.Config{
+		Title:        "Catálogo de produtos",
+		Description:  "Feed de produtos para o Google Merchant Center",
+		SiteURL:      feedSiteURL,
+		Currency:     "BRL",
+		DefaultBrand: os.Getenv("FEED_DEFAULT_BRAND"),
+	})
+	feedHandler := handler.NewFeedHandler(feedService)
+	go feedService.ListenProductEvents(kafkaBroker)
+	go feedService.RunRebuild(time.Hour)
+
+	// Pontos de pedido e sugestões de compra a partir das vendas publicadas pelo serviço de pedidos
+	replenishmentRepo := repository.NewMongoReplenishmentRepository(mongoURI)
+	replenishmentService := service.NewReplenishmentService(replenishmentRepo, productRepo, locations.NewClient(locationServiceURL))
+	replenishmentHandler := handler.NewReplenishmentHandler(replenishmentService)
+	go replenishmentService.ListenOrderEvents(kafkaBroker)
+	go replenishmentService.ListenProductEvents(kafkaBroker)
+	go replenishmentService.RunMonitor(15 * time.Minute)
+
+	// Previsões semanais de demanda a partir dos pedidos, recalculadas todas as noites
+	forecastRepo := repository.NewMongoForecastRepository(mongoURI)
+	forecastService := service.NewForecastService(forecastRepo, productRepo, locations.NewClient(locationServiceURL))
+	forecastHandler := handler.NewForecastHandler(forecastService)
+	go forecastService.RunNightly(service.DefaultForecastHour)
+
+	// Traduções de produtos e categorias, com pt-BR como idioma padrão
+	translationService := service.NewTranslationService(productRepo, categoryRepo, categoryService)
+	translationHandler := handler.NewTranslationHandler(translationService)
+
+	// A disponibilidade e o preço dos kits acompanham os eventos dos componentes
+	go bundleService.ListenProductEvents(kafkaBroker)
+
 	r.POST("/login", authenticate)
 
 	authorized := r.Group("/")
@@ -40,10 +164,80 @@ func main() {
 
 	// Configurando as rotas
 	r.GET("/products", productHandler.ListProducts)
+	r.GET("/products/search", searchHandler.SearchProducts)
+	r.GET("/products/export", importHandler.ExportCatalog)
+	r.POST("/products/import", importHandler.StartImport)
+	r.GET("/products/import/jobs", importHandler.ListJobs)
+	r.GET("/products/import/jobs/:id", importHandler.GetJob)
+	r.GET("/products/import/jobs/:id/errors", importHandler.DownloadErrorReport)
 	r.GET("/products/:id", productHandler.GetProductByID)
 	r.POST("/products", productHandler.AddProduct)
 	r.PUT("/products/:id", productHandler.UpdateProduct)
 	r.DELETE("/products/:id", productHandler.DeleteProduct)
+	r.POST("/products/:id/stock", stockHandler.AdjustStock)
+	r.GET("/products/sku/:sku", productHandler.GetProductBySKU)
+	r.GET("/products/by-barcode/:gtin", barcodeHandler.GetByBarcode)
+	r.POST("/products/:id/barcode", barcodeHandler.GenerateEAN13)
+	r.PUT("/products/:id/options", productHandler.UpdateOptions)
+	r.POST("/products/:id/variants", productHandler.AddVariant)
+	r.PUT("/products/:id/variants/:sku", productHandler.UpdateVariant)
+	r.DELETE("/products/:id/variants/:sku", productHandler.RemoveVariant)
+	r.GET("/products/:id/prices", priceHandler.GetPriceHistory)
+	r.POST("/products/:id/prices", priceHandler.SchedulePrice)
+	r.DELETE("/products/:id/prices/:changeId", priceHandler.CancelPriceChange)
+	r.GET("/products/:id/price-at", priceHandler.GetPriceAt)
+	r.GET("/products/:id/media", mediaHandler.ListMedia)
+	r.POST("/products/:id/media", mediaHandler.UploadMedia)
+	r.PUT("/products/:id/media/order", mediaHandler.ReorderMedia)
+	r.DELETE("/products/:id/media/:mediaId", mediaHandler.DeleteMedia)
+	r.GET("/media/*key", mediaHandler.ServeMedia)
+	r.GET("/products/:id/recommendations", recommendationHandler.GetRecommendations)
+	r.POST("/recommendations/recompute", recommendationHandler.Recompute)
+	r.GET("/products/:id/reviews", reviewHandler.ListReviews)
+	authorized.POST("/products/:id/reviews", reviewHandler.CreateReview)
+	authorized.GET("/reviews/moderation", reviewHandler.ModerationQueue)
+	authorized.POST("/reviews/:id/approve", reviewHandler.Approve)
+	authorized.POST("/reviews/:id/reject", reviewHandler.Reject)
+	authorized.POST("/reviews/:id/votes", reviewHandler.Vote)
+	r.GET("/feeds/:file", feedHandler.GetGoogleFeed)
+	r.GET("/bundles", bundleHandler.ListBundles)
+	r.PUT("/products/:id/bundle", bundleHandler.UpdateBundle)
+	r.GET("/products/:id/bundle", bundleHandler.GetBreakdown)
+	r.PUT("/products/:id/replenishment", replenishmentHandler.SetPolicy)
+	r.DELETE("/products/:id/replenishment", replenishmentHandler.DeletePolicy)
+	r.GET("/replenishment/policies", replenishmentHandler.ListPolicies)
+	r.GET("/replenishment/report", replenishmentHandler.GetReport)
+	r.GET("/products/:id/forecasts", forecastHandler.GetProductForecasts)
+	r.GET("/forecasts", forecastHandler.ListForecasts)
+	r.POST("/forecasts/refresh", forecastHandler.Refresh)
+	r.GET("/products/:id/translations", translationHandler.GetProductTranslations)
+	r.PUT("/products/:id/translations/:locale", translationHandler.SetProductTranslation)
+	r.DELETE("/products/:id/translations/:locale", translationHandler.RemoveProductTranslation)
+	r.GET("/categories/:id/translations", translationHandler.GetCategoryTranslations)
+	r.PUT("/categories/:id/translations/:locale", translationHandler.SetCategoryTranslation)
+	r.DELETE("/categories/:id/translations/:locale", translationHandler.RemoveCategoryTranslation)
+	r.GET("/translations/incomplete", translationHandler.ListIncomplete)
+
+	// Rotas da árvore de categorias
+	r.GET("/categories", categoryHandler.ListCategories)
+	r.GET("/categories/tree", categoryHandler.GetTree)
+	r.GET("/categories/path/*path", categoryHandler.GetCategoryByPath)
+	r.GET("/categories/:id", categoryHandler.GetCategory)
+	r.GET("/categories/:id/products", categoryHandler.ListProducts)
+	r.GET("/categories/:id/attributes", categoryHandler.GetAttributeSchema)
+	r.PUT("/categories/:id/attributes", categoryHandler.SetAttributeSchema)
+	r.PUT("/categories/:id/google-category", feedHandler.SetCategoryMapping)
+	r.POST("/categories", categoryHandler.CreateCategory)
+	r.PUT("/categories/:id", categoryHandler.UpdateCategory)
+	r.POST("/categories/:id/move", categoryHandler.MoveCategory)
+	r.POST("/categories/:id/merge", categoryHandler.MergeCategory)
+	r.DELETE("/categories/:id", categoryHandler.DeleteCategory)
+
+	// Rotas de reserva de estoque
+	r.POST("/reservations", stockHandler.Reserve)
+	r.GET("/reservations/:id", stockHandler.GetReservation)
+	r.POST("/reservations/:id/commit", stockHandler.Commit)
+	r.POST("/reservations/:id/release", stockHandler.Release)
 
 	// Starting the server
 	r.Run(":8086")
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type AttributeType string
+
+const (
+	// Valor escolhido de uma lista fechada (ex.: gênero, tecido)
+	AttributeEnum AttributeType = "ENUM"
+	// Número com unidade opcional (ex.: tela em polegadas)
+	AttributeNumber  AttributeType = "NUMBER"
+	AttributeBoolean AttributeType = "BOOLEAN"
+	AttributeText    AttributeType = "TEXT"
+)
+
+// AttributeDefinition descreve um atributo do esquema da categoria. As subcategorias
+// herdam os atributos dos ancestrais e podem redefini-los pela mesma chave.
+type AttributeDefinition struct {
+	Key           string        `json:"key" bson:"key"`
+	Label         string        `json:"label" bson:"label"`
+	Type          AttributeType `json:"type" bson:"type"`
+	Unit          string        `json:"unit,omitempty" bson:"unit,omitempty"`
+	Required      bool          `json:"required" bson:"required"`
+	AllowedValues []string      `json:"allowedValues,omitempty" bson:"allowedValues,omitempty"`
+}
+
+// AttributeFilter filtra a listagem por um atributo: pelo valor exato ou, em
+// atributos numéricos, por uma faixa
+type AttributeFilter struct {
+	Key   string
+	Value string
+	Min   *float64
+	Max   *float64
+}
+
+// FormatAttribute converte o valor gravado do atributo em texto, usado na busca
+func FormatAttribute(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	default:
+		return fmt.Sprint(v)
+	}
+}
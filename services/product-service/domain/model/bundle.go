@@ -0,0 +1,76 @@
+package model
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+type ProductType string
+
+const (
+	SimpleProduct ProductType = "SIMPLE"
+	BundleProduct ProductType = "BUNDLE"
+)
+
+type BundlePricingMode string
+
+const (
+	// Preço definido manualmente no kit
+	BundleFixedPrice BundlePricingMode = "FIXED"
+	// Soma dos preços dos componentes menos o desconto percentual
+	BundleComponentsDiscount BundlePricingMode = "COMPONENTS_DISCOUNT"
+)
+
+// Bundle descreve um kit: produtos componentes com as quantidades consumidas por
+// unidade do kit. O kit não tem estoque próprio; sua disponibilidade vem dos componentes.
+type Bundle struct {
+	Components      []BundleComponent `json:"components" bson:"components"`
+	PricingMode     BundlePricingMode `json:"pricingMode" bson:"pricingMode"`
+	DiscountPercent float64           `json:"discountPercent,omitempty" bson:"discountPercent,omitempty"`
+}
+
+type BundleComponent struct {
+	ProductID primitive.ObjectID `json:"productId" bson:"productId"`
+	SKU       string             `json:"sku,omitempty" bson:"sku,omitempty"`
+	Quantity  int                `json:"quantity" bson:"quantity"`
+}
+
+// BundleBreakdown detalha a venda de uma quantidade do kit, com o preço rateado
+// entre os componentes para cálculo de impostos e devoluções
+type BundleBreakdown struct {
+	BundleID  string       `json:"bundleId"`
+	Quantity  int          `json:"quantity"`
+	UnitPrice float64      `json:"unitPrice"`
+	Total     float64      `json:"total"`
+	Available int          `json:"available"`
+	Lines     []BundleLine `json:"lines"`
+}
+
+// BundleLine é a parte de um componente na venda do kit. UnitPrice é o preço rateado
+// e ListPrice o preço avulso do componente.
+type BundleLine struct {
+	ProductID   string  `json:"productId"`
+	SKU         string  `json:"sku,omitempty"`
+	ProductName string  `json:"productName"`
+	Quantity    int     `json:"quantity"`
+	ListPrice   float64 `json:"listPrice"`
+	UnitPrice   float64 `json:"unitPrice"`
+	Total       float64 `json:"total"`
+}
+
+// IsBundle indica se o produto é um kit
+func (p *Product) IsBundle() bool {
+	return p.Type == BundleProduct
+}
+
+// AvailableFor retorna o estoque disponível do produto ou da variante. Produtos
+// descontinuados não têm unidades disponíveis.
+func (p *Product) AvailableFor(sku string) int {
+	if p.Status == Discontinued {
+		return 0
+	}
+	if sku != "" {
+		if variant := p.FindVariant(sku); variant != nil {
+			return variant.Stock
+		}
+		return 0
+	}
+	return p.Stock
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CatalogRow é uma linha do arquivo de importação/exportação do catálogo. Linhas sem
+// ParentSKU descrevem um produto; linhas com ParentSKU descrevem uma variante dele.
+// Campos vazios na importação mantêm o valor atual do produto.
+type CatalogRow struct {
+	SKU         string            `json:"sku"`
+	ParentSKU   string            `json:"parentSku,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Price       *float64          `json:"price,omitempty"`
+	Category    string            `json:"category,omitempty"`
+	Stock       *int              `json:"stock,omitempty"`
+	Status      ProductStatus     `json:"status,omitempty"`
+	Options     map[string]string `json:"options,omitempty"`
+	GTIN        string            `json:"gtin,omitempty"`
+	// Atributos da categoria; no CSV os valores chegam como texto e são convertidos
+	// pelo esquema da categoria
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+type ImportFormat string
+
+const (
+	ImportCSV   ImportFormat = "csv"
+	ImportJSONL ImportFormat = "jsonl"
+)
+
+type ImportJobStatus string
+
+const (
+	ImportPending   ImportJobStatus = "PENDING"
+	ImportRunning   ImportJobStatus = "RUNNING"
+	ImportCompleted ImportJobStatus = "COMPLETED"
+	ImportFailed    ImportJobStatus = "FAILED"
+)
+
+// ImportJob acompanha a importação assíncrona de um arquivo do catálogo. Em DryRun
+// as linhas são validadas e contabilizadas, mas nada é gravado.
+type ImportJob struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id"`
+	Format     ImportFormat       `json:"format" bson:"format"`
+	FileName   string             `json:"fileName" bson:"fileName"`
+	DryRun     bool               `json:"dryRun" bson:"dryRun"`
+	Status     ImportJobStatus    `json:"status" bson:"status"`
+	TotalRows  int                `json:"totalRows" bson:"totalRows"`
+	Created    int                `json:"created" bson:"created"`
+	Updated    int                `json:"updated" bson:"updated"`
+	Failed     int                `json:"failed" bson:"failed"`
+	Errors     []ImportRowError   `json:"errors,omitempty" bson:"errors,omitempty"`
+	Message    string             `json:"message,omitempty" bson:"message,omitempty"`
+	CreatedBy  string             `json:"createdBy,omitempty" bson:"createdBy,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+	StartedAt  *time.Time         `json:"startedAt,omitempty" bson:"startedAt,omitempty"`
+	FinishedAt *time.Time         `json:"finishedAt,omitempty" bson:"finishedAt,omitempty"`
+}
+
+// ImportRowError descreve o motivo da rejeição de uma linha. Row é o número da
+// linha no arquivo (no CSV, a linha 1 é o cabeçalho).
+type ImportRowError struct {
+	Row     int    `json:"row" bson:"row"`
+	SKU     string `json:"sku,omitempty" bson:"sku,omitempty"`
+	Field   string `json:"field,omitempty" bson:"field,omitempty"`
+	Message string `json:"message" bson:"message"`
+}
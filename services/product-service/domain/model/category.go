@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CategoryNode é uma categoria da árvore do catálogo (Eletrônicos > TV > Smart TV).
+// Ancestors guarda os IDs da raiz até o pai, permitindo buscar descendentes com uma única consulta.
+type CategoryNode struct {
+	ID          primitive.ObjectID   `json:"id" bson:"_id"`
+	Name        string               `json:"name" bson:"name"`
+	Slug        string               `json:"slug" bson:"slug"`
+	Path        string               `json:"path" bson:"path"`
+	Description string               `json:"description,omitempty" bson:"description,omitempty"`
+	ParentID    *primitive.ObjectID  `json:"parentId,omitempty" bson:"parentId,omitempty"`
+	Ancestors   []primitive.ObjectID `json:"ancestors" bson:"ancestors"`
+	Position    int                  `json:"position" bson:"position"`
+	// Esquema de atributos próprio da categoria, sem os herdados
+	Attributes []AttributeDefinition `json:"attributes,omitempty" bson:"attributes,omitempty"`
+	// Categoria da taxonomia do Google usada nos feeds; vazia herda a do ancestral mais próximo
+	GoogleCategory string `json:"googleCategory,omitempty" bson:"googleCategory,omitempty"`
+	// Nome e descrição em outros idiomas, pelo código do idioma; copiados para os produtos
+	Translations map[string]Translation `json:"translations,omitempty" bson:"translations,omitempty"`
+	CreatedAt    time.Time              `json:"createdAt" bson:"createdAt"`
+}
+
+// Reference retorna a cópia da categoria gravada nos produtos
+func (c *CategoryNode) Reference() Category {
+	return Category{
+		ID:           c.ID,
+		Name:         c.Name,
+		Slug:         c.Slug,
+		Description:  c.Description,
+		Translations: c.Translations,
+	}
+}
+
+// CategoryTree é um nó da árvore com seus filhos já ordenados
+type CategoryTree struct {
+	*CategoryNode
+	Children []*CategoryTree `json:"children"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Forecast é a previsão semanal de demanda de um produto ou variante em uma loja.
+// Sem localização, a previsão é da demanda total, inclusive dos pedidos ainda não
+// atendidos por uma loja.
+type Forecast struct {
+	ID         string `json:"id" bson:"_id"`
+	ProductID  string `json:"productId" bson:"productId"`
+	SKU        string `json:"sku,omitempty" bson:"sku"`
+	LocationID string `json:"locationId,omitempty" bson:"locationId"`
+	// Modelo escolhido pelo backtest e seus parâmetros
+	Method     string             `json:"method" bson:"method"`
+	Parameters map[string]float64 `json:"parameters" bson:"parameters"`
+	// Erro absoluto médio, em unidades por semana, nas semanas reservadas para o backtest
+	BacktestMAE     float64        `json:"backtestMae" bson:"backtestMae"`
+	HoldoutWeeks    int            `json:"holdoutWeeks" bson:"holdoutWeeks"`
+	HistoryWeeks    int            `json:"historyWeeks" bson:"historyWeeks"`
+	ConfidenceLevel float64        `json:"confidenceLevel" bson:"confidenceLevel"`
+	Weeks           []ForecastWeek `json:"weeks" bson:"weeks"`
+	GeneratedAt     time.Time      `json:"generatedAt" bson:"generatedAt"`
+}
+
+// ForecastWeek é a demanda prevista para a semana iniciada na segunda-feira WeekStart
+type ForecastWeek struct {
+	WeekStart time.Time `json:"weekStart" bson:"weekStart"`
+	Forecast  float64   `json:"forecast" bson:"forecast"`
+	Lower     float64   `json:"lower" bson:"lower"`
+	Upper     float64   `json:"upper" bson:"upper"`
+}
+
+// SalesLine é um item vendido em um pedido. Nos kits, cada componente é uma linha.
+type SalesLine struct {
+	OrderID   string
+	ProductID string
+	SKU       string
+	Quantity  int
+	OrderDate time.Time
+}
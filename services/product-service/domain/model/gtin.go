@@ -0,0 +1,70 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidGTIN = errors.New("GTIN inválido")
+
+// NormalizeGTIN remove espaços e hífens e valida o tamanho (GTIN-8, 12, 13 ou 14)
+// e o dígito verificador, retornando o código como deve ser gravado
+func NormalizeGTIN(code string) (string, error) {
+	code = strings.NewReplacer(" ", "", "-", "").Replace(code)
+
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return "", fmt.Errorf("%w: %q deve ter 8, 12, 13 ou 14 dígitos", ErrInvalidGTIN, code)
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("%w: %q deve conter apenas dígitos", ErrInvalidGTIN, code)
+		}
+	}
+
+	if GTINCheckDigit(code[:len(code)-1]) != int(code[len(code)-1]-'0') {
+		return "", fmt.Errorf("%w: dígito verificador de %s não confere", ErrInvalidGTIN, code)
+	}
+	return code, nil
+}
+
+// GTINCheckDigit calcula o dígito verificador GS1 (módulo 10) do código sem o dígito final
+func GTINCheckDigit(body string) int {
+	sum := 0
+	// Da direita para a esquerda, os pesos alternam entre 3 e 1
+	for i := 0; i < len(body); i++ {
+		digit := int(body[len(body)-1-i] - '0')
+		if i%2 == 0 {
+			sum += digit * 3
+		} else {
+			sum += digit
+		}
+	}
+	return (10 - sum%10) % 10
+}
+
+// GTIN14 completa o código com zeros à esquerda. UPC-12, EAN-13 e GTIN-14 do mesmo
+// item têm a mesma forma de 14 dígitos, usada como chave única do cadastro.
+func GTIN14(code string) string {
+	return strings.Repeat("0", 14-len(code)) + code
+}
+
+// Barcode registra a qual produto (ou variante) pertence cada GTIN. O ID é a forma
+// de 14 dígitos, o que garante a unicidade entre produtos e variantes.
+type Barcode struct {
+	ID        string    `json:"gtin14" bson:"_id"`
+	Code      string    `json:"code" bson:"code"`
+	ProductID string    `json:"productId" bson:"productId"`
+	SKU       string    `json:"sku,omitempty" bson:"sku,omitempty"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// BarcodeMatch é o resultado da busca por código de barras
+type BarcodeMatch struct {
+	GTIN    string   `json:"gtin"`
+	Product *Product `json:"product"`
+	Variant *Variant `json:"variant,omitempty"`
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGTINCheckDigit(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"EAN-13", "400638133393", 1},
+		{"EAN-13 brasileiro", "789100031550", 7},
+		{"UPC-12", "03600029145", 2},
+		{"GTIN-8", "9638507", 4},
+		{"GTIN-14", "1001234567890", 2},
+		{"soma múltipla de 10", "000000000000", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GTINCheckDigit(tt.body); got != tt.want {
+				t.Errorf("GTINCheckDigit(%q) = %d, esperado %d", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeGTIN(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{"EAN-13 válido", "7891000315507", "7891000315507", false},
+		{"remove espaços e hífens", "789-1000 315507", "7891000315507", false},
+		{"UPC-12 válido", "036000291452", "036000291452", false},
+		{"GTIN-8 válido", "96385074", "96385074", false},
+		{"GTIN-14 válido", "10012345678902", "10012345678902", false},
+		{"dígito verificador errado", "7891000315508", "", true},
+		{"tamanho inválido", "78910003155", "", true},
+		{"letras", "789100031550A", "", true},
+		{"vazio", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeGTIN(tt.code)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidGTIN) {
+					t.Fatalf("esperado ErrInvalidGTIN, obtido %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeGTIN(%q) = %q, esperado %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGTIN14(t *testing.T) {
+	if got := GTIN14("036000291452"); got != "00036000291452" {
+		t.Errorf("GTIN14 do UPC-12 = %q", got)
+	}
+	if got := GTIN14("10012345678902"); got != "10012345678902" {
+		t.Errorf("GTIN14 do GTIN-14 = %q", got)
+	}
+}
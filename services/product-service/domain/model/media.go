@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+type MediaType string
+
+const (
+	MediaImage MediaType = "IMAGE"
+	MediaVideo MediaType = "VIDEO"
+)
+
+// Media é uma imagem ou vídeo do produto. A ordem em Product.Media é a ordem de exibição.
+type Media struct {
+	ID          string      `json:"id" bson:"id"`
+	Type        MediaType   `json:"type" bson:"type"`
+	ContentType string      `json:"contentType" bson:"contentType"`
+	FileName    string      `json:"fileName,omitempty" bson:"fileName,omitempty"`
+	Size        int64       `json:"size" bson:"size"`
+	Width       int         `json:"width,omitempty" bson:"width,omitempty"`
+	Height      int         `json:"height,omitempty" bson:"height,omitempty"`
+	Key         string      `json:"-" bson:"key"`
+	URL         string      `json:"url" bson:"url"`
+	Thumbnails  []Thumbnail `json:"thumbnails,omitempty" bson:"thumbnails,omitempty"`
+	AltText     string      `json:"altText,omitempty" bson:"altText,omitempty"`
+	UploadedAt  time.Time   `json:"uploadedAt" bson:"uploadedAt"`
+}
+
+// Thumbnail é uma miniatura gerada a partir de uma imagem do produto
+type Thumbnail struct {
+	Size   string `json:"size" bson:"size"`
+	Width  int    `json:"width" bson:"width"`
+	Height int    `json:"height" bson:"height"`
+	Key    string `json:"-" bson:"key"`
+	URL    string `json:"url" bson:"url"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PriceChange é uma versão do preço do produto (ou de uma variante, quando SKU é informado).
+// Mudanças futuras ficam SCHEDULED até EffectiveAt e são aplicadas automaticamente.
+// Inherit remove a sobrescrita da variante, que volta a usar o preço do produto.
+type PriceChange struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id"`
+	ProductID   primitive.ObjectID `json:"productId" bson:"productId"`
+	SKU         string             `json:"sku,omitempty" bson:"sku,omitempty"`
+	Version     int                `json:"version,omitempty" bson:"version,omitempty"`
+	OldPrice    *float64           `json:"oldPrice,omitempty" bson:"oldPrice,omitempty"`
+	NewPrice    float64            `json:"newPrice" bson:"newPrice"`
+	Inherit     bool               `json:"inherit,omitempty" bson:"inherit,omitempty"`
+	EffectiveAt time.Time          `json:"effectiveAt" bson:"effectiveAt"`
+	Status      PriceChangeStatus  `json:"status" bson:"status"`
+	Reason      string             `json:"reason,omitempty" bson:"reason,omitempty"`
+	CreatedBy   string             `json:"createdBy,omitempty" bson:"createdBy,omitempty"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
+	AppliedAt   *time.Time         `json:"appliedAt,omitempty" bson:"appliedAt,omitempty"`
+	ClaimedAt   *time.Time         `json:"-" bson:"claimedAt,omitempty"`
+}
+
+// PriceChangeStatus APPLYING indica uma mudança em aplicação: o produto pode já
+// ter o novo preço, mas a versão ainda não foi registrada no histórico
+type PriceChangeStatus string
+
+const (
+	PriceScheduled PriceChangeStatus = "SCHEDULED"
+	PriceApplying  PriceChangeStatus = "APPLYING"
+	PriceApplied   PriceChangeStatus = "APPLIED"
+	PriceCanceled  PriceChangeStatus = "CANCELED"
+)
+
+// PriceAt é a resposta da consulta "preço no instante T"
+type PriceAt struct {
+	ProductID string       `json:"productId"`
+	SKU       string       `json:"sku,omitempty"`
+	At        time.Time    `json:"at"`
+	Price     float64      `json:"price"`
+	Change    *PriceChange `json:"change,omitempty"`
+}
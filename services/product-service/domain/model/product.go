@@ -1,6 +1,8 @@
 package model
 
 import (
+	"sort"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -8,18 +10,90 @@ import (
 
 type Product struct {
 	ID          primitive.ObjectID `json:"id" bson:"_id"`
+	SKU         string             `json:"sku,omitempty" bson:"sku,omitempty"` // SKU do produto; as variantes têm SKU próprio
+	GTIN        string             `json:"gtin,omitempty" bson:"gtin,omitempty"`
+	Type        ProductType        `json:"type,omitempty" bson:"type,omitempty"`
 	Name        string             `json:"name" bson:"name"`
 	Description string             `json:"description" bson:"description"`
 	Price       float64            `json:"price" bson:"price"`
-	Category    Category           `json:"category" bson:"category"`
-	Stock       int                `json:"stock" bson:"stock"`
-	AddedDate   time.Time          `json:"addedDate" bson:"addedDate"`
-	Status      ProductStatus      `json:"status" bson:"status"`
+	// Versão do preço, incrementada a cada mudança aplicada (inclusive de variantes)
+	PriceVersion int             `json:"priceVersion" bson:"priceVersion"`
+	Category     Category        `json:"category" bson:"category"`
+	Stock        int             `json:"stock" bson:"stock"`
+	Reserved     int             `json:"reserved" bson:"reserved"`
+	AddedDate    time.Time       `json:"addedDate" bson:"addedDate"`
+	Status       ProductStatus   `json:"status" bson:"status"`
+	Options      []ProductOption `json:"options,omitempty" bson:"options,omitempty"`
+	// Atributos definidos pelo esquema da categoria; os valores são string, número ou booleano
+	Attributes map[string]interface{} `json:"attributes,omitempty" bson:"attributes,omitempty"`
+	Variants   []Variant              `json:"variants,omitempty" bson:"variants,omitempty"`
+	Media      []Media                `json:"media,omitempty" bson:"media,omitempty"`
+	Bundle     *Bundle                `json:"bundle,omitempty" bson:"bundle,omitempty"`
+	Rating     *RatingSummary         `json:"rating,omitempty" bson:"rating,omitempty"`
+	// Nome e descrição em outros idiomas, pelo código do idioma (es, en); Name e Description ficam em pt-BR
+	Translations map[string]Translation `json:"translations,omitempty" bson:"translations,omitempty"`
 }
 
+// ProductOption é um eixo de variação do produto (tamanho, cor, voltagem...) e seus valores permitidos
+type ProductOption struct {
+	Name   string   `json:"name" bson:"name"`
+	Values []string `json:"values" bson:"values"`
+}
+
+// Variant é uma combinação de opções vendida com SKU, código de barras e estoque próprios.
+// Quando o produto tem variantes, Product.Stock é a soma do estoque das variantes.
+type Variant struct {
+	SKU      string            `json:"sku" bson:"sku"`
+	Options  map[string]string `json:"options" bson:"options"`
+	GTIN     string            `json:"gtin,omitempty" bson:"gtin,omitempty"`
+	Price    *float64          `json:"price,omitempty" bson:"price,omitempty"`
+	Stock    int               `json:"stock" bson:"stock"`
+	Reserved int               `json:"reserved" bson:"reserved"`
+	Images   []string          `json:"images,omitempty" bson:"images,omitempty"`
+}
+
+// FindVariant retorna a variante com o SKU informado, ou nil
+func (p *Product) FindVariant(sku string) *Variant {
+	for i := range p.Variants {
+		if p.Variants[i].SKU == sku {
+			return &p.Variants[i]
+		}
+	}
+	return nil
+}
+
+// PriceFor retorna o preço da variante, usando o preço do produto quando não há sobrescrita
+func (p *Product) PriceFor(sku string) float64 {
+	if variant := p.FindVariant(sku); variant != nil && variant.Price != nil {
+		return *variant.Price
+	}
+	return p.Price
+}
+
+// OptionKey gera uma chave canônica da combinação de opções, independente da ordem
+func OptionKey(options map[string]string) string {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, strings.ToLower(name)+"="+strings.ToLower(options[name]))
+	}
+	return strings.Join(parts, "|")
+}
+
+// Category é a referência à categoria gravada no produto. Nome, slug, descrição e traduções
+// são cópias do CategoryNode e são ressincronizados quando a categoria muda.
 type Category struct {
-	Name        string `json:"name" bson:"name"`
-	Description string `json:"description,omitempty" bson:"description,omitempty"`
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"id,omitempty"`
+	Name        string             `json:"name" bson:"name"`
+	Slug        string             `json:"slug,omitempty" bson:"slug,omitempty"`
+	Description string             `json:"description,omitempty" bson:"description,omitempty"`
+	// Cópia das traduções da categoria, para localizar o produto sem consultar o cadastro
+	Translations map[string]Translation `json:"translations,omitempty" bson:"translations,omitempty"`
 }
 
 type ProductStatus string
@@ -29,3 +103,15 @@ const (
 	OutOfStock   ProductStatus = "OUT_OF_STOCK"
 	Discontinued ProductStatus = "DISCONTINUED"
 )
+
+// StatusForStock retorna o status correspondente ao estoque disponível.
+// Produtos descontinuados mantêm o status independentemente do estoque.
+func StatusForStock(current ProductStatus, stock int) ProductStatus {
+	if current == Discontinued {
+		return Discontinued
+	}
+	if stock > 0 {
+		return Available
+	}
+	return OutOfStock
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// ProductEvent é publicado a cada alteração do catálogo para que índices
+// e integrações se mantenham atualizados sem consultar o banco.
+type ProductEvent struct {
+	Type      ProductEventType `json:"type"`
+	ProductID string           `json:"productId"`
+	Product   *Product         `json:"product,omitempty"`
+	// Preenchido somente nos eventos PRODUCT_PRICE_CHANGED
+	PriceChange *PriceChange `json:"priceChange,omitempty"`
+	OccurredAt  time.Time    `json:"occurredAt"`
+}
+
+type ProductEventType string
+
+const (
+	ProductCreated      ProductEventType = "PRODUCT_CREATED"
+	ProductUpdated      ProductEventType = "PRODUCT_UPDATED"
+	ProductDeleted      ProductEventType = "PRODUCT_DELETED"
+	ProductStockChanged ProductEventType = "PRODUCT_STOCK_CHANGED"
+	ProductPriceChanged ProductEventType = "PRODUCT_PRICE_CHANGED"
+)
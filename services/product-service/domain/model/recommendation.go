@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// CoPurchaseItem guarda quantos pedidos contêm o produto. Os pesos de itens, pares e
+// do total de pedidos usam a mesma escala com decaimento, então as razões entre eles
+// (suporte, confiança e lift) não dependem do instante da consulta.
+type CoPurchaseItem struct {
+	ProductID string  `bson:"_id"`
+	Weight    float64 `bson:"weight"`
+}
+
+// CoPurchasePair guarda quantos pedidos contêm os dois produtos; A é sempre o menor ID
+type CoPurchasePair struct {
+	ID     string  `bson:"_id"`
+	A      string  `bson:"a"`
+	B      string  `bson:"b"`
+	Weight float64 `bson:"weight"`
+}
+
+// Recommendation é um produto comprado junto com o produto consultado
+type Recommendation struct {
+	Product *Product `json:"product"`
+	// Pedidos recentes com os dois produtos, já com o decaimento aplicado
+	Orders     float64 `json:"orders"`
+	Support    float64 `json:"support"`
+	Confidence float64 `json:"confidence"`
+	Lift       float64 `json:"lift"`
+}
+
+// ProcessedOrder registra um pedido já contabilizado, com os produtos e o peso
+// somados, para que o cancelamento subtraia exatamente o que foi somado
+type ProcessedOrder struct {
+	ID         string   `bson:"_id"`
+	ProductIDs []string `bson:"productIds,omitempty"`
+	Weight     float64  `bson:"weight"`
+	Canceled   bool     `bson:"canceled,omitempty"`
+}
+
+// PurchasedOrder é um pedido já reduzido aos produtos distintos que contém
+type PurchasedOrder struct {
+	ID         string
+	ProductIDs []string
+	OrderDate  time.Time
+}
@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// ReplenishmentPolicy define quando e quanto repor de um produto (ou variante) em uma
+// localização. Sem localização, vale para o estoque do catálogo (Product.Stock).
+// Sem ponto de pedido fixo, ele é calculado pela demanda no prazo de entrega mais
+// o estoque de segurança.
+type ReplenishmentPolicy struct {
+	ID           string `json:"id" bson:"_id"`
+	ProductID    string `json:"productId" bson:"productId"`
+	SKU          string `json:"sku,omitempty" bson:"sku"`
+	LocationID   string `json:"locationId,omitempty" bson:"locationId"`
+	ReorderPoint int    `json:"reorderPoint" bson:"reorderPoint"`
+	SafetyStock  int    `json:"safetyStock" bson:"safetyStock"`
+	LeadTimeDays int    `json:"leadTimeDays" bson:"leadTimeDays"`
+	// Lote mínimo de compra e estoque máximo (nível até onde o pedido repõe)
+	MinQuantity int `json:"minQuantity" bson:"minQuantity"`
+	MaxQuantity int `json:"maxQuantity" bson:"maxQuantity"`
+	// Indica que o estoque está no ponto de pedido ou abaixo; o alerta só é
+	// publicado quando o estoque cruza o limite
+	Low         bool       `json:"low" bson:"low"`
+	LastAlertAt *time.Time `json:"lastAlertAt,omitempty" bson:"lastAlertAt,omitempty"`
+	UpdatedAt   time.Time  `json:"updatedAt" bson:"updatedAt"`
+}
+
+// PolicyID é a chave da política: produto, SKU e localização
+func PolicyID(productID, sku, locationID string) string {
+	return productID + "|" + sku + "|" + locationID
+}
+
+// LocationStock é o estoque de um produto em uma localização, lido do serviço de localizações
+type LocationStock struct {
+	Available int
+	InTransit int
+}
+
+// ReplenishmentSuggestion é uma linha do relatório de reposição
+type ReplenishmentSuggestion struct {
+	Policy    *ReplenishmentPolicy `json:"policy"`
+	Available int                  `json:"available"`
+	InTransit int                  `json:"inTransit"`
+	// Vendas médias por dia na janela de cálculo
+	DailyVelocity  float64 `json:"dailyVelocity"`
+	LeadTimeDemand float64 `json:"leadTimeDemand"`
+	ReorderPoint   int     `json:"reorderPoint"`
+	// Dias que o estoque disponível cobre na velocidade atual; nulo sem vendas
+	DaysOfCover       *float64 `json:"daysOfCover,omitempty"`
+	BelowReorderPoint bool     `json:"belowReorderPoint"`
+	SuggestedQuantity int      `json:"suggestedQuantity"`
+}
+
+// LowStockEvent é publicado quando o estoque disponível cruza o ponto de pedido
+type LowStockEvent struct {
+	ProductID         string    `json:"productId"`
+	SKU               string    `json:"sku,omitempty"`
+	LocationID        string    `json:"locationId,omitempty"`
+	Available         int       `json:"available"`
+	ReorderPoint      int       `json:"reorderPoint"`
+	SafetyStock       int       `json:"safetyStock"`
+	SuggestedQuantity int       `json:"suggestedQuantity"`
+	OccurredAt        time.Time `json:"occurredAt"`
+}
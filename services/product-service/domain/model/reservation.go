@@ -0,0 +1,87 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reservation segura unidades de estoque durante o checkout. Se não for
+// confirmada até ExpiresAt, as unidades voltam ao estoque automaticamente.
+// A reserva é gravada como PENDING antes de baixar o estoque; ReservedItems
+// conta quantos itens, na ordem de Items, já tiveram as unidades separadas.
+// O encerramento segue o mesmo caminho: a reserva passa a COMMITTING, RELEASING ou
+// EXPIRING, FinishedItems conta os itens já baixados ou devolvidos e só no fim ela
+// recebe o status final.
+type Reservation struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id"`
+	OrderID       string             `json:"orderId,omitempty" bson:"orderId,omitempty"`
+	Items         []ReservationItem  `json:"items" bson:"items"`
+	Status        ReservationStatus  `json:"status" bson:"status"`
+	ReservedItems int                `json:"-" bson:"reservedItems"`
+	FinishedItems int                `json:"-" bson:"finishedItems"`
+	FinishingAt   *time.Time         `json:"-" bson:"finishingAt,omitempty"`
+	ExpiresAt     time.Time          `json:"expiresAt" bson:"expiresAt"`
+	CreatedAt     time.Time          `json:"createdAt" bson:"createdAt"`
+	FinishedAt    *time.Time         `json:"finishedAt,omitempty" bson:"finishedAt,omitempty"`
+}
+
+type ReservationItem struct {
+	ProductID primitive.ObjectID `json:"productId" bson:"productId"`
+	SKU       string             `json:"sku,omitempty" bson:"sku,omitempty"`
+	Quantity  int                `json:"quantity" bson:"quantity"`
+	// Kit que originou o item, quando a reserva foi feita para um kit
+	BundleID string `json:"bundleId,omitempty" bson:"bundleId,omitempty"`
+}
+
+type ReservationStatus string
+
+const (
+	ReservationPending   ReservationStatus = "PENDING"
+	ReservationActive    ReservationStatus = "ACTIVE"
+	ReservationCommitted ReservationStatus = "COMMITTED"
+	ReservationReleased  ReservationStatus = "RELEASED"
+	ReservationExpired   ReservationStatus = "EXPIRED"
+
+	// Encerramentos em andamento
+	ReservationCommitting ReservationStatus = "COMMITTING"
+	ReservationReleasing  ReservationStatus = "RELEASING"
+	ReservationExpiring   ReservationStatus = "EXPIRING"
+)
+
+// finishingStatuses associa cada status final ao status usado enquanto o estoque é movimentado.
+var finishingStatuses = map[ReservationStatus]ReservationStatus{
+	ReservationCommitted: ReservationCommitting,
+	ReservationReleased:  ReservationReleasing,
+	ReservationExpired:   ReservationExpiring,
+}
+
+// FinishingStatus retorna o status intermediário do encerramento com o status final informado.
+func FinishingStatus(final ReservationStatus) (ReservationStatus, bool) {
+	status, ok := finishingStatuses[final]
+	return status, ok
+}
+
+// FinalStatus retorna o status final de um encerramento em andamento.
+func FinalStatus(finishing ReservationStatus) (ReservationStatus, bool) {
+	for final, status := range finishingStatuses {
+		if status == finishing {
+			return final, true
+		}
+	}
+	return "", false
+}
+
+// IsFinishing informa se o encerramento da reserva começou e ainda não terminou.
+func (r *Reservation) IsFinishing() bool {
+	_, ok := FinalStatus(r.Status)
+	return ok
+}
+
+// RemainingItems retorna os itens que o encerramento ainda precisa baixar ou devolver.
+func (r *Reservation) RemainingItems() []ReservationItem {
+	if r.FinishedItems >= len(r.Items) {
+		return nil
+	}
+	return r.Items[r.FinishedItems:]
+}
@@ -0,0 +1,73 @@
+package model
+
+import "testing"
+
+func TestReservationFinishingStatus(t *testing.T) {
+	tests := []struct {
+		final     ReservationStatus
+		finishing ReservationStatus
+		ok        bool
+	}{
+		{ReservationCommitted, ReservationCommitting, true},
+		{ReservationReleased, ReservationReleasing, true},
+		{ReservationExpired, ReservationExpiring, true},
+		{ReservationActive, "", false},
+		{ReservationPending, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.final), func(t *testing.T) {
+			finishing, ok := FinishingStatus(tt.final)
+			if finishing != tt.finishing || ok != tt.ok {
+				t.Fatalf("FinishingStatus(%s) = %s, %v; esperado %s, %v", tt.final, finishing, ok, tt.finishing, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if final, _ := FinalStatus(finishing); final != tt.final {
+				t.Errorf("FinalStatus(%s) = %s, esperado %s", finishing, final, tt.final)
+			}
+			reservation := &Reservation{Status: finishing}
+			if !reservation.IsFinishing() {
+				t.Errorf("reserva em %s deveria estar em encerramento", finishing)
+			}
+		})
+	}
+
+	for _, status := range []ReservationStatus{ReservationPending, ReservationActive, ReservationCommitted, ReservationReleased, ReservationExpired} {
+		reservation := &Reservation{Status: status}
+		if reservation.IsFinishing() {
+			t.Errorf("reserva em %s não deveria estar em encerramento", status)
+		}
+	}
+}
+
+func TestReservationRemainingItems(t *testing.T) {
+	items := []ReservationItem{{Quantity: 1}, {Quantity: 2}, {Quantity: 3}}
+
+	tests := []struct {
+		name     string
+		finished int
+		want     []int
+	}{
+		{"nenhum item movimentado", 0, []int{1, 2, 3}},
+		{"encerramento interrompido no meio", 2, []int{3}},
+		{"todos movimentados", 3, nil},
+		{"contagem acima dos itens", 4, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reservation := &Reservation{Items: items, FinishedItems: tt.finished}
+			remaining := reservation.RemainingItems()
+			if len(remaining) != len(tt.want) {
+				t.Fatalf("restam %d itens, esperado %d", len(remaining), len(tt.want))
+			}
+			for i, item := range remaining {
+				if item.Quantity != tt.want[i] {
+					t.Errorf("item %d com quantidade %d, esperado %d", i, item.Quantity, tt.want[i])
+				}
+			}
+		})
+	}
+}
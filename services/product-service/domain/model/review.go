@@ -0,0 +1,62 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ReviewStatus string
+
+const (
+	// Aguardando moderação; ainda não aparece na página do produto
+	ReviewPending  ReviewStatus = "PENDING"
+	ReviewApproved ReviewStatus = "APPROVED"
+	ReviewRejected ReviewStatus = "REJECTED"
+)
+
+type ReviewSort string
+
+const (
+	ReviewSortRecent     ReviewSort = "recent"
+	ReviewSortHelpful    ReviewSort = "helpful"
+	ReviewSortRatingDesc ReviewSort = "rating_desc"
+	ReviewSortRatingAsc  ReviewSort = "rating_asc"
+)
+
+// Review é a avaliação de um produto por um cliente que o comprou
+type Review struct {
+	ID               primitive.ObjectID `json:"id" bson:"_id"`
+	ProductID        primitive.ObjectID `json:"productId" bson:"productId"`
+	CustomerID       string             `json:"customerId" bson:"customerId"`
+	OrderID          string             `json:"orderId,omitempty" bson:"orderId,omitempty"`
+	VerifiedPurchase bool               `json:"verifiedPurchase" bson:"verifiedPurchase"`
+	Rating           int                `json:"rating" bson:"rating"`
+	Title            string             `json:"title,omitempty" bson:"title,omitempty"`
+	Text             string             `json:"text" bson:"text"`
+	Photos           []Media            `json:"photos,omitempty" bson:"photos,omitempty"`
+	Status           ReviewStatus       `json:"status" bson:"status"`
+	RejectionReason  string             `json:"rejectionReason,omitempty" bson:"rejectionReason,omitempty"`
+	ModeratedBy      string             `json:"moderatedBy,omitempty" bson:"moderatedBy,omitempty"`
+	ModeratedAt      *time.Time         `json:"moderatedAt,omitempty" bson:"moderatedAt,omitempty"`
+	HelpfulVotes     int                `json:"helpfulVotes" bson:"helpfulVotes"`
+	UnhelpfulVotes   int                `json:"unhelpfulVotes" bson:"unhelpfulVotes"`
+	CreatedAt        time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// ReviewVote é o voto de um cliente sobre a utilidade de uma avaliação; cada
+// cliente tem um voto por avaliação e pode trocá-lo
+type ReviewVote struct {
+	ReviewID   primitive.ObjectID `json:"reviewId" bson:"reviewId"`
+	CustomerID string             `json:"customerId" bson:"customerId"`
+	Helpful    bool               `json:"helpful" bson:"helpful"`
+	VotedAt    time.Time          `json:"votedAt" bson:"votedAt"`
+}
+
+// RatingSummary resume as avaliações aprovadas do produto. Distribution conta as
+// avaliações por nota, de "1" a "5".
+type RatingSummary struct {
+	Average      float64        `json:"average" bson:"average"`
+	Count        int            `json:"count" bson:"count"`
+	Distribution map[string]int `json:"distribution" bson:"distribution"`
+}
@@ -0,0 +1,195 @@
+package model
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale é o idioma dos campos Name e Description do produto e da categoria,
+// usado quando não há tradução para o idioma pedido
+const DefaultLocale = "pt-BR"
+
+// SupportedLocales são os idiomas aceitos nas traduções e na negociação, além do padrão
+var SupportedLocales = []string{DefaultLocale, "es", "en"}
+
+var ErrUnsupportedLocale = errors.New("idioma não suportado")
+
+// Campos traduzíveis, usados no relatório de traduções incompletas
+const (
+	TranslationName        = "name"
+	TranslationDescription = "description"
+)
+
+// Translation é o texto de um produto ou categoria em um idioma. Campos vazios usam o texto em pt-BR.
+type Translation struct {
+	Name        string `json:"name,omitempty" bson:"name,omitempty"`
+	Description string `json:"description,omitempty" bson:"description,omitempty"`
+}
+
+// NormalizeLocale retorna o idioma suportado correspondente, com a grafia canônica
+// (pt-br -> pt-BR). Um idioma regional sem tradução própria cai no idioma base.
+func NormalizeLocale(locale string) (string, error) {
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	if locale == "" {
+		return "", ErrUnsupportedLocale
+	}
+	for _, supported := range SupportedLocales {
+		if strings.EqualFold(supported, locale) {
+			return supported, nil
+		}
+	}
+	// Pelo idioma base: es-MX é atendido pelo es e pt-PT pelo pt-BR
+	base := strings.SplitN(locale, "-", 2)[0]
+	for _, supported := range SupportedLocales {
+		if strings.EqualFold(strings.SplitN(supported, "-", 2)[0], base) {
+			return supported, nil
+		}
+	}
+	return "", ErrUnsupportedLocale
+}
+
+// NegotiateLocale escolhe o idioma da resposta: o parâmetro locale tem precedência e,
+// sem ele, vale o idioma suportado de maior peso no cabeçalho Accept-Language.
+// Sem nenhum idioma suportado, a resposta sai em pt-BR.
+func NegotiateLocale(requested, acceptLanguage string) string {
+	if locale, err := NormalizeLocale(requested); err == nil {
+		return locale
+	}
+
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				value, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err == nil {
+					quality = value
+				}
+			}
+		}
+		if quality > 0 {
+			tags = append(tags, weighted{tag: tag, quality: quality})
+		}
+	}
+	// Com pesos iguais vale a ordem do cabeçalho
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].quality > tags[j].quality })
+
+	for _, item := range tags {
+		if locale, err := NormalizeLocale(item.tag); err == nil {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
+
+// Texto no idioma pedido, campo a campo, com o texto em pt-BR para o que não foi traduzido
+func translate(translations map[string]Translation, locale, name, description string) (string, string) {
+	if translation, ok := translations[locale]; ok && locale != DefaultLocale {
+		if translation.Name != "" {
+			name = translation.Name
+		}
+		if translation.Description != "" {
+			description = translation.Description
+		}
+	}
+	return name, description
+}
+
+// Campos que têm texto em pt-BR e ainda não foram traduzidos em cada idioma suportado;
+// idiomas completos ficam fora do resultado
+func missingTranslations(translations map[string]Translation, name, description string) map[string][]string {
+	missing := make(map[string][]string)
+	for _, locale := range SupportedLocales {
+		if locale == DefaultLocale {
+			continue
+		}
+		translation := translations[locale]
+		var fields []string
+		if name != "" && translation.Name == "" {
+			fields = append(fields, TranslationName)
+		}
+		if description != "" && translation.Description == "" {
+			fields = append(fields, TranslationDescription)
+		}
+		if len(fields) > 0 {
+			missing[locale] = fields
+		}
+	}
+	return missing
+}
+
+// Localized retorna uma cópia do produto com nome, descrição e categoria no idioma
+// informado. As traduções ficam fora da cópia; elas têm endpoint próprio.
+func (p *Product) Localized(locale string) *Product {
+	localized := *p
+	localized.Name, localized.Description = translate(p.Translations, locale, p.Name, p.Description)
+	localized.Category = p.Category.Localized(locale)
+	localized.Translations = nil
+	return &localized
+}
+
+// MissingTranslations lista, por idioma, os campos do produto sem tradução
+func (p *Product) MissingTranslations() map[string][]string {
+	return missingTranslations(p.Translations, p.Name, p.Description)
+}
+
+// Localized retorna a referência da categoria no idioma informado
+func (c Category) Localized(locale string) Category {
+	c.Name, c.Description = translate(c.Translations, locale, c.Name, c.Description)
+	c.Translations = nil
+	return c
+}
+
+// Localized retorna uma cópia da categoria no idioma informado, sem as traduções
+func (c *CategoryNode) Localized(locale string) *CategoryNode {
+	localized := *c
+	localized.Name, localized.Description = translate(c.Translations, locale, c.Name, c.Description)
+	localized.Translations = nil
+	return &localized
+}
+
+// MissingTranslations lista, por idioma, os campos da categoria sem tradução
+func (c *CategoryNode) MissingTranslations() map[string][]string {
+	return missingTranslations(c.Translations, c.Name, c.Description)
+}
+
+// Localized retorna uma cópia da árvore com todas as categorias no idioma informado
+func (t *CategoryTree) Localized(locale string) *CategoryTree {
+	localized := &CategoryTree{
+		CategoryNode: t.CategoryNode.Localized(locale),
+		Children:     make([]*CategoryTree, 0, len(t.Children)),
+	}
+	for _, child := range t.Children {
+		localized.Children = append(localized.Children, child.Localized(locale))
+	}
+	return localized
+}
+
+// TranslationStatus mostra as traduções de um produto ou categoria e os campos que
+// faltam traduzir em cada idioma
+type TranslationStatus struct {
+	ID            string                 `json:"id"`
+	Kind          string                 `json:"kind"`
+	Name          string                 `json:"name"`
+	DefaultLocale string                 `json:"defaultLocale"`
+	Translations  map[string]Translation `json:"translations"`
+	Missing       map[string][]string    `json:"missing"`
+	Complete      bool                   `json:"complete"`
+}
+
+const (
+	TranslationKindProduct  = "PRODUCT"
+	TranslationKindCategory = "CATEGORY"
+)
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/infra/db"
+	"context"
+	"errors"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrDuplicateGTIN = errors.New("o GTIN já está cadastrado em outro produto ou variante")
+
+type MongoBarcodeRepository struct {
+	client *mongo.Client
+}
+
+func NewMongoBarcodeRepository(mongoURI string) *MongoBarcodeRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	collection := client.Database("productDB").Collection("barcodes")
+	_, err = collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "productId", Value: 1}},
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índice de códigos de barras: %v\n", err)
+	}
+
+	return &MongoBarcodeRepository{
+		client: client,
+	}
+}
+
+// Register reserva o GTIN para o produto. Registrar de novo o mesmo GTIN para o
+// mesmo produto e SKU não é erro.
+func (r *MongoBarcodeRepository) Register(barcode *model.Barcode) error {
+	collection := r.client.Database("productDB").Collection("barcodes")
+
+	_, err := collection.InsertOne(context.TODO(), barcode)
+	if mongo.IsDuplicateKeyError(err) {
+		existing, findErr := r.FindByGTIN14(barcode.ID)
+		if findErr == nil && existing != nil && existing.ProductID == barcode.ProductID && existing.SKU == barcode.SKU {
+			return nil
+		}
+		return ErrDuplicateGTIN
+	}
+	return err
+}
+
+func (r *MongoBarcodeRepository) FindByGTIN14(gtin14 string) (*model.Barcode, error) {
+	collection := r.client.Database("productDB").Collection("barcodes")
+
+	var barcode model.Barcode
+	err := collection.FindOne(context.TODO(), bson.M{"_id": gtin14}).Decode(&barcode)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &barcode, nil
+}
+
+// Release libera o GTIN apenas se ele ainda pertencer ao produto e SKU informados
+func (r *MongoBarcodeRepository) Release(gtin14, productID, sku string) error {
+	collection := r.client.Database("productDB").Collection("barcodes")
+
+	filter := bson.M{"_id": gtin14, "productId": productID}
+	if sku != "" {
+		filter["sku"] = sku
+	} else {
+		filter["sku"] = bson.M{"$exists": false}
+	}
+
+	_, err := collection.DeleteOne(context.TODO(), filter)
+	return err
+}
+
+func (r *MongoBarcodeRepository) ReleaseProduct(productID string) error {
+	collection := r.client.Database("productDB").Collection("barcodes")
+
+	_, err := collection.DeleteMany(context.TODO(), bson.M{"productId": productID})
+	return err
+}
+
+// NextSequence incrementa e retorna o contador informado
+func (r *MongoBarcodeRepository) NextSequence(name string) (int64, error) {
+	collection := r.client.Database("productDB").Collection("counters")
+
+	var counter struct {
+		Value int64 `bson:"value"`
+	}
+	err := collection.FindOneAndUpdate(context.TODO(),
+		bson.M{"_id": name},
+		bson.M{"$inc": bson.M{"value": 1}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	if err != nil {
+		return 0, err
+	}
+
+	return counter.Value, nil
+}
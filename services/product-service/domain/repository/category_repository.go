@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/infra/db"
+	"context"
+	"errors"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrDuplicateCategoryPath = errors.New("já existe uma categoria com este caminho")
+
+type MongoCategoryRepository struct {
+	client *mongo.Client
+}
+
+func NewMongoCategoryRepository(mongoURI string) *MongoCategoryRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	collection := client.Database("productDB").Collection("categories")
+	_, err = collection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "path", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "ancestors", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índices de categorias: %v\n", err)
+	}
+
+	return &MongoCategoryRepository{
+		client: client,
+	}
+}
+
+func (r *MongoCategoryRepository) Save(category *model.CategoryNode) error {
+	collection := r.client.Database("productDB").Collection("categories")
+
+	_, err := collection.InsertOne(context.TODO(), category)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicateCategoryPath
+	}
+	return err
+}
+
+func (r *MongoCategoryRepository) FindByID(id primitive.ObjectID) (*model.CategoryNode, error) {
+	return r.findOne(bson.M{"_id": id})
+}
+
+func (r *MongoCategoryRepository) FindByPath(path string) (*model.CategoryNode, error) {
+	return r.findOne(bson.M{"path": path})
+}
+
+// Busca pelo nome sem diferenciar maiúsculas; usado para produtos enviados só com o nome da categoria
+func (r *MongoCategoryRepository) FindByName(name string) (*model.CategoryNode, error) {
+	collection := r.client.Database("productDB").Collection("categories")
+
+	var category model.CategoryNode
+	opts := options.FindOne().SetCollation(&options.Collation{Locale: "pt", Strength: 1})
+	err := collection.FindOne(context.TODO(), bson.M{"name": name}, opts).Decode(&category)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (r *MongoCategoryRepository) findOne(filter bson.M) (*model.CategoryNode, error) {
+	collection := r.client.Database("productDB").Collection("categories")
+
+	var category model.CategoryNode
+	err := collection.FindOne(context.TODO(), filter).Decode(&category)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (r *MongoCategoryRepository) ListAll() ([]*model.CategoryNode, error) {
+	return r.find(bson.M{})
+}
+
+func (r *MongoCategoryRepository) ListChildren(parentID *primitive.ObjectID) ([]*model.CategoryNode, error) {
+	if parentID == nil {
+		return r.find(bson.M{"parentId": bson.M{"$exists": false}})
+	}
+	return r.find(bson.M{"parentId": *parentID})
+}
+
+// Lista todas as categorias abaixo da informada, em qualquer nível
+func (r *MongoCategoryRepository) ListDescendants(id primitive.ObjectID) ([]*model.CategoryNode, error) {
+	return r.find(bson.M{"ancestors": id})
+}
+
+func (r *MongoCategoryRepository) find(filter bson.M) ([]*model.CategoryNode, error) {
+	collection := r.client.Database("productDB").Collection("categories")
+
+	opts := options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "name", Value: 1}})
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var categories []*model.CategoryNode
+	for cursor.Next(context.TODO()) {
+		var category model.CategoryNode
+		if err := cursor.Decode(&category); err != nil {
+			return nil, err
+		}
+		categories = append(categories, &category)
+	}
+
+	return categories, cursor.Err()
+}
+
+func (r *MongoCategoryRepository) Update(category *model.CategoryNode) error {
+	collection := r.client.Database("productDB").Collection("categories")
+
+	updateData := bson.M{
+		"name":        category.Name,
+		"slug":        category.Slug,
+		"path":        category.Path,
+		"description": category.Description,
+		"ancestors":   category.Ancestors,
+		"position":    category.Position,
+	}
+	update := bson.M{"$set": updateData}
+	if category.ParentID == nil {
+		update["$unset"] = bson.M{"parentId": ""}
+	} else {
+		updateData["parentId"] = category.ParentID
+	}
+
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": category.ID}, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicateCategoryPath
+	}
+	return err
+}
+
+// SetAttributes substitui o esquema de atributos próprio da categoria
+func (r *MongoCategoryRepository) SetAttributes(id primitive.ObjectID, attributes []model.AttributeDefinition) error {
+	collection := r.client.Database("productDB").Collection("categories")
+
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": bson.M{"attributes": attributes}})
+	return err
+}
+
+// SetGoogleCategory grava a categoria do Google; vazia remove o mapeamento
+func (r *MongoCategoryRepository) SetGoogleCategory(id primitive.ObjectID, googleCategory string) error {
+	collection := r.client.Database("productDB").Collection("categories")
+
+	update := bson.M{"$set": bson.M{"googleCategory": googleCategory}}
+	if googleCategory == "" {
+		update = bson.M{"$unset": bson.M{"googleCategory": ""}}
+	}
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id}, update)
+	return err
+}
+
+// SetTranslation grava o nome e a descrição da categoria no idioma informado
+func (r *MongoCategoryRepository) SetTranslation(id primitive.ObjectID, locale string, translation model.Translation) error {
+	collection := r.client.Database("productDB").Collection("categories")
+
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": bson.M{"translations." + locale: translation}})
+	return err
+}
+
+func (r *MongoCategoryRepository) RemoveTranslation(id primitive.ObjectID, locale string) error {
+	collection := r.client.Database("productDB").Collection("categories")
+
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$unset": bson.M{"translations." + locale: ""}})
+	return err
+}
+
+func (r *MongoCategoryRepository) Delete(id primitive.ObjectID) error {
+	collection := r.client.Database("productDB").Collection("categories")
+
+	result, err := collection.DeleteOne(context.TODO(), bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("categoria não encontrada")
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/infra/db"
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const forecastBatchSize = 500
+
+type MongoForecastRepository struct {
+	client *mongo.Client
+}
+
+func NewMongoForecastRepository(mongoURI string) *MongoForecastRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	_, err = client.Database("productDB").Collection("forecasts").Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "productId", Value: 1}, {Key: "sku", Value: 1}, {Key: "locationId", Value: 1}}},
+		{Keys: bson.D{{Key: "locationId", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índices de previsões: %v\n", err)
+	}
+
+	return &MongoForecastRepository{
+		client: client,
+	}
+}
+
+// EachSalesLine percorre os itens dos pedidos não cancelados da coleção orders do
+// serviço de pedidos feitos a partir de since. Os kits são trocados pelos componentes.
+func (r *MongoForecastRepository) EachSalesLine(since time.Time, fn func(line *model.SalesLine) error) error {
+	collection := r.client.Database("orderDB").Collection("orders")
+
+	filter := bson.M{"status": bson.M{"$ne": "CANCELED"}, "orderDate": bson.M{"$gte": since}}
+	opts := options.Find().SetProjection(bson.M{
+		"products.productId": 1, "products.sku": 1, "products.quantity": 1, "products.components": 1, "orderDate": 1,
+	})
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.TODO())
+
+	type item struct {
+		ProductID string `bson:"productId"`
+		SKU       string `bson:"sku"`
+		Quantity  int    `bson:"quantity"`
+	}
+	for cursor.Next(context.TODO()) {
+		var document struct {
+			ID       primitive.ObjectID `bson:"_id"`
+			Products []struct {
+				ProductID  string `bson:"productId"`
+				SKU        string `bson:"sku"`
+				Quantity   int    `bson:"quantity"`
+				Components []item `bson:"components"`
+			} `bson:"products"`
+			OrderDate primitive.DateTime `bson:"orderDate"`
+		}
+		if err := cursor.Decode(&document); err != nil {
+			return err
+		}
+
+		for _, product := range document.Products {
+			items := product.Components
+			if len(items) == 0 {
+				items = []item{{ProductID: product.ProductID, SKU: product.SKU, Quantity: product.Quantity}}
+			}
+			for _, sold := range items {
+				line := &model.SalesLine{
+					OrderID:   document.ID.Hex(),
+					ProductID: sold.ProductID,
+					SKU:       sold.SKU,
+					Quantity:  sold.Quantity,
+					OrderDate: document.OrderDate.Time(),
+				}
+				if err := fn(line); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return cursor.Err()
+}
+
+// Replace grava as previsões geradas e remove as das séries que não foram
+// recalculadas, como as de produtos sem vendas no histórico
+func (r *MongoForecastRepository) Replace(forecasts []*model.Forecast, generatedAt time.Time) error {
+	collection := r.client.Database("productDB").Collection("forecasts")
+
+	for start := 0; start < len(forecasts); start += forecastBatchSize {
+		end := start + forecastBatchSize
+		if end > len(forecasts) {
+			end = len(forecasts)
+		}
+
+		models := make([]mongo.WriteModel, 0, end-start)
+		for _, forecast := range forecasts[start:end] {
+			models = append(models, mongo.NewReplaceOneModel().
+				SetFilter(bson.M{"_id": forecast.ID}).
+				SetReplacement(forecast).
+				SetUpsert(true))
+		}
+		if _, err := collection.BulkWrite(context.TODO(), models, options.BulkWrite().SetOrdered(false)); err != nil {
+			return err
+		}
+	}
+
+	_, err := collection.DeleteMany(context.TODO(), bson.M{"generatedAt": bson.M{"$lt": generatedAt}})
+	return err
+}
+
+// List filtra por produto, SKU e localização; locationID nulo não filtra a
+// localização e vazio retorna só as previsões da demanda total
+func (r *MongoForecastRepository) List(productID, sku string, locationID *string) ([]*model.Forecast, error) {
+	collection := r.client.Database("productDB").Collection("forecasts")
+
+	filter := bson.M{}
+	if productID != "" {
+		filter["productId"] = productID
+	}
+	if sku != "" {
+		filter["sku"] = sku
+	}
+	if locationID != nil {
+		filter["locationId"] = *locationID
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "productId", Value: 1}, {Key: "sku", Value: 1}, {Key: "locationId", Value: 1}})
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	forecasts := make([]*model.Forecast, 0)
+	if err := cursor.All(context.TODO(), &forecasts); err != nil {
+		return nil, err
+	}
+	return forecasts, nil
+}
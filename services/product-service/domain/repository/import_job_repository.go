@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/infra/db"
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoImportJobRepository struct {
+	client *mongo.Client
+}
+
+func NewMongoImportJobRepository(mongoURI string) *MongoImportJobRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	return &MongoImportJobRepository{
+		client: client,
+	}
+}
+
+func (r *MongoImportJobRepository) Save(job *model.ImportJob) error {
+	collection := r.client.Database("productDB").Collection("import_jobs")
+
+	_, err := collection.InsertOne(context.TODO(), job)
+	if err != nil {
+		log.Printf("Erro ao inserir importação no MongoDB: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// Update grava o progresso e o resultado da importação
+func (r *MongoImportJobRepository) Update(job *model.ImportJob) error {
+	collection := r.client.Database("productDB").Collection("import_jobs")
+
+	_, err := collection.ReplaceOne(context.TODO(), bson.M{"_id": job.ID}, job)
+	return err
+}
+
+func (r *MongoImportJobRepository) FindByID(id primitive.ObjectID) (*model.ImportJob, error) {
+	collection := r.client.Database("productDB").Collection("import_jobs")
+
+	var job model.ImportJob
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&job)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// List retorna as importações mais recentes, sem o relatório de erros
+func (r *MongoImportJobRepository) List(limit int64) ([]*model.ImportJob, error) {
+	collection := r.client.Database("productDB").Collection("import_jobs")
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(limit).
+		SetProjection(bson.M{"errors": 0})
+	cursor, err := collection.Find(context.TODO(), bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var jobs []*model.ImportJob
+	for cursor.Next(context.TODO()) {
+		var job model.ImportJob
+		if err := cursor.Decode(&job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, &job)
+	}
+
+	return jobs, cursor.Err()
+}
+
+// FailInterrupted marca como falhas as importações que estavam em andamento
+// quando o serviço foi encerrado; o arquivo não é mantido após a reinicialização.
+func (r *MongoImportJobRepository) FailInterrupted(now time.Time) (int64, error) {
+	collection := r.client.Database("productDB").Collection("import_jobs")
+
+	filter := bson.M{"status": bson.M{"$in": []model.ImportJobStatus{model.ImportPending, model.ImportRunning}}}
+	update := bson.M{"$set": bson.M{
+		"status":     model.ImportFailed,
+		"message":    "importação interrompida pela reinicialização do serviço; envie o arquivo novamente",
+		"finishedAt": now,
+	}}
+
+	result, err := collection.UpdateMany(context.TODO(), filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/infra/db"
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoPriceRepository struct {
+	client *mongo.Client
+}
+
+func NewMongoPriceRepository(mongoURI string) *MongoPriceRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	collection := client.Database("productDB").Collection("price_changes")
+	_, err = collection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "productId", Value: 1}, {Key: "sku", Value: 1}, {Key: "effectiveAt", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "effectiveAt", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índices do histórico de preços: %v\n", err)
+	}
+
+	return &MongoPriceRepository{
+		client: client,
+	}
+}
+
+func (r *MongoPriceRepository) Save(change *model.PriceChange) error {
+	collection := r.client.Database("productDB").Collection("price_changes")
+
+	_, err := collection.InsertOne(context.TODO(), change)
+	return err
+}
+
+func (r *MongoPriceRepository) FindByID(id primitive.ObjectID) (*model.PriceChange, error) {
+	return r.findOne(bson.M{"_id": id}, nil)
+}
+
+// Lista o histórico do produto, do mais recente para o mais antigo
+func (r *MongoPriceRepository) ListByProduct(productID primitive.ObjectID) ([]*model.PriceChange, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "effectiveAt", Value: -1}, {Key: "createdAt", Value: -1}})
+	return r.find(bson.M{"productId": productID}, opts)
+}
+
+// Lista as mudanças agendadas cujo horário já chegou, na ordem em que devem ser aplicadas,
+// e as que ficaram em aplicação desde antes de staleBefore
+func (r *MongoPriceRepository) ListDue(now, staleBefore time.Time) ([]*model.PriceChange, error) {
+	filter := bson.M{"$or": []bson.M{
+		{"status": model.PriceScheduled, "effectiveAt": bson.M{"$lte": now}},
+		{"status": model.PriceApplying, "claimedAt": bson.M{"$lte": staleBefore}},
+	}}
+	opts := options.Find().SetSort(bson.D{{Key: "effectiveAt", Value: 1}, {Key: "createdAt", Value: 1}})
+	return r.find(filter, opts)
+}
+
+// Busca a última mudança aplicada com vigência até o instante informado
+func (r *MongoPriceRepository) FindEffective(productID primitive.ObjectID, sku string, at time.Time) (*model.PriceChange, error) {
+	filter := bson.M{
+		"productId":   productID,
+		"status":      model.PriceApplied,
+		"effectiveAt": bson.M{"$lte": at},
+	}
+	if sku == "" {
+		filter["sku"] = bson.M{"$exists": false}
+	} else {
+		filter["sku"] = sku
+	}
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "effectiveAt", Value: -1}, {Key: "version", Value: -1}})
+	return r.findOne(filter, opts)
+}
+
+// Claim troca o status de SCHEDULED para APPLYING antes de alterar o produto, para
+// que duas instâncias do agendador não apliquem a mesma mudança. Uma mudança que
+// ficou em APPLYING desde antes de staleBefore pode ser retomada.
+func (r *MongoPriceRepository) Claim(id primitive.ObjectID, now, staleBefore time.Time) (bool, error) {
+	collection := r.client.Database("productDB").Collection("price_changes")
+
+	filter := bson.M{"_id": id, "$or": []bson.M{
+		{"status": model.PriceScheduled},
+		{"status": model.PriceApplying, "claimedAt": bson.M{"$lte": staleBefore}},
+	}}
+	update := bson.M{"$set": bson.M{"status": model.PriceApplying, "claimedAt": now}}
+
+	result, err := collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// Unclaim devolve a mudança para SCHEDULED quando o preço não chegou a ser aplicado
+func (r *MongoPriceRepository) Unclaim(id primitive.ObjectID) error {
+	collection := r.client.Database("productDB").Collection("price_changes")
+
+	filter := bson.M{"_id": id, "status": model.PriceApplying}
+	update := bson.M{"$set": bson.M{"status": model.PriceScheduled}, "$unset": bson.M{"claimedAt": ""}}
+	_, err := collection.UpdateOne(context.TODO(), filter, update)
+	return err
+}
+
+// Conclui a mudança em aplicação gravando o preço anterior e a versão
+func (r *MongoPriceRepository) SetApplied(change *model.PriceChange) error {
+	collection := r.client.Database("productDB").Collection("price_changes")
+
+	filter := bson.M{"_id": change.ID, "status": model.PriceApplying}
+	update := bson.M{"$set": bson.M{
+		"status":    model.PriceApplied,
+		"appliedAt": change.AppliedAt,
+		"oldPrice":  change.OldPrice,
+		"newPrice":  change.NewPrice,
+		"version":   change.Version,
+	}}
+	_, err := collection.UpdateOne(context.TODO(), filter, update)
+	return err
+}
+
+func (r *MongoPriceRepository) Cancel(id primitive.ObjectID) (bool, error) {
+	collection := r.client.Database("productDB").Collection("price_changes")
+
+	filter := bson.M{"_id": id, "status": model.PriceScheduled}
+	result, err := collection.UpdateOne(context.TODO(), filter, bson.M{"$set": bson.M{"status": model.PriceCanceled}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (r *MongoPriceRepository) findOne(filter bson.M, opts *options.FindOneOptions) (*model.PriceChange, error) {
+	collection := r.client.Database("productDB").Collection("price_changes")
+
+	if opts == nil {
+		opts = options.FindOne()
+	}
+
+	var change model.PriceChange
+	err := collection.FindOne(context.TODO(), filter, opts).Decode(&change)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &change, nil
+}
+
+func (r *MongoPriceRepository) find(filter bson.M, opts *options.FindOptions) ([]*model.PriceChange, error) {
+	collection := r.client.Database("productDB").Collection("price_changes")
+
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var changes []*model.PriceChange
+	for cursor.Next(context.TODO()) {
+		var change model.PriceChange
+		if err := cursor.Decode(&change); err != nil {
+			return nil, err
+		}
+		changes = append(changes, &change)
+	}
+
+	return changes, cursor.Err()
+}
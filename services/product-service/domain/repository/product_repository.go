@@ -6,12 +6,34 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Tópico com os eventos de alteração do catálogo
+const ProductEventTopic = "Product_Event_Topic"
+
+// Tópico dos alertas de estoque baixo, separado dos eventos de produto porque não carrega o produto
+const LowStockTopic = "Low_Stock_Topic"
+
+var (
+	// ErrInsufficientStock indica que o produto não tem unidades disponíveis suficientes
+	ErrInsufficientStock = errors.New("estoque insuficiente")
+	ErrVariantNotFound   = errors.New("variante não encontrada")
+	ErrDuplicateSKU      = errors.New("já existe um produto ou variante com este SKU")
+	ErrGTINAssigned      = errors.New("o produto ou variante já possui GTIN")
+	ErrMediaNotFound     = errors.New("mídia não encontrada")
+	ErrMediaLimit        = errors.New("o produto atingiu o limite de mídias")
+	ErrMediaChanged      = errors.New("a galeria foi alterada por outra operação, tente novamente")
 )
 
 type MongoProductRepository struct {
@@ -33,6 +55,39 @@ func NewMongoProductRepository(mongoURI string, kafkaBroker string) *MongoProduc
 		log.Fatalf("Erro ao conectar-se ao Kafka: %v", err)
 	}
 
+	// Busca dos kits que usam um componente
+	_, err = client.Database("productDB").Collection("products").Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "bundle.components.productId", Value: 1}},
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índice de componentes de kits: %v\n", err)
+	}
+
+	// Filtros por atributos dinâmicos da categoria
+	_, err = client.Database("productDB").Collection("products").Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "attributes.$**", Value: 1}},
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índice de atributos: %v\n", err)
+	}
+
+	// O SKU das variantes e o SKU dos produtos são únicos entre todos os produtos
+	_, err = client.Database("productDB").Collection("products").Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "variants.sku", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"variants.sku": bson.M{"$exists": true}}),
+		},
+		{
+			Keys: bson.D{{Key: "sku", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"sku": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índice de SKU: %v\n", err)
+	}
+
 	return &MongoProductRepository{
 		client: client,
 		kafka:  producer,
@@ -64,6 +119,28 @@ func (r *MongoProductRepository) ListAll() ([]*model.Product, error) {
 	return products, nil
 }
 
+// Each percorre o catálogo com um cursor, sem carregar todos os produtos em memória
+func (r *MongoProductRepository) Each(fn func(product *model.Product) error) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+	cursor, err := productCollection.Find(context.TODO(), bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var product model.Product
+		if err := cursor.Decode(&product); err != nil {
+			return err
+		}
+		if err := fn(&product); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
 func (r *MongoProductRepository) FindByID(id string) (*model.Product, error) {
 	collection := r.client.Database("productDB").Collection("products")
 
@@ -91,46 +168,19 @@ func (r *MongoProductRepository) SaveProduct(product *model.Product) error {
 
 	// Inserir o produto na coleção
 	_, err := productCollection.InsertOne(context.TODO(), product)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicateSKU
+	}
 	if err != nil {
 		log.Printf("Erro ao inserir produto no MongoDB: %v\n", err)
 		return err
 	}
 
-	// Convertendo o produto para JSON para enviar ao Kafka
-	productJSON, err := json.Marshal(product)
-	if err != nil {
-		log.Printf("Erro ao organizar o produto: %v", err)
+	if err := r.publish("Product_Topic_One", product); err != nil {
 		return err
 	}
 
-	// Definindo o tópico do Kafka para o produto
-	topic := "Product_Topic_One"
-	message := &kafka.Message{
-		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
-		Value:          productJSON,
-	}
-
-	// Enviando a mensagem ao Kafka
-	deliveryChan := make(chan kafka.Event)
-	err = r.kafka.Produce(message, deliveryChan)
-	if err != nil {
-		log.Printf("Erro ao produzir mensagem para Kafka: %v\n", err)
-		close(deliveryChan)
-		return err
-	}
-
-	// Manipulando a resposta do envio ao Kafka
-	e := <-deliveryChan
-	switch ev := e.(type) {
-	case *kafka.Message:
-		if ev.TopicPartition.Error != nil {
-			log.Printf("Erro ao enviar a mensagem ao Kafka: %v\n", ev.TopicPartition.Error)
-			close(deliveryChan)
-			return ev.TopicPartition.Error
-		}
-	}
-
-	close(deliveryChan)
+	r.publishEvent(model.ProductCreated, product.ID)
 	return nil
 }
 
@@ -140,25 +190,52 @@ func (r *MongoProductRepository) Update(product *model.Product) error {
 	// Usando o ID diretamente para o filtro
 	filter := bson.M{"_id": product.ID}
 
-	// Dados de atualização com todos os campos do Product
+	// Estoque e preço não são sobrescritos aqui: o estoque muda pelas operações
+	// atômicas de estoque e o preço pelo histórico de preços
 	updateData := bson.M{
 		"name":        product.Name,
 		"description": product.Description,
-		"price":       product.Price,
-		"category": bson.M{
-			"name":        product.Category.Name,
-			"description": product.Category.Description,
-		},
-		"stock":     product.Stock,
-		"addedDate": product.AddedDate,
+		"category":    product.Category,
+		"addedDate":   product.AddedDate,
+	}
+	// O SKU só é alterado quando informado, para não apagar o de clientes que não o enviam
+	if product.SKU != "" {
+		updateData["sku"] = product.SKU
+	}
+	if product.GTIN != "" {
+		updateData["gtin"] = product.GTIN
+	}
+	if product.Attributes != nil {
+		updateData["attributes"] = product.Attributes
 	}
 
 	// Atualiza o documento
 	_, err := productCollection.UpdateOne(context.TODO(), filter, bson.M{"$set": updateData})
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicateSKU
+	}
 	if err != nil {
 		return err
 	}
 
+	r.publishEvent(model.ProductUpdated, product.ID)
+	return nil
+}
+
+// SetStatus grava o status do produto; usado para descontinuar e reativar, já que
+// AVAILABLE e OUT_OF_STOCK acompanham o estoque e não passam por Update
+func (r *MongoProductRepository) SetStatus(id primitive.ObjectID, status model.ProductStatus) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	result, err := productCollection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": bson.M{"status": status}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("produto não encontrado")
+	}
+
+	r.publishEvent(model.ProductUpdated, id)
 	return nil
 }
 
@@ -185,9 +262,770 @@ func (r *MongoProductRepository) Delete(id string) error {
 		return errors.New("nenhum produto encontrado com o ID fornecido")
 	}
 
+	r.publishEvent(model.ProductDeleted, objID)
+	return nil
+}
+
+// ReserveStock retira unidades do estoque disponível e as move para reservado.
+// O filtro garante que o estoque nunca fique negativo, mesmo com pedidos concorrentes.
+// Com SKU informado, o estoque da variante e o total do produto mudam no mesmo update.
+func (r *MongoProductRepository) ReserveStock(id primitive.ObjectID, sku string, quantity int) error {
+	filter := bson.M{
+		"_id":    id,
+		"status": bson.M{"$ne": model.Discontinued},
+	}
+	r.stockCondition(filter, sku, "stock", quantity)
+
+	return r.incStock(filter, stockUpdate(sku, -quantity, quantity))
+}
+
+// ReleaseStock devolve ao estoque disponível unidades que estavam reservadas
+func (r *MongoProductRepository) ReleaseStock(id primitive.ObjectID, sku string, quantity int) error {
+	filter := bson.M{"_id": id}
+	r.stockCondition(filter, sku, "reserved", quantity)
+
+	return r.incStock(filter, stockUpdate(sku, quantity, -quantity))
+}
+
+// CommitStock baixa definitivamente unidades reservadas após a confirmação do pedido
+func (r *MongoProductRepository) CommitStock(id primitive.ObjectID, sku string, quantity int) error {
+	filter := bson.M{"_id": id}
+	r.stockCondition(filter, sku, "reserved", quantity)
+
+	return r.incStock(filter, stockUpdate(sku, 0, -quantity))
+}
+
+// AdjustStock soma (ou subtrai) unidades do estoque disponível sem deixá-lo negativo
+func (r *MongoProductRepository) AdjustStock(id primitive.ObjectID, sku string, delta int) error {
+	filter := bson.M{"_id": id}
+	minimum := 0
+	if delta < 0 {
+		minimum = -delta
+	}
+	r.stockCondition(filter, sku, "stock", minimum)
+
+	return r.incStock(filter, stockUpdate(sku, delta, 0))
+}
+
+// Exige que o campo (do produto ou da variante) tenha ao menos a quantidade informada
+func (r *MongoProductRepository) stockCondition(filter bson.M, sku string, field string, minimum int) {
+	// O estoque dos kits é calculado a partir dos componentes e nunca movimentado diretamente
+	filter["type"] = bson.M{"$ne": model.BundleProduct}
+
+	if sku == "" {
+		// Produtos com variantes só movimentam estoque pelo SKU
+		filter["variants.0"] = bson.M{"$exists": false}
+		if minimum > 0 {
+			filter[field] = bson.M{"$gte": minimum}
+		}
+		return
+	}
+
+	match := bson.M{"sku": sku}
+	if minimum > 0 {
+		match[field] = bson.M{"$gte": minimum}
+	}
+	filter["variants"] = bson.M{"$elemMatch": match}
+}
+
+func stockUpdate(sku string, stockDelta, reservedDelta int) bson.M {
+	inc := bson.M{"stock": stockDelta, "reserved": reservedDelta}
+	if sku != "" {
+		inc["variants.$.stock"] = stockDelta
+		inc["variants.$.reserved"] = reservedDelta
+	}
+	return bson.M{"$inc": inc}
+}
+
+func (r *MongoProductRepository) incStock(filter bson.M, update bson.M) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	// O documento retornado pelo próprio update vira o snapshot do evento,
+	// sem uma leitura extra a cada movimentação de estoque
+	var product model.Product
+	err := productCollection.FindOneAndUpdate(context.TODO(), filter, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&product)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return ErrInsufficientStock
+		}
+		return err
+	}
+
+	updated, err := r.syncStatusOf(&product)
+	if err != nil {
+		return err
+	}
+
+	r.publishProduct(model.ProductStockChanged, updated)
+	return nil
+}
+
+// Variante de syncStatus que parte do documento já carregado: só grava quando o
+// status precisa trocar e devolve o documento resultante
+func (r *MongoProductRepository) syncStatusOf(product *model.Product) (*model.Product, error) {
+	var filter bson.M
+	var status model.ProductStatus
+	switch {
+	case product.Status == model.Available && product.Stock <= 0:
+		filter = bson.M{"_id": product.ID, "status": model.Available, "stock": bson.M{"$lte": 0}}
+		status = model.OutOfStock
+	case product.Status == model.OutOfStock && product.Stock > 0:
+		filter = bson.M{"_id": product.ID, "status": model.OutOfStock, "stock": bson.M{"$gt": 0}}
+		status = model.Available
+	default:
+		return product, nil
+	}
+
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	var updated model.Product
+	err := productCollection.FindOneAndUpdate(context.TODO(), filter,
+		bson.M{"$set": bson.M{"status": status}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&updated)
+	if err != nil {
+		// Outra movimentação já alterou o estoque e acerta o status ao terminar
+		if err == mongo.ErrNoDocuments {
+			return product, nil
+		}
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// Alterna o status entre AVAILABLE e OUT_OF_STOCK conforme o estoque disponível.
+// Cada troca é condicional, então atualizações concorrentes não deixam o status inconsistente.
+func (r *MongoProductRepository) syncStatus(id primitive.ObjectID) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	_, err := productCollection.UpdateOne(context.TODO(),
+		bson.M{"_id": id, "status": model.Available, "stock": bson.M{"$lte": 0}},
+		bson.M{"$set": bson.M{"status": model.OutOfStock}})
+	if err != nil {
+		return err
+	}
+
+	_, err = productCollection.UpdateOne(context.TODO(),
+		bson.M{"_id": id, "status": model.OutOfStock, "stock": bson.M{"$gt": 0}},
+		bson.M{"$set": bson.M{"status": model.Available}})
+	return err
+}
+
+// ListByIDs busca vários produtos de uma vez; IDs inexistentes são ignorados
+func (r *MongoProductRepository) ListByIDs(ids []primitive.ObjectID) ([]*model.Product, error) {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	cursor, err := productCollection.Find(context.TODO(), bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var products []*model.Product
+	for cursor.Next(context.TODO()) {
+		var product model.Product
+		if err := cursor.Decode(&product); err != nil {
+			return nil, err
+		}
+		products = append(products, &product)
+	}
+
+	return products, cursor.Err()
+}
+
+// Lista os produtos de qualquer uma das categorias informadas
+func (r *MongoProductRepository) ListByCategoryIDs(ids []primitive.ObjectID) ([]*model.Product, error) {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	cursor, err := productCollection.Find(context.TODO(), bson.M{"category.id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var products []*model.Product
+	for cursor.Next(context.TODO()) {
+		var product model.Product
+		if err := cursor.Decode(&product); err != nil {
+			return nil, err
+		}
+		products = append(products, &product)
+	}
+
+	return products, cursor.Err()
+}
+
+// ListByAttributes lista os produtos que atendem a todos os filtros de atributo.
+// O valor exato é comparado como texto, número e booleano, pois o tipo do
+// atributo depende do esquema da categoria de cada produto.
+func (r *MongoProductRepository) ListByAttributes(filters []model.AttributeFilter) ([]*model.Product, error) {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	filter := bson.M{}
+	for _, attributeFilter := range filters {
+		// As chaves do esquema não têm "." nem "$"; uma chave assim viraria um caminho
+		// aninhado no documento e não corresponde a nenhum atributo
+		if strings.ContainsAny(attributeFilter.Key, ".$") {
+			return nil, nil
+		}
+		field := "attributes." + attributeFilter.Key
+		condition := bson.M{}
+		if attributeFilter.Value != "" {
+			candidates := bson.A{attributeFilter.Value}
+			if number, err := strconv.ParseFloat(attributeFilter.Value, 64); err == nil {
+				candidates = append(candidates, number)
+			}
+			if boolean, err := strconv.ParseBool(attributeFilter.Value); err == nil {
+				candidates = append(candidates, boolean)
+			}
+			condition["$in"] = candidates
+		}
+		if attributeFilter.Min != nil {
+			condition["$gte"] = *attributeFilter.Min
+		}
+		if attributeFilter.Max != nil {
+			condition["$lte"] = *attributeFilter.Max
+		}
+		filter[field] = condition
+	}
+
+	cursor, err := productCollection.Find(context.TODO(), filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var products []*model.Product
+	for cursor.Next(context.TODO()) {
+		var product model.Product
+		if err := cursor.Decode(&product); err != nil {
+			return nil, err
+		}
+		products = append(products, &product)
+	}
+
+	return products, cursor.Err()
+}
+
+func (r *MongoProductRepository) CountByCategory(id primitive.ObjectID) (int64, error) {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	return productCollection.CountDocuments(context.TODO(), bson.M{"category.id": id})
+}
+
+// SetCategory grava a referência de categoria em todos os produtos que apontam para
+// a categoria de origem. Usado para ressincronizar renomeações e para mesclar categorias.
+func (r *MongoProductRepository) SetCategory(fromID primitive.ObjectID, category model.Category) error {
+	return r.setCategory(bson.M{"category.id": fromID}, category)
+}
+
+// ListUncategorizedNames lista os nomes de categoria dos produtos gravados antes
+// do cadastro de categorias, que ainda não têm category.id
+func (r *MongoProductRepository) ListUncategorizedNames() ([]string, error) {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	values, err := productCollection.Distinct(context.TODO(), "category.name", bson.M{"category.id": bson.M{"$exists": false}})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(values))
+	for _, value := range values {
+		if name, ok := value.(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// SetCategoryByName vincula à categoria os produtos sem category.id que usam o nome informado
+func (r *MongoProductRepository) SetCategoryByName(name string, category model.Category) error {
+	return r.setCategory(bson.M{"category.id": bson.M{"$exists": false}, "category.name": name}, category)
+}
+
+func (r *MongoProductRepository) setCategory(filter bson.M, category model.Category) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	cursor, err := productCollection.Find(context.TODO(), filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return err
+	}
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(context.TODO(), &docs); err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	_, err = productCollection.UpdateMany(context.TODO(), filter, bson.M{"$set": bson.M{"category": category}})
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		r.publishEvent(model.ProductUpdated, doc.ID)
+	}
+	return nil
+}
+
+// AddVariant inclui uma variante no produto somando seu estoque ao total.
+// O filtro impede que o mesmo SKU seja incluído duas vezes.
+func (r *MongoProductRepository) AddVariant(id primitive.ObjectID, variant *model.Variant) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	filter := bson.M{"_id": id, "variants.sku": bson.M{"$ne": variant.SKU}}
+	update := bson.M{
+		"$push": bson.M{"variants": variant},
+		"$inc":  bson.M{"stock": variant.Stock},
+	}
+
+	result, err := productCollection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrDuplicateSKU
+		}
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrDuplicateSKU
+	}
+
+	return r.afterCatalogChange(id)
+}
+
+// UpdateVariant altera os dados cadastrais da variante; estoque e preço têm operações próprias
+func (r *MongoProductRepository) UpdateVariant(id primitive.ObjectID, variant *model.Variant) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	filter := bson.M{"_id": id, "variants.sku": variant.SKU}
+	update := bson.M{"$set": bson.M{
+		"variants.$.options": variant.Options,
+		"variants.$.gtin":    variant.GTIN,
+		"variants.$.images":  variant.Images,
+	}}
+
+	result, err := productCollection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrVariantNotFound
+	}
+
+	r.publishEvent(model.ProductUpdated, id)
+	return nil
+}
+
+// RemoveVariant exclui a variante se ela não tiver unidades reservadas, descontando seu estoque do total
+func (r *MongoProductRepository) RemoveVariant(id primitive.ObjectID, variant *model.Variant) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	filter := bson.M{
+		"_id":      id,
+		"variants": bson.M{"$elemMatch": bson.M{"sku": variant.SKU, "stock": variant.Stock, "reserved": 0}},
+	}
+	update := bson.M{
+		"$pull": bson.M{"variants": bson.M{"sku": variant.SKU}},
+		"$inc":  bson.M{"stock": -variant.Stock},
+	}
+
+	result, err := productCollection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("a variante tem reservas em aberto ou foi alterada por outra operação")
+	}
+
+	return r.afterCatalogChange(id)
+}
+
+// Substitui as opções do produto
+func (r *MongoProductRepository) UpdateOptions(id primitive.ObjectID, options []model.ProductOption) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	_, err := productCollection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": bson.M{"options": options}})
+	if err != nil {
+		return err
+	}
+
+	r.publishEvent(model.ProductUpdated, id)
+	return nil
+}
+
+// SetTranslation grava o nome e a descrição do produto no idioma informado
+func (r *MongoProductRepository) SetTranslation(id primitive.ObjectID, locale string, translation model.Translation) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	_, err := productCollection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": bson.M{"translations." + locale: translation}})
+	if err != nil {
+		return err
+	}
+
+	r.publishEvent(model.ProductUpdated, id)
+	return nil
+}
+
+// RemoveTranslation apaga a tradução; o produto volta a ser exibido em pt-BR nesse idioma
+func (r *MongoProductRepository) RemoveTranslation(id primitive.ObjectID, locale string) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	_, err := productCollection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$unset": bson.M{"translations." + locale: ""}})
+	if err != nil {
+		return err
+	}
+
+	r.publishEvent(model.ProductUpdated, id)
+	return nil
+}
+
+// AddMedia acrescenta a mídia ao final da galeria, desde que o produto tenha menos de maxMedia itens
+func (r *MongoProductRepository) AddMedia(id primitive.ObjectID, media *model.Media, maxMedia int) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	filter := bson.M{"_id": id, fmt.Sprintf("media.%d", maxMedia-1): bson.M{"$exists": false}}
+	result, err := productCollection.UpdateOne(context.TODO(), filter, bson.M{"$push": bson.M{"media": media}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrMediaLimit
+	}
+
+	r.publishEvent(model.ProductUpdated, id)
+	return nil
+}
+
+// ReorderMedia grava a galeria na nova ordem. A condição garante que nenhuma
+// mídia foi incluída ou removida desde a leitura.
+func (r *MongoProductRepository) ReorderMedia(id primitive.ObjectID, media []model.Media) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	ids := make([]string, 0, len(media))
+	for _, item := range media {
+		ids = append(ids, item.ID)
+	}
+	filter := bson.M{"_id": id, "media": bson.M{"$size": len(media)}, "media.id": bson.M{"$all": ids}}
+
+	result, err := productCollection.UpdateOne(context.TODO(), filter, bson.M{"$set": bson.M{"media": media}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrMediaChanged
+	}
+
+	r.publishEvent(model.ProductUpdated, id)
+	return nil
+}
+
+func (r *MongoProductRepository) RemoveMedia(id primitive.ObjectID, mediaID string) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	filter := bson.M{"_id": id, "media.id": mediaID}
+	result, err := productCollection.UpdateOne(context.TODO(), filter, bson.M{"$pull": bson.M{"media": bson.M{"id": mediaID}}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrMediaNotFound
+	}
+
+	r.publishEvent(model.ProductUpdated, id)
+	return nil
+}
+
+// SetGTIN grava o GTIN do produto (ou da variante, quando o SKU é informado) se ainda não houver um
+func (r *MongoProductRepository) SetGTIN(id primitive.ObjectID, sku string, gtin string) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	unset := bson.M{"$in": []interface{}{nil, ""}}
+	filter := bson.M{"_id": id, "gtin": unset}
+	field := "gtin"
+	if sku != "" {
+		filter = bson.M{"_id": id, "variants": bson.M{"$elemMatch": bson.M{"sku": sku, "gtin": unset}}}
+		field = "variants.$.gtin"
+	}
+
+	result, err := productCollection.UpdateOne(context.TODO(), filter, bson.M{"$set": bson.M{field: gtin}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrGTINAssigned
+	}
+
+	r.publishEvent(model.ProductUpdated, id)
+	return nil
+}
+
+// ListBundles retorna os kits; com componentID, apenas os que usam o componente
+func (r *MongoProductRepository) ListBundles(componentID *primitive.ObjectID) ([]*model.Product, error) {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	filter := bson.M{"type": model.BundleProduct}
+	if componentID != nil {
+		filter["bundle.components.productId"] = *componentID
+	}
+
+	cursor, err := productCollection.Find(context.TODO(), filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var products []*model.Product
+	for cursor.Next(context.TODO()) {
+		var product model.Product
+		if err := cursor.Decode(&product); err != nil {
+			return nil, err
+		}
+		products = append(products, &product)
+	}
+
+	return products, cursor.Err()
+}
+
+// SetBundle substitui os componentes e a precificação do kit
+func (r *MongoProductRepository) SetBundle(id primitive.ObjectID, bundle *model.Bundle) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	filter := bson.M{"_id": id, "type": model.BundleProduct}
+	result, err := productCollection.UpdateOne(context.TODO(), filter, bson.M{"$set": bson.M{"bundle": bundle}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("kit não encontrado")
+	}
+
+	r.publishEvent(model.ProductUpdated, id)
 	return nil
 }
 
+// SetBundleStock grava a disponibilidade calculada do kit e ajusta o status
+func (r *MongoProductRepository) SetBundleStock(id primitive.ObjectID, stock int) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	filter := bson.M{"_id": id, "type": model.BundleProduct}
+	result, err := productCollection.UpdateOne(context.TODO(), filter, bson.M{"$set": bson.M{"stock": stock}})
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return nil
+	}
+
+	if err := r.syncStatus(id); err != nil {
+		return err
+	}
+	r.publishEvent(model.ProductStockChanged, id)
+	return nil
+}
+
+// SetRating grava o resumo das avaliações aprovadas do produto
+func (r *MongoProductRepository) SetRating(id primitive.ObjectID, rating *model.RatingSummary) error {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	result, err := productCollection.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": bson.M{"rating": rating}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("produto não encontrado")
+	}
+
+	r.publishEvent(model.ProductUpdated, id)
+	return nil
+}
+
+// Busca o produto que contém a variante com o SKU informado
+func (r *MongoProductRepository) FindBySKU(sku string) (*model.Product, error) {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	var product model.Product
+	filter := bson.M{"$or": []bson.M{{"sku": sku}, {"variants.sku": sku}}}
+	err := productCollection.FindOne(context.TODO(), filter).Decode(&product)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrVariantNotFound
+		}
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+// ApplyPrice grava o novo preço do produto (ou da variante) e incrementa a versão de preço
+// em um único update, retornando o preço anterior e a nova versão. Com inherit, a
+// sobrescrita da variante é removida e ela volta a usar o preço do produto.
+func (r *MongoProductRepository) ApplyPrice(id primitive.ObjectID, sku string, price float64, inherit bool) (float64, int, error) {
+	productCollection := r.client.Database("productDB").Collection("products")
+
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": bson.M{"price": price}, "$inc": bson.M{"priceVersion": 1}}
+	if sku != "" {
+		filter["variants.sku"] = sku
+		update["$set"] = bson.M{"variants.$.price": price}
+		if inherit {
+			delete(update, "$set")
+			update["$unset"] = bson.M{"variants.$.price": ""}
+		}
+	}
+
+	var before model.Product
+	err := productCollection.FindOneAndUpdate(context.TODO(), filter, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.Before)).Decode(&before)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			if sku != "" {
+				return 0, 0, ErrVariantNotFound
+			}
+			return 0, 0, errors.New("produto não encontrado")
+		}
+		return 0, 0, err
+	}
+
+	oldPrice := before.Price
+	if sku != "" {
+		oldPrice = before.PriceFor(sku)
+	}
+
+	return oldPrice, before.PriceVersion + 1, nil
+}
+
+// PublishPriceChanged publica o evento PRODUCT_PRICE_CHANGED com o produto já atualizado
+func (r *MongoProductRepository) PublishPriceChanged(change *model.PriceChange) {
+	product, err := r.FindByID(change.ProductID.Hex())
+	if err != nil {
+		log.Printf("Erro ao carregar produto %s para o evento de preço: %v\n", change.ProductID.Hex(), err)
+		return
+	}
+
+	event := model.ProductEvent{
+		Type:        model.ProductPriceChanged,
+		ProductID:   change.ProductID.Hex(),
+		Product:     product,
+		PriceChange: change,
+		OccurredAt:  time.Now(),
+	}
+	r.publishAsync(ProductEventTopic, change.ProductID.Hex(), event)
+}
+
+// PublishLowStock publica o alerta de estoque no ponto de pedido
+func (r *MongoProductRepository) PublishLowStock(event *model.LowStockEvent) error {
+	return r.publish(LowStockTopic, event)
+}
+
+func (r *MongoProductRepository) afterCatalogChange(id primitive.ObjectID) error {
+	if err := r.syncStatus(id); err != nil {
+		return err
+	}
+
+	r.publishEvent(model.ProductUpdated, id)
+	return nil
+}
+
+// Publica o evento do produto com o documento atual. A alteração já foi gravada,
+// então falhas na publicação são apenas registradas.
+func (r *MongoProductRepository) publishEvent(eventType model.ProductEventType, id primitive.ObjectID) {
+	if eventType == model.ProductDeleted {
+		r.publishAsync(ProductEventTopic, id.Hex(), model.ProductEvent{
+			Type:       eventType,
+			ProductID:  id.Hex(),
+			OccurredAt: time.Now(),
+		})
+		return
+	}
+
+	product, err := r.FindByID(id.Hex())
+	if err != nil {
+		log.Printf("Erro ao carregar produto %s para o evento %s: %v\n", id.Hex(), eventType, err)
+		return
+	}
+	r.publishProduct(eventType, product)
+}
+
+// Publica o evento com um documento que o chamador já tem em mãos
+func (r *MongoProductRepository) publishProduct(eventType model.ProductEventType, product *model.Product) {
+	r.publishAsync(ProductEventTopic, product.ID.Hex(), model.ProductEvent{
+		Type:       eventType,
+		ProductID:  product.ID.Hex(),
+		Product:    product,
+		OccurredAt: time.Now(),
+	})
+}
+
+func (r *MongoProductRepository) publish(topic string, payload interface{}) error {
+	message, err := newMessage(topic, "", payload)
+	if err != nil {
+		return err
+	}
+
+	// Enviando a mensagem ao Kafka
+	deliveryChan := make(chan kafka.Event)
+	defer close(deliveryChan)
+
+	err = r.kafka.Produce(message, deliveryChan)
+	if err != nil {
+		log.Printf("Erro ao produzir mensagem para Kafka: %v\n", err)
+		return err
+	}
+
+	// Manipulando a resposta do envio ao Kafka
+	e := <-deliveryChan
+	if ev, ok := e.(*kafka.Message); ok && ev.TopicPartition.Error != nil {
+		log.Printf("Erro ao enviar a mensagem ao Kafka: %v\n", ev.TopicPartition.Error)
+		return ev.TopicPartition.Error
+	}
+
+	return nil
+}
+
+// Publica sem esperar a confirmação do broker; a confirmação é tratada em segundo
+// plano. A chave fixa a partição, então os eventos do mesmo produto chegam na ordem
+// em que foram produzidos.
+func (r *MongoProductRepository) publishAsync(topic, key string, payload interface{}) {
+	message, err := newMessage(topic, key, payload)
+	if err != nil {
+		return
+	}
+
+	deliveryChan := make(chan kafka.Event, 1)
+	if err := r.kafka.Produce(message, deliveryChan); err != nil {
+		log.Printf("Erro ao produzir mensagem para Kafka: %v\n", err)
+		return
+	}
+
+	go func() {
+		e := <-deliveryChan
+		if ev, ok := e.(*kafka.Message); ok && ev.TopicPartition.Error != nil {
+			log.Printf("Erro ao enviar a mensagem %s ao Kafka: %v\n", key, ev.TopicPartition.Error)
+		}
+	}()
+}
+
+func newMessage(topic, key string, payload interface{}) (*kafka.Message, error) {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Erro ao organizar a mensagem: %v", err)
+		return nil, err
+	}
+
+	message := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Value:          payloadJSON,
+	}
+	if key != "" {
+		message.Key = []byte(key)
+	}
+	return message, nil
+}
+
 // Fecha a conexão Kafka
 func (r *MongoProductRepository) Close() {
 	r.kafka.Close()
@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/infra/db"
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	coPurchaseItems  = "copurchase_items"
+	coPurchasePairs  = "copurchase_pairs"
+	coPurchaseOrders = "copurchase_orders"
+	coPurchaseTotals = "copurchase_totals"
+	// Sufixo das coleções montadas pelo recálculo antes da troca
+	rebuildSuffix = "_rebuild"
+)
+
+type MongoRecommendationRepository struct {
+	client *mongo.Client
+}
+
+func NewMongoRecommendationRepository(mongoURI string) *MongoRecommendationRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	repo := &MongoRecommendationRepository{
+		client: client,
+	}
+	if err := repo.createPairIndexes(coPurchasePairs); err != nil {
+		log.Printf("Erro ao criar índices de compras conjuntas: %v\n", err)
+	}
+	return repo
+}
+
+func (r *MongoRecommendationRepository) createPairIndexes(collection string) error {
+	_, err := r.client.Database("productDB").Collection(collection).Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "a", Value: 1}, {Key: "weight", Value: -1}}},
+		{Keys: bson.D{{Key: "b", Value: 1}, {Key: "weight", Value: -1}}},
+	})
+	return err
+}
+
+// FindProcessed retorna o registro do pedido contabilizado, ou nil se ele ainda não foi
+func (r *MongoRecommendationRepository) FindProcessed(orderID string) (*model.ProcessedOrder, error) {
+	collection := r.client.Database("productDB").Collection(coPurchaseOrders)
+
+	var order model.ProcessedOrder
+	err := collection.FindOne(context.TODO(), bson.M{"_id": orderID}).Decode(&order)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// MarkProcessed registra o pedido como contabilizado com os produtos e o peso somados
+func (r *MongoRecommendationRepository) MarkProcessed(order *model.ProcessedOrder) error {
+	collection := r.client.Database("productDB").Collection(coPurchaseOrders)
+
+	_, err := collection.ReplaceOne(context.TODO(), bson.M{"_id": order.ID}, order, options.Replace().SetUpsert(true))
+	return err
+}
+
+// MarkCanceled marca o pedido como cancelado. Um pedido ainda não contabilizado também
+// é registrado, para que um evento de criação atrasado não o conte.
+func (r *MongoRecommendationRepository) MarkCanceled(orderID string) error {
+	collection := r.client.Database("productDB").Collection(coPurchaseOrders)
+
+	update := bson.M{"$set": bson.M{"canceled": true}}
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": orderID}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// Record soma o peso do pedido ao total, a cada produto e a cada par de produtos
+func (r *MongoRecommendationRepository) Record(productIDs []string, weight float64) error {
+	return r.record("", productIDs, weight)
+}
+
+func (r *MongoRecommendationRepository) record(suffix string, productIDs []string, weight float64) error {
+	database := r.client.Database("productDB")
+	upsert := options.Update().SetUpsert(true)
+	inc := bson.M{"$inc": bson.M{"weight": weight}}
+
+	if _, err := database.Collection(coPurchaseTotals+suffix).UpdateOne(context.TODO(), bson.M{"_id": "orders"}, inc, upsert); err != nil {
+		return err
+	}
+
+	var items []mongo.WriteModel
+	for _, id := range productIDs {
+		items = append(items, mongo.NewUpdateOneModel().SetFilter(bson.M{"_id": id}).SetUpdate(inc).SetUpsert(true))
+	}
+	if len(items) > 0 {
+		if _, err := database.Collection(coPurchaseItems+suffix).BulkWrite(context.TODO(), items, options.BulkWrite().SetOrdered(false)); err != nil {
+			return err
+		}
+	}
+
+	var pairs []mongo.WriteModel
+	for i := range productIDs {
+		for _, other := range productIDs[i+1:] {
+			a, b := productIDs[i], other
+			if b < a {
+				a, b = b, a
+			}
+			update := bson.M{"$inc": bson.M{"weight": weight}, "$setOnInsert": bson.M{"a": a, "b": b}}
+			pairs = append(pairs, mongo.NewUpdateOneModel().SetFilter(bson.M{"_id": a + "|" + b}).SetUpdate(update).SetUpsert(true))
+		}
+	}
+	if len(pairs) > 0 {
+		if _, err := database.Collection(coPurchasePairs+suffix).BulkWrite(context.TODO(), pairs, options.BulkWrite().SetOrdered(false)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TotalWeight retorna o peso somado de todos os pedidos
+func (r *MongoRecommendationRepository) TotalWeight() (float64, error) {
+	collection := r.client.Database("productDB").Collection(coPurchaseTotals)
+
+	var total struct {
+		Weight float64 `bson:"weight"`
+	}
+	err := collection.FindOne(context.TODO(), bson.M{"_id": "orders"}).Decode(&total)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	return total.Weight, err
+}
+
+// ItemWeights retorna o peso de cada produto informado
+func (r *MongoRecommendationRepository) ItemWeights(productIDs []string) (map[string]float64, error) {
+	collection := r.client.Database("productDB").Collection(coPurchaseItems)
+
+	cursor, err := collection.Find(context.TODO(), bson.M{"_id": bson.M{"$in": productIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	weights := make(map[string]float64, len(productIDs))
+	for cursor.Next(context.TODO()) {
+		var item model.CoPurchaseItem
+		if err := cursor.Decode(&item); err != nil {
+			return nil, err
+		}
+		weights[item.ProductID] = item.Weight
+	}
+	return weights, cursor.Err()
+}
+
+// ListPairs retorna os pares do produto com maior peso, até o limite
+func (r *MongoRecommendationRepository) ListPairs(productID string, minWeight float64, limit int64) ([]*model.CoPurchasePair, error) {
+	collection := r.client.Database("productDB").Collection(coPurchasePairs)
+
+	var pairs []*model.CoPurchasePair
+	for _, field := range []string{"a", "b"} {
+		filter := bson.M{field: productID, "weight": bson.M{"$gte": minWeight}}
+		opts := options.Find().SetSort(bson.D{{Key: "weight", Value: -1}}).SetLimit(limit)
+
+		cursor, err := collection.Find(context.TODO(), filter, opts)
+		if err != nil {
+			return nil, err
+		}
+		for cursor.Next(context.TODO()) {
+			var pair model.CoPurchasePair
+			if err := cursor.Decode(&pair); err != nil {
+				cursor.Close(context.TODO())
+				return nil, err
+			}
+			pairs = append(pairs, &pair)
+		}
+		err = cursor.Err()
+		cursor.Close(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pairs, nil
+}
+
+// EachOrder percorre os pedidos não cancelados da coleção orders do serviço de pedidos
+func (r *MongoRecommendationRepository) EachOrder(fn func(order *model.PurchasedOrder) error) error {
+	collection := r.client.Database("orderDB").Collection("orders")
+
+	filter := bson.M{"status": bson.M{"$ne": "CANCELED"}}
+	opts := options.Find().SetProjection(bson.M{"products.productId": 1, "orderDate": 1})
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var document struct {
+			ID       primitive.ObjectID `bson:"_id"`
+			Products []struct {
+				ProductID string `bson:"productId"`
+			} `bson:"products"`
+			OrderDate primitive.DateTime `bson:"orderDate"`
+		}
+		if err := cursor.Decode(&document); err != nil {
+			return err
+		}
+
+		order := &model.PurchasedOrder{ID: document.ID.Hex(), OrderDate: document.OrderDate.Time()}
+		for _, product := range document.Products {
+			order.ProductIDs = append(order.ProductIDs, product.ProductID)
+		}
+		if err := fn(order); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// Rebuild monta as estatísticas em coleções temporárias com record e depois as troca
+// pelas atuais, para que as consultas não vejam o recálculo pela metade
+func (r *MongoRecommendationRepository) Rebuild(build func(record func(orderID string, productIDs []string, weight float64) error) error) error {
+	database := r.client.Database("productDB")
+	collections := []string{coPurchaseTotals, coPurchaseItems, coPurchasePairs, coPurchaseOrders}
+
+	for _, name := range collections {
+		if err := database.Collection(name + rebuildSuffix).Drop(context.TODO()); err != nil {
+			return err
+		}
+	}
+	if err := r.createPairIndexes(coPurchasePairs + rebuildSuffix); err != nil {
+		return err
+	}
+
+	err := build(func(orderID string, productIDs []string, weight float64) error {
+		processed := &model.ProcessedOrder{ID: orderID, ProductIDs: productIDs, Weight: weight}
+		if _, err := database.Collection(coPurchaseOrders+rebuildSuffix).InsertOne(context.TODO(), processed); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return nil
+			}
+			return err
+		}
+		return r.record(rebuildSuffix, productIDs, weight)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range collections {
+		// Coleções vazias não são criadas pelo recálculo; a atual é apenas limpa
+		count, err := database.Collection(name + rebuildSuffix).EstimatedDocumentCount(context.TODO())
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			if _, err := database.Collection(name).DeleteMany(context.TODO(), bson.M{}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		command := bson.D{
+			{Key: "renameCollection", Value: "productDB." + name + rebuildSuffix},
+			{Key: "to", Value: "productDB." + name},
+			{Key: "dropTarget", Value: true},
+		}
+		if err := r.client.Database("admin").RunCommand(context.TODO(), command).Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
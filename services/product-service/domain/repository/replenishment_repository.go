@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/infra/db"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	replenishmentPolicies = "replenishment_policies"
+	salesDaily            = "sales_daily"
+	salesOrders           = "sales_orders"
+)
+
+// Pedidos guardados em cada dia de vendas para reconhecer uma repetição
+const maxRecordedOrders = 100
+
+// Código do MongoDB para violação de chave única
+const duplicateKeyCode = 11000
+
+var ErrPolicyNotFound = errors.New("política de reposição não encontrada")
+
+type MongoReplenishmentRepository struct {
+	client *mongo.Client
+}
+
+func NewMongoReplenishmentRepository(mongoURI string) *MongoReplenishmentRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	database := client.Database("productDB")
+	_, err = database.Collection(replenishmentPolicies).Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "productId", Value: 1}, {Key: "locationId", Value: 1}},
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índice de políticas de reposição: %v\n", err)
+	}
+	_, err = database.Collection(salesDaily).Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "day", Value: 1}},
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índice de vendas diárias: %v\n", err)
+	}
+
+	return &MongoReplenishmentRepository{
+		client: client,
+	}
+}
+
+// SavePolicy grava os parâmetros da política, preservando o estado do alerta
+func (r *MongoReplenishmentRepository) SavePolicy(policy *model.ReplenishmentPolicy) error {
+	collection := r.client.Database("productDB").Collection(replenishmentPolicies)
+
+	policy.UpdatedAt = time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"productId":    policy.ProductID,
+			"sku":          policy.SKU,
+			"locationId":   policy.LocationID,
+			"reorderPoint": policy.ReorderPoint,
+			"safetyStock":  policy.SafetyStock,
+			"leadTimeDays": policy.LeadTimeDays,
+			"minQuantity":  policy.MinQuantity,
+			"maxQuantity":  policy.MaxQuantity,
+			"updatedAt":    policy.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{"low": false},
+	}
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": policy.ID}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *MongoReplenishmentRepository) FindPolicy(id string) (*model.ReplenishmentPolicy, error) {
+	collection := r.client.Database("productDB").Collection(replenishmentPolicies)
+
+	var policy model.ReplenishmentPolicy
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&policy)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrPolicyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// ListPolicies filtra por produto e localização; locationID nulo não filtra a
+// localização e vazio retorna só as políticas do estoque do catálogo
+func (r *MongoReplenishmentRepository) ListPolicies(productID string, locationID *string) ([]*model.ReplenishmentPolicy, error) {
+	collection := r.client.Database("productDB").Collection(replenishmentPolicies)
+
+	filter := bson.M{}
+	if productID != "" {
+		filter["productId"] = productID
+	}
+	if locationID != nil {
+		filter["locationId"] = *locationID
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "productId", Value: 1}, {Key: "sku", Value: 1}, {Key: "locationId", Value: 1}})
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	policies := make([]*model.ReplenishmentPolicy, 0)
+	if err := cursor.All(context.TODO(), &policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (r *MongoReplenishmentRepository) DeletePolicy(id string) error {
+	collection := r.client.Database("productDB").Collection(replenishmentPolicies)
+
+	result, err := collection.DeleteOne(context.TODO(), bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrPolicyNotFound
+	}
+	return nil
+}
+
+// SetLow grava o estado do alerta e retorna true apenas se ele mudou, para que
+// instâncias concorrentes não publiquem o mesmo alerta
+func (r *MongoReplenishmentRepository) SetLow(id string, low bool) (bool, error) {
+	collection := r.client.Database("productDB").Collection(replenishmentPolicies)
+
+	set := bson.M{"low": low}
+	if low {
+		set["lastAlertAt"] = time.Now()
+	}
+	result, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id, "low": !low}, bson.M{"$set": set})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// IsOrderProcessed indica se as vendas do pedido já foram contabilizadas
+func (r *MongoReplenishmentRepository) IsOrderProcessed(orderID string) (bool, error) {
+	collection := r.client.Database("productDB").Collection(salesOrders)
+
+	count, err := collection.CountDocuments(context.TODO(), bson.M{"_id": orderID})
+	return count > 0, err
+}
+
+// MarkOrderProcessed registra o pedido depois que suas vendas foram contabilizadas
+func (r *MongoReplenishmentRepository) MarkOrderProcessed(orderID string) error {
+	collection := r.client.Database("productDB").Collection(salesOrders)
+
+	_, err := collection.InsertOne(context.TODO(), bson.M{"_id": orderID, "processedAt": time.Now()})
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// RecordSales soma as quantidades vendidas no dia, por produto e SKU. Cada dia guarda
+// os últimos pedidos somados, então repetir o pedido depois de uma falha antes de
+// MarkOrderProcessed não conta as vendas de novo.
+func (r *MongoReplenishmentRepository) RecordSales(orderID string, day time.Time, quantities map[[2]string]int) error {
+	collection := r.client.Database("productDB").Collection(salesDaily)
+
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	var models []mongo.WriteModel
+	for key, quantity := range quantities {
+		id := key[0] + "|" + key[1] + "|" + day.Format("2006-01-02")
+		update := bson.M{
+			"$inc":         bson.M{"quantity": quantity},
+			"$push":        bson.M{"orders": bson.M{"$each": bson.A{orderID}, "$slice": -maxRecordedOrders}},
+			"$setOnInsert": bson.M{"productId": key[0], "sku": key[1], "day": day},
+		}
+		filter := bson.M{"_id": id, "orders": bson.M{"$ne": orderID}}
+		models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true))
+	}
+	if len(models) == 0 {
+		return nil
+	}
+
+	_, err := collection.BulkWrite(context.TODO(), models, options.BulkWrite().SetOrdered(false))
+	// Com o pedido já somado no dia, o filtro não encontra o documento e o upsert
+	// esbarra no _id existente
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+		for _, writeErr := range bulkErr.WriteErrors {
+			if writeErr.Code != duplicateKeyCode {
+				return err
+			}
+		}
+		return nil
+	}
+	return err
+}
+
+// SalesSince soma as vendas de cada produto e SKU informado a partir do dia informado
+func (r *MongoReplenishmentRepository) SalesSince(productIDs []string, since time.Time) (map[[2]string]int, error) {
+	collection := r.client.Database("productDB").Collection(salesDaily)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"productId": bson.M{"$in": productIDs}, "day": bson.M{"$gte": since}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      bson.M{"productId": "$productId", "sku": "$sku"},
+			"quantity": bson.M{"$sum": "$quantity"},
+		}}},
+	}
+	cursor, err := collection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	sales := make(map[[2]string]int)
+	for cursor.Next(context.TODO()) {
+		var doc struct {
+			ID struct {
+				ProductID string `bson:"productId"`
+				SKU       string `bson:"sku"`
+			} `bson:"_id"`
+			Quantity int `bson:"quantity"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		sales[[2]string{doc.ID.ProductID, doc.ID.SKU}] = doc.Quantity
+	}
+	return sales, cursor.Err()
+}
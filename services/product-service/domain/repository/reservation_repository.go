@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/infra/db"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoReservationRepository struct {
+	client *mongo.Client
+}
+
+func NewMongoReservationRepository(mongoURI string) *MongoReservationRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	collection := client.Database("productDB").Collection("reservations")
+	_, err = collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "expiresAt", Value: 1}},
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índice de reservas: %v\n", err)
+	}
+
+	return &MongoReservationRepository{
+		client: client,
+	}
+}
+
+func (r *MongoReservationRepository) Save(reservation *model.Reservation) error {
+	collection := r.client.Database("productDB").Collection("reservations")
+
+	_, err := collection.InsertOne(context.TODO(), reservation)
+	if err != nil {
+		log.Printf("Erro ao inserir reserva no MongoDB: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *MongoReservationRepository) FindByID(id string) (*model.Reservation, error) {
+	collection := r.client.Database("productDB").Collection("reservations")
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("ID da reserva inválido")
+	}
+
+	var reservation model.Reservation
+	err = collection.FindOne(context.TODO(), bson.M{"_id": objID}).Decode(&reservation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// SetReserved registra quantos itens já tiveram o estoque separado. Retorna false
+// quando a reserva deixou de estar pendente (ex.: foi expirada pela varredura).
+func (r *MongoReservationRepository) SetReserved(id primitive.ObjectID, count int) (bool, error) {
+	collection := r.client.Database("productDB").Collection("reservations")
+
+	filter := bson.M{"_id": id, "status": model.ReservationPending}
+	result, err := collection.UpdateOne(context.TODO(), filter, bson.M{"$set": bson.M{"reservedItems": count}})
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// Activate passa a reserva de PENDING para ACTIVE depois que todos os itens foram separados
+func (r *MongoReservationRepository) Activate(id primitive.ObjectID) (bool, error) {
+	collection := r.client.Database("productDB").Collection("reservations")
+
+	filter := bson.M{"_id": id, "status": model.ReservationPending}
+	result, err := collection.UpdateOne(context.TODO(), filter, bson.M{"$set": bson.M{"status": model.ReservationActive}})
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// Abandon encerra uma reserva que não chegou a ficar ativa. Retorna o documento
+// com ReservedItems para que as unidades já separadas sejam devolvidas, ou nil
+// quando a reserva já não está pendente.
+func (r *MongoReservationRepository) Abandon(id primitive.ObjectID, status model.ReservationStatus, now time.Time) (*model.Reservation, error) {
+	collection := r.client.Database("productDB").Collection("reservations")
+
+	filter := bson.M{"_id": id, "status": model.ReservationPending}
+	update := bson.M{"$set": bson.M{"status": status, "finishedAt": now}}
+
+	var reservation model.Reservation
+	err := collection.FindOneAndUpdate(context.TODO(), filter, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&reservation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// BeginFinish passa uma reserva ativa para o status intermediário do encerramento
+// (COMMITTING, RELEASING ou EXPIRING) de forma condicional. Retorna nil quando a
+// reserva já não está ativa (ou já expirou, no caso de confirmação), garantindo
+// que cada reserva seja encerrada uma única vez.
+func (r *MongoReservationRepository) BeginFinish(id primitive.ObjectID, finishing model.ReservationStatus, now time.Time) (*model.Reservation, error) {
+	collection := r.client.Database("productDB").Collection("reservations")
+
+	filter := bson.M{"_id": id, "status": model.ReservationActive}
+	if finishing == model.ReservationCommitting {
+		filter["expiresAt"] = bson.M{"$gt": now}
+	}
+	update := bson.M{"$set": bson.M{"status": finishing, "finishedItems": 0, "finishingAt": now}}
+
+	var reservation model.Reservation
+	err := collection.FindOneAndUpdate(context.TODO(), filter, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&reservation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// SetFinished registra quantos itens o encerramento já movimentou. Retorna false
+// quando a reserva saiu do status intermediário (o encerramento foi concluído por outro).
+func (r *MongoReservationRepository) SetFinished(id primitive.ObjectID, finishing model.ReservationStatus, count int, now time.Time) (bool, error) {
+	collection := r.client.Database("productDB").Collection("reservations")
+
+	filter := bson.M{"_id": id, "status": finishing}
+	update := bson.M{"$set": bson.M{"finishedItems": count, "finishingAt": now}}
+	result, err := collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// CompleteFinish grava o status final depois que todos os itens foram movimentados
+func (r *MongoReservationRepository) CompleteFinish(id primitive.ObjectID, finishing, final model.ReservationStatus, now time.Time) (bool, error) {
+	collection := r.client.Database("productDB").Collection("reservations")
+
+	filter := bson.M{"_id": id, "status": finishing}
+	update := bson.M{
+		"$set":   bson.M{"status": final, "finishedAt": now},
+		"$unset": bson.M{"finishingAt": ""},
+	}
+	result, err := collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// Lista as reservas com encerramento em andamento sem progresso desde before,
+// ou seja, cujo encerramento foi interrompido
+func (r *MongoReservationRepository) ListStaleFinishing(before time.Time) ([]*model.Reservation, error) {
+	collection := r.client.Database("productDB").Collection("reservations")
+
+	filter := bson.M{
+		"status":      bson.M{"$in": []model.ReservationStatus{model.ReservationCommitting, model.ReservationReleasing, model.ReservationExpiring}},
+		"finishingAt": bson.M{"$lte": before},
+	}
+	cursor, err := collection.Find(context.TODO(), filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var reservations []*model.Reservation
+	if err := cursor.All(context.TODO(), &reservations); err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+// Lista os IDs das reservas no status informado cujo prazo já venceu
+func (r *MongoReservationRepository) ListExpired(status model.ReservationStatus, now time.Time) ([]primitive.ObjectID, error) {
+	collection := r.client.Database("productDB").Collection("reservations")
+
+	filter := bson.M{"status": status, "expiresAt": bson.M{"$lte": now}}
+	cursor, err := collection.Find(context.TODO(), filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var ids []primitive.ObjectID
+	for cursor.Next(context.TODO()) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		ids = append(ids, doc.ID)
+	}
+
+	return ids, cursor.Err()
+}
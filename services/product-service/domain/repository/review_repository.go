@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/infra/db"
+	"context"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MongoReviewRepository struct {
+	client *mongo.Client
+}
+
+func NewMongoReviewRepository(mongoURI string) *MongoReviewRepository {
+	client, err := db.ConnectMongoDB(mongoURI)
+	if err != nil {
+		log.Fatalf("Erro ao conectar-se ao MongoDB: %v", err)
+	}
+
+	_, err = client.Database("productDB").Collection("reviews").Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "productId", Value: 1}, {Key: "status", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "productId", Value: 1}, {Key: "customerId", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índices de avaliações: %v\n", err)
+	}
+
+	// Um voto por cliente em cada avaliação
+	_, err = client.Database("productDB").Collection("review_votes").Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "reviewId", Value: 1}, {Key: "customerId", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("Erro ao criar índice de votos: %v\n", err)
+	}
+
+	return &MongoReviewRepository{
+		client: client,
+	}
+}
+
+func (r *MongoReviewRepository) Save(review *model.Review) error {
+	collection := r.client.Database("productDB").Collection("reviews")
+
+	_, err := collection.InsertOne(context.TODO(), review)
+	return err
+}
+
+func (r *MongoReviewRepository) FindByID(id primitive.ObjectID) (*model.Review, error) {
+	collection := r.client.Database("productDB").Collection("reviews")
+
+	var review model.Review
+	err := collection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&review)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+// CountActive conta as avaliações pendentes ou aprovadas do cliente para o produto
+func (r *MongoReviewRepository) CountActive(productID primitive.ObjectID, customerID string) (int64, error) {
+	collection := r.client.Database("productDB").Collection("reviews")
+
+	filter := bson.M{
+		"productId":  productID,
+		"customerId": customerID,
+		"status":     bson.M{"$in": []model.ReviewStatus{model.ReviewPending, model.ReviewApproved}},
+	}
+	return collection.CountDocuments(context.TODO(), filter)
+}
+
+// ListByProduct lista as avaliações aprovadas do produto. Com rating maior que zero,
+// apenas as daquela nota.
+func (r *MongoReviewRepository) ListByProduct(productID primitive.ObjectID, rating int, sortBy model.ReviewSort, skip, limit int64) ([]*model.Review, int64, error) {
+	collection := r.client.Database("productDB").Collection("reviews")
+
+	filter := bson.M{"productId": productID, "status": model.ReviewApproved}
+	if rating > 0 {
+		filter["rating"] = rating
+	}
+
+	total, err := collection.CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var sort bson.D
+	switch sortBy {
+	case model.ReviewSortHelpful:
+		sort = bson.D{{Key: "helpfulVotes", Value: -1}}
+	case model.ReviewSortRatingDesc:
+		sort = bson.D{{Key: "rating", Value: -1}}
+	case model.ReviewSortRatingAsc:
+		sort = bson.D{{Key: "rating", Value: 1}}
+	}
+	sort = append(sort, bson.E{Key: "createdAt", Value: -1})
+
+	reviews, err := r.find(filter, options.Find().SetSort(sort).SetSkip(skip).SetLimit(limit))
+	return reviews, total, err
+}
+
+// ListByStatus lista as avaliações no status informado, das mais antigas para as mais novas
+func (r *MongoReviewRepository) ListByStatus(status model.ReviewStatus) ([]*model.Review, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	return r.find(bson.M{"status": status}, opts)
+}
+
+// Moderate muda o status da avaliação, desde que ela não esteja nele. Retorna nil
+// se a avaliação não existe ou já estava no status.
+func (r *MongoReviewRepository) Moderate(id primitive.ObjectID, status model.ReviewStatus, moderator, reason string, now time.Time) (*model.Review, error) {
+	collection := r.client.Database("productDB").Collection("reviews")
+
+	filter := bson.M{"_id": id, "status": bson.M{"$ne": status}}
+	update := bson.M{
+		"$set": bson.M{"status": status, "moderatedBy": moderator, "moderatedAt": now, "rejectionReason": reason},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var review model.Review
+	err := collection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&review)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+// Summarize calcula a média e a distribuição das notas aprovadas do produto
+func (r *MongoReviewRepository) Summarize(productID primitive.ObjectID) (*model.RatingSummary, error) {
+	collection := r.client.Database("productDB").Collection("reviews")
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"productId": productID, "status": model.ReviewApproved}}},
+		{{Key: "$group", Value: bson.M{"_id": "$rating", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := collection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	summary := &model.RatingSummary{Distribution: map[string]int{"1": 0, "2": 0, "3": 0, "4": 0, "5": 0}}
+	sum := 0
+	for cursor.Next(context.TODO()) {
+		var group struct {
+			Rating int `bson:"_id"`
+			Count  int `bson:"count"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return nil, err
+		}
+		summary.Distribution[strconv.Itoa(group.Rating)] = group.Count
+		summary.Count += group.Count
+		sum += group.Rating * group.Count
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	if summary.Count > 0 {
+		summary.Average = math.Round(float64(sum)/float64(summary.Count)*10) / 10
+	}
+	return summary, nil
+}
+
+// Vote grava o voto do cliente e retorna o voto anterior, ou nil se for o primeiro
+func (r *MongoReviewRepository) Vote(vote *model.ReviewVote) (*model.ReviewVote, error) {
+	collection := r.client.Database("productDB").Collection("review_votes")
+
+	filter := bson.M{"reviewId": vote.ReviewID, "customerId": vote.CustomerID}
+	update := bson.M{"$set": bson.M{"helpful": vote.Helpful, "votedAt": vote.VotedAt}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before)
+
+	var previous model.ReviewVote
+	err := collection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&previous)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &previous, nil
+}
+
+// AddVotes ajusta os contadores de votos da avaliação
+func (r *MongoReviewRepository) AddVotes(id primitive.ObjectID, helpful, unhelpful int) error {
+	collection := r.client.Database("productDB").Collection("reviews")
+
+	update := bson.M{"$inc": bson.M{"helpfulVotes": helpful, "unhelpfulVotes": unhelpful}}
+	_, err := collection.UpdateOne(context.TODO(), bson.M{"_id": id}, update)
+	return err
+}
+
+func (r *MongoReviewRepository) find(filter bson.M, opts *options.FindOptions) ([]*model.Review, error) {
+	collection := r.client.Database("productDB").Collection("reviews")
+
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var reviews []*model.Review
+	for cursor.Next(context.TODO()) {
+		var review model.Review
+		if err := cursor.Decode(&review); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, &review)
+	}
+
+	return reviews, cursor.Err()
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBarcodeNotFound         = errors.New("código de barras não encontrado")
+	ErrGTINPrefixNotConfigured = errors.New("prefixo de empresa GS1 não configurado")
+	ErrGTINRangeExhausted      = errors.New("todos os códigos do prefixo de empresa já foram usados")
+)
+
+// Tentativas de gerar um código livre quando o sequencial colide com um GTIN cadastrado manualmente
+const maxGenerationAttempts = 20
+
+type BarcodeService interface {
+	FindByBarcode(code string) (*model.BarcodeMatch, error)
+	ClaimGTIN(productID, sku, code string) (string, error)
+	ReleaseGTIN(productID, sku, code string)
+	ReleaseProduct(productID string)
+	GenerateEAN13(productID, sku string) (*model.Product, error)
+	SyncRegistry()
+}
+
+type BarcodeServiceImpl struct {
+	barcodeRepo   *repository.MongoBarcodeRepository
+	productRepo   *repository.MongoProductRepository
+	companyPrefix string
+}
+
+// NewBarcodeService recebe o prefixo de empresa GS1 usado nos EAN-13 internos
+// (de 6 a 11 dígitos). Sem prefixo válido, a geração de códigos fica desabilitada.
+func NewBarcodeService(barcodeRepo *repository.MongoBarcodeRepository, productRepo *repository.MongoProductRepository, companyPrefix string) BarcodeService {
+	companyPrefix = strings.TrimSpace(companyPrefix)
+	if companyPrefix != "" && !validCompanyPrefix(companyPrefix) {
+		log.Printf("Prefixo de empresa GS1 inválido (%s): a geração de EAN-13 ficará desabilitada\n", companyPrefix)
+		companyPrefix = ""
+	}
+
+	return &BarcodeServiceImpl{
+		barcodeRepo:   barcodeRepo,
+		productRepo:   productRepo,
+		companyPrefix: companyPrefix,
+	}
+}
+
+func validCompanyPrefix(prefix string) bool {
+	if len(prefix) < 6 || len(prefix) > 11 {
+		return false
+	}
+	for _, r := range prefix {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// FindByBarcode resolve o código lido pelo leitor para o produto ou a variante exata.
+// UPC-12 e EAN-13 do mesmo item são equivalentes.
+func (s *BarcodeServiceImpl) FindByBarcode(code string) (*model.BarcodeMatch, error) {
+	gtin, err := model.NormalizeGTIN(code)
+	if err != nil {
+		return nil, err
+	}
+
+	barcode, err := s.barcodeRepo.FindByGTIN14(model.GTIN14(gtin))
+	if err != nil {
+		return nil, err
+	}
+	if barcode == nil {
+		return nil, ErrBarcodeNotFound
+	}
+
+	product, err := s.productRepo.FindByID(barcode.ProductID)
+	if err != nil {
+		return nil, ErrBarcodeNotFound
+	}
+
+	match := &model.BarcodeMatch{GTIN: barcode.Code, Product: product}
+	if barcode.SKU != "" {
+		if match.Variant = product.FindVariant(barcode.SKU); match.Variant == nil {
+			return nil, ErrBarcodeNotFound
+		}
+	}
+	return match, nil
+}
+
+// ClaimGTIN valida o código e o registra para o produto (ou variante), retornando-o normalizado.
+// Código vazio não é registrado.
+func (s *BarcodeServiceImpl) ClaimGTIN(productID, sku, code string) (string, error) {
+	if strings.TrimSpace(code) == "" {
+		return "", nil
+	}
+
+	gtin, err := model.NormalizeGTIN(code)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.barcodeRepo.Register(&model.Barcode{
+		ID:        model.GTIN14(gtin),
+		Code:      gtin,
+		ProductID: productID,
+		SKU:       sku,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, gtin)
+	}
+	return gtin, nil
+}
+
+// ReleaseGTIN libera o código que deixou de ser usado pelo produto ou variante
+func (s *BarcodeServiceImpl) ReleaseGTIN(productID, sku, code string) {
+	gtin, err := model.NormalizeGTIN(code)
+	if err != nil {
+		return
+	}
+	if err := s.barcodeRepo.Release(model.GTIN14(gtin), productID, sku); err != nil {
+		log.Printf("Erro ao liberar o GTIN %s: %v\n", gtin, err)
+	}
+}
+
+func (s *BarcodeServiceImpl) ReleaseProduct(productID string) {
+	if err := s.barcodeRepo.ReleaseProduct(productID); err != nil {
+		log.Printf("Erro ao liberar os GTINs do produto %s: %v\n", productID, err)
+	}
+}
+
+// GenerateEAN13 cria um EAN-13 interno com o prefixo da empresa e um sequencial,
+// para itens de marca própria, e o atribui ao produto ou à variante.
+func (s *BarcodeServiceImpl) GenerateEAN13(productID, sku string) (*model.Product, error) {
+	if s.companyPrefix == "" {
+		return nil, ErrGTINPrefixNotConfigured
+	}
+
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case sku != "":
+		variant := product.FindVariant(sku)
+		if variant == nil {
+			return nil, repository.ErrVariantNotFound
+		}
+		if variant.GTIN != "" {
+			return nil, repository.ErrGTINAssigned
+		}
+	case len(product.Variants) > 0:
+		return nil, fmt.Errorf("%w: informe o SKU da variante que receberá o código", ErrInvalidVariant)
+	case product.GTIN != "":
+		return nil, repository.ErrGTINAssigned
+	}
+
+	code, err := s.nextFreeEAN13(productID, sku)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.productRepo.SetGTIN(product.ID, sku, code); err != nil {
+		s.ReleaseGTIN(productID, sku, code)
+		return nil, err
+	}
+
+	return s.productRepo.FindByID(productID)
+}
+
+func (s *BarcodeServiceImpl) nextFreeEAN13(productID, sku string) (string, error) {
+	itemDigits := 12 - len(s.companyPrefix)
+	capacity, _ := strconv.ParseInt("1"+strings.Repeat("0", itemDigits), 10, 64)
+
+	for attempt := 0; attempt < maxGenerationAttempts; attempt++ {
+		sequence, err := s.barcodeRepo.NextSequence("ean13:" + s.companyPrefix)
+		if err != nil {
+			return "", err
+		}
+		if sequence >= capacity {
+			return "", ErrGTINRangeExhausted
+		}
+
+		body := fmt.Sprintf("%s%0*d", s.companyPrefix, itemDigits, sequence)
+		code := body + strconv.Itoa(model.GTINCheckDigit(body))
+
+		_, err = s.ClaimGTIN(productID, sku, code)
+		if errors.Is(err, repository.ErrDuplicateGTIN) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return code, nil
+	}
+
+	return "", fmt.Errorf("não foi possível gerar um EAN-13 livre após %d tentativas", maxGenerationAttempts)
+}
+
+// SyncRegistry registra os GTINs já gravados nos produtos, como os cadastrados antes
+// do registro existir. Códigos inválidos ou duplicados são apenas registrados no log.
+func (s *BarcodeServiceImpl) SyncRegistry() {
+	err := s.productRepo.Each(func(product *model.Product) error {
+		productID := product.ID.Hex()
+		if product.GTIN != "" {
+			if _, err := s.ClaimGTIN(productID, "", product.GTIN); err != nil {
+				log.Printf("GTIN do produto %s não registrado: %v\n", productID, err)
+			}
+		}
+		for _, variant := range product.Variants {
+			if variant.GTIN == "" {
+				continue
+			}
+			if _, err := s.ClaimGTIN(productID, variant.SKU, variant.GTIN); err != nil {
+				log.Printf("GTIN da variante %s não registrado: %v\n", variant.SKU, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Erro ao sincronizar o registro de códigos de barras: %v\n", err)
+	}
+}
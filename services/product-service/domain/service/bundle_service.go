@@ -0,0 +1,365 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"Varejo-Golang-Microservices/services/product-service/infra/event"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrInvalidBundle = errors.New("kit inválido")
+
+type BundleService interface {
+	PrepareBundle(product *model.Product) error
+	ListBundles() ([]*model.Product, error)
+	UpdateBundle(id string, bundle *model.Bundle) (*model.Product, error)
+	GetBreakdown(id string, quantity int) (*model.BundleBreakdown, error)
+	ListenProductEvents(kafkaBroker string)
+}
+
+type BundleServiceImpl struct {
+	productRepo  *repository.MongoProductRepository
+	priceService PriceService
+}
+
+func NewBundleService(productRepo *repository.MongoProductRepository, priceService PriceService) BundleService {
+	return &BundleServiceImpl{
+		productRepo:  productRepo,
+		priceService: priceService,
+	}
+}
+
+// PrepareBundle valida o kit antes do cadastro e calcula a disponibilidade e,
+// na precificação por componentes, o preço
+func (s *BundleServiceImpl) PrepareBundle(product *model.Product) error {
+	if len(product.Variants) > 0 || len(product.Options) > 0 {
+		return fmt.Errorf("%w: kits não têm variantes", ErrInvalidBundle)
+	}
+
+	components, err := s.validate(product.ID, product.Bundle)
+	if err != nil {
+		return err
+	}
+
+	product.Stock = bundleAvailability(product.Bundle, components)
+	if product.Bundle.PricingMode == model.BundleComponentsDiscount {
+		product.Price = bundlePrice(product.Bundle, components)
+	}
+	return nil
+}
+
+func (s *BundleServiceImpl) ListBundles() ([]*model.Product, error) {
+	return s.productRepo.ListBundles(nil)
+}
+
+// UpdateBundle troca os componentes ou a precificação e recalcula o kit
+func (s *BundleServiceImpl) UpdateBundle(id string, bundle *model.Bundle) (*model.Product, error) {
+	product, err := s.findBundle(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.validate(product.ID, bundle); err != nil {
+		return nil, err
+	}
+	if err := s.productRepo.SetBundle(product.ID, bundle); err != nil {
+		return nil, err
+	}
+
+	product.Bundle = bundle
+	if err := s.refresh(product); err != nil {
+		return nil, err
+	}
+	return s.productRepo.FindByID(id)
+}
+
+// GetBreakdown rateia o preço de quantity kits entre os componentes, na proporção
+// dos preços avulsos. Os centavos são distribuídos para que a soma das linhas
+// seja exatamente o total do kit.
+func (s *BundleServiceImpl) GetBreakdown(id string, quantity int) (*model.BundleBreakdown, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("%w: a quantidade deve ser positiva", ErrInvalidBundle)
+	}
+
+	product, err := s.findBundle(id)
+	if err != nil {
+		return nil, err
+	}
+
+	components, err := s.loadComponents(product.Bundle)
+	if err != nil {
+		return nil, err
+	}
+	for _, component := range product.Bundle.Components {
+		if components[component.ProductID] == nil {
+			return nil, fmt.Errorf("%w: componente %s não encontrado", ErrInvalidBundle, component.ProductID.Hex())
+		}
+	}
+
+	totalCents := int64(math.Round(product.Price*100)) * int64(quantity)
+	weights := make([]float64, len(product.Bundle.Components))
+	for i, component := range product.Bundle.Components {
+		weights[i] = components[component.ProductID].PriceFor(component.SKU) * float64(component.Quantity)
+	}
+	shares := allocateCents(totalCents, weights, product.Bundle.Components)
+
+	breakdown := &model.BundleBreakdown{
+		BundleID:  id,
+		Quantity:  quantity,
+		UnitPrice: product.Price,
+		Total:     float64(totalCents) / 100,
+		Available: bundleAvailability(product.Bundle, components),
+	}
+	for i, component := range product.Bundle.Components {
+		componentProduct := components[component.ProductID]
+		lineQuantity := component.Quantity * quantity
+		breakdown.Lines = append(breakdown.Lines, model.BundleLine{
+			ProductID:   component.ProductID.Hex(),
+			SKU:         component.SKU,
+			ProductName: componentProduct.Name,
+			Quantity:    lineQuantity,
+			ListPrice:   componentProduct.PriceFor(component.SKU),
+			UnitPrice:   math.Round(float64(shares[i])/float64(lineQuantity)) / 100,
+			Total:       float64(shares[i]) / 100,
+		})
+	}
+
+	return breakdown, nil
+}
+
+// Distribui os centavos pelo maior resto. Sem preços nos componentes, rateia pela quantidade.
+func allocateCents(totalCents int64, weights []float64, components []model.BundleComponent) []int64 {
+	sum := 0.0
+	for _, weight := range weights {
+		sum += weight
+	}
+	if sum == 0 {
+		for i, component := range components {
+			weights[i] = float64(component.Quantity)
+			sum += weights[i]
+		}
+	}
+
+	shares := make([]int64, len(weights))
+	remainders := make([]float64, len(weights))
+	allocated := int64(0)
+	for i, weight := range weights {
+		exact := float64(totalCents) * weight / sum
+		shares[i] = int64(math.Floor(exact))
+		remainders[i] = exact - float64(shares[i])
+		allocated += shares[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	for i := 0; allocated < totalCents; i++ {
+		shares[order[i%len(order)]]++
+		allocated++
+	}
+
+	return shares
+}
+
+// ListenProductEvents recalcula os kits quando o estoque, o preço ou o cadastro de
+// um componente muda. O grupo de consumo é compartilhado entre as instâncias.
+func (s *BundleServiceImpl) ListenProductEvents(kafkaBroker string) {
+	s.refreshAll()
+
+	messages := make(chan string)
+	go func() {
+		if err := event.ConsumeMessage(kafkaBroker, repository.ProductEventTopic, "product-bundles", messages); err != nil {
+			log.Printf("Erro ao consumir o tópico %s: %v\n", repository.ProductEventTopic, err)
+		}
+	}()
+
+	for message := range messages {
+		var productEvent model.ProductEvent
+		if err := json.Unmarshal([]byte(message), &productEvent); err != nil {
+			log.Printf("Erro ao ler evento de produto: %v\n", err)
+			continue
+		}
+
+		// Eventos dos próprios kits não afetam outros kits, já que kits não contêm kits
+		if productEvent.Product != nil && productEvent.Product.IsBundle() {
+			continue
+		}
+
+		componentID, err := primitive.ObjectIDFromHex(productEvent.ProductID)
+		if err != nil {
+			continue
+		}
+		bundles, err := s.productRepo.ListBundles(&componentID)
+		if err != nil {
+			log.Printf("Erro ao buscar kits do componente %s: %v\n", productEvent.ProductID, err)
+			continue
+		}
+		for _, bundle := range bundles {
+			if err := s.refresh(bundle); err != nil {
+				log.Printf("Erro ao recalcular o kit %s: %v\n", bundle.ID.Hex(), err)
+			}
+		}
+	}
+}
+
+func (s *BundleServiceImpl) refreshAll() {
+	bundles, err := s.productRepo.ListBundles(nil)
+	if err != nil {
+		log.Printf("Erro ao buscar kits: %v\n", err)
+		return
+	}
+	for _, bundle := range bundles {
+		if err := s.refresh(bundle); err != nil {
+			log.Printf("Erro ao recalcular o kit %s: %v\n", bundle.ID.Hex(), err)
+		}
+	}
+}
+
+// Grava a disponibilidade atual do kit e, na precificação por componentes, registra o novo preço
+func (s *BundleServiceImpl) refresh(bundle *model.Product) error {
+	if bundle.Bundle == nil {
+		return nil
+	}
+
+	components, err := s.loadComponents(bundle.Bundle)
+	if err != nil {
+		return err
+	}
+
+	if err := s.productRepo.SetBundleStock(bundle.ID, bundleAvailability(bundle.Bundle, components)); err != nil {
+		return err
+	}
+
+	if bundle.Bundle.PricingMode != model.BundleComponentsDiscount || len(components) < len(bundle.Bundle.Components) {
+		return nil
+	}
+	price := bundlePrice(bundle.Bundle, components)
+	if price == bundle.Price {
+		return nil
+	}
+	_, err = s.priceService.SchedulePrice(bundle.ID.Hex(), "", price, time.Time{}, "recálculo do preço do kit", "")
+	return err
+}
+
+func (s *BundleServiceImpl) findBundle(id string) (*model.Product, error) {
+	product, err := s.productRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !product.IsBundle() || product.Bundle == nil {
+		return nil, fmt.Errorf("%w: o produto %s não é um kit", ErrInvalidBundle, id)
+	}
+	return product, nil
+}
+
+// Valida a composição do kit e retorna os componentes carregados
+func (s *BundleServiceImpl) validate(bundleID primitive.ObjectID, bundle *model.Bundle) (map[primitive.ObjectID]*model.Product, error) {
+	if bundle == nil || len(bundle.Components) == 0 {
+		return nil, fmt.Errorf("%w: informe os componentes do kit", ErrInvalidBundle)
+	}
+
+	switch bundle.PricingMode {
+	case "":
+		bundle.PricingMode = model.BundleFixedPrice
+	case model.BundleFixedPrice, model.BundleComponentsDiscount:
+	default:
+		return nil, fmt.Errorf("%w: precificação %s não suportada", ErrInvalidBundle, bundle.PricingMode)
+	}
+	if bundle.PricingMode == model.BundleFixedPrice {
+		bundle.DiscountPercent = 0
+	}
+	if bundle.DiscountPercent < 0 || bundle.DiscountPercent > 100 {
+		return nil, fmt.Errorf("%w: o desconto deve estar entre 0 e 100%%", ErrInvalidBundle)
+	}
+
+	components, err := s.loadComponents(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(bundle.Components))
+	for _, component := range bundle.Components {
+		id := component.ProductID.Hex()
+		if component.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantidade inválida para o componente %s", ErrInvalidBundle, id)
+		}
+		if component.ProductID == bundleID {
+			return nil, fmt.Errorf("%w: o kit não pode conter a si mesmo", ErrInvalidBundle)
+		}
+
+		product := components[component.ProductID]
+		switch {
+		case product == nil:
+			return nil, fmt.Errorf("%w: componente %s não encontrado", ErrInvalidBundle, id)
+		case product.IsBundle():
+			return nil, fmt.Errorf("%w: kits não podem conter outros kits", ErrInvalidBundle)
+		case len(product.Variants) > 0 && product.FindVariant(component.SKU) == nil:
+			return nil, fmt.Errorf("%w: informe um SKU válido do componente %s", ErrInvalidBundle, id)
+		case len(product.Variants) == 0 && component.SKU != "":
+			return nil, fmt.Errorf("%w: o componente %s não tem variantes", ErrInvalidBundle, id)
+		}
+
+		key := id + "|" + component.SKU
+		if seen[key] {
+			return nil, fmt.Errorf("%w: componente %s repetido", ErrInvalidBundle, key)
+		}
+		seen[key] = true
+	}
+
+	return components, nil
+}
+
+// Carrega os produtos componentes; componentes excluídos ficam fora do mapa
+func (s *BundleServiceImpl) loadComponents(bundle *model.Bundle) (map[primitive.ObjectID]*model.Product, error) {
+	components := make(map[primitive.ObjectID]*model.Product, len(bundle.Components))
+	for _, component := range bundle.Components {
+		if _, ok := components[component.ProductID]; ok {
+			continue
+		}
+		product, err := s.productRepo.FindByID(component.ProductID.Hex())
+		if err != nil {
+			continue
+		}
+		components[component.ProductID] = product
+	}
+	return components, nil
+}
+
+// Quantos kits podem ser montados com o estoque disponível dos componentes
+func bundleAvailability(bundle *model.Bundle, components map[primitive.ObjectID]*model.Product) int {
+	available := -1
+	for _, component := range bundle.Components {
+		product := components[component.ProductID]
+		if product == nil {
+			return 0
+		}
+		units := product.AvailableFor(component.SKU) / component.Quantity
+		if available < 0 || units < available {
+			available = units
+		}
+	}
+	if available < 0 {
+		return 0
+	}
+	return available
+}
+
+func bundlePrice(bundle *model.Bundle, components map[primitive.ObjectID]*model.Product) float64 {
+	sum := 0.0
+	for _, component := range bundle.Components {
+		if product := components[component.ProductID]; product != nil {
+			sum += product.PriceFor(component.SKU) * float64(component.Quantity)
+		}
+	}
+	return math.Round(sum*(1-bundle.DiscountPercent/100)*100) / 100
+}
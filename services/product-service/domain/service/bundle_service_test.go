@@ -0,0 +1,49 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"reflect"
+	"testing"
+)
+
+func TestAllocateCents(t *testing.T) {
+	components := func(quantities ...int) []model.BundleComponent {
+		result := make([]model.BundleComponent, len(quantities))
+		for i, quantity := range quantities {
+			result[i].Quantity = quantity
+		}
+		return result
+	}
+
+	tests := []struct {
+		name       string
+		total      int64
+		weights    []float64
+		components []model.BundleComponent
+		want       []int64
+	}{
+		{"divisão exata", 1000, []float64{3, 1}, components(1, 1), []int64{750, 250}},
+		{"sobra vai para o primeiro em empate", 1000, []float64{1, 1, 1}, components(1, 1, 1), []int64{334, 333, 333}},
+		{"sobra vai para os maiores restos", 999, []float64{0.5, 0.25, 0.25}, components(1, 1, 1), []int64{499, 250, 250}},
+		{"pesos zerados usam as quantidades", 1000, []float64{0, 0, 0}, components(2, 1, 1), []int64{500, 250, 250}},
+		{"um centavo", 1, []float64{1, 1, 1}, components(1, 1, 1), []int64{1, 0, 0}},
+		{"total zero", 0, []float64{2, 1}, components(1, 1), []int64{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := allocateCents(tt.total, append([]float64{}, tt.weights...), tt.components)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("allocateCents(%d, %v) = %v, esperado %v", tt.total, tt.weights, got, tt.want)
+			}
+
+			sum := int64(0)
+			for _, share := range got {
+				sum += share
+			}
+			if sum != tt.total {
+				t.Errorf("soma das partes %d difere do total %d", sum, tt.total)
+			}
+		})
+	}
+}
@@ -0,0 +1,707 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"Varejo-Golang-Microservices/services/product-service/infra/search"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("categoria não encontrada")
+	ErrInvalidCategory  = errors.New("operação de categoria inválida")
+	// ErrInvalidAttributes indica um esquema de atributos inválido ou um produto fora do esquema
+	ErrInvalidAttributes = errors.New("atributos inválidos")
+)
+
+type CategoryService interface {
+	CreateCategory(category *model.CategoryNode) error
+	GetCategory(id string) (*model.CategoryNode, error)
+	GetCategoryByPath(path string) (*model.CategoryNode, error)
+	ListCategories() ([]*model.CategoryNode, error)
+	GetTree() ([]*model.CategoryTree, error)
+	UpdateCategory(id, name, description string, position *int) (*model.CategoryNode, error)
+	MoveCategory(id string, parentID *primitive.ObjectID, position *int) (*model.CategoryNode, error)
+	MergeCategory(sourceID, targetID string) (*model.CategoryNode, error)
+	DeleteCategory(id string) error
+	ListProducts(id string) ([]*model.Product, error)
+	ResolveReference(category model.Category) (model.Category, error)
+	BackfillProductCategories() error
+	GetAttributeSchema(id string) ([]model.AttributeDefinition, error)
+	SetAttributeSchema(id string, attributes []model.AttributeDefinition) (*model.CategoryNode, error)
+	ValidateAttributes(categoryID primitive.ObjectID, values map[string]interface{}) (map[string]interface{}, error)
+	SetGoogleCategory(id, googleCategory string) (*model.CategoryNode, error)
+}
+
+type CategoryServiceImpl struct {
+	categoryRepo *repository.MongoCategoryRepository
+	productRepo  *repository.MongoProductRepository
+}
+
+func NewCategoryService(categoryRepo *repository.MongoCategoryRepository, productRepo *repository.MongoProductRepository) CategoryService {
+	return &CategoryServiceImpl{
+		categoryRepo: categoryRepo,
+		productRepo:  productRepo,
+	}
+}
+
+func (s *CategoryServiceImpl) CreateCategory(category *model.CategoryNode) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return fmt.Errorf("%w: o nome é obrigatório", ErrInvalidCategory)
+	}
+
+	category.ID = primitive.NewObjectID()
+	category.Slug = Slugify(category.Name)
+	category.CreatedAt = time.Now()
+
+	parent, err := s.parent(category.ParentID)
+	if err != nil {
+		return err
+	}
+	setParent(category, parent)
+
+	siblings, err := s.categoryRepo.ListChildren(category.ParentID)
+	if err != nil {
+		return err
+	}
+	if category.Position <= 0 {
+		category.Position = len(siblings) + 1
+	}
+
+	return s.categoryRepo.Save(category)
+}
+
+func (s *CategoryServiceImpl) GetCategory(id string) (*model.CategoryNode, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrCategoryNotFound
+	}
+	return s.find(objID)
+}
+
+func (s *CategoryServiceImpl) GetCategoryByPath(path string) (*model.CategoryNode, error) {
+	category, err := s.categoryRepo.FindByPath(strings.Trim(path, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return nil, ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+func (s *CategoryServiceImpl) ListCategories() ([]*model.CategoryNode, error) {
+	return s.categoryRepo.ListAll()
+}
+
+// GetTree monta a árvore completa, com os irmãos na ordem definida por Position
+func (s *CategoryServiceImpl) GetTree() ([]*model.CategoryTree, error) {
+	categories, err := s.categoryRepo.ListAll()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[primitive.ObjectID]*model.CategoryTree, len(categories))
+	for _, category := range categories {
+		nodes[category.ID] = &model.CategoryTree{CategoryNode: category, Children: []*model.CategoryTree{}}
+	}
+
+	roots := []*model.CategoryTree{}
+	// ListAll já vem ordenado por posição, então a ordem dos filhos é preservada
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if category.ParentID == nil || nodes[*category.ParentID] == nil {
+			roots = append(roots, node)
+			continue
+		}
+		parent := nodes[*category.ParentID]
+		parent.Children = append(parent.Children, node)
+	}
+
+	return roots, nil
+}
+
+// UpdateCategory renomeia ou reposiciona a categoria. Ao renomear, o caminho da
+// subárvore e a cópia da categoria nos produtos são atualizados.
+func (s *CategoryServiceImpl) UpdateCategory(id, name, description string, position *int) (*model.CategoryNode, error) {
+	category, err := s.GetCategory(id)
+	if err != nil {
+		return nil, err
+	}
+
+	renamed := false
+	if name = strings.TrimSpace(name); name != "" && name != category.Name {
+		category.Name = name
+		category.Slug = Slugify(name)
+		renamed = true
+	}
+	if description != "" {
+		category.Description = description
+	}
+	if position != nil {
+		category.Position = *position
+	}
+
+	parent, err := s.parent(category.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	setParent(category, parent)
+
+	// Os produtos são sincronizados antes de gravar a categoria: se a gravação
+	// falhar, a categoria continua com o nome antigo e repetir a operação refaz
+	// as duas etapas. Por isso o caminho é conferido antes de tocar nos produtos.
+	if renamed {
+		existing, err := s.categoryRepo.FindByPath(category.Path)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil && existing.ID != category.ID {
+			return nil, repository.ErrDuplicateCategoryPath
+		}
+	}
+	if renamed || description != "" {
+		if err := s.productRepo.SetCategory(category.ID, category.Reference()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.saveSubtree(category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// MoveCategory muda a categoria (com toda a subárvore) para outro pai. Sem pai, vira raiz.
+func (s *CategoryServiceImpl) MoveCategory(id string, parentID *primitive.ObjectID, position *int) (*model.CategoryNode, error) {
+	category, err := s.GetCategory(id)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := s.parent(parentID)
+	if err != nil {
+		return nil, err
+	}
+	if parent != nil && (parent.ID == category.ID || containsID(parent.Ancestors, category.ID)) {
+		return nil, fmt.Errorf("%w: a categoria não pode ser movida para dentro de si mesma", ErrInvalidCategory)
+	}
+
+	setParent(category, parent)
+	if position != nil {
+		category.Position = *position
+	} else {
+		siblings, err := s.categoryRepo.ListChildren(category.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		category.Position = len(siblings) + 1
+	}
+
+	if err := s.saveSubtree(category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// MergeCategory move os filhos e os produtos da categoria de origem para a de destino
+// e exclui a origem. A origem só é excluída no fim, então uma mescla interrompida é
+// concluída repetindo a operação: os filhos e produtos restantes ainda apontam para ela.
+func (s *CategoryServiceImpl) MergeCategory(sourceID, targetID string) (*model.CategoryNode, error) {
+	source, err := s.GetCategory(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.GetCategory(targetID)
+	if err != nil {
+		return nil, err
+	}
+	if source.ID == target.ID || containsID(target.Ancestors, source.ID) {
+		return nil, fmt.Errorf("%w: a categoria de destino não pode estar dentro da origem", ErrInvalidCategory)
+	}
+
+	children, err := s.categoryRepo.ListChildren(&source.ID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.categoryRepo.ListChildren(&target.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, child := range children {
+		setParent(child, target)
+		child.Position = len(existing) + i + 1
+		if err := s.saveSubtree(child); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.productRepo.SetCategory(source.ID, target.Reference()); err != nil {
+		return nil, err
+	}
+
+	if err := s.categoryRepo.Delete(source.ID); err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// DeleteCategory exclui somente categorias sem filhos e sem produtos
+func (s *CategoryServiceImpl) DeleteCategory(id string) error {
+	category, err := s.GetCategory(id)
+	if err != nil {
+		return err
+	}
+
+	children, err := s.categoryRepo.ListChildren(&category.ID)
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		return fmt.Errorf("%w: a categoria tem subcategorias", ErrInvalidCategory)
+	}
+
+	count, err := s.productRepo.CountByCategory(category.ID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: a categoria tem %d produtos; mescle-a com outra categoria", ErrInvalidCategory, count)
+	}
+
+	return s.categoryRepo.Delete(category.ID)
+}
+
+// ListProducts lista os produtos da categoria e de todas as suas descendentes
+func (s *CategoryServiceImpl) ListProducts(id string) ([]*model.Product, error) {
+	category, err := s.GetCategory(id)
+	if err != nil {
+		return nil, err
+	}
+
+	descendants, err := s.categoryRepo.ListDescendants(category.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []primitive.ObjectID{category.ID}
+	for _, descendant := range descendants {
+		ids = append(ids, descendant.ID)
+	}
+
+	return s.productRepo.ListByCategoryIDs(ids)
+}
+
+// ResolveReference completa a referência de categoria de um produto a partir do ID
+// ou, para clientes que ainda enviam só o nome, procurando a categoria pelo nome.
+// Um nome ainda não cadastrado cria a categoria na raiz, como antes do cadastro de categorias.
+func (s *CategoryServiceImpl) ResolveReference(reference model.Category) (model.Category, error) {
+	var category *model.CategoryNode
+	var err error
+
+	name := strings.TrimSpace(reference.Name)
+	switch {
+	case !reference.ID.IsZero():
+		category, err = s.categoryRepo.FindByID(reference.ID)
+	case name != "":
+		category, err = s.categoryRepo.FindByName(name)
+		if err == nil && category == nil {
+			category, err = s.createByName(name)
+		}
+	default:
+		return model.Category{}, fmt.Errorf("%w: informe a categoria do produto", ErrInvalidCategory)
+	}
+	if err != nil {
+		return model.Category{}, err
+	}
+	if category == nil {
+		return model.Category{}, ErrCategoryNotFound
+	}
+
+	return category.Reference(), nil
+}
+
+// Cria a categoria raiz com o nome informado. Se outra requisição criou a mesma
+// categoria (ou uma com o mesmo slug) ao mesmo tempo, usa a existente.
+func (s *CategoryServiceImpl) createByName(name string) (*model.CategoryNode, error) {
+	category := &model.CategoryNode{Name: name}
+	err := s.CreateCategory(category)
+	if errors.Is(err, repository.ErrDuplicateCategoryPath) {
+		return s.categoryRepo.FindByPath(Slugify(name))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// BackfillProductCategories vincula ao cadastro de categorias os produtos gravados
+// só com o nome da categoria, criando as categorias que faltarem. Pode ser
+// executado a cada inicialização: produtos já vinculados são ignorados.
+func (s *CategoryServiceImpl) BackfillProductCategories() error {
+	names, err := s.productRepo.ListUncategorizedNames()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		reference, err := s.ResolveReference(model.Category{Name: name})
+		if err != nil {
+			return fmt.Errorf("erro ao vincular a categoria %s: %w", name, err)
+		}
+		if err := s.productRepo.SetCategoryByName(name, reference); err != nil {
+			return fmt.Errorf("erro ao vincular os produtos da categoria %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// GetAttributeSchema retorna o esquema efetivo da categoria: os atributos dos
+// ancestrais, da raiz para o pai, seguidos dos próprios. Uma redefinição pela
+// mesma chave substitui a herdada na mesma posição.
+func (s *CategoryServiceImpl) GetAttributeSchema(id string) ([]model.AttributeDefinition, error) {
+	category, err := s.GetCategory(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.attributeSchema(category)
+}
+
+// SetAttributeSchema substitui os atributos próprios da categoria. Os produtos já
+// cadastrados passam a ser validados pelo novo esquema na próxima alteração.
+func (s *CategoryServiceImpl) SetAttributeSchema(id string, attributes []model.AttributeDefinition) (*model.CategoryNode, error) {
+	category, err := s.GetCategory(id)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(attributes))
+	for i := range attributes {
+		if err := normalizeDefinition(&attributes[i]); err != nil {
+			return nil, err
+		}
+		if seen[attributes[i].Key] {
+			return nil, fmt.Errorf("%w: atributo %s repetido", ErrInvalidAttributes, attributes[i].Key)
+		}
+		seen[attributes[i].Key] = true
+	}
+
+	if err := s.categoryRepo.SetAttributes(category.ID, attributes); err != nil {
+		return nil, err
+	}
+	category.Attributes = attributes
+	return category, nil
+}
+
+// ValidateAttributes confere os atributos do produto contra o esquema da categoria
+// e retorna os valores convertidos para o tipo de cada atributo
+func (s *CategoryServiceImpl) ValidateAttributes(categoryID primitive.ObjectID, values map[string]interface{}) (map[string]interface{}, error) {
+	category, err := s.find(categoryID)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := s.attributeSchema(category)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := make(map[string]model.AttributeDefinition, len(schema))
+	for _, definition := range schema {
+		definitions[definition.Key] = definition
+	}
+
+	var problems []string
+	invalid := make(map[string]bool)
+	normalized := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		definition, ok := definitions[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("o atributo %s não existe na categoria %s", key, category.Name))
+			continue
+		}
+		converted, err := convertAttribute(definition, value)
+		if err != nil {
+			problems = append(problems, err.Error())
+			invalid[key] = true
+			continue
+		}
+		if converted != nil {
+			normalized[key] = converted
+		}
+	}
+	for _, definition := range schema {
+		if _, ok := normalized[definition.Key]; definition.Required && !ok && !invalid[definition.Key] {
+			problems = append(problems, fmt.Sprintf("o atributo %s é obrigatório", definition.Key))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAttributes, strings.Join(problems, "; "))
+	}
+	return normalized, nil
+}
+
+func (s *CategoryServiceImpl) attributeSchema(category *model.CategoryNode) ([]model.AttributeDefinition, error) {
+	var schema []model.AttributeDefinition
+	positions := make(map[string]int)
+	merge := func(attributes []model.AttributeDefinition) {
+		for _, attribute := range attributes {
+			if i, ok := positions[attribute.Key]; ok {
+				schema[i] = attribute
+				continue
+			}
+			positions[attribute.Key] = len(schema)
+			schema = append(schema, attribute)
+		}
+	}
+
+	for _, ancestorID := range category.Ancestors {
+		ancestor, err := s.categoryRepo.FindByID(ancestorID)
+		if err != nil {
+			return nil, err
+		}
+		if ancestor != nil {
+			merge(ancestor.Attributes)
+		}
+	}
+	merge(category.Attributes)
+
+	return schema, nil
+}
+
+// As chaves viram campos do documento do produto, então só aceitam minúsculas, dígitos e "_"
+func normalizeDefinition(definition *model.AttributeDefinition) error {
+	definition.Key = strings.ToLower(strings.TrimSpace(definition.Key))
+	if definition.Key == "" {
+		return fmt.Errorf("%w: a chave do atributo é obrigatória", ErrInvalidAttributes)
+	}
+	for _, r := range definition.Key {
+		if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '_' {
+			return fmt.Errorf("%w: chave %s inválida, use letras minúsculas, números e _", ErrInvalidAttributes, definition.Key)
+		}
+	}
+	definition.Label = strings.TrimSpace(definition.Label)
+	if definition.Label == "" {
+		definition.Label = definition.Key
+	}
+	definition.Unit = strings.TrimSpace(definition.Unit)
+
+	switch definition.Type {
+	case model.AttributeEnum:
+		if len(definition.AllowedValues) == 0 {
+			return fmt.Errorf("%w: o atributo %s precisa de valores permitidos", ErrInvalidAttributes, definition.Key)
+		}
+		seen := make(map[string]bool, len(definition.AllowedValues))
+		for i, value := range definition.AllowedValues {
+			value = strings.TrimSpace(value)
+			if value == "" || seen[search.Fold(value)] {
+				return fmt.Errorf("%w: valores permitidos do atributo %s vazios ou repetidos", ErrInvalidAttributes, definition.Key)
+			}
+			seen[search.Fold(value)] = true
+			definition.AllowedValues[i] = value
+		}
+	case model.AttributeNumber, model.AttributeBoolean, model.AttributeText:
+		if len(definition.AllowedValues) > 0 {
+			return fmt.Errorf("%w: apenas atributos %s têm valores permitidos", ErrInvalidAttributes, model.AttributeEnum)
+		}
+	default:
+		return fmt.Errorf("%w: tipo %s do atributo %s não suportado", ErrInvalidAttributes, definition.Type, definition.Key)
+	}
+	if definition.Unit != "" && definition.Type != model.AttributeNumber {
+		return fmt.Errorf("%w: apenas atributos %s têm unidade", ErrInvalidAttributes, model.AttributeNumber)
+	}
+
+	return nil
+}
+
+// Converte o valor recebido para o tipo do atributo. Valores vazios contam como
+// não informados e retornam nil.
+func convertAttribute(definition model.AttributeDefinition, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	text, isText := value.(string)
+	if isText {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, nil
+		}
+	}
+
+	switch definition.Type {
+	case model.AttributeEnum:
+		if isText {
+			for _, allowed := range definition.AllowedValues {
+				if search.Fold(allowed) == search.Fold(text) {
+					return allowed, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("valor %v não permitido para o atributo %s (use %s)",
+			value, definition.Key, strings.Join(definition.AllowedValues, ", "))
+	case model.AttributeNumber:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case string:
+			if number, err := strconv.ParseFloat(strings.Replace(text, ",", ".", 1), 64); err == nil {
+				return number, nil
+			}
+		}
+		return nil, fmt.Errorf("o atributo %s deve ser numérico", definition.Key)
+	case model.AttributeBoolean:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+		switch search.Fold(text) {
+		case "true", "sim":
+			return true, nil
+		case "false", "nao":
+			return false, nil
+		}
+		return nil, fmt.Errorf("o atributo %s deve ser verdadeiro ou falso", definition.Key)
+	default:
+		if !isText {
+			return nil, fmt.Errorf("o atributo %s deve ser texto", definition.Key)
+		}
+		return text, nil
+	}
+}
+
+func (s *CategoryServiceImpl) find(id primitive.ObjectID) (*model.CategoryNode, error) {
+	category, err := s.categoryRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return nil, ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+func (s *CategoryServiceImpl) parent(parentID *primitive.ObjectID) (*model.CategoryNode, error) {
+	if parentID == nil || parentID.IsZero() {
+		return nil, nil
+	}
+	parent, err := s.find(*parentID)
+	if errors.Is(err, ErrCategoryNotFound) {
+		return nil, fmt.Errorf("%w: categoria pai não encontrada", ErrInvalidCategory)
+	}
+	return parent, err
+}
+
+// Grava a categoria e recalcula ancestrais e caminho de todas as descendentes.
+// Os caminhos são calculados antes de qualquer gravação; se uma gravação falhar,
+// repetir a operação conclui o trabalho, pois as descendentes continuam achadas
+// pelo ID da categoria em ancestors.
+func (s *CategoryServiceImpl) saveSubtree(category *model.CategoryNode) error {
+	descendants, err := s.categoryRepo.ListDescendants(category.ID)
+	if err != nil {
+		return err
+	}
+
+	// Processa do nível mais alto para o mais baixo, para que o pai já esteja atualizado
+	sort.Slice(descendants, func(i, j int) bool {
+		return len(descendants[i].Ancestors) < len(descendants[j].Ancestors)
+	})
+
+	updated := map[primitive.ObjectID]*model.CategoryNode{category.ID: category}
+	for _, descendant := range descendants {
+		var parent *model.CategoryNode
+		if descendant.ParentID != nil {
+			parent = updated[*descendant.ParentID]
+		}
+		if parent == nil {
+			return fmt.Errorf("%w: o pai da subcategoria %s não está na subárvore de %s", ErrInvalidCategory, descendant.Name, category.Name)
+		}
+		setParent(descendant, parent)
+		updated[descendant.ID] = descendant
+	}
+
+	if err := s.categoryRepo.Update(category); err != nil {
+		return err
+	}
+	for _, descendant := range descendants {
+		if err := s.categoryRepo.Update(descendant); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Atualiza pai, ancestrais e caminho da categoria a partir do pai informado (nil = raiz)
+func setParent(category *model.CategoryNode, parent *model.CategoryNode) {
+	if parent == nil {
+		category.ParentID = nil
+		category.Ancestors = []primitive.ObjectID{}
+		category.Path = category.Slug
+		return
+	}
+
+	parentID := parent.ID
+	category.ParentID = &parentID
+	category.Ancestors = append(append([]primitive.ObjectID{}, parent.Ancestors...), parent.ID)
+	category.Path = parent.Path + "/" + category.Slug
+}
+
+func containsID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Slugify gera o slug da categoria: minúsculas, sem acentos e com hífens ("Smart TV" -> "smart-tv")
+func Slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range search.Fold(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		if !hyphen && b.Len() > 0 {
+			b.WriteRune('-')
+			hyphen = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// SetGoogleCategory associa a categoria à taxonomia do Google, pelo ID numérico ou
+// pelo caminho completo (Eletrônicos > Áudio). Vazio remove o mapeamento.
+func (s *CategoryServiceImpl) SetGoogleCategory(id, googleCategory string) (*model.CategoryNode, error) {
+	category, err := s.GetCategory(id)
+	if err != nil {
+		return nil, err
+	}
+
+	googleCategory = strings.TrimSpace(googleCategory)
+	if err := s.categoryRepo.SetGoogleCategory(category.ID, googleCategory); err != nil {
+		return nil, err
+	}
+	category.GoogleCategory = googleCategory
+	return category, nil
+}
@@ -0,0 +1,27 @@
+package service
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"espaço vira hífen", "Smart TV", "smart-tv"},
+		{"remove acentos", "Eletrônicos e Áudio", "eletronicos-e-audio"},
+		{"pontuação colapsa em um hífen", "Cama, Mesa & Banho", "cama-mesa-banho"},
+		{"sem hífen nas pontas", "  --Games--  ", "games"},
+		{"mantém dígitos", "TV 4K 55\"", "tv-4k-55"},
+		{"só pontuação", "***", ""},
+		{"vazio", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.in); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, esperado %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"Varejo-Golang-Microservices/services/product-service/infra/event"
+	"Varejo-Golang-Microservices/services/product-service/infra/feed"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Intervalo entre as tentativas da primeira geração do feed
+const feedRetryInterval = 30 * time.Second
+
+type FeedService interface {
+	GetFeed(format feed.Format) (*feed.Document, error)
+	Rebuild() error
+	SetCategoryMapping(categoryID, googleCategory string) (*model.CategoryNode, error)
+	ListenProductEvents(kafkaBroker string)
+	RunRebuild(interval time.Duration)
+}
+
+type FeedServiceImpl struct {
+	productRepo     *repository.MongoProductRepository
+	categoryService CategoryService
+	feed            *feed.Feed
+	config          feed.Config
+
+	mu         sync.RWMutex
+	categories map[primitive.ObjectID]*model.CategoryNode
+	// Serializa as gerações completas, disparadas pelo timer e pelo mapeamento de categorias
+	rebuildMu sync.Mutex
+}
+
+func NewFeedService(productRepo *repository.MongoProductRepository, categoryService CategoryService, config feed.Config) FeedService {
+	return &FeedServiceImpl{
+		productRepo:     productRepo,
+		categoryService: categoryService,
+		feed:            feed.New(config),
+		config:          config,
+		categories:      make(map[primitive.ObjectID]*model.CategoryNode),
+	}
+}
+
+func (s *FeedServiceImpl) GetFeed(format feed.Format) (*feed.Document, error) {
+	return s.feed.Document(format)
+}
+
+// Rebuild recarrega as categorias e gera novamente as ofertas de todo o catálogo.
+// Os produtos alterados por eventos durante a leitura mantêm a versão do evento.
+func (s *FeedServiceImpl) Rebuild() error {
+	s.rebuildMu.Lock()
+	defer s.rebuildMu.Unlock()
+
+	s.feed.BeginReplace()
+	if err := s.loadCategories(); err != nil {
+		s.feed.AbortReplace()
+		return err
+	}
+
+	items := make(map[string][]feed.Item)
+	err := s.productRepo.Each(func(product *model.Product) error {
+		items[product.ID.Hex()] = s.items(product)
+		return nil
+	})
+	if err != nil {
+		s.feed.AbortReplace()
+		return err
+	}
+
+	s.feed.Replace(items)
+	log.Printf("Feed de produtos gerado com %d produtos\n", len(items))
+	return nil
+}
+
+// SetCategoryMapping grava a categoria do Google e regenera o feed, pois o
+// mapeamento vale para os produtos da categoria e das subcategorias.
+func (s *FeedServiceImpl) SetCategoryMapping(categoryID, googleCategory string) (*model.CategoryNode, error) {
+	category, err := s.categoryService.SetGoogleCategory(categoryID, googleCategory)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Rebuild(); err != nil {
+		log.Printf("Erro ao regenerar o feed de produtos: %v\n", err)
+	}
+	return category, nil
+}
+
+// RunRebuild regenera o feed periodicamente. Mudanças na árvore de categorias
+// não geram eventos de produto e só entram no feed nesta regeneração.
+func (s *FeedServiceImpl) RunRebuild(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.Rebuild(); err != nil {
+			log.Printf("Erro ao regenerar o feed de produtos: %v\n", err)
+		}
+	}
+}
+
+// ListenProductEvents gera o feed completo e depois atualiza só as ofertas dos
+// produtos alterados. Cada instância usa o próprio grupo de consumo, pois o feed fica em memória.
+// Enquanto a primeira geração não der certo, ela é repetida e o feed responde 503.
+func (s *FeedServiceImpl) ListenProductEvents(kafkaBroker string) {
+	for {
+		err := s.Rebuild()
+		if err == nil {
+			break
+		}
+		log.Printf("Erro ao gerar o feed de produtos: %v\n", err)
+		time.Sleep(feedRetryInterval)
+	}
+
+	hostname, _ := os.Hostname()
+	groupID := "product-feed-" + hostname
+
+	messages := make(chan string)
+	go func() {
+		if err := event.ConsumeMessage(kafkaBroker, repository.ProductEventTopic, groupID, messages); err != nil {
+			log.Printf("Erro ao consumir o tópico %s: %v\n", repository.ProductEventTopic, err)
+		}
+	}()
+
+	for message := range messages {
+		var productEvent model.ProductEvent
+		if err := json.Unmarshal([]byte(message), &productEvent); err != nil {
+			log.Printf("Erro ao ler evento de produto: %v\n", err)
+			continue
+		}
+
+		if productEvent.Type == model.ProductDeleted || productEvent.Product == nil {
+			s.feed.Delete(productEvent.ProductID)
+			continue
+		}
+		s.feed.Upsert(productEvent.ProductID, s.items(productEvent.Product))
+	}
+}
+
+func (s *FeedServiceImpl) items(product *model.Product) []feed.Item {
+	return feed.Items(product, s.categoryInfo(product.Category.ID), s.config)
+}
+
+// Resolve os nomes da raiz até a categoria e a categoria do Google, herdada do
+// ancestral mais próximo com mapeamento. Categorias criadas depois da última carga
+// recarregam o cache.
+func (s *FeedServiceImpl) categoryInfo(id primitive.ObjectID) feed.CategoryInfo {
+	if id.IsZero() {
+		return feed.CategoryInfo{}
+	}
+
+	s.mu.RLock()
+	_, ok := s.categories[id]
+	s.mu.RUnlock()
+	if !ok {
+		if err := s.loadCategories(); err != nil {
+			log.Printf("Erro ao carregar as categorias do feed: %v\n", err)
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	category, ok := s.categories[id]
+	if !ok {
+		return feed.CategoryInfo{}
+	}
+	names := make([]string, 0, len(category.Ancestors)+1)
+	for _, ancestorID := range category.Ancestors {
+		if ancestor, ok := s.categories[ancestorID]; ok {
+			names = append(names, ancestor.Name)
+		}
+	}
+	names = append(names, category.Name)
+
+	info := feed.CategoryInfo{ProductType: strings.Join(names, " > "), GoogleCategory: category.GoogleCategory}
+	for i := len(category.Ancestors) - 1; i >= 0 && info.GoogleCategory == ""; i-- {
+		if ancestor, ok := s.categories[category.Ancestors[i]]; ok {
+			info.GoogleCategory = ancestor.GoogleCategory
+		}
+	}
+	return info
+}
+
+func (s *FeedServiceImpl) loadCategories() error {
+	categories, err := s.categoryService.ListCategories()
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[primitive.ObjectID]*model.CategoryNode, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	s.mu.Lock()
+	s.categories = byID
+	s.mu.Unlock()
+	return nil
+}
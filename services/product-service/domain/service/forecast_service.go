@@ -0,0 +1,234 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"Varejo-Golang-Microservices/services/product-service/infra/forecast"
+	"Varejo-Golang-Microservices/services/product-service/infra/locations"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// Semanas de histórico lidas dos pedidos: três anos, para a sazonalidade anual
+	ForecastHistoryWeeks = 156
+	// Semanas previstas a partir da semana atual
+	ForecastHorizonWeeks = 12
+	// Hora local em que o job noturno recalcula as previsões
+	DefaultForecastHour = 2
+)
+
+var ErrForecastRunning = errors.New("já existe um recálculo de previsões em andamento")
+
+type ForecastService interface {
+	GetForecasts(productID, sku string, locationID *string) ([]*model.Forecast, error)
+	StartRefresh() error
+	RunNightly(hour int)
+}
+
+type ForecastServiceImpl struct {
+	forecastRepo *repository.MongoForecastRepository
+	productRepo  *repository.MongoProductRepository
+	inventory    locations.Inventory
+
+	mu      sync.Mutex
+	running bool
+}
+
+func NewForecastService(forecastRepo *repository.MongoForecastRepository, productRepo *repository.MongoProductRepository, inventory locations.Inventory) ForecastService {
+	return &ForecastServiceImpl{
+		forecastRepo: forecastRepo,
+		productRepo:  productRepo,
+		inventory:    inventory,
+	}
+}
+
+// GetForecasts retorna as previsões gravadas pelo último recálculo. Com o produto
+// informado, ele precisa existir no catálogo.
+func (s *ForecastServiceImpl) GetForecasts(productID, sku string, locationID *string) ([]*model.Forecast, error) {
+	if productID != "" {
+		if _, err := s.productRepo.FindByID(productID); err != nil {
+			return nil, err
+		}
+	}
+	return s.forecastRepo.List(productID, sku, locationID)
+}
+
+// StartRefresh recalcula as previsões em segundo plano
+func (s *ForecastServiceImpl) StartRefresh() error {
+	if !s.begin() {
+		return ErrForecastRunning
+	}
+	go s.refreshAndLog()
+	return nil
+}
+
+func (s *ForecastServiceImpl) begin() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		return false
+	}
+	s.running = true
+	return true
+}
+
+func (s *ForecastServiceImpl) refreshAndLog() {
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	started := time.Now()
+	series, err := s.refresh()
+	if err != nil {
+		log.Printf("Erro ao recalcular as previsões de demanda: %v\n", err)
+		return
+	}
+	log.Printf("Previsões de demanda recalculadas para %d séries em %s\n", series, time.Since(started).Round(time.Second))
+}
+
+// RunNightly recalcula as previsões todos os dias na hora informada
+func (s *ForecastServiceImpl) RunNightly(hour int) {
+	for {
+		now := time.Now()
+		next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		time.Sleep(time.Until(next))
+
+		if s.begin() {
+			s.refreshAndLog()
+		}
+	}
+}
+
+// Início da semana (segunda-feira, em UTC) que contém o instante
+func weekStart(at time.Time) time.Time {
+	at = at.UTC()
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// Série semanal de vendas, indexada pelas semanas desde o início do histórico
+type salesSeries struct {
+	productID  string
+	sku        string
+	locationID string
+	weeks      map[int]float64
+	first      int
+}
+
+// refresh monta as séries semanais por produto/SKU, no total e por loja, ajusta os
+// modelos e substitui as previsões gravadas. A semana atual, incompleta, fica fora
+// do histórico e é a primeira prevista.
+func (s *ForecastServiceImpl) refresh() (int, error) {
+	current := weekStart(time.Now())
+	since := current.AddDate(0, 0, -7*ForecastHistoryWeeks)
+
+	stores, err := s.orderLocations(since)
+	if err != nil {
+		return 0, err
+	}
+
+	series := make(map[string]*salesSeries)
+	add := func(line *model.SalesLine, locationID string, week int, quantity float64) {
+		id := line.ProductID + "|" + line.SKU + "|" + locationID
+		item := series[id]
+		if item == nil {
+			item = &salesSeries{productID: line.ProductID, sku: line.SKU, locationID: locationID, weeks: make(map[int]float64), first: week}
+			series[id] = item
+		}
+		item.weeks[week] += quantity
+		if week < item.first {
+			item.first = week
+		}
+	}
+
+	err = s.forecastRepo.EachSalesLine(since, func(line *model.SalesLine) error {
+		if line.ProductID == "" || line.Quantity <= 0 || !line.OrderDate.Before(current) {
+			return nil
+		}
+		week := int(weekStart(line.OrderDate).Sub(since).Hours() / (24 * 7))
+		add(line, "", week, float64(line.Quantity))
+		// Um item atendido por mais de uma loja é repartido na proporção das baixas
+		shipped := stores[line.OrderID+"|"+line.ProductID+"|"+line.SKU]
+		total := 0
+		for _, quantity := range shipped {
+			total += quantity
+		}
+		for locationID, quantity := range shipped {
+			add(line, locationID, week, float64(line.Quantity*quantity)/float64(total))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	generatedAt := time.Now().Truncate(time.Millisecond)
+	forecasts := make([]*model.Forecast, 0, len(series))
+	for id, item := range series {
+		// A série começa na primeira venda; as semanas sem vendas depois dela valem zero
+		history := make([]float64, ForecastHistoryWeeks-item.first)
+		for week, quantity := range item.weeks {
+			history[week-item.first] = quantity
+		}
+
+		result := forecast.Forecast(history, ForecastHorizonWeeks)
+		if result == nil {
+			continue
+		}
+		record := &model.Forecast{
+			ID:              id,
+			ProductID:       item.productID,
+			SKU:             item.sku,
+			LocationID:      item.locationID,
+			Method:          string(result.Method),
+			Parameters:      result.Parameters,
+			BacktestMAE:     result.BacktestMAE,
+			HoldoutWeeks:    result.HoldoutWeeks,
+			HistoryWeeks:    len(history),
+			ConfidenceLevel: forecast.ConfidenceLevel,
+			GeneratedAt:     generatedAt,
+		}
+		for step, point := range result.Points {
+			record.Weeks = append(record.Weeks, model.ForecastWeek{
+				WeekStart: current.AddDate(0, 0, 7*step),
+				Forecast:  point.Forecast,
+				Lower:     point.Lower,
+				Upper:     point.Upper,
+			})
+		}
+		forecasts = append(forecasts, record)
+	}
+
+	return len(forecasts), s.forecastRepo.Replace(forecasts, generatedAt)
+}
+
+// orderLocations consulta no livro de estoque do serviço de localizações as unidades
+// que cada loja baixou para cada item de pedido, pela chave pedido|produto|SKU
+func (s *ForecastServiceImpl) orderLocations(since time.Time) (map[string]map[string]int, error) {
+	sales, err := s.inventory.Sales("", since)
+	if err != nil {
+		return nil, err
+	}
+
+	stores := make(map[string]map[string]int)
+	for _, sale := range sales {
+		if sale.ReferenceType != "ORDER" || sale.Quantity >= 0 {
+			continue
+		}
+		key := sale.Reference + "|" + sale.ProductID + "|" + sale.SKU
+		if stores[key] == nil {
+			stores[key] = make(map[string]int)
+		}
+		stores[key][sale.LocationID] -= sale.Quantity
+	}
+	return stores, nil
+}
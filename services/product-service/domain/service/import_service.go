@@ -0,0 +1,684 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"Varejo-Golang-Microservices/services/product-service/infra/catalog"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrImportJobNotFound = errors.New("importação não encontrada")
+
+const (
+	// Limite de erros guardados no relatório; Failed continua contando todas as linhas rejeitadas
+	MaxImportErrors = 10000
+	// A cada quantas linhas o progresso da importação é gravado
+	importProgressEvery = 200
+)
+
+type ImportService interface {
+	StartImport(format model.ImportFormat, fileName string, data []byte, dryRun bool, actor string) (*model.ImportJob, error)
+	GetJob(id string) (*model.ImportJob, error)
+	ListJobs() ([]*model.ImportJob, error)
+	ExportCatalog(format model.ImportFormat, w io.Writer) error
+	FailInterruptedJobs()
+}
+
+type ImportServiceImpl struct {
+	jobRepo         *repository.MongoImportJobRepository
+	productRepo     *repository.MongoProductRepository
+	productService  ProductService
+	stockService    StockService
+	categoryService CategoryService
+}
+
+func NewImportService(jobRepo *repository.MongoImportJobRepository, productRepo *repository.MongoProductRepository,
+	productService ProductService, stockService StockService, categoryService CategoryService) ImportService {
+	return &ImportServiceImpl{
+		jobRepo:         jobRepo,
+		productRepo:     productRepo,
+		productService:  productService,
+		stockService:    stockService,
+		categoryService: categoryService,
+	}
+}
+
+// StartImport valida o cabeçalho do arquivo, registra a importação e processa as
+// linhas em segundo plano. O andamento é consultado pelo ID da importação.
+func (s *ImportServiceImpl) StartImport(format model.ImportFormat, fileName string, data []byte, dryRun bool, actor string) (*model.ImportJob, error) {
+	if _, err := catalog.NewReader(format, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	job := &model.ImportJob{
+		ID:        primitive.NewObjectID(),
+		Format:    format,
+		FileName:  fileName,
+		DryRun:    dryRun,
+		Status:    model.ImportPending,
+		CreatedBy: actor,
+		CreatedAt: time.Now(),
+	}
+	if err := s.jobRepo.Save(job); err != nil {
+		return nil, err
+	}
+
+	// A goroutine trabalha em uma cópia; o job retornado é o estado inicial
+	running := *job
+	go s.run(&running, data)
+
+	return job, nil
+}
+
+func (s *ImportServiceImpl) GetJob(id string) (*model.ImportJob, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrImportJobNotFound
+	}
+
+	job, err := s.jobRepo.FindByID(objID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrImportJobNotFound
+	}
+	return job, nil
+}
+
+func (s *ImportServiceImpl) ListJobs() ([]*model.ImportJob, error) {
+	return s.jobRepo.List(50)
+}
+
+// FailInterruptedJobs encerra as importações que ficaram pendentes em uma reinicialização
+func (s *ImportServiceImpl) FailInterruptedJobs() {
+	n, err := s.jobRepo.FailInterrupted(time.Now())
+	if err != nil {
+		log.Printf("Erro ao encerrar importações interrompidas: %v\n", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("%d importações interrompidas marcadas como falhas\n", n)
+	}
+}
+
+// Estado do dry run: o que a simulação já teria gravado, para validar as linhas
+// seguintes do mesmo arquivo contra ele
+type importSimulation struct {
+	// SKUs de produtos e variantes que a simulação teria cadastrado
+	pending map[string]bool
+	// Cópias dos produtos existentes com as opções e variantes simuladas
+	products map[primitive.ObjectID]*model.Product
+}
+
+func newImportSimulation() *importSimulation {
+	return &importSimulation{
+		pending:  make(map[string]bool),
+		products: make(map[primitive.ObjectID]*model.Product),
+	}
+}
+
+// Retorna a cópia simulada do produto, criada no primeiro uso
+func (sim *importSimulation) product(product *model.Product) *model.Product {
+	if simulated, ok := sim.products[product.ID]; ok {
+		return simulated
+	}
+	simulated := *product
+	simulated.Options = append([]model.ProductOption{}, product.Options...)
+	simulated.Variants = append([]model.Variant{}, product.Variants...)
+	sim.products[product.ID] = &simulated
+	return &simulated
+}
+
+func (s *ImportServiceImpl) run(job *model.ImportJob, data []byte) {
+	// Um panic em uma linha não pode derrubar o serviço nem deixar a importação em RUNNING
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic na importação %s: %v\n", job.ID.Hex(), r)
+			s.finish(job, fmt.Errorf("erro interno na linha %d: %v", job.TotalRows, r))
+		}
+	}()
+
+	started := time.Now()
+	job.Status = model.ImportRunning
+	job.StartedAt = &started
+	s.saveProgress(job)
+
+	reader, err := catalog.NewReader(job.Format, bytes.NewReader(data))
+	if err != nil {
+		s.finish(job, err)
+		return
+	}
+
+	sim := newImportSimulation()
+
+	for {
+		row, line, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		var rowErr *catalog.RowError
+		if errors.As(err, &rowErr) {
+			job.TotalRows++
+			s.reject(job, line, "", err)
+			continue
+		}
+		if err != nil {
+			s.finish(job, err)
+			return
+		}
+
+		job.TotalRows++
+		created, err := s.importRow(row, job.DryRun, sim)
+		switch {
+		case err != nil:
+			s.reject(job, line, row.SKU, err)
+		case created:
+			job.Created++
+		default:
+			job.Updated++
+		}
+
+		if job.TotalRows%importProgressEvery == 0 {
+			s.saveProgress(job)
+		}
+	}
+
+	s.finish(job, nil)
+}
+
+func (s *ImportServiceImpl) reject(job *model.ImportJob, line int, sku string, err error) {
+	job.Failed++
+	if len(job.Errors) >= MaxImportErrors {
+		return
+	}
+
+	rowError := model.ImportRowError{Row: line, SKU: sku, Message: err.Error()}
+	var rowErr *catalog.RowError
+	if errors.As(err, &rowErr) {
+		rowError.Field = rowErr.Field
+		rowError.Message = rowErr.Message
+	}
+	job.Errors = append(job.Errors, rowError)
+}
+
+func (s *ImportServiceImpl) finish(job *model.ImportJob, err error) {
+	finished := time.Now()
+	job.FinishedAt = &finished
+	job.Status = model.ImportCompleted
+	if err != nil {
+		job.Status = model.ImportFailed
+		job.Message = err.Error()
+	}
+	s.saveProgress(job)
+}
+
+func (s *ImportServiceImpl) saveProgress(job *model.ImportJob) {
+	if err := s.jobRepo.Update(job); err != nil {
+		log.Printf("Erro ao gravar o andamento da importação %s: %v\n", job.ID.Hex(), err)
+	}
+}
+
+// importRow aplica uma linha com semântica de upsert pelo SKU e informa se ela cadastrou algo novo
+func (s *ImportServiceImpl) importRow(row *model.CatalogRow, dryRun bool, sim *importSimulation) (bool, error) {
+	if err := validateRow(row); err != nil {
+		return false, err
+	}
+	if row.ParentSKU != "" {
+		return s.importVariant(row, dryRun, sim)
+	}
+	return s.importProduct(row, dryRun, sim)
+}
+
+func validateRow(row *model.CatalogRow) error {
+	if row.SKU == "" {
+		return &catalog.RowError{Field: "sku", Message: "o SKU é obrigatório"}
+	}
+	if row.ParentSKU == row.SKU {
+		return &catalog.RowError{Field: "parentSku", Message: "a variante não pode ser o próprio produto"}
+	}
+	if row.Price != nil && *row.Price < 0 {
+		return &catalog.RowError{Field: "price", Message: "o preço não pode ser negativo"}
+	}
+	if row.Stock != nil && *row.Stock < 0 {
+		return &catalog.RowError{Field: "stock", Message: "o estoque não pode ser negativo"}
+	}
+
+	switch row.Status {
+	case "", model.Available, model.OutOfStock, model.Discontinued:
+	default:
+		return &catalog.RowError{Field: "status", Message: "status inválido: " + string(row.Status)}
+	}
+
+	if row.ParentSKU != "" && row.Status != "" {
+		return &catalog.RowError{Field: "status", Message: "o status é do produto; variantes não têm status próprio"}
+	}
+	if row.ParentSKU == "" && len(row.Options) > 0 {
+		return &catalog.RowError{Field: "options", Message: "opções só valem para variantes; informe parentSku"}
+	}
+	if row.GTIN != "" {
+		if _, err := model.NormalizeGTIN(row.GTIN); err != nil {
+			return &catalog.RowError{Field: "gtin", Message: err.Error()}
+		}
+	}
+	return nil
+}
+
+func (s *ImportServiceImpl) importProduct(row *model.CatalogRow, dryRun bool, sim *importSimulation) (bool, error) {
+	existing, err := s.productRepo.FindBySKU(row.SKU)
+	if err != nil && !errors.Is(err, repository.ErrVariantNotFound) {
+		return false, err
+	}
+
+	if existing == nil {
+		if sim.pending[row.SKU] {
+			return false, nil
+		}
+		return s.createProduct(row, dryRun, sim)
+	}
+
+	if existing.SKU != row.SKU {
+		return false, &catalog.RowError{Field: "sku", Message: fmt.Sprintf("o SKU pertence a uma variante do produto %s; informe parentSku", existing.ID.Hex())}
+	}
+	if row.Stock != nil && len(existing.Variants) > 0 {
+		return false, &catalog.RowError{Field: "stock", Message: "o estoque de produtos com variantes é informado nas linhas das variantes"}
+	}
+
+	updated := *existing
+	if row.Name != "" {
+		updated.Name = row.Name
+	}
+	if row.Description != "" {
+		updated.Description = row.Description
+	}
+	if row.Price != nil {
+		updated.Price = *row.Price
+	}
+	if row.GTIN != "" {
+		updated.GTIN = row.GTIN
+	}
+	if row.Category != "" {
+		category, err := s.resolveCategory(row.Category, dryRun)
+		if err != nil {
+			return false, err
+		}
+		updated.Category = category
+	}
+	// Os atributos da linha se somam aos atuais; os omitidos são mantidos
+	if row.Attributes != nil {
+		updated.Attributes = make(map[string]interface{}, len(existing.Attributes)+len(row.Attributes))
+		for key, value := range existing.Attributes {
+			updated.Attributes[key] = value
+		}
+		for key, value := range row.Attributes {
+			updated.Attributes[key] = value
+		}
+	}
+
+	stock := existing.Stock
+	if row.Stock != nil {
+		stock = *row.Stock
+	}
+	status, err := importedStatus(existing.Status, row.Status, stock)
+	if err != nil {
+		return false, err
+	}
+
+	if dryRun {
+		return false, s.validateAttributes(updated.Category, updated.Attributes)
+	}
+
+	if err := s.productService.UpdateProduct(&updated); err != nil {
+		return false, err
+	}
+	if err := s.setStock(existing.ID.Hex(), "", existing.Stock, row.Stock); err != nil {
+		return false, err
+	}
+	if status != existing.Status {
+		if err := s.productRepo.SetStatus(existing.ID, status); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// Status de um produto existente depois da importação. DISCONTINUED é gravado como
+// veio; AVAILABLE e OUT_OF_STOCK acompanham o estoque, então a linha só é aceita
+// quando o status informado corresponde ao estoque resultante (o que também
+// reativa um produto descontinuado). Sem status na linha, o atual é mantido.
+func importedStatus(current, requested model.ProductStatus, stock int) (model.ProductStatus, error) {
+	switch requested {
+	case "":
+		return current, nil
+	case model.Discontinued:
+		return model.Discontinued, nil
+	}
+
+	status := model.StatusForStock(model.Available, stock)
+	if requested != status {
+		return "", &catalog.RowError{Field: "status", Message: fmt.Sprintf("o status %s não corresponde ao estoque %d; o status segue o estoque", requested, stock)}
+	}
+	return status, nil
+}
+
+func (s *ImportServiceImpl) createProduct(row *model.CatalogRow, dryRun bool, sim *importSimulation) (bool, error) {
+	if row.Name == "" {
+		return false, &catalog.RowError{Field: "name", Message: "o nome é obrigatório no cadastro"}
+	}
+	if row.Price == nil {
+		return false, &catalog.RowError{Field: "price", Message: "o preço é obrigatório no cadastro"}
+	}
+	if row.Category == "" {
+		return false, &catalog.RowError{Field: "category", Message: "a categoria é obrigatória no cadastro"}
+	}
+
+	category, err := s.resolveCategory(row.Category, dryRun)
+	if err != nil {
+		return false, err
+	}
+
+	if dryRun {
+		if err := s.validateAttributes(category, row.Attributes); err != nil {
+			return false, err
+		}
+		sim.pending[row.SKU] = true
+		return true, nil
+	}
+
+	product := &model.Product{
+		ID:          primitive.NewObjectID(),
+		SKU:         row.SKU,
+		GTIN:        row.GTIN,
+		Name:        row.Name,
+		Description: row.Description,
+		Price:       *row.Price,
+		Category:    category,
+		Attributes:  row.Attributes,
+		AddedDate:   time.Now(),
+		Status:      row.Status,
+	}
+	if row.Stock != nil {
+		product.Stock = *row.Stock
+	}
+
+	if err := s.productService.SaveProduct(product); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *ImportServiceImpl) importVariant(row *model.CatalogRow, dryRun bool, sim *importSimulation) (bool, error) {
+	parent, err := s.productRepo.FindBySKU(row.ParentSKU)
+	if errors.Is(err, repository.ErrVariantNotFound) {
+		if dryRun && sim.pending[row.ParentSKU] {
+			if len(row.Options) == 0 {
+				return false, &catalog.RowError{Field: "options", Message: "as opções são obrigatórias no cadastro da variante"}
+			}
+			created := !sim.pending[row.SKU]
+			sim.pending[row.SKU] = true
+			return created, nil
+		}
+		return false, &catalog.RowError{Field: "parentSku", Message: "produto " + row.ParentSKU + " não encontrado"}
+	}
+	if err != nil {
+		return false, err
+	}
+	if parent.SKU != row.ParentSKU {
+		return false, &catalog.RowError{Field: "parentSku", Message: "parentSku deve ser o SKU de um produto, não de uma variante"}
+	}
+
+	current := parent.FindVariant(row.SKU)
+	if dryRun {
+		// Variantes criadas por linhas anteriores do arquivo já existem na cópia simulada
+		current = sim.product(parent).FindVariant(row.SKU)
+	}
+	if current == nil {
+		return s.createVariant(parent, row, dryRun, sim)
+	}
+
+	variant := *current
+	if len(row.Options) > 0 {
+		variant.Options = row.Options
+	}
+	if row.GTIN != "" {
+		variant.GTIN = row.GTIN
+	}
+	if row.Price != nil {
+		variant.Price = row.Price
+	}
+
+	if dryRun {
+		return false, simulateVariant(sim.product(parent), variant, false)
+	}
+
+	if err := s.mergeOptions(parent, variant.Options); err != nil {
+		return false, err
+	}
+	if _, err := s.productService.UpdateVariant(parent.ID.Hex(), &variant); err != nil {
+		return false, err
+	}
+	if err := s.setStock(parent.ID.Hex(), row.SKU, current.Stock, row.Stock); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+func (s *ImportServiceImpl) createVariant(parent *model.Product, row *model.CatalogRow, dryRun bool, sim *importSimulation) (bool, error) {
+	if len(row.Options) == 0 {
+		return false, &catalog.RowError{Field: "options", Message: "as opções são obrigatórias no cadastro da variante"}
+	}
+
+	other, err := s.productRepo.FindBySKU(row.SKU)
+	if err != nil && !errors.Is(err, repository.ErrVariantNotFound) {
+		return false, err
+	}
+	if other != nil {
+		return false, &catalog.RowError{Field: "sku", Message: fmt.Sprintf("o SKU já está em uso no produto %s", other.ID.Hex())}
+	}
+
+	variant := &model.Variant{
+		SKU:     row.SKU,
+		Options: row.Options,
+		GTIN:    row.GTIN,
+		Price:   row.Price,
+	}
+	if row.Stock != nil {
+		variant.Stock = *row.Stock
+	}
+
+	if dryRun {
+		if sim.pending[row.SKU] {
+			return false, &catalog.RowError{Field: "sku", Message: "o SKU já está em uso em outra linha do arquivo"}
+		}
+		if err := simulateVariant(sim.product(parent), *variant, true); err != nil {
+			return false, err
+		}
+		sim.pending[row.SKU] = true
+		return true, nil
+	}
+
+	if err := s.mergeOptions(parent, row.Options); err != nil {
+		return false, err
+	}
+	if _, err := s.productService.AddVariant(parent.ID.Hex(), variant); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// No dry run, confere os atributos contra o esquema da categoria. Categorias que a
+// importação criaria ainda não têm esquema, então não há o que conferir.
+func (s *ImportServiceImpl) validateAttributes(category model.Category, attributes map[string]interface{}) error {
+	if category.ID.IsZero() {
+		return nil
+	}
+	_, err := s.categoryService.ValidateAttributes(category.ID, attributes)
+	return err
+}
+
+// Aplica à cópia simulada do produto as mesmas validações de AddVariant e
+// UpdateVariant, incluindo as opções que a importação acrescentaria
+func simulateVariant(product *model.Product, variant model.Variant, adding bool) error {
+	if adding {
+		if product.IsBundle() {
+			return fmt.Errorf("%w: kits não têm variantes", ErrInvalidBundle)
+		}
+		if len(product.Variants) == 0 && (product.Stock > 0 || product.Reserved > 0) {
+			return fmt.Errorf("%w: zere o estoque do produto antes de cadastrar variantes", ErrInvalidVariant)
+		}
+	}
+
+	options, _ := mergedOptions(product.Options, variant.Options)
+	variants := append([]model.Variant{}, product.Variants...)
+	if adding {
+		variants = append(variants, variant)
+	} else {
+		for i := range variants {
+			if variants[i].SKU == variant.SKU {
+				variant.Stock, variant.Reserved = variants[i].Stock, variants[i].Reserved
+				variants[i] = variant
+			}
+		}
+	}
+
+	if err := validateVariants(options, variants); err != nil {
+		return err
+	}
+	product.Options = options
+	product.Variants = variants
+	return nil
+}
+
+// Acrescenta às opções do produto os valores usados pela variante, para que a
+// planilha não precise declarar as opções separadamente
+func (s *ImportServiceImpl) mergeOptions(product *model.Product, values map[string]string) error {
+	options, changed := mergedOptions(product.Options, values)
+	if !changed {
+		return nil
+	}
+
+	updated, err := s.productService.UpdateOptions(product.ID.Hex(), options)
+	if err != nil {
+		return err
+	}
+	product.Options = updated.Options
+	return nil
+}
+
+// Retorna as opções acrescidas dos valores informados e se houve mudança
+func mergedOptions(current []model.ProductOption, values map[string]string) ([]model.ProductOption, bool) {
+	options := make([]model.ProductOption, len(current))
+	copy(options, current)
+
+	changed := false
+	for name, value := range values {
+		found := false
+		for i := range options {
+			if options[i].Name != name {
+				continue
+			}
+			found = true
+			if !containsString(options[i].Values, value) {
+				options[i].Values = append(append([]string{}, options[i].Values...), value)
+				changed = true
+			}
+		}
+		if !found {
+			options = append(options, model.ProductOption{Name: name, Values: []string{value}})
+			changed = true
+		}
+	}
+
+	return options, changed
+}
+
+// Ajusta o estoque para a quantidade informada na planilha, quando houver
+func (s *ImportServiceImpl) setStock(productID, sku string, current int, target *int) error {
+	if target == nil || *target == current {
+		return nil
+	}
+	if _, err := s.stockService.AdjustStock(productID, sku, *target-current); err != nil {
+		return fmt.Errorf("cadastro atualizado, mas o estoque não: %w", err)
+	}
+	return nil
+}
+
+// A categoria pode ser informada pelo caminho (como na exportação) ou pelo nome.
+// Um nome novo cria a categoria, exceto no dry run, que não grava nada.
+func (s *ImportServiceImpl) resolveCategory(value string, dryRun bool) (model.Category, error) {
+	category, err := s.categoryService.GetCategoryByPath(value)
+	if err == nil {
+		return category.Reference(), nil
+	}
+	if !errors.Is(err, ErrCategoryNotFound) {
+		return model.Category{}, err
+	}
+	if dryRun {
+		return model.Category{Name: value}, nil
+	}
+
+	reference, err := s.categoryService.ResolveReference(model.Category{Name: value})
+	if errors.Is(err, ErrCategoryNotFound) {
+		return model.Category{}, &catalog.RowError{Field: "category", Message: "categoria " + value + " não encontrada"}
+	}
+	return reference, err
+}
+
+// ExportCatalog grava o catálogo no formato usado pela importação, produto a produto
+func (s *ImportServiceImpl) ExportCatalog(format model.ImportFormat, w io.Writer) error {
+	writer, err := catalog.NewWriter(format, w)
+	if err != nil {
+		return err
+	}
+
+	categories, err := s.categoryService.ListCategories()
+	if err != nil {
+		return err
+	}
+	paths := make(map[primitive.ObjectID]string, len(categories))
+	for _, category := range categories {
+		paths[category.ID] = category.Path
+	}
+
+	count := 0
+	err = s.productRepo.Each(func(product *model.Product) error {
+		path, ok := paths[product.Category.ID]
+		if !ok {
+			path = product.Category.Name
+		}
+
+		for _, row := range catalog.Rows(product, path) {
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+
+		count++
+		if count%100 == 0 {
+			return writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return writer.Flush()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"testing"
+)
+
+func TestImportedStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   model.ProductStatus
+		requested model.ProductStatus
+		stock     int
+		want      model.ProductStatus
+		wantErr   bool
+	}{
+		{"sem status mantém o atual", model.Available, "", 10, model.Available, false},
+		{"sem status mantém o descontinuado", model.Discontinued, "", 10, model.Discontinued, false},
+		{"descontinua produto com estoque", model.Available, model.Discontinued, 10, model.Discontinued, false},
+		{"descontinua produto sem estoque", model.OutOfStock, model.Discontinued, 0, model.Discontinued, false},
+		{"reativa descontinuado com estoque", model.Discontinued, model.Available, 5, model.Available, false},
+		{"reativa descontinuado sem estoque", model.Discontinued, model.OutOfStock, 0, model.OutOfStock, false},
+		{"disponível coerente com o estoque", model.OutOfStock, model.Available, 3, model.Available, false},
+		{"disponível sem estoque", model.OutOfStock, model.Available, 0, "", true},
+		{"esgotado com estoque", model.Available, model.OutOfStock, 3, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := importedStatus(tt.current, tt.requested, tt.stock)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("importedStatus erro = %v, esperado erro: %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("importedStatus = %s, esperado %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRowStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		row     model.CatalogRow
+		wantErr bool
+	}{
+		{"produto descontinuado", model.CatalogRow{SKU: "P1", Status: model.Discontinued}, false},
+		{"status inválido", model.CatalogRow{SKU: "P1", Status: "ARCHIVED"}, true},
+		{"variante com status", model.CatalogRow{SKU: "P1-AZ", ParentSKU: "P1", Status: model.Discontinued}, true},
+		{"variante sem status", model.CatalogRow{SKU: "P1-AZ", ParentSKU: "P1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateRow(&tt.row); (err != nil) != tt.wantErr {
+				t.Errorf("validateRow = %v, esperado erro: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
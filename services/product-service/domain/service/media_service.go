@@ -0,0 +1,285 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"Varejo-Golang-Microservices/services/product-service/infra/imaging"
+	"Varejo-Golang-Microservices/services/product-service/infra/storage"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrUnsupportedMedia  = errors.New("tipo de arquivo não suportado")
+	ErrMediaTooLarge     = errors.New("o arquivo excede o tamanho máximo")
+	ErrInvalidMediaOrder = errors.New("a nova ordem deve conter cada mídia do produto uma única vez")
+)
+
+const (
+	MaxImageSize       int64 = 10 << 20
+	MaxVideoSize       int64 = 100 << 20
+	MaxMediaPerProduct       = 20
+)
+
+type mediaFormat struct {
+	Type      model.MediaType
+	Extension string
+}
+
+// Tipos aceitos, identificados pelo conteúdo do arquivo e não pela extensão enviada
+var mediaFormats = map[string]mediaFormat{
+	"image/jpeg": {Type: model.MediaImage, Extension: "jpg"},
+	"image/png":  {Type: model.MediaImage, Extension: "png"},
+	"image/gif":  {Type: model.MediaImage, Extension: "gif"},
+	"image/webp": {Type: model.MediaImage, Extension: "webp"},
+	"video/mp4":  {Type: model.MediaVideo, Extension: "mp4"},
+	"video/webm": {Type: model.MediaVideo, Extension: "webm"},
+}
+
+type MediaService interface {
+	UploadMedia(productID, fileName, altText string, r io.Reader) (*model.Media, error)
+	ListMedia(productID string) ([]model.Media, error)
+	ReorderMedia(productID string, mediaIDs []string) ([]model.Media, error)
+	DeleteMedia(productID, mediaID string) error
+	OpenMedia(key string) (io.ReadSeekCloser, error)
+}
+
+type MediaServiceImpl struct {
+	productRepo *repository.MongoProductRepository
+	storage     storage.Storage
+}
+
+func NewMediaService(productRepo *repository.MongoProductRepository, storage storage.Storage) MediaService {
+	return &MediaServiceImpl{
+		productRepo: productRepo,
+		storage:     storage,
+	}
+}
+
+// UploadMedia identifica o tipo pelo conteúdo, aplica o limite de tamanho do tipo,
+// grava o arquivo e, para imagens, as miniaturas em cada tamanho padrão.
+func (s *MediaServiceImpl) UploadMedia(productID, fileName, altText string, r io.Reader) (*model.Media, error) {
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(product.Media) >= MaxMediaPerProduct {
+		return nil, repository.ErrMediaLimit
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: arquivo vazio", ErrUnsupportedMedia)
+		}
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	format, ok := mediaFormats[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMedia, contentType)
+	}
+
+	id := primitive.NewObjectID().Hex()
+	// As miniaturas ficam ao lado do original: <base>_<tamanho>.<extensão>
+	base := fmt.Sprintf("products/%s/%s", product.ID.Hex(), id)
+	media := &model.Media{
+		ID:          id,
+		Type:        format.Type,
+		ContentType: contentType,
+		FileName:    fileName,
+		Key:         base + "." + format.Extension,
+		AltText:     altText,
+		UploadedAt:  time.Now(),
+	}
+	body := io.MultiReader(bytes.NewReader(head), r)
+
+	if format.Type == model.MediaImage {
+		err = saveImage(s.storage, media, base, body)
+	} else {
+		err = s.saveVideo(media, body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	media.URL = s.storage.URL(media.Key)
+
+	if err := s.productRepo.AddMedia(product.ID, media, MaxMediaPerProduct); err != nil {
+		deleteFiles(s.storage, media)
+		return nil, err
+	}
+
+	return media, nil
+}
+
+// Grava a imagem e as miniaturas. Também usada pelas fotos das avaliações.
+func saveImage(store storage.Storage, media *model.Media, base string, body io.Reader) error {
+	data, err := io.ReadAll(io.LimitReader(body, MaxImageSize+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > MaxImageSize {
+		return fmt.Errorf("%w: imagens podem ter até %d MB", ErrMediaTooLarge, MaxImageSize>>20)
+	}
+	media.Size = int64(len(data))
+
+	// WebP não tem decodificador na biblioteca padrão; é guardado sem miniaturas
+	var thumbnails []imaging.Thumbnail
+	if imaging.Decodable(media.ContentType) {
+		if media.Width, media.Height, err = imaging.Dimensions(data); err != nil {
+			return fmt.Errorf("%w: imagem corrompida", ErrUnsupportedMedia)
+		}
+		if thumbnails, err = imaging.Generate(data, imaging.DefaultSizes); err != nil {
+			if errors.Is(err, imaging.ErrImageTooLarge) {
+				return fmt.Errorf("%w: %v", ErrMediaTooLarge, err)
+			}
+			return fmt.Errorf("%w: imagem corrompida", ErrUnsupportedMedia)
+		}
+	}
+
+	if err := store.Save(media.Key, bytes.NewReader(data)); err != nil {
+		return err
+	}
+
+	for _, thumbnail := range thumbnails {
+		key := fmt.Sprintf("%s_%s.%s", base, thumbnail.Size, thumbnail.Extension)
+		if err := store.Save(key, bytes.NewReader(thumbnail.Data)); err != nil {
+			deleteFiles(store, media)
+			return err
+		}
+		media.Thumbnails = append(media.Thumbnails, model.Thumbnail{
+			Size:   thumbnail.Size,
+			Width:  thumbnail.Width,
+			Height: thumbnail.Height,
+			Key:    key,
+			URL:    store.URL(key),
+		})
+	}
+
+	return nil
+}
+
+func (s *MediaServiceImpl) saveVideo(media *model.Media, body io.Reader) error {
+	limited := &sizeLimitReader{reader: body, limit: MaxVideoSize}
+	if err := s.storage.Save(media.Key, limited); err != nil {
+		if errors.Is(err, ErrMediaTooLarge) {
+			return fmt.Errorf("%w: vídeos podem ter até %d MB", ErrMediaTooLarge, MaxVideoSize>>20)
+		}
+		return err
+	}
+
+	media.Size = limited.read
+	return nil
+}
+
+func (s *MediaServiceImpl) ListMedia(productID string) ([]model.Media, error) {
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	return product.Media, nil
+}
+
+// ReorderMedia recebe os IDs de todas as mídias do produto na ordem de exibição desejada
+func (s *MediaServiceImpl) ReorderMedia(productID string, mediaIDs []string) ([]model.Media, error) {
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(mediaIDs) != len(product.Media) {
+		return nil, ErrInvalidMediaOrder
+	}
+	// Galeria vazia não tem o que reordenar; o filtro com $all vazio nunca casaria
+	if len(mediaIDs) == 0 {
+		return []model.Media{}, nil
+	}
+
+	byID := make(map[string]model.Media, len(product.Media))
+	for _, media := range product.Media {
+		byID[media.ID] = media
+	}
+
+	ordered := make([]model.Media, 0, len(mediaIDs))
+	for _, id := range mediaIDs {
+		media, ok := byID[id]
+		if !ok {
+			return nil, ErrInvalidMediaOrder
+		}
+		delete(byID, id)
+		ordered = append(ordered, media)
+	}
+
+	if err := s.productRepo.ReorderMedia(product.ID, ordered); err != nil {
+		return nil, err
+	}
+	return ordered, nil
+}
+
+func (s *MediaServiceImpl) DeleteMedia(productID, mediaID string) error {
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil {
+		return err
+	}
+
+	var media *model.Media
+	for i := range product.Media {
+		if product.Media[i].ID == mediaID {
+			media = &product.Media[i]
+		}
+	}
+	if media == nil {
+		return repository.ErrMediaNotFound
+	}
+
+	if err := s.productRepo.RemoveMedia(product.ID, mediaID); err != nil {
+		return err
+	}
+
+	deleteFiles(s.storage, media)
+	return nil
+}
+
+func (s *MediaServiceImpl) OpenMedia(key string) (io.ReadSeekCloser, error) {
+	return s.storage.Open(key)
+}
+
+// Remove o arquivo original e as miniaturas. Falhas só são registradas, pois o
+// cadastro já não referencia os arquivos.
+func deleteFiles(store storage.Storage, media *model.Media) {
+	keys := []string{media.Key}
+	for _, thumbnail := range media.Thumbnails {
+		keys = append(keys, thumbnail.Key)
+	}
+
+	for _, key := range keys {
+		if err := store.Delete(key); err != nil {
+			log.Printf("Erro ao remover o arquivo de mídia %s: %v\n", key, err)
+		}
+	}
+}
+
+// sizeLimitReader interrompe a leitura com ErrMediaTooLarge quando o limite é ultrapassado
+type sizeLimitReader struct {
+	reader io.Reader
+	limit  int64
+	read   int64
+}
+
+func (r *sizeLimitReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	if r.read > r.limit {
+		return n, ErrMediaTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrPriceChangeNotFound = errors.New("mudança de preço não encontrada")
+	ErrPriceChangeClosed   = errors.New("a mudança de preço já foi aplicada ou cancelada")
+	ErrNoPriceAt           = errors.New("o produto não tinha preço no instante informado")
+)
+
+// Mudanças em APPLYING há mais tempo que isso são retomadas pelo agendador
+const staleApplyingAfter = 5 * time.Minute
+
+type PriceService interface {
+	SchedulePrice(productID, sku string, price float64, effectiveAt time.Time, reason, actor string) (*model.PriceChange, error)
+	ScheduleInherit(productID, sku string, effectiveAt time.Time, reason, actor string) (*model.PriceChange, error)
+	CancelPriceChange(productID, changeID string) error
+	GetPriceHistory(productID string) ([]*model.PriceChange, error)
+	GetPriceAt(productID, sku string, at time.Time) (*model.PriceAt, error)
+	RecordInitialPrice(product *model.Product) error
+	RecordVariantPrice(productID primitive.ObjectID, variant model.Variant) error
+	ApplyDueChanges() int
+	RunScheduler(interval time.Duration)
+}
+
+type PriceServiceImpl struct {
+	priceRepo   *repository.MongoPriceRepository
+	productRepo *repository.MongoProductRepository
+}
+
+func NewPriceService(priceRepo *repository.MongoPriceRepository, productRepo *repository.MongoProductRepository) PriceService {
+	return &PriceServiceImpl{
+		priceRepo:   priceRepo,
+		productRepo: productRepo,
+	}
+}
+
+// SchedulePrice registra uma mudança de preço. Sem data de vigência (ou com data
+// passada) o preço é aplicado imediatamente; com data futura, fica agendado.
+func (s *PriceServiceImpl) SchedulePrice(productID, sku string, price float64, effectiveAt time.Time, reason, actor string) (*model.PriceChange, error) {
+	if price < 0 {
+		return nil, errors.New("o preço não pode ser negativo")
+	}
+	return s.schedule(productID, sku, &price, effectiveAt, reason, actor)
+}
+
+// ScheduleInherit remove a sobrescrita de preço da variante, que volta a seguir o
+// preço do produto. A vigência funciona como em SchedulePrice.
+func (s *PriceServiceImpl) ScheduleInherit(productID, sku string, effectiveAt time.Time, reason, actor string) (*model.PriceChange, error) {
+	if sku == "" {
+		return nil, errors.New("informe o SKU da variante")
+	}
+	return s.schedule(productID, sku, nil, effectiveAt, reason, actor)
+}
+
+// Sem preço, a mudança é uma herança: registra o preço do produto como novo preço
+func (s *PriceServiceImpl) schedule(productID, sku string, price *float64, effectiveAt time.Time, reason, actor string) (*model.PriceChange, error) {
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if sku != "" && product.FindVariant(sku) == nil {
+		return nil, repository.ErrVariantNotFound
+	}
+
+	now := time.Now()
+	immediate := effectiveAt.IsZero() || !effectiveAt.After(now)
+	if immediate {
+		effectiveAt = now
+	}
+
+	change := &model.PriceChange{
+		ID:          primitive.NewObjectID(),
+		ProductID:   product.ID,
+		SKU:         sku,
+		NewPrice:    product.Price,
+		Inherit:     price == nil,
+		EffectiveAt: effectiveAt,
+		Status:      model.PriceScheduled,
+		Reason:      reason,
+		CreatedBy:   actor,
+		CreatedAt:   now,
+	}
+	if price != nil {
+		change.NewPrice = math.Round(*price*100) / 100
+	}
+
+	if err := s.priceRepo.Save(change); err != nil {
+		return nil, err
+	}
+
+	if immediate {
+		if err := s.apply(change); err != nil {
+			return nil, err
+		}
+	}
+
+	return change, nil
+}
+
+func (s *PriceServiceImpl) CancelPriceChange(productID, changeID string) error {
+	id, err := primitive.ObjectIDFromHex(changeID)
+	if err != nil {
+		return ErrPriceChangeNotFound
+	}
+
+	change, err := s.priceRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if change == nil || change.ProductID.Hex() != productID {
+		return ErrPriceChangeNotFound
+	}
+
+	canceled, err := s.priceRepo.Cancel(id)
+	if err != nil {
+		return err
+	}
+	if !canceled {
+		return ErrPriceChangeClosed
+	}
+
+	return nil
+}
+
+func (s *PriceServiceImpl) GetPriceHistory(productID string) ([]*model.PriceChange, error) {
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	return s.priceRepo.ListByProduct(product.ID)
+}
+
+// GetPriceAt responde qual era o preço no instante informado. Para variantes sem
+// mudança própria até o instante, vale o preço do produto.
+func (s *PriceServiceImpl) GetPriceAt(productID, sku string, at time.Time) (*model.PriceAt, error) {
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if sku != "" && product.FindVariant(sku) == nil {
+		return nil, repository.ErrVariantNotFound
+	}
+
+	result := &model.PriceAt{ProductID: productID, SKU: sku, At: at}
+
+	var change *model.PriceChange
+	if sku != "" {
+		if change, err = s.priceRepo.FindEffective(product.ID, sku, at); err != nil {
+			return nil, err
+		}
+		// A variante herdava o preço do produto no instante
+		if change != nil && change.Inherit {
+			change = nil
+		}
+	}
+	if change == nil {
+		if change, err = s.priceRepo.FindEffective(product.ID, "", at); err != nil {
+			return nil, err
+		}
+	}
+
+	if change != nil {
+		result.Price = change.NewPrice
+		result.Change = change
+		return result, nil
+	}
+
+	// Produtos cadastrados antes do histórico: vale o preço atual a partir do cadastro
+	if !product.AddedDate.IsZero() && at.Before(product.AddedDate) {
+		return nil, ErrNoPriceAt
+	}
+	result.Price = product.PriceFor(sku)
+	return result, nil
+}
+
+// RecordInitialPrice grava a primeira versão do preço no cadastro do produto e das
+// variantes cadastradas com preço próprio
+func (s *PriceServiceImpl) RecordInitialPrice(product *model.Product) error {
+	effectiveAt := product.AddedDate
+	if effectiveAt.IsZero() {
+		effectiveAt = time.Now()
+	}
+
+	if err := s.recordApplied(product.ID, "", product.Price, product.PriceVersion, effectiveAt, "cadastro do produto"); err != nil {
+		return err
+	}
+	for _, variant := range product.Variants {
+		if variant.Price == nil {
+			continue
+		}
+		if err := s.recordApplied(product.ID, variant.SKU, *variant.Price, product.PriceVersion, effectiveAt, "cadastro da variante"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordVariantPrice grava a primeira versão do preço de uma variante incluída
+// depois do cadastro do produto. Sem preço próprio não há o que registrar.
+func (s *PriceServiceImpl) RecordVariantPrice(productID primitive.ObjectID, variant model.Variant) error {
+	if variant.Price == nil {
+		return nil
+	}
+	return s.recordApplied(productID, variant.SKU, *variant.Price, 0, time.Now(), "cadastro da variante")
+}
+
+func (s *PriceServiceImpl) recordApplied(productID primitive.ObjectID, sku string, price float64, version int, effectiveAt time.Time, reason string) error {
+	now := time.Now()
+	return s.priceRepo.Save(&model.PriceChange{
+		ID:          primitive.NewObjectID(),
+		ProductID:   productID,
+		SKU:         sku,
+		Version:     version,
+		NewPrice:    price,
+		EffectiveAt: effectiveAt,
+		Status:      model.PriceApplied,
+		Reason:      reason,
+		CreatedAt:   now,
+		AppliedAt:   &now,
+	})
+}
+
+// Aplica a mudança ao produto e publica o evento PRODUCT_PRICE_CHANGED. A mudança
+// fica em APPLYING enquanto o produto é alterado: se a alteração falhar ela volta
+// a SCHEDULED; se falhar o registro da versão, o agendador retoma a mudança
+// reaplicando o mesmo preço.
+func (s *PriceServiceImpl) apply(change *model.PriceChange) error {
+	now := time.Now()
+	claimed, err := s.priceRepo.Claim(change.ID, now, now.Add(-staleApplyingAfter))
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrPriceChangeClosed
+	}
+
+	oldPrice, version, err := s.productRepo.ApplyPrice(change.ProductID, change.SKU, change.NewPrice, change.Inherit)
+	if err != nil {
+		if unclaimErr := s.priceRepo.Unclaim(change.ID); unclaimErr != nil {
+			log.Printf("Erro ao devolver a mudança de preço %s para o agendamento: %v\n", change.ID.Hex(), unclaimErr)
+		}
+		return fmt.Errorf("erro ao aplicar o preço %s: %w", change.ID.Hex(), err)
+	}
+
+	// A variante passa a valer o preço do produto no momento da aplicação
+	if change.Inherit {
+		product, err := s.productRepo.FindByID(change.ProductID.Hex())
+		if err != nil {
+			return fmt.Errorf("preço %s aplicado, mas não registrado no histórico: %w", change.ID.Hex(), err)
+		}
+		change.NewPrice = product.Price
+	}
+
+	change.Status = model.PriceApplied
+	change.AppliedAt = &now
+	change.OldPrice = &oldPrice
+	change.Version = version
+	if err := s.priceRepo.SetApplied(change); err != nil {
+		return fmt.Errorf("preço %s aplicado, mas não registrado no histórico: %w", change.ID.Hex(), err)
+	}
+
+	s.productRepo.PublishPriceChanged(change)
+	return nil
+}
+
+// ApplyDueChanges aplica as mudanças agendadas que já entraram em vigor
+func (s *PriceServiceImpl) ApplyDueChanges() int {
+	now := time.Now()
+	changes, err := s.priceRepo.ListDue(now, now.Add(-staleApplyingAfter))
+	if err != nil {
+		log.Printf("Erro ao buscar mudanças de preço agendadas: %v\n", err)
+		return 0
+	}
+
+	applied := 0
+	for _, change := range changes {
+		if err := s.apply(change); err != nil {
+			if !errors.Is(err, ErrPriceChangeClosed) {
+				log.Printf("Erro ao aplicar mudança de preço %s: %v\n", change.ID.Hex(), err)
+			}
+			continue
+		}
+		applied++
+	}
+
+	return applied
+}
+
+// RunScheduler aplica periodicamente as mudanças de preço agendadas. Deve ser executado em uma goroutine.
+func (s *PriceServiceImpl) RunScheduler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if n := s.ApplyDueChanges(); n > 0 {
+			log.Printf("%d mudanças de preço aplicadas\n", n)
+		}
+	}
+}
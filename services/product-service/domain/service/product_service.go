@@ -3,23 +3,46 @@ package service
 import (
 	"Varejo-Golang-Microservices/services/product-service/domain/model"
 	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+var ErrInvalidVariant = errors.New("variante inválida")
+
 type ProductService interface {
 	GetProductByID(id string) (*model.Product, error)
+	GetProductBySKU(sku string) (*model.Product, error)
 	SaveProduct(product *model.Product) error
 	UpdateProduct(product *model.Product) error
 	DeleteProduct(id string) error
 	ListAllProducts() ([]*model.Product, error)
+	ListByAttributes(filters []model.AttributeFilter) ([]*model.Product, error)
+	UpdateOptions(id string, options []model.ProductOption) (*model.Product, error)
+	AddVariant(id string, variant *model.Variant) (*model.Product, error)
+	UpdateVariant(id string, variant *model.Variant) (*model.Product, error)
+	RemoveVariant(id, sku string) (*model.Product, error)
 }
 
 type ProductServiceImpl struct {
-	productRepo *repository.MongoProductRepository
+	productRepo     *repository.MongoProductRepository
+	categoryService CategoryService
+	priceService    PriceService
+	barcodeService  BarcodeService
+	bundleService   BundleService
 }
 
-func NewProductService(productRepo *repository.MongoProductRepository) ProductService {
+func NewProductService(productRepo *repository.MongoProductRepository, categoryService CategoryService,
+	priceService PriceService, barcodeService BarcodeService, bundleService BundleService) ProductService {
 	return &ProductServiceImpl{
-		productRepo: productRepo,
+		productRepo:     productRepo,
+		categoryService: categoryService,
+		priceService:    priceService,
+		barcodeService:  barcodeService,
+		bundleService:   bundleService,
 	}
 }
 
@@ -27,18 +50,389 @@ func (s *ProductServiceImpl) GetProductByID(id string) (*model.Product, error) {
 	return s.productRepo.FindByID(id)
 }
 
+func (s *ProductServiceImpl) GetProductBySKU(sku string) (*model.Product, error) {
+	return s.productRepo.FindBySKU(sku)
+}
+
 func (s *ProductServiceImpl) SaveProduct(product *model.Product) error {
-	return s.productRepo.SaveProduct(product)
+	if product.Stock < 0 {
+		return errors.New("o estoque não pode ser negativo")
+	}
+	if err := validateVariants(product.Options, product.Variants); err != nil {
+		return err
+	}
+
+	// Kits têm estoque e, conforme a precificação, preço calculados a partir dos componentes
+	switch product.Type {
+	case model.BundleProduct:
+		if err := s.bundleService.PrepareBundle(product); err != nil {
+			return err
+		}
+	case "", model.SimpleProduct:
+		if product.Bundle != nil {
+			return fmt.Errorf("%w: apenas produtos do tipo %s têm componentes", ErrInvalidBundle, model.BundleProduct)
+		}
+	default:
+		return fmt.Errorf("tipo de produto inválido: %s", product.Type)
+	}
+
+	skus := []string{product.SKU}
+	for _, variant := range product.Variants {
+		skus = append(skus, variant.SKU)
+	}
+	if err := s.checkSKUsAvailable(skus...); err != nil {
+		return err
+	}
+
+	category, err := s.categoryService.ResolveReference(product.Category)
+	if err != nil {
+		return err
+	}
+	product.Category = category
+
+	if product.Attributes, err = s.categoryService.ValidateAttributes(category.ID, product.Attributes); err != nil {
+		return err
+	}
+
+	// Com variantes, o estoque do produto é a soma das variantes
+	if len(product.Variants) > 0 {
+		product.Stock = 0
+		for i := range product.Variants {
+			product.Variants[i].Reserved = 0
+			product.Stock += product.Variants[i].Stock
+		}
+	}
+	product.Reserved = 0
+	product.Status = model.StatusForStock(product.Status, product.Stock)
+	product.PriceVersion = 1
+
+	if err := s.claimGTINs(product); err != nil {
+		return err
+	}
+	if err := s.productRepo.SaveProduct(product); err != nil {
+		s.barcodeService.ReleaseProduct(product.ID.Hex())
+		return err
+	}
+
+	return s.priceService.RecordInitialPrice(product)
 }
 
 func (s *ProductServiceImpl) UpdateProduct(product *model.Product) error {
-	return s.productRepo.Update(product)
+	category, err := s.categoryService.ResolveReference(product.Category)
+	if err != nil {
+		return err
+	}
+	product.Category = category
+
+	current, err := s.productRepo.FindByID(product.ID.Hex())
+	if err != nil {
+		return err
+	}
+
+	// Sem atributos no corpo, os atuais são mantidos e revalidados, pois a categoria pode ter mudado
+	attributes := product.Attributes
+	if attributes == nil {
+		attributes = current.Attributes
+	}
+	if product.Attributes, err = s.categoryService.ValidateAttributes(category.ID, attributes); err != nil {
+		return err
+	}
+
+	// Um GTIN novo é registrado antes da gravação e o anterior liberado depois
+	gtinChanged := false
+	if product.GTIN != "" {
+		if product.GTIN, err = model.NormalizeGTIN(product.GTIN); err != nil {
+			return err
+		}
+		if product.GTIN != current.GTIN {
+			if _, err := s.barcodeService.ClaimGTIN(current.ID.Hex(), "", product.GTIN); err != nil {
+				return err
+			}
+			gtinChanged = true
+		}
+	}
+
+	if err := s.productRepo.Update(product); err != nil {
+		if gtinChanged {
+			s.barcodeService.ReleaseGTIN(current.ID.Hex(), "", product.GTIN)
+		}
+		return err
+	}
+	if gtinChanged && current.GTIN != "" {
+		s.barcodeService.ReleaseGTIN(current.ID.Hex(), "", current.GTIN)
+	}
+
+	// O preço de kits precificados pelos componentes é recalculado pelo serviço de kits
+	if current.IsBundle() && current.Bundle != nil && current.Bundle.PricingMode == model.BundleComponentsDiscount {
+		product.Price = current.Price
+	}
+
+	// O preço muda pelo histórico, para que a alteração fique registrada
+	if current.Price != product.Price {
+		_, err = s.priceService.SchedulePrice(product.ID.Hex(), "", product.Price, time.Time{}, "atualização do produto", "")
+	}
+	return err
 }
 
 func (s *ProductServiceImpl) DeleteProduct(id string) error {
-	return s.productRepo.Delete(id)
+	// Um componente não pode sumir de kits ativos; o kit deve ser alterado antes
+	if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+		bundles, err := s.productRepo.ListBundles(&objID)
+		if err != nil {
+			return err
+		}
+		if len(bundles) > 0 {
+			return fmt.Errorf("%w: o produto é componente do kit %s", ErrInvalidBundle, bundles[0].ID.Hex())
+		}
+	}
+
+	if err := s.productRepo.Delete(id); err != nil {
+		return err
+	}
+
+	s.barcodeService.ReleaseProduct(id)
+	return nil
 }
 
 func (s *ProductServiceImpl) ListAllProducts() ([]*model.Product, error) {
 	return s.productRepo.ListAll()
 }
+
+func (s *ProductServiceImpl) ListByAttributes(filters []model.AttributeFilter) ([]*model.Product, error) {
+	return s.productRepo.ListByAttributes(filters)
+}
+
+// UpdateOptions substitui as opções do produto, desde que as variantes existentes continuem válidas
+func (s *ProductServiceImpl) UpdateOptions(id string, options []model.ProductOption) (*model.Product, error) {
+	product, err := s.productRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateVariants(options, product.Variants); err != nil {
+		return nil, err
+	}
+
+	if err := s.productRepo.UpdateOptions(product.ID, options); err != nil {
+		return nil, err
+	}
+
+	return s.productRepo.FindByID(id)
+}
+
+func (s *ProductServiceImpl) AddVariant(id string, variant *model.Variant) (*model.Product, error) {
+	product, err := s.productRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if product.IsBundle() {
+		return nil, fmt.Errorf("%w: kits não têm variantes", ErrInvalidBundle)
+	}
+	if len(product.Variants) == 0 && (product.Stock > 0 || product.Reserved > 0) {
+		return nil, fmt.Errorf("%w: zere o estoque do produto antes de cadastrar variantes", ErrInvalidVariant)
+	}
+
+	variant.Reserved = 0
+	if err := validateVariants(product.Options, append(product.Variants, *variant)); err != nil {
+		return nil, err
+	}
+	if err := s.checkSKUsAvailable(variant.SKU); err != nil {
+		return nil, err
+	}
+
+	if variant.GTIN, err = s.barcodeService.ClaimGTIN(id, variant.SKU, variant.GTIN); err != nil {
+		return nil, err
+	}
+	if err := s.productRepo.AddVariant(product.ID, variant); err != nil {
+		if variant.GTIN != "" {
+			s.barcodeService.ReleaseGTIN(id, variant.SKU, variant.GTIN)
+		}
+		return nil, err
+	}
+	if err := s.priceService.RecordVariantPrice(product.ID, *variant); err != nil {
+		return nil, err
+	}
+
+	return s.productRepo.FindByID(id)
+}
+
+func (s *ProductServiceImpl) UpdateVariant(id string, variant *model.Variant) (*model.Product, error) {
+	product, err := s.productRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	current := product.FindVariant(variant.SKU)
+	if current == nil {
+		return nil, repository.ErrVariantNotFound
+	}
+
+	// Como na atualização do produto, um GTIN omitido mantém o atual
+	previousGTIN := current.GTIN
+	if variant.GTIN == "" {
+		variant.GTIN = previousGTIN
+	} else if variant.GTIN, err = model.NormalizeGTIN(variant.GTIN); err != nil {
+		return nil, err
+	}
+	gtinChanged := variant.GTIN != previousGTIN
+
+	*current = model.Variant{
+		SKU:      variant.SKU,
+		Options:  variant.Options,
+		GTIN:     variant.GTIN,
+		Price:    variant.Price,
+		Stock:    current.Stock,
+		Reserved: current.Reserved,
+		Images:   variant.Images,
+	}
+
+	if err := validateVariants(product.Options, product.Variants); err != nil {
+		return nil, err
+	}
+
+	if gtinChanged && variant.GTIN != "" {
+		if _, err := s.barcodeService.ClaimGTIN(id, variant.SKU, variant.GTIN); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.productRepo.UpdateVariant(product.ID, current); err != nil {
+		if gtinChanged && variant.GTIN != "" {
+			s.barcodeService.ReleaseGTIN(id, variant.SKU, variant.GTIN)
+		}
+		return nil, err
+	}
+	if gtinChanged && previousGTIN != "" {
+		s.barcodeService.ReleaseGTIN(id, variant.SKU, previousGTIN)
+	}
+
+	if variant.Price != nil && product.PriceFor(variant.SKU) != *variant.Price {
+		if _, err := s.priceService.SchedulePrice(id, variant.SKU, *variant.Price, time.Time{}, "atualização da variante", ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.productRepo.FindByID(id)
+}
+
+func (s *ProductServiceImpl) RemoveVariant(id, sku string) (*model.Product, error) {
+	product, err := s.productRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	variant := product.FindVariant(sku)
+	if variant == nil {
+		return nil, repository.ErrVariantNotFound
+	}
+
+	if err := s.productRepo.RemoveVariant(product.ID, variant); err != nil {
+		return nil, err
+	}
+	if variant.GTIN != "" {
+		s.barcodeService.ReleaseGTIN(id, sku, variant.GTIN)
+	}
+
+	return s.productRepo.FindByID(id)
+}
+
+// Registra os GTINs do produto e das variantes, gravando-os normalizados. Se algum
+// já pertencer a outro item, libera os que chegaram a ser registrados.
+func (s *ProductServiceImpl) claimGTINs(product *model.Product) error {
+	productID := product.ID.Hex()
+
+	gtin, err := s.barcodeService.ClaimGTIN(productID, "", product.GTIN)
+	if err != nil {
+		return err
+	}
+	product.GTIN = gtin
+
+	for i := range product.Variants {
+		variant := &product.Variants[i]
+		if variant.GTIN, err = s.barcodeService.ClaimGTIN(productID, variant.SKU, variant.GTIN); err != nil {
+			s.barcodeService.ReleaseProduct(productID)
+			return err
+		}
+	}
+	return nil
+}
+
+// O índice único cobre produtos e variantes separadamente; aqui garantimos
+// que um SKU novo também não colide com o do outro tipo.
+func (s *ProductServiceImpl) checkSKUsAvailable(skus ...string) error {
+	for _, sku := range skus {
+		if sku == "" {
+			continue
+		}
+		_, err := s.productRepo.FindBySKU(sku)
+		if err == nil {
+			return fmt.Errorf("%w: %s", repository.ErrDuplicateSKU, sku)
+		}
+		if !errors.Is(err, repository.ErrVariantNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Valida que cada variante tem um SKU único, um valor permitido para cada opção
+// do produto e uma combinação de opções que não se repete.
+func validateVariants(options []model.ProductOption, variants []model.Variant) error {
+	allowed := make(map[string]map[string]bool, len(options))
+	for _, option := range options {
+		name := strings.TrimSpace(option.Name)
+		if name == "" || len(option.Values) == 0 {
+			return fmt.Errorf("%w: toda opção precisa de nome e valores", ErrInvalidVariant)
+		}
+		if _, ok := allowed[name]; ok {
+			return fmt.Errorf("%w: opção %s repetida", ErrInvalidVariant, name)
+		}
+		allowed[name] = make(map[string]bool, len(option.Values))
+		for _, value := range option.Values {
+			allowed[name][value] = true
+		}
+	}
+
+	if len(variants) > 0 && len(options) == 0 {
+		return fmt.Errorf("%w: cadastre as opções do produto antes das variantes", ErrInvalidVariant)
+	}
+
+	skus := make(map[string]bool, len(variants))
+	combinations := make(map[string]string, len(variants))
+	for _, variant := range variants {
+		if strings.TrimSpace(variant.SKU) == "" {
+			return fmt.Errorf("%w: o SKU é obrigatório", ErrInvalidVariant)
+		}
+		if skus[variant.SKU] {
+			return fmt.Errorf("%w: SKU %s repetido", ErrInvalidVariant, variant.SKU)
+		}
+		skus[variant.SKU] = true
+
+		if variant.Stock < 0 {
+			return fmt.Errorf("%w: estoque negativo na variante %s", ErrInvalidVariant, variant.SKU)
+		}
+		if variant.Price != nil && *variant.Price < 0 {
+			return fmt.Errorf("%w: preço negativo na variante %s", ErrInvalidVariant, variant.SKU)
+		}
+
+		if len(variant.Options) != len(allowed) {
+			return fmt.Errorf("%w: a variante %s deve informar um valor para cada opção do produto", ErrInvalidVariant, variant.SKU)
+		}
+		for name, value := range variant.Options {
+			values, ok := allowed[name]
+			if !ok {
+				return fmt.Errorf("%w: opção %s não existe no produto", ErrInvalidVariant, name)
+			}
+			if !values[value] {
+				return fmt.Errorf("%w: valor %s não permitido para a opção %s", ErrInvalidVariant, value, name)
+			}
+		}
+
+		key := model.OptionKey(variant.Options)
+		if other, ok := combinations[key]; ok {
+			return fmt.Errorf("%w: as variantes %s e %s têm a mesma combinação de opções", ErrInvalidVariant, other, variant.SKU)
+		}
+		combinations[key] = variant.SKU
+	}
+
+	return nil
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"Varejo-Golang-Microservices/services/product-service/infra/event"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tópico em que o serviço de pedidos publica os pedidos criados
+const OrderTopic = "Order_Topic_One"
+
+const (
+	// Meia-vida padrão do peso de um pedido: um pedido de 90 dias atrás vale metade de um de hoje
+	DefaultRecommendationHalfLife = 90 * 24 * time.Hour
+	// Pares com menos pedidos recentes que isso não são recomendados
+	MinPairOrders = 2.0
+	// Pedidos com mais produtos distintos são truncados, para limitar a quantidade de pares
+	MaxProductsPerOrder    = 50
+	DefaultRecommendations = 10
+)
+
+// Instante de referência da escala de pesos
+var recommendationEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var ErrRecomputeRunning = errors.New("já existe um recálculo de recomendações em andamento")
+
+type RecommendationService interface {
+	GetRecommendations(productID string, limit int) ([]model.Recommendation, error)
+	RecordOrder(order *model.PurchasedOrder) error
+	CancelOrder(orderID string) error
+	StartRecompute() error
+	ListenOrderEvents(kafkaBroker string)
+}
+
+type RecommendationServiceImpl struct {
+	recommendationRepo *repository.MongoRecommendationRepository
+	productRepo        *repository.MongoProductRepository
+	halfLife           time.Duration
+	// Bloqueia o consumo de eventos enquanto o recálculo troca as coleções
+	mu        sync.Mutex
+	computing bool
+}
+
+func NewRecommendationService(recommendationRepo *repository.MongoRecommendationRepository, productRepo *repository.MongoProductRepository,
+	halfLife time.Duration) RecommendationService {
+	if halfLife <= 0 {
+		halfLife = DefaultRecommendationHalfLife
+	}
+	return &RecommendationServiceImpl{
+		recommendationRepo: recommendationRepo,
+		productRepo:        productRepo,
+		halfLife:           halfLife,
+	}
+}
+
+// Peso de um pedido feito no instante at, na escala comum: dobra a cada meia-vida
+// a partir da referência, o que equivale a decair os pedidos antigos
+func (s *RecommendationServiceImpl) weight(at time.Time) float64 {
+	return math.Exp2(float64(at.Sub(recommendationEpoch)) / float64(s.halfLife))
+}
+
+// GetRecommendations retorna os produtos comprados junto com o produto, ordenados
+// pela confiança (fração dos pedidos do produto que também têm o recomendado).
+// Apenas pares com lift acima de 1 entram, e produtos sem estoque ou descontinuados
+// são descartados.
+func (s *RecommendationServiceImpl) GetRecommendations(productID string, limit int) ([]model.Recommendation, error) {
+	if _, err := s.productRepo.FindByID(productID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 50 {
+		limit = DefaultRecommendations
+	}
+
+	now := s.weight(time.Now())
+	pairs, err := s.recommendationRepo.ListPairs(productID, MinPairOrders*now, 200)
+	if err != nil {
+		return nil, err
+	}
+	if len(pairs) == 0 {
+		return []model.Recommendation{}, nil
+	}
+
+	total, err := s.recommendationRepo.TotalWeight()
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{productID}
+	for _, pair := range pairs {
+		ids = append(ids, otherProduct(pair, productID))
+	}
+	items, err := s.recommendationRepo.ItemWeights(ids)
+	if err != nil {
+		return nil, err
+	}
+	products, err := s.loadProducts(ids[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations := []model.Recommendation{}
+	for _, pair := range pairs {
+		otherID := otherProduct(pair, productID)
+		product := products[otherID]
+		if product == nil || product.Status != model.Available || product.AvailableFor("") <= 0 {
+			continue
+		}
+		if items[productID] == 0 || items[otherID] == 0 || total == 0 {
+			continue
+		}
+
+		lift := pair.Weight * total / (items[productID] * items[otherID])
+		if lift <= 1 {
+			continue
+		}
+		recommendations = append(recommendations, model.Recommendation{
+			Product:    product,
+			Orders:     roundTo(pair.Weight/now, 2),
+			Support:    roundTo(pair.Weight/total, 4),
+			Confidence: roundTo(pair.Weight/items[productID], 4),
+			Lift:       roundTo(lift, 2),
+		})
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].Confidence != recommendations[j].Confidence {
+			return recommendations[i].Confidence > recommendations[j].Confidence
+		}
+		return recommendations[i].Lift > recommendations[j].Lift
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations, nil
+}
+
+func otherProduct(pair *model.CoPurchasePair, productID string) string {
+	if pair.A == productID {
+		return pair.B
+	}
+	return pair.A
+}
+
+func roundTo(value float64, decimals int) float64 {
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}
+
+func (s *RecommendationServiceImpl) loadProducts(ids []string) (map[string]*model.Product, error) {
+	var objIDs []primitive.ObjectID
+	for _, id := range ids {
+		if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objIDs = append(objIDs, objID)
+		}
+	}
+
+	list, err := s.productRepo.ListByIDs(objIDs)
+	if err != nil {
+		return nil, err
+	}
+	products := make(map[string]*model.Product, len(list))
+	for _, product := range list {
+		products[product.ID.Hex()] = product
+	}
+	return products, nil
+}
+
+// RecordOrder contabiliza um pedido uma única vez, mesmo que o evento seja reentregue.
+// Os pesos são somados antes de o pedido ser marcado: se a marcação falhar, a
+// reentrega conta o pedido de novo, o que é preferível a perdê-lo.
+func (s *RecommendationServiceImpl) RecordOrder(order *model.PurchasedOrder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	processed, err := s.recommendationRepo.FindProcessed(order.ID)
+	if err != nil || processed != nil {
+		return err
+	}
+
+	recorded := &model.ProcessedOrder{
+		ID:         order.ID,
+		ProductIDs: distinctProducts(order.ProductIDs),
+		Weight:     s.weight(orderTime(order)),
+	}
+	if err := s.recommendationRepo.Record(recorded.ProductIDs, recorded.Weight); err != nil {
+		return err
+	}
+	return s.recommendationRepo.MarkProcessed(recorded)
+}
+
+// CancelOrder subtrai o peso que o pedido somou, na mesma ordem de RecordOrder.
+// Pedidos contabilizados antes de os produtos serem guardados no registro não têm o
+// que subtrair; o recálculo os descarta.
+func (s *RecommendationServiceImpl) CancelOrder(orderID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	processed, err := s.recommendationRepo.FindProcessed(orderID)
+	if err != nil {
+		return err
+	}
+	if processed != nil && processed.Canceled {
+		return nil
+	}
+	if processed != nil && len(processed.ProductIDs) > 0 {
+		if err := s.recommendationRepo.Record(processed.ProductIDs, -processed.Weight); err != nil {
+			return err
+		}
+	}
+	return s.recommendationRepo.MarkCanceled(orderID)
+}
+
+func orderTime(order *model.PurchasedOrder) time.Time {
+	if order.OrderDate.IsZero() || order.OrderDate.After(time.Now()) {
+		return time.Now()
+	}
+	return order.OrderDate
+}
+
+func distinctProducts(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	var distinct []string
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		distinct = append(distinct, id)
+		if len(distinct) == MaxProductsPerOrder {
+			break
+		}
+	}
+	return distinct
+}
+
+// StartRecompute recalcula as estatísticas a partir da coleção de pedidos em segundo plano
+func (s *RecommendationServiceImpl) StartRecompute() error {
+	s.mu.Lock()
+	if s.computing {
+		s.mu.Unlock()
+		return ErrRecomputeRunning
+	}
+	s.computing = true
+	s.mu.Unlock()
+
+	go func() {
+		started := time.Now()
+		orders, err := s.recompute()
+		if err != nil {
+			log.Printf("Erro ao recalcular as recomendações: %v\n", err)
+			return
+		}
+		log.Printf("Recomendações recalculadas a partir de %d pedidos em %s\n", orders, time.Since(started).Round(time.Second))
+	}()
+	return nil
+}
+
+func (s *RecommendationServiceImpl) recompute() (int, error) {
+	// Os eventos que chegam durante o recálculo esperam a troca das coleções; os
+	// pedidos que eles trazem já estão na coleção lida ou são contabilizados depois
+	s.mu.Lock()
+	defer func() {
+		s.computing = false
+		s.mu.Unlock()
+	}()
+
+	orders := 0
+	err := s.recommendationRepo.Rebuild(func(record func(orderID string, productIDs []string, weight float64) error) error {
+		return s.recommendationRepo.EachOrder(func(order *model.PurchasedOrder) error {
+			orders++
+			return record(order.ID, distinctProducts(order.ProductIDs), s.weight(orderTime(order)))
+		})
+	})
+	return orders, err
+}
+
+// Formato do pedido publicado pelo serviço de pedidos; apenas os campos usados aqui
+type orderEvent struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Products []struct {
+		ProductID string `json:"productId"`
+	} `json:"products"`
+	OrderDate time.Time `json:"orderDate"`
+}
+
+// ListenOrderEvents atualiza as estatísticas a cada pedido criado ou cancelado
+func (s *RecommendationServiceImpl) ListenOrderEvents(kafkaBroker string) {
+	messages := make(chan string)
+	go func() {
+		if err := event.ConsumeMessage(kafkaBroker, OrderTopic, "product-recommendations", messages); err != nil {
+			log.Printf("Erro ao consumir o tópico %s: %v\n", OrderTopic, err)
+		}
+	}()
+
+	for message := range messages {
+		var orderMessage orderEvent
+		if err := json.Unmarshal([]byte(message), &orderMessage); err != nil {
+			log.Printf("Erro ao ler evento de pedido: %v\n", err)
+			continue
+		}
+		if orderMessage.ID == "" {
+			continue
+		}
+		if orderMessage.Status == "CANCELED" {
+			if err := s.CancelOrder(orderMessage.ID); err != nil {
+				log.Printf("Erro ao retirar o pedido cancelado %s das recomendações: %v\n", orderMessage.ID, err)
+			}
+			continue
+		}
+
+		order := &model.PurchasedOrder{ID: orderMessage.ID, OrderDate: orderMessage.OrderDate}
+		for _, product := range orderMessage.Products {
+			order.ProductIDs = append(order.ProductIDs, product.ProductID)
+		}
+		if err := s.RecordOrder(order); err != nil {
+			log.Printf("Erro ao contabilizar o pedido %s nas recomendações: %v\n", order.ID, err)
+		}
+	}
+}
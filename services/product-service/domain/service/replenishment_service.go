@@ -0,0 +1,473 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"Varejo-Golang-Microservices/services/product-service/infra/event"
+	"Varejo-Golang-Microservices/services/product-service/infra/locations"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Janela de vendas usada no cálculo da velocidade
+	DefaultVelocityWindow = 28 * 24 * time.Hour
+	// Dias de demanda cobertos pelo pedido sugerido quando a política não define estoque máximo
+	DefaultCoverageDays = 30
+)
+
+var ErrInvalidPolicy = errors.New("política de reposição inválida")
+
+type ReplenishmentService interface {
+	SetPolicy(policy *model.ReplenishmentPolicy) (*model.ReplenishmentSuggestion, error)
+	ListPolicies(productID string, locationID *string) ([]*model.ReplenishmentPolicy, error)
+	DeletePolicy(productID, sku, locationID string) error
+	GetReport(locationID *string, onlyBelow bool) ([]*model.ReplenishmentSuggestion, error)
+	ListenOrderEvents(kafkaBroker string)
+	ListenProductEvents(kafkaBroker string)
+	RunMonitor(interval time.Duration)
+}
+
+type ReplenishmentServiceImpl struct {
+	replenishmentRepo *repository.MongoReplenishmentRepository
+	productRepo       *repository.MongoProductRepository
+	inventory         locations.Inventory
+}
+
+func NewReplenishmentService(replenishmentRepo *repository.MongoReplenishmentRepository, productRepo *repository.MongoProductRepository, inventory locations.Inventory) ReplenishmentService {
+	return &ReplenishmentServiceImpl{
+		replenishmentRepo: replenishmentRepo,
+		productRepo:       productRepo,
+		inventory:         inventory,
+	}
+}
+
+// SetPolicy cria ou altera a política e já avalia o estoque atual, publicando o
+// alerta se ele estiver no ponto de pedido
+func (s *ReplenishmentServiceImpl) SetPolicy(policy *model.ReplenishmentPolicy) (*model.ReplenishmentSuggestion, error) {
+	if policy.ReorderPoint < 0 || policy.SafetyStock < 0 || policy.LeadTimeDays < 0 || policy.MinQuantity < 0 || policy.MaxQuantity < 0 {
+		return nil, ErrInvalidPolicy
+	}
+	if policy.MaxQuantity > 0 && policy.MaxQuantity < policy.MinQuantity {
+		return nil, ErrInvalidPolicy
+	}
+
+	product, err := s.productRepo.FindByID(policy.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	// O kit não tem estoque próprio; a reposição é feita pelos componentes
+	if product.IsBundle() {
+		return nil, ErrInvalidPolicy
+	}
+	if policy.SKU != "" && product.FindVariant(policy.SKU) == nil {
+		return nil, ErrInvalidVariant
+	}
+	if policy.SKU == "" && len(product.Variants) > 0 {
+		return nil, ErrInvalidVariant
+	}
+
+	policy.ID = model.PolicyID(policy.ProductID, policy.SKU, policy.LocationID)
+	if err := s.replenishmentRepo.SavePolicy(policy); err != nil {
+		return nil, err
+	}
+	saved, err := s.replenishmentRepo.FindPolicy(policy.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions, err := s.evaluate([]*model.ReplenishmentPolicy{saved}, map[string]*model.Product{policy.ProductID: product})
+	if err != nil {
+		return nil, err
+	}
+	return suggestions[0], nil
+}
+
+func (s *ReplenishmentServiceImpl) ListPolicies(productID string, locationID *string) ([]*model.ReplenishmentPolicy, error) {
+	return s.replenishmentRepo.ListPolicies(productID, locationID)
+}
+
+func (s *ReplenishmentServiceImpl) DeletePolicy(productID, sku, locationID string) error {
+	return s.replenishmentRepo.DeletePolicy(model.PolicyID(productID, sku, locationID))
+}
+
+// GetReport calcula a sugestão de compra de cada política, com os itens abaixo do
+// ponto de pedido primeiro e, entre eles, os de menor cobertura. O relatório só lê:
+// o estado dos alertas é mantido pelo monitor e pelos eventos.
+func (s *ReplenishmentServiceImpl) GetReport(locationID *string, onlyBelow bool) ([]*model.ReplenishmentSuggestion, error) {
+	policies, err := s.replenishmentRepo.ListPolicies("", locationID)
+	if err != nil {
+		return nil, err
+	}
+	products, err := s.loadPolicyProducts(policies)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions, err := s.suggestions(policies, products)
+	if err != nil {
+		return nil, err
+	}
+
+	report := make([]*model.ReplenishmentSuggestion, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		if onlyBelow && !suggestion.BelowReorderPoint {
+			continue
+		}
+		report = append(report, suggestion)
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		if report[i].BelowReorderPoint != report[j].BelowReorderPoint {
+			return report[i].BelowReorderPoint
+		}
+		return coverOf(report[i]) < coverOf(report[j])
+	})
+	return report, nil
+}
+
+// Itens sem vendas ficam depois dos que têm cobertura calculada
+func coverOf(suggestion *model.ReplenishmentSuggestion) float64 {
+	if suggestion.DaysOfCover == nil {
+		return math.Inf(1)
+	}
+	return *suggestion.DaysOfCover
+}
+
+func (s *ReplenishmentServiceImpl) loadPolicyProducts(policies []*model.ReplenishmentPolicy) (map[string]*model.Product, error) {
+	seen := make(map[string]bool)
+	var objIDs []primitive.ObjectID
+	for _, policy := range policies {
+		if seen[policy.ProductID] {
+			continue
+		}
+		seen[policy.ProductID] = true
+		if objID, err := primitive.ObjectIDFromHex(policy.ProductID); err == nil {
+			objIDs = append(objIDs, objID)
+		}
+	}
+	if len(objIDs) == 0 {
+		return map[string]*model.Product{}, nil
+	}
+
+	list, err := s.productRepo.ListByIDs(objIDs)
+	if err != nil {
+		return nil, err
+	}
+	products := make(map[string]*model.Product, len(list))
+	for _, product := range list {
+		products[product.ID.Hex()] = product
+	}
+	return products, nil
+}
+
+// evaluate calcula as sugestões das políticas e atualiza o estado do alerta de cada
+// uma, publicando LowStock apenas quando o estoque cruza o ponto de pedido
+func (s *ReplenishmentServiceImpl) evaluate(policies []*model.ReplenishmentPolicy, products map[string]*model.Product) ([]*model.ReplenishmentSuggestion, error) {
+	suggestions, err := s.suggestions(policies, products)
+	if err != nil {
+		return nil, err
+	}
+	for i, policy := range policies {
+		s.updateAlert(policy, suggestions[i])
+	}
+	return suggestions, nil
+}
+
+// suggestions calcula a sugestão de cada política, na mesma ordem. O estoque das
+// políticas sem localização vem do catálogo; o das demais, do serviço de localizações.
+func (s *ReplenishmentServiceImpl) suggestions(policies []*model.ReplenishmentPolicy, products map[string]*model.Product) ([]*model.ReplenishmentSuggestion, error) {
+	since := time.Now().Add(-DefaultVelocityWindow)
+	productIDs := make([]string, 0, len(policies))
+	var locationProducts []string
+	seen, seenLocation := make(map[string]bool), make(map[string]bool)
+	for _, policy := range policies {
+		if !seen[policy.ProductID] {
+			seen[policy.ProductID] = true
+			productIDs = append(productIDs, policy.ProductID)
+		}
+		if policy.LocationID != "" && !seenLocation[policy.ProductID] {
+			seenLocation[policy.ProductID] = true
+			locationProducts = append(locationProducts, policy.ProductID)
+		}
+	}
+
+	sales, err := s.replenishmentRepo.SalesSince(productIDs, since)
+	if err != nil {
+		return nil, err
+	}
+	stock, locationSales, err := s.locationStock(locationProducts, since)
+	if err != nil {
+		return nil, err
+	}
+	// Vendas de cada produto e SKU somadas em todas as localizações
+	salesByItem := make(map[string]int)
+	for _, sale := range locationSales {
+		salesByItem[model.PolicyID(sale.ProductID, sale.SKU, "")] -= sale.Quantity
+	}
+	salesByPolicy := make(map[string]int)
+	for _, sale := range locationSales {
+		salesByPolicy[model.PolicyID(sale.ProductID, sale.SKU, sale.LocationID)] -= sale.Quantity
+	}
+
+	windowDays := DefaultVelocityWindow.Hours() / 24
+	suggestions := make([]*model.ReplenishmentSuggestion, 0, len(policies))
+	for _, policy := range policies {
+		velocity := float64(sales[[2]string{policy.ProductID, policy.SKU}]) / windowDays
+
+		var current model.LocationStock
+		if policy.LocationID == "" {
+			if product := products[policy.ProductID]; product != nil {
+				current.Available = product.AvailableFor(policy.SKU)
+			}
+		} else {
+			current = stock[policy.ID]
+			// A demanda da localização é proporcional à sua parte nas vendas lançadas no
+			// livro de estoque; sem esse histórico, vale a demanda total
+			if total := salesByItem[model.PolicyID(policy.ProductID, policy.SKU, "")]; total > 0 {
+				velocity *= float64(salesByPolicy[policy.ID]) / float64(total)
+			}
+		}
+
+		suggestions = append(suggestions, suggest(policy, current, velocity))
+	}
+	return suggestions, nil
+}
+
+// Consulta no serviço de localizações o estoque dos produtos, pela chave
+// produto|SKU|localização, e as vendas lançadas no livro desde since
+func (s *ReplenishmentServiceImpl) locationStock(productIDs []string, since time.Time) (map[string]model.LocationStock, []locations.Movement, error) {
+	stock := make(map[string]model.LocationStock)
+	var sales []locations.Movement
+	for _, productID := range productIDs {
+		records, err := s.inventory.ProductStock(productID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, record := range records {
+			stock[model.PolicyID(record.ProductID, record.SKU, record.LocationID)] = model.LocationStock{
+				Available: record.Available,
+				InTransit: record.InTransit,
+			}
+		}
+
+		productSales, err := s.inventory.Sales(productID, since)
+		if err != nil {
+			return nil, nil, err
+		}
+		sales = append(sales, productSales...)
+	}
+	return stock, sales, nil
+}
+
+// suggest aplica a política mínimo/máximo: no ponto de pedido ou abaixo, o pedido
+// repõe até o estoque máximo (ou a demanda do prazo de entrega mais a cobertura
+// padrão), descontando o que já está em trânsito e respeitando o lote mínimo
+func suggest(policy *model.ReplenishmentPolicy, stock model.LocationStock, velocity float64) *model.ReplenishmentSuggestion {
+	leadTimeDemand := velocity * float64(policy.LeadTimeDays)
+	reorderPoint := policy.ReorderPoint
+	if reorderPoint == 0 {
+		reorderPoint = int(math.Ceil(leadTimeDemand)) + policy.SafetyStock
+	}
+
+	suggestion := &model.ReplenishmentSuggestion{
+		Policy:         policy,
+		Available:      stock.Available,
+		InTransit:      stock.InTransit,
+		DailyVelocity:  roundTo(velocity, 2),
+		LeadTimeDemand: roundTo(leadTimeDemand, 2),
+		ReorderPoint:   reorderPoint,
+	}
+	if velocity > 0 {
+		cover := roundTo(float64(stock.Available)/velocity, 1)
+		suggestion.DaysOfCover = &cover
+	}
+
+	suggestion.BelowReorderPoint = stock.Available <= reorderPoint
+	if !suggestion.BelowReorderPoint || stock.Available+stock.InTransit > reorderPoint {
+		return suggestion
+	}
+
+	target := policy.MaxQuantity
+	if target == 0 {
+		target = int(math.Ceil(velocity*float64(policy.LeadTimeDays+DefaultCoverageDays))) + policy.SafetyStock
+	}
+	quantity := target - stock.Available - stock.InTransit
+	if quantity <= 0 {
+		return suggestion
+	}
+	if quantity < policy.MinQuantity {
+		quantity = policy.MinQuantity
+	}
+	suggestion.SuggestedQuantity = quantity
+	return suggestion
+}
+
+// Grava o estado do alerta; só a instância que efetivamente muda o estado publica o evento
+func (s *ReplenishmentServiceImpl) updateAlert(policy *model.ReplenishmentPolicy, suggestion *model.ReplenishmentSuggestion) {
+	if policy.Low == suggestion.BelowReorderPoint {
+		return
+	}
+
+	changed, err := s.replenishmentRepo.SetLow(policy.ID, suggestion.BelowReorderPoint)
+	if err != nil {
+		log.Printf("Erro ao atualizar o alerta de estoque da política %s: %v\n", policy.ID, err)
+		return
+	}
+	policy.Low = suggestion.BelowReorderPoint
+	if !changed || !suggestion.BelowReorderPoint {
+		return
+	}
+
+	lowStock := &model.LowStockEvent{
+		ProductID:         policy.ProductID,
+		SKU:               policy.SKU,
+		LocationID:        policy.LocationID,
+		Available:         suggestion.Available,
+		ReorderPoint:      suggestion.ReorderPoint,
+		SafetyStock:       policy.SafetyStock,
+		SuggestedQuantity: suggestion.SuggestedQuantity,
+		OccurredAt:        time.Now(),
+	}
+	if err := s.productRepo.PublishLowStock(lowStock); err != nil {
+		log.Printf("Erro ao publicar o alerta de estoque baixo da política %s: %v\n", policy.ID, err)
+	}
+}
+
+// Formato do pedido publicado pelo serviço de pedidos, com as quantidades vendidas
+type salesOrderEvent struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Products []struct {
+		ProductID  string `json:"productId"`
+		SKU        string `json:"sku"`
+		Quantity   int    `json:"quantity"`
+		Components []struct {
+			ProductID string `json:"productId"`
+			SKU       string `json:"sku"`
+			Quantity  int    `json:"quantity"`
+		} `json:"components"`
+	} `json:"products"`
+	OrderDate time.Time `json:"orderDate"`
+}
+
+// ListenOrderEvents acumula as vendas diárias usadas no cálculo da velocidade. Os
+// kits contam as unidades dos componentes, que são os itens com estoque.
+func (s *ReplenishmentServiceImpl) ListenOrderEvents(kafkaBroker string) {
+	messages := make(chan string)
+	go func() {
+		if err := event.ConsumeMessage(kafkaBroker, OrderTopic, "product-replenishment", messages); err != nil {
+			log.Printf("Erro ao consumir o tópico %s: %v\n", OrderTopic, err)
+		}
+	}()
+
+	for message := range messages {
+		var order salesOrderEvent
+		if err := json.Unmarshal([]byte(message), &order); err != nil {
+			log.Printf("Erro ao ler evento de pedido: %v\n", err)
+			continue
+		}
+		if order.ID == "" || order.Status == "CANCELED" {
+			continue
+		}
+
+		quantities := make(map[[2]string]int)
+		for _, product := range order.Products {
+			if len(product.Components) > 0 {
+				for _, component := range product.Components {
+					quantities[[2]string{component.ProductID, component.SKU}] += component.Quantity
+				}
+				continue
+			}
+			quantities[[2]string{product.ProductID, product.SKU}] += product.Quantity
+		}
+
+		// As vendas são somadas antes de o pedido ser marcado; uma repetição depois de
+		// falha na marcação é reconhecida pelo próprio dia de vendas
+		processed, err := s.replenishmentRepo.IsOrderProcessed(order.ID)
+		if err != nil {
+			log.Printf("Erro ao consultar o pedido %s nas vendas: %v\n", order.ID, err)
+			continue
+		}
+		if processed {
+			continue
+		}
+		day := order.OrderDate
+		if day.IsZero() || day.After(time.Now()) {
+			day = time.Now()
+		}
+		if err := s.replenishmentRepo.RecordSales(order.ID, day, quantities); err != nil {
+			log.Printf("Erro ao contabilizar as vendas do pedido %s: %v\n", order.ID, err)
+			continue
+		}
+		if err := s.replenishmentRepo.MarkOrderProcessed(order.ID); err != nil {
+			log.Printf("Erro ao registrar o pedido %s nas vendas: %v\n", order.ID, err)
+		}
+	}
+}
+
+// ListenProductEvents reavalia as políticas do estoque do catálogo a cada alteração
+// do produto, para que o alerta saia assim que o estoque cruzar o ponto de pedido
+func (s *ReplenishmentServiceImpl) ListenProductEvents(kafkaBroker string) {
+	messages := make(chan string)
+	go func() {
+		if err := event.ConsumeMessage(kafkaBroker, repository.ProductEventTopic, "product-replenishment-stock", messages); err != nil {
+			log.Printf("Erro ao consumir o tópico %s: %v\n", repository.ProductEventTopic, err)
+		}
+	}()
+
+	catalog := ""
+	for message := range messages {
+		var productEvent model.ProductEvent
+		if err := json.Unmarshal([]byte(message), &productEvent); err != nil {
+			log.Printf("Erro ao ler evento de produto: %v\n", err)
+			continue
+		}
+		if productEvent.Type == model.ProductDeleted || productEvent.Product == nil {
+			continue
+		}
+
+		policies, err := s.replenishmentRepo.ListPolicies(productEvent.ProductID, &catalog)
+		if err != nil {
+			log.Printf("Erro ao listar as políticas de reposição do produto %s: %v\n", productEvent.ProductID, err)
+			continue
+		}
+		if len(policies) == 0 {
+			continue
+		}
+		if _, err := s.evaluate(policies, map[string]*model.Product{productEvent.ProductID: productEvent.Product}); err != nil {
+			log.Printf("Erro ao avaliar as políticas de reposição do produto %s: %v\n", productEvent.ProductID, err)
+		}
+	}
+}
+
+// RunMonitor reavalia periodicamente todas as políticas. É o que detecta o cruzamento
+// nas localizações, cujo estoque é movimentado pelo serviço de localizações.
+func (s *ReplenishmentServiceImpl) RunMonitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.evaluateAll(); err != nil {
+			log.Printf("Erro ao avaliar as políticas de reposição: %v\n", err)
+		}
+	}
+}
+
+func (s *ReplenishmentServiceImpl) evaluateAll() error {
+	policies, err := s.replenishmentRepo.ListPolicies("", nil)
+	if err != nil {
+		return err
+	}
+	products, err := s.loadPolicyProducts(policies)
+	if err != nil {
+		return err
+	}
+	_, err = s.evaluate(policies, products)
+	return err
+}
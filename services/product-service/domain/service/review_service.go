@@ -0,0 +1,346 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"Varejo-Golang-Microservices/services/product-service/infra/orders"
+	"Varejo-Golang-Microservices/services/product-service/infra/storage"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrReviewNotFound       = errors.New("avaliação não encontrada")
+	ErrInvalidReview        = errors.New("avaliação inválida")
+	ErrPurchaseNotVerified  = errors.New("apenas clientes que compraram o produto podem avaliá-lo")
+	ErrDuplicateReview      = errors.New("o cliente já avaliou este produto")
+	ErrReviewAlreadyInState = errors.New("a avaliação já está neste status")
+	// ErrRatingNotUpdated indica que a moderação foi gravada, mas o resumo de notas não;
+	// repetir a moderação refaz o resumo
+	ErrRatingNotUpdated = errors.New("avaliação moderada, mas as notas do produto não foram atualizadas")
+)
+
+const (
+	MaxReviewPhotos     = 5
+	MinReviewTextLength = 10
+	MaxReviewTextLength = 5000
+)
+
+// ReviewPhoto é uma foto enviada junto com a avaliação
+type ReviewPhoto struct {
+	FileName string
+	Body     io.Reader
+}
+
+// ReviewPage é uma página das avaliações aprovadas de um produto
+type ReviewPage struct {
+	Total    int64                `json:"total"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"pageSize"`
+	Rating   *model.RatingSummary `json:"rating"`
+	Reviews  []*model.Review      `json:"reviews"`
+}
+
+type ReviewService interface {
+	CreateReview(productID, customerID string, rating int, title, text string, photos []ReviewPhoto) (*model.Review, error)
+	ListReviews(productID string, rating int, sort model.ReviewSort, page, pageSize int) (*ReviewPage, error)
+	ModerationQueue() ([]*model.Review, error)
+	Approve(id, moderator string) (*model.Review, error)
+	Reject(id, moderator, reason string) (*model.Review, error)
+	Vote(id, customerID string, helpful bool) (*model.Review, error)
+}
+
+type ReviewServiceImpl struct {
+	reviewRepo  *repository.MongoReviewRepository
+	productRepo *repository.MongoProductRepository
+	verifier    orders.PurchaseVerifier
+	storage     storage.Storage
+}
+
+func NewReviewService(reviewRepo *repository.MongoReviewRepository, productRepo *repository.MongoProductRepository,
+	verifier orders.PurchaseVerifier, storage storage.Storage) ReviewService {
+	return &ReviewServiceImpl{
+		reviewRepo:  reviewRepo,
+		productRepo: productRepo,
+		verifier:    verifier,
+		storage:     storage,
+	}
+}
+
+// CreateReview confere a compra no serviço de pedidos, grava as fotos e coloca a
+// avaliação na fila de moderação
+func (s *ReviewServiceImpl) CreateReview(productID, customerID string, rating int, title, text string, photos []ReviewPhoto) (*model.Review, error) {
+	customerID = strings.TrimSpace(customerID)
+	title, text = strings.TrimSpace(title), strings.TrimSpace(text)
+	switch {
+	case customerID == "":
+		return nil, fmt.Errorf("%w: o cliente é obrigatório", ErrInvalidReview)
+	case rating < 1 || rating > 5:
+		return nil, fmt.Errorf("%w: a nota deve ser de 1 a 5", ErrInvalidReview)
+	case utf8.RuneCountInString(text) < MinReviewTextLength:
+		return nil, fmt.Errorf("%w: o texto deve ter ao menos %d caracteres", ErrInvalidReview, MinReviewTextLength)
+	case utf8.RuneCountInString(text) > MaxReviewTextLength:
+		return nil, fmt.Errorf("%w: o texto deve ter até %d caracteres", ErrInvalidReview, MaxReviewTextLength)
+	case len(photos) > MaxReviewPhotos:
+		return nil, fmt.Errorf("%w: envie até %d fotos", ErrInvalidReview, MaxReviewPhotos)
+	}
+
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.reviewRepo.CountActive(product.ID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if active > 0 {
+		return nil, ErrDuplicateReview
+	}
+
+	purchase, err := s.verifier.VerifyPurchase(customerID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if !purchase.Verified {
+		return nil, ErrPurchaseNotVerified
+	}
+
+	review := &model.Review{
+		ID:               primitive.NewObjectID(),
+		ProductID:        product.ID,
+		CustomerID:       customerID,
+		OrderID:          purchase.OrderID,
+		VerifiedPurchase: true,
+		Rating:           rating,
+		Title:            title,
+		Text:             text,
+		Status:           model.ReviewPending,
+		CreatedAt:        time.Now(),
+	}
+
+	for _, photo := range photos {
+		media, err := s.savePhoto(review, photo)
+		if err != nil {
+			s.deletePhotos(review)
+			return nil, err
+		}
+		review.Photos = append(review.Photos, *media)
+	}
+
+	if err := s.reviewRepo.Save(review); err != nil {
+		s.deletePhotos(review)
+		return nil, err
+	}
+	return review, nil
+}
+
+// Fotos de avaliações aceitam apenas imagens, com as mesmas miniaturas das mídias do produto
+func (s *ReviewServiceImpl) savePhoto(review *model.Review, photo ReviewPhoto) (*model.Media, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(photo.Body, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: arquivo vazio", ErrUnsupportedMedia)
+		}
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	format, ok := mediaFormats[contentType]
+	if !ok || format.Type != model.MediaImage {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMedia, contentType)
+	}
+
+	id := primitive.NewObjectID().Hex()
+	base := fmt.Sprintf("reviews/%s/%s", review.ID.Hex(), id)
+	media := &model.Media{
+		ID:          id,
+		Type:        model.MediaImage,
+		ContentType: contentType,
+		FileName:    photo.FileName,
+		Key:         base + "." + format.Extension,
+		UploadedAt:  time.Now(),
+	}
+
+	if err := saveImage(s.storage, media, base, io.MultiReader(bytes.NewReader(head), photo.Body)); err != nil {
+		return nil, err
+	}
+	media.URL = s.storage.URL(media.Key)
+	return media, nil
+}
+
+func (s *ReviewServiceImpl) deletePhotos(review *model.Review) {
+	for i := range review.Photos {
+		deleteFiles(s.storage, &review.Photos[i])
+	}
+}
+
+func (s *ReviewServiceImpl) ListReviews(productID string, rating int, sort model.ReviewSort, page, pageSize int) (*ReviewPage, error) {
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch sort {
+	case "":
+		sort = model.ReviewSortRecent
+	case model.ReviewSortRecent, model.ReviewSortHelpful, model.ReviewSortRatingDesc, model.ReviewSortRatingAsc:
+	default:
+		return nil, fmt.Errorf("%w: ordenação %s não suportada", ErrInvalidReview, sort)
+	}
+	if rating < 0 || rating > 5 {
+		return nil, fmt.Errorf("%w: a nota deve ser de 1 a 5", ErrInvalidReview)
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	reviews, total, err := s.reviewRepo.ListByProduct(product.ID, rating, sort, int64((page-1)*pageSize), int64(pageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReviewPage{
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		Rating:   product.Rating,
+		Reviews:  reviews,
+	}, nil
+}
+
+// ModerationQueue lista as avaliações pendentes, das mais antigas para as mais novas
+func (s *ReviewServiceImpl) ModerationQueue() ([]*model.Review, error) {
+	return s.reviewRepo.ListByStatus(model.ReviewPending)
+}
+
+func (s *ReviewServiceImpl) Approve(id, moderator string) (*model.Review, error) {
+	return s.moderate(id, model.ReviewApproved, moderator, "")
+}
+
+// Reject recusa uma avaliação pendente ou retira do ar uma já aprovada
+func (s *ReviewServiceImpl) Reject(id, moderator, reason string) (*model.Review, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: informe o motivo da recusa", ErrInvalidReview)
+	}
+	return s.moderate(id, model.ReviewRejected, moderator, reason)
+}
+
+func (s *ReviewServiceImpl) moderate(id string, status model.ReviewStatus, moderator, reason string) (*model.Review, error) {
+	current, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := s.reviewRepo.Moderate(current.ID, status, moderator, reason, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		// Repetir a moderação refaz o resumo, que pode ter falhado na primeira vez
+		if err := s.refreshRating(current.ProductID); err != nil {
+			return nil, err
+		}
+		return nil, ErrReviewAlreadyInState
+	}
+
+	// Só as avaliações aprovadas entram no resumo, então ele muda ao aprovar ou retirar
+	if status == model.ReviewApproved || current.Status == model.ReviewApproved {
+		if err := s.refreshRating(review.ProductID); err != nil {
+			return nil, err
+		}
+	}
+	return review, nil
+}
+
+// Vote registra se a avaliação foi útil para o cliente. Votar de novo troca o voto.
+func (s *ReviewServiceImpl) Vote(id, customerID string, helpful bool) (*model.Review, error) {
+	customerID = strings.TrimSpace(customerID)
+	if customerID == "" {
+		return nil, fmt.Errorf("%w: o cliente é obrigatório", ErrInvalidReview)
+	}
+
+	review, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != model.ReviewApproved {
+		return nil, ErrReviewNotFound
+	}
+	if review.CustomerID == customerID {
+		return nil, fmt.Errorf("%w: o autor não pode votar na própria avaliação", ErrInvalidReview)
+	}
+
+	previous, err := s.reviewRepo.Vote(&model.ReviewVote{
+		ReviewID:   review.ID,
+		CustomerID: customerID,
+		Helpful:    helpful,
+		VotedAt:    time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	helpfulDelta, unhelpfulDelta := voteDelta(helpful)
+	if previous != nil {
+		if previous.Helpful == helpful {
+			return review, nil
+		}
+		undoHelpful, undoUnhelpful := voteDelta(previous.Helpful)
+		helpfulDelta -= undoHelpful
+		unhelpfulDelta -= undoUnhelpful
+	}
+	if err := s.reviewRepo.AddVotes(review.ID, helpfulDelta, unhelpfulDelta); err != nil {
+		return nil, err
+	}
+
+	return s.find(id)
+}
+
+func voteDelta(helpful bool) (int, int) {
+	if helpful {
+		return 1, 0
+	}
+	return 0, 1
+}
+
+// O resumo é recalculado do zero a partir das avaliações aprovadas, então pode ser refeito
+func (s *ReviewServiceImpl) refreshRating(productID primitive.ObjectID) error {
+	summary, err := s.reviewRepo.Summarize(productID)
+	if err == nil {
+		err = s.productRepo.SetRating(productID, summary)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: produto %s: %v", ErrRatingNotUpdated, productID.Hex(), err)
+	}
+	return nil
+}
+
+func (s *ReviewServiceImpl) find(id string) (*model.Review, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrReviewNotFound
+	}
+	review, err := s.reviewRepo.FindByID(objID)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, ErrReviewNotFound
+	}
+	return review, nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"Varejo-Golang-Microservices/services/product-service/infra/event"
+	"Varejo-Golang-Microservices/services/product-service/infra/search"
+	"encoding/json"
+	"log"
+	"os"
+)
+
+type SearchService interface {
+	Search(query search.Query) *search.Result
+	Rebuild() error
+	ListenProductEvents(kafkaBroker string)
+}
+
+type SearchServiceImpl struct {
+	productRepo *repository.MongoProductRepository
+	index       *search.Index
+}
+
+func NewSearchService(productRepo *repository.MongoProductRepository, index *search.Index) SearchService {
+	return &SearchServiceImpl{
+		productRepo: productRepo,
+		index:       index,
+	}
+}
+
+func (s *SearchServiceImpl) Search(query search.Query) *search.Result {
+	return s.index.Search(query)
+}
+
+// Rebuild carrega todo o catálogo do banco para o índice
+func (s *SearchServiceImpl) Rebuild() error {
+	products, err := s.productRepo.ListAll()
+	if err != nil {
+		return err
+	}
+
+	s.index.Replace(products)
+	log.Printf("Índice de busca reconstruído com %d produtos\n", len(products))
+	return nil
+}
+
+// ListenProductEvents reconstrói o índice e depois o mantém atualizado com os eventos do catálogo.
+// Cada instância usa o próprio grupo de consumo, pois o índice fica em memória.
+func (s *SearchServiceImpl) ListenProductEvents(kafkaBroker string) {
+	if err := s.Rebuild(); err != nil {
+		log.Printf("Erro ao reconstruir o índice de busca: %v\n", err)
+	}
+
+	hostname, _ := os.Hostname()
+	groupID := "product-search-" + hostname
+
+	messages := make(chan string)
+	go func() {
+		if err := event.ConsumeMessage(kafkaBroker, repository.ProductEventTopic, groupID, messages); err != nil {
+			log.Printf("Erro ao consumir o tópico %s: %v\n", repository.ProductEventTopic, err)
+		}
+	}()
+
+	for message := range messages {
+		var productEvent model.ProductEvent
+		if err := json.Unmarshal([]byte(message), &productEvent); err != nil {
+			log.Printf("Erro ao ler evento de produto: %v\n", err)
+			continue
+		}
+
+		if productEvent.Type == model.ProductDeleted || productEvent.Product == nil {
+			s.index.Delete(productEvent.ProductID)
+			continue
+		}
+		s.index.Upsert(productEvent.Product)
+	}
+}
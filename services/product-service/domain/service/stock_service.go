@@ -0,0 +1,394 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tempo padrão que as unidades ficam reservadas aguardando a confirmação do checkout
+const DefaultReservationTTL = 15 * time.Minute
+
+// Tempo sem progresso a partir do qual um encerramento em andamento é considerado
+// interrompido e concluído pela varredura
+const staleFinishingAfter = 2 * time.Minute
+
+var (
+	ErrReservationNotFound = errors.New("reserva não encontrada")
+	ErrReservationClosed   = errors.New("a reserva não está mais ativa")
+)
+
+type StockService interface {
+	Reserve(orderID string, items []model.ReservationItem, ttl time.Duration) (*model.Reservation, error)
+	GetReservation(id string) (*model.Reservation, error)
+	Commit(id string) (*model.Reservation, error)
+	Release(id string) (*model.Reservation, error)
+	AdjustStock(productID, sku string, delta int) (*model.Product, error)
+	ExpireReservations() int
+	RunExpiry(interval time.Duration)
+}
+
+type StockServiceImpl struct {
+	productRepo     *repository.MongoProductRepository
+	reservationRepo *repository.MongoReservationRepository
+}
+
+func NewStockService(productRepo *repository.MongoProductRepository, reservationRepo *repository.MongoReservationRepository) StockService {
+	return &StockServiceImpl{
+		productRepo:     productRepo,
+		reservationRepo: reservationRepo,
+	}
+}
+
+// Reserve reserva todos os itens ou nenhum: se algum produto não tiver estoque,
+// as unidades já reservadas dos itens anteriores são devolvidas.
+func (s *StockServiceImpl) Reserve(orderID string, items []model.ReservationItem, ttl time.Duration) (*model.Reservation, error) {
+	if len(items) == 0 {
+		return nil, errors.New("a reserva precisa de ao menos um item")
+	}
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("quantidade inválida para o produto %s", item.ProductID.Hex())
+		}
+	}
+	items, err := s.expandBundles(items)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if err := s.checkSKU(item.ProductID.Hex(), item.SKU); err != nil {
+			return nil, err
+		}
+	}
+	if ttl <= 0 {
+		ttl = DefaultReservationTTL
+	}
+
+	// A reserva é gravada antes de baixar o estoque: se o processo cair no meio,
+	// a varredura de expiração encontra a reserva pendente e devolve o que foi separado
+	now := time.Now()
+	reservation := &model.Reservation{
+		ID:        primitive.NewObjectID(),
+		OrderID:   orderID,
+		Items:     items,
+		Status:    model.ReservationPending,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+	if err := s.reservationRepo.Save(reservation); err != nil {
+		return nil, err
+	}
+
+	for i, item := range items {
+		if err := s.productRepo.ReserveStock(item.ProductID, item.SKU, item.Quantity); err != nil {
+			s.abandon(reservation.ID)
+			if errors.Is(err, repository.ErrInsufficientStock) {
+				return nil, fmt.Errorf("%w para o produto %s", err, item.ProductID.Hex())
+			}
+			return nil, err
+		}
+
+		pending, err := s.reservationRepo.SetReserved(reservation.ID, i+1)
+		if err == nil && !pending {
+			// A varredura expirou a reserva e devolveu os itens anteriores; falta este
+			err = s.releaseItems(items[i : i+1])
+			if err == nil {
+				err = ErrReservationClosed
+			}
+			return nil, err
+		}
+		if err != nil {
+			if releaseErr := s.releaseItems(items[i : i+1]); releaseErr != nil {
+				log.Printf("Erro ao devolver estoque da reserva %s: %v\n", reservation.ID.Hex(), releaseErr)
+			}
+			s.abandon(reservation.ID)
+			return nil, err
+		}
+		reservation.ReservedItems = i + 1
+	}
+
+	active, err := s.reservationRepo.Activate(reservation.ID)
+	if err != nil {
+		s.abandon(reservation.ID)
+		return nil, err
+	}
+	if !active {
+		return nil, ErrReservationClosed
+	}
+	reservation.Status = model.ReservationActive
+
+	return reservation, nil
+}
+
+// Encerra uma reserva pendente que falhou e devolve as unidades já separadas.
+// Se algo falhar aqui, a reserva continua pendente e a varredura tenta de novo.
+func (s *StockServiceImpl) abandon(id primitive.ObjectID) {
+	reservation, err := s.reservationRepo.Abandon(id, model.ReservationReleased, time.Now())
+	if err != nil {
+		log.Printf("Erro ao cancelar a reserva %s: %v\n", id.Hex(), err)
+		return
+	}
+	if reservation == nil {
+		return
+	}
+	if err := s.releaseItems(reservation.Items[:reservation.ReservedItems]); err != nil {
+		log.Printf("Erro ao devolver estoque da reserva %s: %v\n", id.Hex(), err)
+	}
+}
+
+func (s *StockServiceImpl) GetReservation(id string) (*model.Reservation, error) {
+	reservation, err := s.reservationRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if reservation == nil {
+		return nil, ErrReservationNotFound
+	}
+	return reservation, nil
+}
+
+// Commit confirma a reserva, baixando definitivamente as unidades reservadas
+func (s *StockServiceImpl) Commit(id string) (*model.Reservation, error) {
+	return s.finish(id, model.ReservationCommitted)
+}
+
+// Release cancela a reserva e devolve as unidades ao estoque disponível
+func (s *StockServiceImpl) Release(id string) (*model.Reservation, error) {
+	return s.finish(id, model.ReservationReleased)
+}
+
+// Encerra a reserva em duas etapas: primeiro a reserva passa ao status
+// intermediário, depois o estoque de cada item é movimentado e só então ela recebe
+// o status final. Se a movimentação falhar, a reserva continua no status
+// intermediário e a varredura de expiração conclui o encerramento.
+func (s *StockServiceImpl) finish(id string, final model.ReservationStatus) (*model.Reservation, error) {
+	current, err := s.GetReservation(id)
+	if err != nil {
+		return nil, err
+	}
+	if current.IsFinishing() {
+		return nil, fmt.Errorf("%w: encerramento em andamento (%s)", ErrReservationClosed, current.Status)
+	}
+
+	finishing, _ := model.FinishingStatus(final)
+	reservation, err := s.reservationRepo.BeginFinish(current.ID, finishing, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if reservation == nil {
+		return nil, ErrReservationClosed
+	}
+
+	if err := s.completeFinish(reservation); err != nil {
+		return reservation, err
+	}
+	return reservation, nil
+}
+
+// Movimenta o estoque dos itens que faltam, registrando o progresso item a item,
+// e grava o status final da reserva
+func (s *StockServiceImpl) completeFinish(reservation *model.Reservation) error {
+	final, ok := model.FinalStatus(reservation.Status)
+	if !ok {
+		return fmt.Errorf("a reserva %s não está em encerramento (%s)", reservation.ID.Hex(), reservation.Status)
+	}
+
+	for _, item := range reservation.RemainingItems() {
+		var err error
+		if final == model.ReservationCommitted {
+			err = s.productRepo.CommitStock(item.ProductID, item.SKU, item.Quantity)
+		} else {
+			err = s.productRepo.ReleaseStock(item.ProductID, item.SKU, item.Quantity)
+		}
+		if err != nil {
+			return fmt.Errorf("erro ao movimentar o estoque do produto %s na reserva %s: %w", item.ProductID.Hex(), reservation.ID.Hex(), err)
+		}
+
+		finishing, err := s.reservationRepo.SetFinished(reservation.ID, reservation.Status, reservation.FinishedItems+1, time.Now())
+		if err != nil {
+			return err
+		}
+		if !finishing {
+			return ErrReservationClosed
+		}
+		reservation.FinishedItems++
+	}
+
+	now := time.Now()
+	completed, err := s.reservationRepo.CompleteFinish(reservation.ID, reservation.Status, final, now)
+	if err != nil {
+		return err
+	}
+	if !completed {
+		return ErrReservationClosed
+	}
+	reservation.Status = final
+	reservation.FinishedAt = &now
+	return nil
+}
+
+// Devolve as unidades ao estoque disponível. Todos os itens são tentados; o
+// primeiro erro é retornado com a quantidade de itens que falharam.
+func (s *StockServiceImpl) releaseItems(items []model.ReservationItem) error {
+	var firstErr error
+	failed := 0
+	for _, item := range items {
+		if err := s.productRepo.ReleaseStock(item.ProductID, item.SKU, item.Quantity); err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = fmt.Errorf("erro ao devolver estoque do produto %s: %w", item.ProductID.Hex(), err)
+			}
+		}
+	}
+	if firstErr != nil {
+		return fmt.Errorf("%d de %d itens não devolvidos: %w", failed, len(items), firstErr)
+	}
+	return nil
+}
+
+// AdjustStock altera manualmente o estoque disponível, por exemplo no recebimento de mercadoria
+func (s *StockServiceImpl) AdjustStock(productID, sku string, delta int) (*model.Product, error) {
+	if err := s.checkSKU(productID, sku); err != nil {
+		return nil, err
+	}
+
+	objID, err := primitive.ObjectIDFromHex(productID)
+	if err != nil {
+		return nil, fmt.Errorf("ID de produto inválido: %s", productID)
+	}
+	if err := s.productRepo.AdjustStock(objID, sku, delta); err != nil {
+		return nil, err
+	}
+
+	return s.productRepo.FindByID(productID)
+}
+
+// Substitui os kits pelos seus componentes: a reserva de um kit reserva as
+// unidades de cada componente, marcadas com o kit de origem
+func (s *StockServiceImpl) expandBundles(items []model.ReservationItem) ([]model.ReservationItem, error) {
+	expanded := make([]model.ReservationItem, 0, len(items))
+	for _, item := range items {
+		product, err := s.productRepo.FindByID(item.ProductID.Hex())
+		if err != nil {
+			return nil, err
+		}
+		if !product.IsBundle() || product.Bundle == nil {
+			expanded = append(expanded, item)
+			continue
+		}
+		if item.SKU != "" {
+			return nil, fmt.Errorf("%w: kits não têm variantes", ErrInvalidBundle)
+		}
+
+		for _, component := range product.Bundle.Components {
+			expanded = append(expanded, model.ReservationItem{
+				ProductID: component.ProductID,
+				SKU:       component.SKU,
+				Quantity:  component.Quantity * item.Quantity,
+				BundleID:  product.ID.Hex(),
+			})
+		}
+	}
+	return expanded, nil
+}
+
+// Produtos com variantes exigem o SKU; produtos simples não aceitam SKU.
+// O estoque dos kits vem dos componentes e não é movimentado diretamente.
+func (s *StockServiceImpl) checkSKU(productID, sku string) error {
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil {
+		return err
+	}
+
+	if product.IsBundle() {
+		return fmt.Errorf("%w: o estoque do kit vem dos componentes", ErrInvalidBundle)
+	}
+
+	if sku == "" && len(product.Variants) > 0 {
+		return fmt.Errorf("%w: informe o SKU da variante do produto %s", ErrInvalidVariant, productID)
+	}
+	if sku != "" && product.FindVariant(sku) == nil {
+		return fmt.Errorf("%w: %s", repository.ErrVariantNotFound, sku)
+	}
+
+	return nil
+}
+
+// ExpireReservations devolve ao estoque as reservas vencidas e retorna quantas expiraram.
+// Reservas que ficaram pendentes (falha no meio de Reserve) devolvem apenas os
+// itens que chegaram a ser separados, e os encerramentos interrompidos são concluídos.
+func (s *StockServiceImpl) ExpireReservations() int {
+	now := time.Now()
+	expired := 0
+
+	ids, err := s.reservationRepo.ListExpired(model.ReservationPending, now)
+	if err != nil {
+		log.Printf("Erro ao buscar reservas pendentes vencidas: %v\n", err)
+	}
+	for _, id := range ids {
+		reservation, err := s.reservationRepo.Abandon(id, model.ReservationExpired, now)
+		if err != nil {
+			log.Printf("Erro ao expirar reserva %s: %v\n", id.Hex(), err)
+			continue
+		}
+		// Já ativada ou encerrada por outra requisição
+		if reservation == nil {
+			continue
+		}
+		if err := s.releaseItems(reservation.Items[:reservation.ReservedItems]); err != nil {
+			log.Printf("Erro ao devolver estoque da reserva %s: %v\n", id.Hex(), err)
+		}
+		expired++
+	}
+
+	ids, err = s.reservationRepo.ListExpired(model.ReservationActive, now)
+	if err != nil {
+		log.Printf("Erro ao buscar reservas vencidas: %v\n", err)
+	}
+	for _, id := range ids {
+		reservation, err := s.reservationRepo.BeginFinish(id, model.ReservationExpiring, now)
+		if err != nil {
+			log.Printf("Erro ao expirar reserva %s: %v\n", id.Hex(), err)
+			continue
+		}
+		// Já confirmada ou liberada por outra requisição
+		if reservation == nil {
+			continue
+		}
+		if err := s.completeFinish(reservation); err != nil {
+			log.Printf("Erro ao devolver estoque da reserva %s: %v\n", id.Hex(), err)
+			continue
+		}
+		expired++
+	}
+
+	// Encerramentos sem progresso recente foram interrompidos; a varredura os conclui
+	stale, err := s.reservationRepo.ListStaleFinishing(now.Add(-staleFinishingAfter))
+	if err != nil {
+		log.Printf("Erro ao buscar reservas com encerramento interrompido: %v\n", err)
+	}
+	for _, reservation := range stale {
+		if err := s.completeFinish(reservation); err != nil {
+			log.Printf("Erro ao concluir o encerramento da reserva %s: %v\n", reservation.ID.Hex(), err)
+		}
+	}
+
+	return expired
+}
+
+// RunExpiry verifica periodicamente as reservas vencidas. Deve ser executado em uma goroutine.
+func (s *StockServiceImpl) RunExpiry(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if n := s.ExpireReservations(); n > 0 {
+			log.Printf("%d reservas de estoque expiradas\n", n)
+		}
+	}
+}
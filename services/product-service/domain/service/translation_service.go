@@ -0,0 +1,224 @@
+package service
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"Varejo-Golang-Microservices/services/product-service/domain/repository"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidTranslation = errors.New("tradução inválida")
+
+type TranslationService interface {
+	GetProductTranslations(id string) (*model.TranslationStatus, error)
+	SetProductTranslation(id, locale string, translation model.Translation) (*model.TranslationStatus, error)
+	RemoveProductTranslation(id, locale string) (*model.TranslationStatus, error)
+	GetCategoryTranslations(id string) (*model.TranslationStatus, error)
+	SetCategoryTranslation(id, locale string, translation model.Translation) (*model.TranslationStatus, error)
+	RemoveCategoryTranslation(id, locale string) (*model.TranslationStatus, error)
+	ListIncomplete(locale string) ([]*model.TranslationStatus, error)
+}
+
+type TranslationServiceImpl struct {
+	productRepo     *repository.MongoProductRepository
+	categoryRepo    *repository.MongoCategoryRepository
+	categoryService CategoryService
+}
+
+func NewTranslationService(productRepo *repository.MongoProductRepository, categoryRepo *repository.MongoCategoryRepository, categoryService CategoryService) TranslationService {
+	return &TranslationServiceImpl{
+		productRepo:     productRepo,
+		categoryRepo:    categoryRepo,
+		categoryService: categoryService,
+	}
+}
+
+func (s *TranslationServiceImpl) GetProductTranslations(id string) (*model.TranslationStatus, error) {
+	product, err := s.productRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return productTranslationStatus(product), nil
+}
+
+// SetProductTranslation grava a tradução do produto; o evento de atualização
+// reindexa o produto na busca com o texto do novo idioma
+func (s *TranslationServiceImpl) SetProductTranslation(id, locale string, translation model.Translation) (*model.TranslationStatus, error) {
+	locale, err := translationLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	if translation, err = validateTranslation(translation); err != nil {
+		return nil, err
+	}
+	product, err := s.productRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.productRepo.SetTranslation(product.ID, locale, translation); err != nil {
+		return nil, err
+	}
+	return s.GetProductTranslations(id)
+}
+
+func (s *TranslationServiceImpl) RemoveProductTranslation(id, locale string) (*model.TranslationStatus, error) {
+	locale, err := translationLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	product, err := s.productRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.productRepo.RemoveTranslation(product.ID, locale); err != nil {
+		return nil, err
+	}
+	return s.GetProductTranslations(id)
+}
+
+func (s *TranslationServiceImpl) GetCategoryTranslations(id string) (*model.TranslationStatus, error) {
+	category, err := s.categoryService.GetCategory(id)
+	if err != nil {
+		return nil, err
+	}
+	return categoryTranslationStatus(category), nil
+}
+
+// SetCategoryTranslation grava a tradução da categoria e a copia para os produtos dela
+func (s *TranslationServiceImpl) SetCategoryTranslation(id, locale string, translation model.Translation) (*model.TranslationStatus, error) {
+	locale, err := translationLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	if translation, err = validateTranslation(translation); err != nil {
+		return nil, err
+	}
+	category, err := s.categoryService.GetCategory(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.categoryRepo.SetTranslation(category.ID, locale, translation); err != nil {
+		return nil, err
+	}
+	if category.Translations == nil {
+		category.Translations = make(map[string]model.Translation)
+	}
+	category.Translations[locale] = translation
+
+	if err := s.productRepo.SetCategory(category.ID, category.Reference()); err != nil {
+		return nil, err
+	}
+	return categoryTranslationStatus(category), nil
+}
+
+func (s *TranslationServiceImpl) RemoveCategoryTranslation(id, locale string) (*model.TranslationStatus, error) {
+	locale, err := translationLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	category, err := s.categoryService.GetCategory(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.categoryRepo.RemoveTranslation(category.ID, locale); err != nil {
+		return nil, err
+	}
+	delete(category.Translations, locale)
+
+	if err := s.productRepo.SetCategory(category.ID, category.Reference()); err != nil {
+		return nil, err
+	}
+	return categoryTranslationStatus(category), nil
+}
+
+// ListIncomplete lista as categorias e os produtos com campos sem tradução. Com o
+// idioma informado, só os incompletos nele.
+func (s *TranslationServiceImpl) ListIncomplete(locale string) ([]*model.TranslationStatus, error) {
+	if locale != "" {
+		var err error
+		if locale, err = translationLocale(locale); err != nil {
+			return nil, err
+		}
+	}
+	incomplete := func(status *model.TranslationStatus) bool {
+		if locale == "" {
+			return !status.Complete
+		}
+		_, missing := status.Missing[locale]
+		return missing
+	}
+
+	result := make([]*model.TranslationStatus, 0)
+
+	categories, err := s.categoryRepo.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Path < categories[j].Path })
+	for _, category := range categories {
+		if status := categoryTranslationStatus(category); incomplete(status) {
+			result = append(result, status)
+		}
+	}
+
+	err = s.productRepo.Each(func(product *model.Product) error {
+		if status := productTranslationStatus(product); incomplete(status) {
+			result = append(result, status)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// O idioma da tradução precisa ser suportado e diferente do padrão, que é o texto do próprio cadastro
+func translationLocale(locale string) (string, error) {
+	normalized, err := model.NormalizeLocale(locale)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", model.ErrUnsupportedLocale, locale)
+	}
+	if normalized == model.DefaultLocale {
+		return "", fmt.Errorf("%w: o texto em %s é editado no próprio cadastro", ErrInvalidTranslation, model.DefaultLocale)
+	}
+	return normalized, nil
+}
+
+func validateTranslation(translation model.Translation) (model.Translation, error) {
+	translation.Name = strings.TrimSpace(translation.Name)
+	translation.Description = strings.TrimSpace(translation.Description)
+	if translation.Name == "" && translation.Description == "" {
+		return translation, fmt.Errorf("%w: informe o nome ou a descrição", ErrInvalidTranslation)
+	}
+	return translation, nil
+}
+
+func productTranslationStatus(product *model.Product) *model.TranslationStatus {
+	return translationStatus(product.ID.Hex(), model.TranslationKindProduct, product.Name, product.Translations, product.MissingTranslations())
+}
+
+func categoryTranslationStatus(category *model.CategoryNode) *model.TranslationStatus {
+	return translationStatus(category.ID.Hex(), model.TranslationKindCategory, category.Name, category.Translations, category.MissingTranslations())
+}
+
+func translationStatus(id, kind, name string, translations map[string]model.Translation, missing map[string][]string) *model.TranslationStatus {
+	if translations == nil {
+		translations = make(map[string]model.Translation)
+	}
+	return &model.TranslationStatus{
+		ID:            id,
+		Kind:          kind,
+		Name:          name,
+		DefaultLocale: model.DefaultLocale,
+		Translations:  translations,
+		Missing:       missing,
+		Complete:      len(missing) == 0,
+	}
+}
@@ -0,0 +1,5 @@
+package dto
+
+type BarcodeGenerationDTO struct {
+	SKU string `json:"sku,omitempty"`
+}
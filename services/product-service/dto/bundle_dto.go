@@ -0,0 +1,15 @@
+package dto
+
+import "Varejo-Golang-Microservices/services/product-service/domain/model"
+
+type BundleDTO struct {
+	Components      []BundleComponentDTO    `json:"components"`
+	PricingMode     model.BundlePricingMode `json:"pricingMode"`
+	DiscountPercent float64                 `json:"discountPercent,omitempty"`
+}
+
+type BundleComponentDTO struct {
+	ProductID string `json:"productId"`
+	SKU       string `json:"sku,omitempty"`
+	Quantity  int    `json:"quantity"`
+}
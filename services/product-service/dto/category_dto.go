@@ -0,0 +1,32 @@
+package dto
+
+import "Varejo-Golang-Microservices/services/product-service/domain/model"
+
+type CategoryNodeDTO struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ParentID    string `json:"parentId"`
+	Position    *int   `json:"position"`
+}
+
+type MoveCategoryDTO struct {
+	ParentID string `json:"parentId"`
+	Position *int   `json:"position"`
+}
+
+type MergeCategoryDTO struct {
+	TargetID string `json:"targetId"`
+}
+
+type AttributeDefinitionDTO struct {
+	Key           string              `json:"key"`
+	Label         string              `json:"label"`
+	Type          model.AttributeType `json:"type"`
+	Unit          string              `json:"unit,omitempty"`
+	Required      bool                `json:"required"`
+	AllowedValues []string            `json:"allowedValues,omitempty"`
+}
+
+type GoogleCategoryDTO struct {
+	GoogleCategory string `json:"googleCategory"`
+}
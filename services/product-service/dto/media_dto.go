@@ -0,0 +1,5 @@
+package dto
+
+type MediaOrderDTO struct {
+	MediaIDs []string `json:"mediaIds"`
+}
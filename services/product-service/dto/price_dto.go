@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+// PriceChangeDTO agenda um novo preço; com inherit, remove a sobrescrita de preço
+// da variante informada em sku e o campo price é ignorado
+type PriceChangeDTO struct {
+	SKU         string    `json:"sku,omitempty"`
+	Price       float64   `json:"price"`
+	Inherit     bool      `json:"inherit,omitempty"`
+	EffectiveAt time.Time `json:"effectiveAt"`
+	Reason      string    `json:"reason"`
+}
@@ -6,18 +6,40 @@ import (
 )
 
 type ProductDTO struct {
-	ID          string              `json:"id"`
-	Name        string              `json:"name"`
-	Description string              `json:"description"`
-	Price       float64             `json:"price"`
-	Category    CategoryDTO         `json:"category"`
-	Stock       int                 `json:"stock"`
-	AddedDate   time.Time           `json:"addedDate"`
-	UpdatedAt   time.Time           `json:"updatedAt"`
-	Status      model.ProductStatus `json:"status"`
+	ID          string                 `json:"id"`
+	SKU         string                 `json:"sku,omitempty"`
+	GTIN        string                 `json:"gtin,omitempty"`
+	Type        model.ProductType      `json:"type,omitempty"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Price       float64                `json:"price"`
+	Category    CategoryDTO            `json:"category"`
+	Stock       int                    `json:"stock"`
+	AddedDate   time.Time              `json:"addedDate"`
+	UpdatedAt   time.Time              `json:"updatedAt"`
+	Status      model.ProductStatus    `json:"status"`
+	Options     []ProductOptionDTO     `json:"options,omitempty"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+	Variants    []VariantDTO           `json:"variants,omitempty"`
+	Bundle      *BundleDTO             `json:"bundle,omitempty"`
+}
+
+type ProductOptionDTO struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+type VariantDTO struct {
+	SKU     string            `json:"sku"`
+	Options map[string]string `json:"options"`
+	GTIN    string            `json:"gtin,omitempty"`
+	Price   *float64          `json:"price,omitempty"`
+	Stock   int               `json:"stock"`
+	Images  []string          `json:"images,omitempty"`
 }
 
 type CategoryDTO struct {
+	ID          string `json:"id,omitempty"`
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
 }
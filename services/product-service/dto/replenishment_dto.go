@@ -0,0 +1,11 @@
+package dto
+
+type ReplenishmentPolicyDTO struct {
+	SKU          string `json:"sku,omitempty"`
+	LocationID   string `json:"locationId,omitempty"`
+	ReorderPoint int    `json:"reorderPoint"`
+	SafetyStock  int    `json:"safetyStock"`
+	LeadTimeDays int    `json:"leadTimeDays"`
+	MinQuantity  int    `json:"minQuantity"`
+	MaxQuantity  int    `json:"maxQuantity"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+type ReservationDTO struct {
+	OrderID    string               `json:"orderId"`
+	Items      []ReservationItemDTO `json:"items"`
+	TTLSeconds int                  `json:"ttlSeconds"`
+}
+
+type ReservationItemDTO struct {
+	ProductID string `json:"productId"`
+	SKU       string `json:"sku,omitempty"`
+	Quantity  int    `json:"quantity"`
+}
+
+type StockAdjustmentDTO struct {
+	SKU   string `json:"sku,omitempty"`
+	Delta int    `json:"delta"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+type ReviewVoteDTO struct {
+	Helpful *bool `json:"helpful"`
+}
+
+type ReviewRejectionDTO struct {
+	Reason string `json:"reason"`
+}
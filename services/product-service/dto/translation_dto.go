@@ -0,0 +1,6 @@
+package dto
+
+type TranslationDTO struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,419 @@
+package catalog
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Columns são as colunas do arquivo CSV, na ordem usada pela exportação
+var Columns = []string{"sku", "parentSku", "name", "description", "price", "category", "stock", "status", "options", "gtin", "attributes"}
+
+// Tamanho máximo de uma linha JSON
+const maxLineSize = 1024 * 1024
+
+var ErrUnsupportedFormat = errors.New("formato não suportado, use csv ou jsonl")
+
+// RowError indica uma linha que não pôde ser lida; a leitura pode continuar na próxima
+type RowError struct {
+	Row     int
+	Field   string
+	Message string
+}
+
+func (e *RowError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("linha %d, campo %s: %s", e.Row, e.Field, e.Message)
+	}
+	return fmt.Sprintf("linha %d: %s", e.Row, e.Message)
+}
+
+// Reader lê as linhas do arquivo uma a uma. Next retorna io.EOF ao final e
+// *RowError para linhas inválidas; qualquer outro erro interrompe a leitura.
+type Reader interface {
+	Next() (*model.CatalogRow, int, error)
+}
+
+// Writer grava as linhas do catálogo no formato escolhido
+type Writer interface {
+	Write(row *model.CatalogRow) error
+	Flush() error
+}
+
+// ParseFormat aceita o nome do formato ou a extensão do arquivo
+func ParseFormat(value string) (model.ImportFormat, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if i := strings.LastIndex(value, "."); i >= 0 {
+		value = value[i+1:]
+	}
+
+	switch value {
+	case "csv":
+		return model.ImportCSV, nil
+	case "jsonl", "ndjson":
+		return model.ImportJSONL, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+func NewReader(format model.ImportFormat, r io.Reader) (Reader, error) {
+	switch format {
+	case model.ImportCSV:
+		return newCSVReader(r)
+	case model.ImportJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+		return &jsonlReader{scanner: scanner}, nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+func NewWriter(format model.ImportFormat, w io.Writer) (Writer, error) {
+	switch format {
+	case model.ImportCSV:
+		writer := &csvWriter{writer: csv.NewWriter(w)}
+		if err := writer.writer.Write(Columns); err != nil {
+			return nil, err
+		}
+		return writer, nil
+	case model.ImportJSONL:
+		return &jsonlWriter{writer: bufio.NewWriter(w)}, nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// Rows converte o produto em uma linha própria seguida de uma linha por variante
+func Rows(product *model.Product, categoryPath string) []*model.CatalogRow {
+	price := product.Price
+	stock := product.Stock
+	rows := []*model.CatalogRow{{
+		SKU:         product.SKU,
+		GTIN:        product.GTIN,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       &price,
+		Category:    categoryPath,
+		Stock:       &stock,
+		Status:      product.Status,
+		Attributes:  product.Attributes,
+	}}
+
+	// Em produtos com variantes, o estoque do produto é a soma das variantes
+	if len(product.Variants) > 0 {
+		rows[0].Stock = nil
+	}
+
+	for i := range product.Variants {
+		variant := product.Variants[i]
+		variantStock := variant.Stock
+		rows = append(rows, &model.CatalogRow{
+			SKU:       variant.SKU,
+			ParentSKU: product.SKU,
+			Price:     variant.Price,
+			Stock:     &variantStock,
+			Options:   variant.Options,
+			GTIN:      variant.GTIN,
+		})
+	}
+
+	return rows
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	row     int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	buffered := bufio.NewReader(r)
+
+	// Planilhas exportadas em português costumam usar ponto e vírgula como separador
+	header, err := buffered.Peek(4096)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	if i := bytes.IndexByte(header, '\n'); i >= 0 {
+		header = header[:i]
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	names, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("arquivo vazio")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o cabeçalho: %w", err)
+	}
+
+	columns := make(map[string]int, len(names))
+	for i, name := range names {
+		// O Excel grava o BOM no início do arquivo UTF-8
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		for _, column := range Columns {
+			if name == strings.ToLower(column) {
+				columns[column] = i
+			}
+		}
+	}
+	if _, ok := columns["sku"]; !ok {
+		return nil, errors.New("o cabeçalho precisa ter a coluna sku")
+	}
+
+	return &csvReader{reader: reader, columns: columns, row: 1}, nil
+}
+
+func (r *csvReader) Next() (*model.CatalogRow, int, error) {
+	for {
+		record, err := r.reader.Read()
+		if err == io.EOF {
+			return nil, r.row, io.EOF
+		}
+
+		r.row++
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, r.row, &RowError{Row: r.row, Message: parseErr.Err.Error()}
+			}
+			return nil, r.row, err
+		}
+		if isBlank(record) {
+			continue
+		}
+
+		row, err := r.parse(record)
+		return row, r.row, err
+	}
+}
+
+func (r *csvReader) parse(record []string) (*model.CatalogRow, error) {
+	value := func(column string) string {
+		i, ok := r.columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	row := &model.CatalogRow{
+		SKU:         value("sku"),
+		ParentSKU:   value("parentSku"),
+		Name:        value("name"),
+		Description: value("description"),
+		Category:    value("category"),
+		Status:      model.ProductStatus(strings.ToUpper(value("status"))),
+		GTIN:        value("gtin"),
+	}
+
+	if text := value("price"); text != "" {
+		price, err := parseDecimal(text)
+		if err != nil {
+			return nil, &RowError{Row: r.row, Field: "price", Message: "preço inválido: " + text}
+		}
+		row.Price = &price
+	}
+
+	if text := value("stock"); text != "" {
+		stock, err := strconv.Atoi(text)
+		if err != nil {
+			return nil, &RowError{Row: r.row, Field: "stock", Message: "estoque inválido: " + text}
+		}
+		row.Stock = &stock
+	}
+
+	if text := value("options"); text != "" {
+		options, err := ParseOptions(text)
+		if err != nil {
+			return nil, &RowError{Row: r.row, Field: "options", Message: err.Error()}
+		}
+		row.Options = options
+	}
+
+	if text := value("attributes"); text != "" {
+		attributes, err := ParseOptions(text)
+		if err != nil {
+			return nil, &RowError{Row: r.row, Field: "attributes", Message: err.Error()}
+		}
+		row.Attributes = make(map[string]interface{}, len(attributes))
+		for key, value := range attributes {
+			row.Attributes[key] = value
+		}
+	}
+
+	return row, nil
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvWriter) Write(row *model.CatalogRow) error {
+	record := make([]string, 0, len(Columns))
+	record = append(record, row.SKU, row.ParentSKU, row.Name, row.Description)
+
+	if row.Price != nil {
+		record = append(record, strconv.FormatFloat(*row.Price, 'f', 2, 64))
+	} else {
+		record = append(record, "")
+	}
+	record = append(record, row.Category)
+	if row.Stock != nil {
+		record = append(record, strconv.Itoa(*row.Stock))
+	} else {
+		record = append(record, "")
+	}
+	record = append(record, string(row.Status), FormatOptions(row.Options), row.GTIN, FormatAttributes(row.Attributes))
+
+	return w.writer.Write(record)
+}
+
+func (w *csvWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	row     int
+}
+
+func (r *jsonlReader) Next() (*model.CatalogRow, int, error) {
+	for r.scanner.Scan() {
+		r.row++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var row model.CatalogRow
+		if err := json.Unmarshal(line, &row); err != nil {
+			return nil, r.row, &RowError{Row: r.row, Message: "JSON inválido: " + err.Error()}
+		}
+		row.SKU = strings.TrimSpace(row.SKU)
+		row.ParentSKU = strings.TrimSpace(row.ParentSKU)
+		row.Status = model.ProductStatus(strings.ToUpper(string(row.Status)))
+		return &row, r.row, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, r.row + 1, fmt.Errorf("linha %d excede o tamanho máximo", r.row+1)
+		}
+		return nil, r.row, err
+	}
+	return nil, r.row, io.EOF
+}
+
+type jsonlWriter struct {
+	writer *bufio.Writer
+}
+
+func (w *jsonlWriter) Write(row *model.CatalogRow) error {
+	line, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	if _, err := w.writer.Write(line); err != nil {
+		return err
+	}
+	return w.writer.WriteByte('\n')
+}
+
+func (w *jsonlWriter) Flush() error {
+	return w.writer.Flush()
+}
+
+// ParseOptions lê as opções no formato "Cor=Azul|Tamanho=M". A coluna de atributos
+// usa o mesmo formato.
+func ParseOptions(text string) (map[string]string, error) {
+	options := make(map[string]string)
+	for _, pair := range strings.Split(text, "|") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("opção inválida %q, use o formato Nome=Valor|Nome=Valor", pair)
+		}
+		options[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return options, nil
+}
+
+// FormatOptions grava as opções ordenadas pelo nome, no formato lido por ParseOptions
+func FormatOptions(options map[string]string) string {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+"="+options[name])
+	}
+	return strings.Join(pairs, "|")
+}
+
+// FormatAttributes grava os atributos no formato de FormatOptions, com os valores como texto
+func FormatAttributes(attributes map[string]interface{}) string {
+	values := make(map[string]string, len(attributes))
+	for key, value := range attributes {
+		values[key] = model.FormatAttribute(value)
+	}
+	return FormatOptions(values)
+}
+
+// Aceita tanto "1234.56" quanto o formato brasileiro "1.234,56"
+func parseDecimal(text string) (float64, error) {
+	if strings.Contains(text, ",") {
+		text = strings.ReplaceAll(text, ".", "")
+		text = strings.ReplaceAll(text, ",", ".")
+	}
+	return strconv.ParseFloat(text, 64)
+}
+
+func isBlank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteErrorReport grava o relatório de linhas rejeitadas de uma importação em CSV
+func WriteErrorReport(w io.Writer, rowErrors []model.ImportRowError) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"row", "sku", "field", "message"}); err != nil {
+		return err
+	}
+
+	for _, rowError := range rowErrors {
+		record := []string{strconv.Itoa(rowError.Row), rowError.SKU, rowError.Field, rowError.Message}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
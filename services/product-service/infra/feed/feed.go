@@ -0,0 +1,313 @@
+package feed
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatXML Format = "xml"
+	FormatTSV Format = "tsv"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("formato de feed não suportado, use xml ou tsv")
+	// ErrNotReady indica que a primeira geração completa ainda não terminou
+	ErrNotReady = errors.New("o feed ainda está sendo gerado")
+)
+
+// ParseFormat aceita o nome do formato ou a extensão do arquivo
+func ParseFormat(value string) (Format, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if i := strings.LastIndex(value, "."); i >= 0 {
+		value = value[i+1:]
+	}
+
+	switch Format(value) {
+	case FormatXML, FormatTSV:
+		return Format(value), nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// Colunas do arquivo TSV, com os nomes de atributo do Merchant Center
+var tsvColumns = []string{
+	"id", "item_group_id", "title", "description", "link", "image_link", "additional_image_link",
+	"availability", "price", "brand", "gtin", "identifier_exists", "condition",
+	"google_product_category", "product_type", "color", "size", "is_bundle",
+}
+
+// Document é o feed completo já renderizado
+type Document struct {
+	Content     []byte
+	ContentType string
+	ETag        string
+	ModifiedAt  time.Time
+}
+
+// Trechos já renderizados das ofertas de um produto
+type fragment struct {
+	xml []byte
+	tsv []byte
+}
+
+// Feed guarda as ofertas renderizadas por produto. Os eventos do catálogo atualizam
+// só os trechos do produto alterado; o documento completo é montado sob demanda e
+// reaproveitado enquanto nenhum trecho mudar, mantendo o ETag estável.
+//
+// O feed só é servido depois da primeira geração completa, para não publicar um
+// catálogo parcial. Durante uma geração, os produtos alterados pelos eventos ficam
+// marcados em dirty e Replace mantém a versão do evento, que é mais nova que a lida.
+type Feed struct {
+	mu         sync.RWMutex
+	config     Config
+	fragments  map[string]fragment
+	documents  map[Format]*Document
+	modifiedAt time.Time
+	ready      bool
+	dirty      map[string]bool
+}
+
+func New(config Config) *Feed {
+	return &Feed{
+		config:     config,
+		fragments:  make(map[string]fragment),
+		documents:  make(map[Format]*Document),
+		modifiedAt: time.Now(),
+	}
+}
+
+// Upsert substitui as ofertas do produto. Sem ofertas, o produto sai do feed.
+func (f *Feed) Upsert(productID string, items []Item) {
+	if len(items) == 0 {
+		f.Delete(productID)
+		return
+	}
+	rendered := renderFragment(items)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.touch(productID)
+	current, ok := f.fragments[productID]
+	if ok && bytes.Equal(current.xml, rendered.xml) && bytes.Equal(current.tsv, rendered.tsv) {
+		return
+	}
+	f.fragments[productID] = rendered
+	f.changed()
+}
+
+func (f *Feed) Delete(productID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.touch(productID)
+	if _, ok := f.fragments[productID]; !ok {
+		return
+	}
+	delete(f.fragments, productID)
+	f.changed()
+}
+
+// BeginReplace marca o início de uma geração completa, antes da leitura do catálogo
+func (f *Feed) BeginReplace() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.dirty = make(map[string]bool)
+}
+
+// AbortReplace encerra uma geração que falhou, sem alterar as ofertas
+func (f *Feed) AbortReplace() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.dirty = nil
+}
+
+// Replace troca todas as ofertas, usado na geração completa. Os produtos alterados
+// por eventos desde BeginReplace mantêm as ofertas atuais.
+func (f *Feed) Replace(items map[string][]Item) {
+	fragments := make(map[string]fragment, len(items))
+	for productID, productItems := range items {
+		if len(productItems) > 0 {
+			fragments[productID] = renderFragment(productItems)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for productID := range f.dirty {
+		if current, ok := f.fragments[productID]; ok {
+			fragments[productID] = current
+		} else {
+			delete(fragments, productID)
+		}
+	}
+	f.fragments = fragments
+	f.dirty = nil
+	f.ready = true
+	f.changed()
+}
+
+// Marca o produto alterado durante uma geração completa; chamada com o lock de escrita
+func (f *Feed) touch(productID string) {
+	if f.dirty != nil {
+		f.dirty[productID] = true
+	}
+}
+
+// Descarta os documentos montados; chamada com o lock de escrita
+func (f *Feed) changed() {
+	f.documents = make(map[Format]*Document)
+	f.modifiedAt = time.Now()
+}
+
+// Document retorna o feed completo no formato pedido, ou ErrNotReady antes da
+// primeira geração completa
+func (f *Feed) Document(format Format) (*Document, error) {
+	f.mu.RLock()
+	document, ready := f.documents[format], f.ready
+	f.mu.RUnlock()
+	if !ready {
+		return nil, ErrNotReady
+	}
+	if document != nil {
+		return document, nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if document := f.documents[format]; document != nil {
+		return document, nil
+	}
+	document = f.render(format)
+	f.documents[format] = document
+	return document, nil
+}
+
+// Monta o documento com os produtos em ordem de ID, para que o conteúdo e o ETag
+// dependam só das ofertas
+func (f *Feed) render(format Format) *Document {
+	ids := make([]string, 0, len(f.fragments))
+	for id := range f.fragments {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var buf bytes.Buffer
+	document := &Document{ModifiedAt: f.modifiedAt}
+	if format == FormatTSV {
+		document.ContentType = "text/tab-separated-values; charset=utf-8"
+		buf.WriteString(strings.Join(tsvColumns, "\t"))
+		buf.WriteByte('\n')
+		for _, id := range ids {
+			buf.Write(f.fragments[id].tsv)
+		}
+	} else {
+		document.ContentType = "application/rss+xml; charset=utf-8"
+		buf.WriteString(xml.Header)
+		buf.WriteString(`<rss version="2.0" xmlns:g="http://base.google.com/ns/1.0">` + "\n<channel>\n")
+		writeElement(&buf, "title", f.config.Title)
+		writeElement(&buf, "link", f.config.SiteURL)
+		writeElement(&buf, "description", f.config.Description)
+		for _, id := range ids {
+			buf.Write(f.fragments[id].xml)
+		}
+		buf.WriteString("</channel>\n</rss>\n")
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	document.Content = buf.Bytes()
+	document.ETag = `"` + hex.EncodeToString(sum[:16]) + `"`
+	return document
+}
+
+func renderFragment(items []Item) fragment {
+	var xmlBuf, tsvBuf bytes.Buffer
+	for _, item := range items {
+		writeXMLItem(&xmlBuf, item)
+		writeTSVRow(&tsvBuf, item)
+	}
+	return fragment{xml: xmlBuf.Bytes(), tsv: tsvBuf.Bytes()}
+}
+
+func writeXMLItem(buf *bytes.Buffer, item Item) {
+	buf.WriteString("<item>\n")
+	writeElement(buf, "g:id", item.ID)
+	writeElement(buf, "g:item_group_id", item.ItemGroupID)
+	writeElement(buf, "title", item.Title)
+	writeElement(buf, "description", item.Description)
+	writeElement(buf, "link", item.Link)
+	writeElement(buf, "g:image_link", item.ImageLink)
+	for _, image := range item.AdditionalImageLinks {
+		writeElement(buf, "g:additional_image_link", image)
+	}
+	writeElement(buf, "g:availability", item.Availability)
+	writeElement(buf, "g:price", item.Price)
+	writeElement(buf, "g:brand", item.Brand)
+	writeElement(buf, "g:gtin", item.GTIN)
+	if !item.IdentifierExists {
+		writeElement(buf, "g:identifier_exists", "no")
+	}
+	writeElement(buf, "g:condition", item.Condition)
+	writeElement(buf, "g:google_product_category", item.GoogleCategory)
+	writeElement(buf, "g:product_type", item.ProductType)
+	writeElement(buf, "g:color", item.Color)
+	writeElement(buf, "g:size", item.Size)
+	if item.IsBundle {
+		writeElement(buf, "g:is_bundle", "yes")
+	}
+	buf.WriteString("</item>\n")
+}
+
+// Campos vazios são omitidos
+func writeElement(buf *bytes.Buffer, name, value string) {
+	if value == "" {
+		return
+	}
+	buf.WriteString("<" + name + ">")
+	xml.EscapeText(buf, []byte(value))
+	buf.WriteString("</" + name + ">\n")
+}
+
+func writeTSVRow(buf *bytes.Buffer, item Item) {
+	identifierExists := "yes"
+	if !item.IdentifierExists {
+		identifierExists = "no"
+	}
+	isBundle := ""
+	if item.IsBundle {
+		isBundle = "yes"
+	}
+
+	values := []string{
+		item.ID, item.ItemGroupID, item.Title, item.Description, item.Link, item.ImageLink,
+		strings.Join(item.AdditionalImageLinks, ","), item.Availability, item.Price, item.Brand,
+		item.GTIN, identifierExists, item.Condition, item.GoogleCategory, item.ProductType,
+		item.Color, item.Size, isBundle,
+	}
+	for i, value := range values {
+		values[i] = tsvValue(value)
+	}
+	buf.WriteString(strings.Join(values, "\t"))
+	buf.WriteByte('\n')
+}
+
+// O TSV do Merchant Center não tem escape; tabulações e quebras de linha viram espaços
+func tsvValue(value string) string {
+	return strings.Join(strings.FieldsFunc(value, func(r rune) bool {
+		return r == '\t' || r == '\n' || r == '\r'
+	}), " ")
+}
@@ -0,0 +1,76 @@
+package feed
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDocumentNotReady(t *testing.T) {
+	f := New(Config{Title: "Loja"})
+	f.Upsert("p1", []Item{{ID: "p1", Title: "Camiseta"}})
+
+	if _, err := f.Document(FormatXML); !errors.Is(err, ErrNotReady) {
+		t.Fatalf("esperado ErrNotReady antes da primeira geração, obtido %v", err)
+	}
+
+	f.BeginReplace()
+	f.AbortReplace()
+	if _, err := f.Document(FormatXML); !errors.Is(err, ErrNotReady) {
+		t.Fatalf("esperado ErrNotReady depois de uma geração que falhou, obtido %v", err)
+	}
+
+	f.Replace(map[string][]Item{"p1": {{ID: "p1", Title: "Camiseta"}}})
+	if _, err := f.Document(FormatXML); err != nil {
+		t.Fatalf("erro inesperado depois da geração: %v", err)
+	}
+}
+
+func TestReplaceKeepsEventsDuringRebuild(t *testing.T) {
+	f := New(Config{Title: "Loja"})
+	f.Replace(map[string][]Item{
+		"p1": {{ID: "p1", Title: "Camiseta antiga"}},
+		"p2": {{ID: "p2", Title: "Bermuda"}},
+		"p3": {{ID: "p3", Title: "Boné"}},
+	})
+
+	// A geração lê o catálogo enquanto os eventos alteram p1, removem p2 e criam p4
+	f.BeginReplace()
+	f.Upsert("p1", []Item{{ID: "p1", Title: "Camiseta nova"}})
+	f.Delete("p2")
+	f.Upsert("p4", []Item{{ID: "p4", Title: "Meia"}})
+	f.Replace(map[string][]Item{
+		"p1": {{ID: "p1", Title: "Camiseta antiga"}},
+		"p2": {{ID: "p2", Title: "Bermuda"}},
+		"p3": {{ID: "p3", Title: "Boné atualizado"}},
+	})
+
+	// Depois da geração, os eventos voltam a valer sozinhos
+	f.Upsert("p3", []Item{{ID: "p3", Title: "Boné final"}})
+
+	document, err := f.Document(FormatTSV)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	content := string(document.Content)
+
+	tests := []struct {
+		name  string
+		title string
+		want  bool
+	}{
+		{"evento mais novo que a leitura é mantido", "Camiseta nova", true},
+		{"leitura mais antiga que o evento é descartada", "Camiseta antiga", false},
+		{"produto removido por evento não volta", "Bermuda", false},
+		{"produto criado por evento é mantido", "Meia", true},
+		{"evento depois da geração é aplicado", "Boné final", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Contains(content, tt.title); got != tt.want {
+				t.Errorf("feed contém %q = %v, esperado %v", tt.title, got, tt.want)
+			}
+		})
+	}
+}
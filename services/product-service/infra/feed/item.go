@@ -0,0 +1,215 @@
+package feed
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Valores de disponibilidade aceitos pelo Google Merchant Center
+const (
+	InStock    = "in_stock"
+	OutOfStock = "out_of_stock"
+)
+
+// Limites de tamanho dos campos de texto do Merchant Center
+const (
+	maxTitleLength       = 150
+	maxDescriptionLength = 5000
+	maxAdditionalImages  = 10
+)
+
+// Atributos usados como marca, na ordem de preferência
+var brandAttributes = []string{"marca", "brand"}
+
+// Opções das variantes exportadas nos campos próprios do Google
+var optionFields = map[string]string{
+	"cor":     "color",
+	"color":   "color",
+	"tamanho": "size",
+	"size":    "size",
+}
+
+// Config reúne os dados da loja usados nos links e nos campos padrão do feed
+type Config struct {
+	Title       string
+	Description string
+	// Endereço público da loja; prefixa os links dos produtos e as URLs relativas das imagens
+	SiteURL  string
+	Currency string
+	// Marca usada quando o produto não tem o atributo "marca"
+	DefaultBrand string
+}
+
+// CategoryInfo é a categoria do produto já resolvida na árvore
+type CategoryInfo struct {
+	// Nomes da raiz até a categoria, como "Eletrônicos > TV > Smart TV"
+	ProductType    string
+	GoogleCategory string
+}
+
+// Item é uma oferta do feed. Produtos com variantes geram um item por variante,
+// agrupados por ItemGroupID.
+type Item struct {
+	ID                   string
+	ItemGroupID          string
+	Title                string
+	Description          string
+	Link                 string
+	ImageLink            string
+	AdditionalImageLinks []string
+	Availability         string
+	Price                string
+	Brand                string
+	GTIN                 string
+	IdentifierExists     bool
+	Condition            string
+	GoogleCategory       string
+	ProductType          string
+	Color                string
+	Size                 string
+	IsBundle             bool
+}
+
+// Availability converte o status do produto na disponibilidade do Google. Produtos
+// descontinuados ficam fora do feed, indicado pelo segundo retorno.
+func Availability(status model.ProductStatus, available int) (string, bool) {
+	switch status {
+	case model.Discontinued:
+		return "", false
+	case model.OutOfStock:
+		return OutOfStock, true
+	}
+	if available > 0 {
+		return InStock, true
+	}
+	return OutOfStock, true
+}
+
+// Items gera as ofertas do produto; retorna vazio para produtos que não devem ser anunciados
+func Items(product *model.Product, category CategoryInfo, config Config) []Item {
+	if product.Status == model.Discontinued {
+		return nil
+	}
+
+	images := productImages(product, config)
+	base := Item{
+		Title:          truncate(product.Name, maxTitleLength),
+		Description:    truncate(product.Description, maxDescriptionLength),
+		Brand:          brand(product, config),
+		GTIN:           product.GTIN,
+		Condition:      "new",
+		GoogleCategory: category.GoogleCategory,
+		ProductType:    category.ProductType,
+		IsBundle:       product.IsBundle(),
+	}
+	if base.ProductType == "" {
+		base.ProductType = product.Category.Name
+	}
+	link := strings.TrimRight(config.SiteURL, "/") + "/products/" + product.ID.Hex()
+
+	if len(product.Variants) == 0 {
+		if product.Price <= 0 {
+			return nil
+		}
+		item := base
+		item.ID = product.SKU
+		if item.ID == "" {
+			item.ID = product.ID.Hex()
+		}
+		item.Link = link
+		item.Price = formatPrice(product.Price, config.Currency)
+		item.Availability, _ = Availability(product.Status, product.AvailableFor(""))
+		setImages(&item, images)
+		item.IdentifierExists = item.GTIN != "" || item.Brand != ""
+		return []Item{item}
+	}
+
+	items := make([]Item, 0, len(product.Variants))
+	for _, variant := range product.Variants {
+		price := product.PriceFor(variant.SKU)
+		if price <= 0 {
+			continue
+		}
+
+		item := base
+		item.ID = variant.SKU
+		item.ItemGroupID = product.ID.Hex()
+		item.Link = link + "?sku=" + url.QueryEscape(variant.SKU)
+		item.Price = formatPrice(price, config.Currency)
+		item.Availability, _ = Availability(product.Status, product.AvailableFor(variant.SKU))
+		if variant.GTIN != "" {
+			item.GTIN = variant.GTIN
+		}
+		for name, value := range variant.Options {
+			switch optionFields[strings.ToLower(name)] {
+			case "color":
+				item.Color = value
+			case "size":
+				item.Size = value
+			}
+		}
+
+		// As imagens próprias da variante vêm antes das imagens do produto
+		variantImages := make([]string, 0, len(variant.Images)+len(images))
+		for _, image := range variant.Images {
+			variantImages = append(variantImages, absoluteURL(image, config.SiteURL))
+		}
+		setImages(&item, append(variantImages, images...))
+		item.IdentifierExists = item.GTIN != "" || item.Brand != ""
+		items = append(items, item)
+	}
+	return items
+}
+
+func productImages(product *model.Product, config Config) []string {
+	images := make([]string, 0, len(product.Media))
+	for _, media := range product.Media {
+		if media.Type == model.MediaImage && media.URL != "" {
+			images = append(images, absoluteURL(media.URL, config.SiteURL))
+		}
+	}
+	return images
+}
+
+func setImages(item *Item, images []string) {
+	if len(images) == 0 {
+		return
+	}
+	item.ImageLink = images[0]
+	additional := images[1:]
+	if len(additional) > maxAdditionalImages {
+		additional = additional[:maxAdditionalImages]
+	}
+	item.AdditionalImageLinks = additional
+}
+
+func brand(product *model.Product, config Config) string {
+	for _, key := range brandAttributes {
+		if value, ok := product.Attributes[key].(string); ok && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return config.DefaultBrand
+}
+
+// O Merchant Center exige URLs absolutas; as URLs do armazenamento local são relativas ao site
+func absoluteURL(value, siteURL string) string {
+	if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+		return value
+	}
+	return strings.TrimRight(siteURL, "/") + "/" + strings.TrimLeft(value, "/")
+}
+
+func formatPrice(price float64, currency string) string {
+	return fmt.Sprintf("%.2f %s", price, currency)
+}
+
+func truncate(value string, limit int) string {
+	runes := []rune(strings.TrimSpace(value))
+	if len(runes) <= limit {
+		return string(runes)
+	}
+	return string(runes[:limit])
+}
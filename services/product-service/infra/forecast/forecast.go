@@ -0,0 +1,249 @@
+// Package forecast ajusta modelos simples a séries semanais de vendas e escolhe,
+// por backtest, o que melhor prevê as últimas semanas conhecidas.
+package forecast
+
+import "math"
+
+type Method string
+
+const (
+	MovingAverage Method = "MOVING_AVERAGE"
+	// Suavização exponencial de Holt-Winters com sazonalidade aditiva
+	HoltWinters Method = "HOLT_WINTERS"
+)
+
+// Nível de confiança dos intervalos e o quantil da normal correspondente
+const (
+	ConfidenceLevel = 0.95
+	z95             = 1.96
+)
+
+var (
+	movingAverageWindows = []int{4, 8, 12}
+	// Sazonalidade anual e trimestral, em semanas; cada uma exige duas temporadas de histórico
+	seasonLengths = []int{52, 13}
+	alphaGrid     = []float64{0.1, 0.2, 0.4, 0.6, 0.8}
+	betaGrid      = []float64{0, 0.05, 0.15}
+	gammaGrid     = []float64{0.05, 0.2, 0.4}
+)
+
+// Point é a previsão de uma semana com o intervalo de confiança
+type Point struct {
+	Forecast float64
+	Lower    float64
+	Upper    float64
+}
+
+// Result é o modelo escolhido e as previsões para as próximas semanas. BacktestMAE
+// é o erro absoluto médio nas HoldoutWeeks semanas reservadas; sem histórico
+// suficiente para o backtest, HoldoutWeeks é zero.
+type Result struct {
+	Method       Method
+	Parameters   map[string]float64
+	BacktestMAE  float64
+	HoldoutWeeks int
+	Points       []Point
+}
+
+// Um modelo ajustado: previsões para o horizonte e desvio padrão dos erros de um passo
+type fit struct {
+	method     Method
+	parameters map[string]float64
+	forecast   []float64
+	sigma      float64
+}
+
+// Cada candidato ajusta uma família de modelos ao histórico; ok é falso quando o
+// histórico é curto demais para ele
+type candidate func(history []float64, horizon int) (fit, bool)
+
+func candidates() []candidate {
+	var list []candidate
+	for _, window := range movingAverageWindows {
+		window := window
+		list = append(list, func(history []float64, horizon int) (fit, bool) {
+			return fitMovingAverage(history, horizon, window)
+		})
+	}
+	for _, season := range seasonLengths {
+		season := season
+		list = append(list, func(history []float64, horizon int) (fit, bool) {
+			return fitHoltWinters(history, horizon, season)
+		})
+	}
+	return list
+}
+
+// Forecast prevê as próximas horizon semanas da série. Cada candidato é ajustado sem
+// as últimas semanas, que servem de backtest; o de menor erro é reajustado com a
+// série inteira.
+func Forecast(series []float64, horizon int) *Result {
+	if len(series) == 0 || horizon <= 0 {
+		return nil
+	}
+
+	holdout := horizon
+	if holdout > len(series)/4 {
+		holdout = len(series) / 4
+	}
+
+	var best candidate
+	bestMAE := math.Inf(1)
+	if holdout > 0 {
+		train, test := series[:len(series)-holdout], series[len(series)-holdout:]
+		for _, c := range candidates() {
+			result, ok := c(train, holdout)
+			if !ok {
+				continue
+			}
+			if mae := meanAbsoluteError(result.forecast, test); mae < bestMAE {
+				best, bestMAE = c, mae
+			}
+		}
+	}
+
+	var chosen fit
+	ok := false
+	if best != nil {
+		chosen, ok = best(series, horizon)
+	}
+	if !ok {
+		// Histórico curto: média de todas as semanas, sem backtest
+		chosen, _ = fitMovingAverage(series, horizon, len(series))
+		bestMAE, holdout = 0, 0
+	}
+
+	result := &Result{
+		Method:       chosen.method,
+		Parameters:   chosen.parameters,
+		BacktestMAE:  round(bestMAE),
+		HoldoutWeeks: holdout,
+	}
+	// O erro cresce com a distância da previsão; a raiz do passo é uma aproximação
+	for step, value := range chosen.forecast {
+		value = math.Max(value, 0)
+		margin := z95 * chosen.sigma * math.Sqrt(float64(step+1))
+		result.Points = append(result.Points, Point{
+			Forecast: round(value),
+			Lower:    round(math.Max(value-margin, 0)),
+			Upper:    round(value + margin),
+		})
+	}
+	return result
+}
+
+// Média das últimas window semanas, repetida no horizonte
+func fitMovingAverage(history []float64, horizon, window int) (fit, bool) {
+	if window <= 0 || len(history) < window {
+		return fit{}, false
+	}
+
+	// Sem semanas depois da primeira janela, o desvio vem da própria série
+	sigma := stddev(history)
+	if len(history) > window {
+		var squared float64
+		for t := window; t < len(history); t++ {
+			e := history[t] - mean(history[t-window:t])
+			squared += e * e
+		}
+		sigma = math.Sqrt(squared / float64(len(history)-window))
+	}
+
+	level := mean(history[len(history)-window:])
+	forecast := make([]float64, horizon)
+	for i := range forecast {
+		forecast[i] = level
+	}
+	return fit{
+		method:     MovingAverage,
+		parameters: map[string]float64{"window": float64(window)},
+		forecast:   forecast,
+		sigma:      sigma,
+	}, true
+}
+
+// Holt-Winters aditivo com os parâmetros de suavização escolhidos em grade pelo
+// menor erro quadrático de um passo no próprio histórico
+func fitHoltWinters(history []float64, horizon, season int) (fit, bool) {
+	if len(history) < 2*season {
+		return fit{}, false
+	}
+
+	best := fit{sigma: math.Inf(1)}
+	for _, alpha := range alphaGrid {
+		for _, beta := range betaGrid {
+			for _, gamma := range gammaGrid {
+				forecast, sigma := holtWinters(history, horizon, season, alpha, beta, gamma)
+				if sigma < best.sigma {
+					best = fit{
+						method: HoltWinters,
+						parameters: map[string]float64{
+							"alpha": alpha, "beta": beta, "gamma": gamma, "season": float64(season),
+						},
+						forecast: forecast,
+						sigma:    sigma,
+					}
+				}
+			}
+		}
+	}
+	return best, true
+}
+
+func holtWinters(history []float64, horizon, season int, alpha, beta, gamma float64) ([]float64, float64) {
+	// Nível e tendência iniciais pelas médias das duas primeiras temporadas
+	first, second := mean(history[:season]), mean(history[season:2*season])
+	level := first
+	trend := (second - first) / float64(season)
+	seasonal := make([]float64, season)
+	for i := range seasonal {
+		seasonal[i] = history[i] - first
+	}
+
+	var squared float64
+	for t := season; t < len(history); t++ {
+		index := t % season
+		e := history[t] - (level + trend + seasonal[index])
+		squared += e * e
+
+		previous := level
+		level = alpha*(history[t]-seasonal[index]) + (1-alpha)*(level+trend)
+		trend = beta*(level-previous) + (1-beta)*trend
+		seasonal[index] = gamma*(history[t]-level) + (1-gamma)*seasonal[index]
+	}
+
+	forecast := make([]float64, horizon)
+	for k := 1; k <= horizon; k++ {
+		forecast[k-1] = level + float64(k)*trend + seasonal[(len(history)+k-1)%season]
+	}
+	return forecast, math.Sqrt(squared / float64(len(history)-season))
+}
+
+func meanAbsoluteError(forecast, actual []float64) float64 {
+	var total float64
+	for i := range actual {
+		total += math.Abs(math.Max(forecast[i], 0) - actual[i])
+	}
+	return total / float64(len(actual))
+}
+
+func mean(values []float64) float64 {
+	var total float64
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
+
+func stddev(values []float64) float64 {
+	average := mean(values)
+	var squared float64
+	for _, value := range values {
+		squared += (value - average) * (value - average)
+	}
+	return math.Sqrt(squared / float64(len(values)))
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,97 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+)
+
+// Série com sazonalidade trimestral (13 semanas) e pico no fim de cada temporada
+func seasonalSeries(weeks int) []float64 {
+	series := make([]float64, weeks)
+	for t := range series {
+		series[t] = 40 + 30*math.Sin(2*math.Pi*float64(t)/13)
+		if t%13 == 12 {
+			series[t] += 60
+		}
+	}
+	return series
+}
+
+func constantSeries(weeks int, value float64) []float64 {
+	series := make([]float64, weeks)
+	for t := range series {
+		series[t] = value
+	}
+	return series
+}
+
+func TestForecastChoosesModelByBacktest(t *testing.T) {
+	tests := []struct {
+		name        string
+		series      []float64
+		wantMethod  Method
+		wantSeason  float64
+		wantHoldout int
+	}{
+		{"série sazonal escolhe Holt-Winters", seasonalSeries(60), HoltWinters, 13, 12},
+		{"série estável fica com a média móvel", constantSeries(60, 20), MovingAverage, 0, 12},
+		{"histórico curto demais para Holt-Winters usa a média móvel", seasonalSeries(20), MovingAverage, 0, 5},
+		{"histórico curto demais para o backtest usa a média de tudo", []float64{3, 5, 4}, MovingAverage, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Forecast(tt.series, 12)
+			if result == nil {
+				t.Fatal("esperada uma previsão")
+			}
+			if result.Method != tt.wantMethod {
+				t.Fatalf("método = %s, esperado %s", result.Method, tt.wantMethod)
+			}
+			if tt.wantSeason != 0 && result.Parameters["season"] != tt.wantSeason {
+				t.Errorf("temporada = %v, esperada %v", result.Parameters["season"], tt.wantSeason)
+			}
+			if result.HoldoutWeeks != tt.wantHoldout {
+				t.Errorf("semanas de backtest = %d, esperado %d", result.HoldoutWeeks, tt.wantHoldout)
+			}
+			if len(result.Points) != 12 {
+				t.Fatalf("previstas %d semanas, esperado 12", len(result.Points))
+			}
+			for i, point := range result.Points {
+				if point.Lower < 0 || point.Lower > point.Forecast || point.Forecast > point.Upper {
+					t.Errorf("semana %d fora do intervalo: %+v", i, point)
+				}
+			}
+		})
+	}
+}
+
+func TestForecastHoltWintersFollowsSeason(t *testing.T) {
+	series := seasonalSeries(78)
+	result := Forecast(series[:66], 12)
+	if result.Method != HoltWinters {
+		t.Fatalf("método = %s, esperado %s", result.Method, HoltWinters)
+	}
+
+	// O pico da semana 77 (fim da temporada) deve aparecer na previsão
+	if peak := result.Points[77-66].Forecast; math.Abs(peak-series[77]) > 5 {
+		t.Errorf("previsão do pico = %.2f, real %.2f", peak, series[77])
+	}
+	if mae := meanAbsoluteError(pointForecasts(result), series[66:]); mae > 10 {
+		t.Errorf("erro absoluto médio = %.2f, esperado até 10", mae)
+	}
+}
+
+func TestForecastEmptySeries(t *testing.T) {
+	if result := Forecast(nil, 12); result != nil {
+		t.Errorf("esperado nil para série vazia, obtido %+v", result)
+	}
+}
+
+func pointForecasts(result *Result) []float64 {
+	values := make([]float64, len(result.Points))
+	for i, point := range result.Points {
+		values[i] = point.Forecast
+	}
+	return values
+}
@@ -0,0 +1,168 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/gif" // registra o decodificador de GIF usado por image.Decode
+	"image/jpeg"
+	"image/png"
+)
+
+// Limite de pixels aceito na decodificação, para evitar imagens que ocupam gigabytes em memória
+const MaxPixels = 50_000_000
+
+var ErrImageTooLarge = errors.New("a imagem excede o limite de resolução")
+
+// Size é um tamanho de miniatura, limitado pelo maior lado
+type Size struct {
+	Name string
+	Max  int
+}
+
+var DefaultSizes = []Size{
+	{Name: "small", Max: 150},
+	{Name: "medium", Max: 400},
+	{Name: "large", Max: 800},
+}
+
+// Thumbnail é uma miniatura já codificada
+type Thumbnail struct {
+	Size        string
+	Width       int
+	Height      int
+	ContentType string
+	Extension   string
+	Data        []byte
+}
+
+// Decodable indica se o tipo de imagem pode ser decodificado para gerar miniaturas
+func Decodable(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	default:
+		return false
+	}
+}
+
+// Dimensions lê apenas o cabeçalho da imagem
+func Dimensions(data []byte) (int, int, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, err
+	}
+	return config.Width, config.Height, nil
+}
+
+// Generate gera as miniaturas nos tamanhos menores que a imagem original. Imagens
+// com transparência geram PNG; as demais, JPEG.
+func Generate(data []byte, sizes []Size) ([]Thumbnail, error) {
+	width, height, err := Dimensions(data)
+	if err != nil {
+		return nil, err
+	}
+	if width*height > MaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	rgba := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(rgba, rgba.Bounds(), src, src.Bounds().Min, draw.Src)
+	opaque := rgba.Opaque()
+
+	var thumbnails []Thumbnail
+	for _, size := range sizes {
+		w, h := fit(width, height, size.Max)
+		if w >= width && h >= height {
+			continue
+		}
+
+		resized := resize(rgba, w, h)
+		var buf bytes.Buffer
+		thumbnail := Thumbnail{Size: size.Name, Width: w, Height: h}
+		if opaque {
+			err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: 85})
+			thumbnail.ContentType, thumbnail.Extension = "image/jpeg", "jpg"
+		} else {
+			err = png.Encode(&buf, resized)
+			thumbnail.ContentType, thumbnail.Extension = "image/png", "png"
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		thumbnail.Data = buf.Bytes()
+		thumbnails = append(thumbnails, thumbnail)
+	}
+
+	return thumbnails, nil
+}
+
+// Calcula as dimensões que cabem em limit x limit mantendo a proporção
+func fit(width, height, limit int) (int, int) {
+	if width <= limit && height <= limit {
+		return width, height
+	}
+	if width >= height {
+		h := height * limit / width
+		if h < 1 {
+			h = 1
+		}
+		return limit, h
+	}
+	w := width * limit / height
+	if w < 1 {
+		w = 1
+	}
+	return w, limit
+}
+
+// Reduz a imagem pela média das áreas de origem (box filter), que evita o
+// serrilhado de uma amostragem simples ao reduzir bastante
+func resize(src *image.RGBA, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcH / height
+		y1 := (y + 1) * srcH / height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+
+		for x := 0; x < width; x++ {
+			x0 := x * srcW / width
+			x1 := (x + 1) * srcW / width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				offset := sy*src.Stride + x0*4
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(src.Pix[offset])
+					g += uint64(src.Pix[offset+1])
+					b += uint64(src.Pix[offset+2])
+					a += uint64(src.Pix[offset+3])
+					n++
+					offset += 4
+				}
+			}
+
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
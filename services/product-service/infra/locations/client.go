@@ -0,0 +1,125 @@
+// Package locations consulta o estoque e o livro de estoque do serviço de localizações
+// a partir do serviço de produtos.
+package locations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnavailable indica que o serviço de localizações não respondeu
+var ErrUnavailable = errors.New("serviço de localizações indisponível")
+
+// Stock é o saldo de um produto (ou variante) em uma localização
+type Stock struct {
+	ProductID  string `json:"productId"`
+	SKU        string `json:"sku"`
+	LocationID string `json:"locationId"`
+	Available  int    `json:"available"`
+	InTransit  int    `json:"inTransit"`
+}
+
+// Movement é um lançamento do livro de estoque. Quantity tem o sinal do efeito no
+// estoque, negativo nas vendas.
+type Movement struct {
+	ID            string    `json:"id"`
+	ProductID     string    `json:"productId"`
+	SKU           string    `json:"sku"`
+	LocationID    string    `json:"locationId"`
+	Quantity      int       `json:"quantity"`
+	ReferenceType string    `json:"referenceType"`
+	Reference     string    `json:"reference"`
+	Status        string    `json:"status"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// Inventory lê o estoque e as vendas das localizações
+type Inventory interface {
+	// ProductStock retorna o saldo do produto em cada localização
+	ProductStock(productID string) ([]Stock, error)
+	// Sales retorna as vendas lançadas no livro a partir de since; sem produto,
+	// as de todos os produtos
+	Sales(productID string, since time.Time) ([]Movement, error)
+}
+
+// Tamanho da página usado na leitura do livro, o máximo aceito pelo serviço
+const movementsPageSize = 200
+
+// Client consulta os endpoints /inventory do serviço de localizações
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *Client) ProductStock(productID string) ([]Stock, error) {
+	var stock []Stock
+	if err := c.get("/inventory/product/"+url.PathEscape(productID), &stock); err != nil {
+		return nil, err
+	}
+	return stock, nil
+}
+
+// Sales lê o livro página a página. O fim do período é fixado na primeira página,
+// para que lançamentos novos não desloquem as páginas seguintes; os lançamentos
+// pendentes ou descartados são ignorados.
+func (c *Client) Sales(productID string, since time.Time) ([]Movement, error) {
+	query := url.Values{
+		"type":     {"SALE"},
+		"from":     {since.UTC().Format(time.RFC3339)},
+		"to":       {time.Now().UTC().Format(time.RFC3339)},
+		"pageSize": {strconv.Itoa(movementsPageSize)},
+	}
+	if productID != "" {
+		query.Set("productId", productID)
+	}
+
+	var sales []Movement
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var result struct {
+			Total     int64      `json:"total"`
+			Movements []Movement `json:"movements"`
+		}
+		if err := c.get("/inventory/movements?"+query.Encode(), &result); err != nil {
+			return nil, err
+		}
+		for _, movement := range result.Movements {
+			// Só contam os lançamentos aplicados; os anteriores ao status vêm sem o campo
+			if movement.Status == "" || movement.Status == "APPLIED" {
+				sales = append(sales, movement)
+			}
+		}
+		if len(result.Movements) < movementsPageSize || int64(page*movementsPageSize) >= result.Total {
+			return sales, nil
+		}
+	}
+}
+
+func (c *Client) get(path string, target interface{}) error {
+	resp, err := c.http.Get(c.baseURL + path)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: status %d", ErrUnavailable, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("%w: resposta inválida: %v", ErrUnavailable, err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Package orders consulta o serviço de pedidos a partir do serviço de produtos.
+package orders
+
+import (
+	"Varejo-Golang-Microservices/middleware"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrUnavailable indica que o serviço de pedidos não respondeu
+var ErrUnavailable = errors.New("serviço de pedidos indisponível")
+
+// Purchase é o resultado da verificação de compra
+type Purchase struct {
+	Verified    bool      `json:"verified"`
+	OrderID     string    `json:"orderId"`
+	PurchasedAt time.Time `json:"purchasedAt"`
+}
+
+// PurchaseVerifier verifica se o cliente comprou o produto
+type PurchaseVerifier interface {
+	VerifyPurchase(customerID, productID string) (*Purchase, error)
+}
+
+// Identidade do serviço de produtos no token enviado ao serviço de pedidos
+const serviceUser = "product-service"
+
+// Client consulta o endpoint /orders/verify-purchase do serviço de pedidos, que exige autenticação
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (c *Client) VerifyPurchase(customerID, productID string) (*Purchase, error) {
+	query := url.Values{"customerId": {customerID}, "productId": {productID}}
+	token, err := middleware.GenerateToken(serviceUser)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar o token do serviço: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/orders/verify-purchase?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrUnavailable, resp.StatusCode)
+	}
+
+	var purchase Purchase
+	if err := json.NewDecoder(resp.Body).Decode(&purchase); err != nil {
+		return nil, fmt.Errorf("%w: resposta inválida: %v", ErrUnavailable, err)
+	}
+	return &purchase, nil
+}
@@ -0,0 +1,176 @@
+package search
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"strings"
+	"unicode"
+)
+
+// Mapeamento de caracteres acentuados para a forma sem acento
+var accentFolding = map[rune]rune{
+	'á': 'a', 'à': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ç': 'c', 'ñ': 'n',
+}
+
+// Analisador de cada idioma suportado; o pt-BR é o padrão
+type analyzer struct {
+	stopwords map[string]bool
+	stem      func(string) string
+}
+
+var analyzers = map[string]analyzer{
+	model.DefaultLocale: {stopwords: stopwords, stem: Stem},
+	"es":                {stopwords: spanishStopwords, stem: stemSpanish},
+	"en":                {stopwords: englishStopwords, stem: stemEnglish},
+}
+
+var stopwords = map[string]bool{
+	"a": true, "o": true, "as": true, "os": true, "um": true, "uma": true, "uns": true, "umas": true,
+	"de": true, "da": true, "do": true, "das": true, "dos": true, "e": true, "ou": true,
+	"em": true, "na": true, "no": true, "nas": true, "nos": true, "para": true, "pra": true,
+	"por": true, "com": true, "sem": true, "que": true, "se": true, "ao": true, "aos": true,
+}
+
+var spanishStopwords = map[string]bool{
+	"a": true, "el": true, "la": true, "los": true, "las": true, "un": true, "una": true, "unos": true,
+	"unas": true, "de": true, "del": true, "al": true, "y": true, "o": true, "en": true, "para": true,
+	"por": true, "con": true, "sin": true, "que": true, "se": true,
+}
+
+var englishStopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "of": true, "in": true, "on": true,
+	"for": true, "with": true, "without": true, "to": true, "by": true, "at": true, "from": true, "is": true,
+}
+
+// Regras de redução de sufixo aplicadas em ordem, no estilo do stemmer RSLP.
+// Cada regra só é aplicada se sobrar um radical com pelo menos minStem letras.
+type suffixRule struct {
+	suffix      string
+	replacement string
+	minStem     int
+}
+
+var (
+	pluralRules = []suffixRule{
+		{"oes", "ao", 1}, {"aes", "ao", 1}, {"ais", "al", 1}, {"eis", "el", 2},
+		{"ois", "ol", 1}, {"uis", "ul", 1}, {"is", "il", 2}, {"les", "l", 2}, {"res", "r", 2},
+		{"ns", "m", 1}, {"s", "", 2},
+	}
+	adverbRules = []suffixRule{
+		{"mente", "", 4},
+	}
+	augmentativeRules = []suffixRule{
+		{"zinho", "", 3}, {"zinha", "", 3}, {"inho", "", 3}, {"inha", "", 3},
+		{"issimo", "", 3}, {"issima", "", 3},
+	}
+	feminineRules = []suffixRule{
+		{"ona", "ao", 3}, {"ora", "or", 3}, {"eira", "eiro", 3}, {"ica", "ico", 3},
+		{"osa", "oso", 3}, {"iva", "ivo", 3}, {"ada", "ado", 2}, {"ida", "ido", 3},
+		{"a", "o", 3},
+	}
+	vowelRules = []suffixRule{
+		{"o", "", 3}, {"e", "", 3},
+	}
+
+	// Espanhol: plural, advérbio, diminutivo e vogal final
+	spanishRules = [][]suffixRule{
+		{{"ces", "z", 2}, {"es", "", 3}, {"s", "", 2}},
+		{{"mente", "", 4}},
+		{{"ito", "", 3}, {"ita", "", 3}, {"isimo", "", 3}, {"isima", "", 3}},
+		{{"a", "", 3}, {"o", "", 3}, {"e", "", 3}},
+	}
+	// Inglês: plural, gerúndio e particípio, advérbio e e final
+	englishRules = [][]suffixRule{
+		{{"sses", "ss", 1}, {"ies", "y", 2}, {"ss", "ss", 1}, {"us", "us", 1}, {"s", "", 2}},
+		{{"ing", "", 3}, {"ed", "", 3}},
+		{{"ly", "", 3}},
+		{{"e", "", 3}},
+	}
+)
+
+// Analyze transforma o texto em termos indexáveis: minúsculas, sem acento,
+// sem stopwords e reduzidos ao radical em português.
+func Analyze(text string) []string {
+	return AnalyzeLocale(text, model.DefaultLocale)
+}
+
+// AnalyzeLocale analisa o texto com as stopwords e o radical do idioma. Um idioma
+// sem analisador próprio usa o do pt-BR.
+func AnalyzeLocale(text, locale string) []string {
+	a, ok := analyzers[locale]
+	if !ok {
+		a = analyzers[model.DefaultLocale]
+	}
+
+	fields := strings.FieldsFunc(Fold(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if a.stopwords[field] {
+			continue
+		}
+		terms = append(terms, a.stem(field))
+	}
+	return terms
+}
+
+// Fold converte para minúsculas e remove os acentos
+func Fold(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range strings.ToLower(text) {
+		if folded, ok := accentFolding[r]; ok {
+			r = folded
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Stem reduz uma palavra já normalizada ao seu radical. Números e palavras curtas não são alterados.
+func Stem(word string) string {
+	if len(word) < 4 || !unicode.IsLetter(rune(word[0])) {
+		return word
+	}
+
+	word = applyFirst(word, pluralRules)
+	word = applyFirst(word, adverbRules)
+	word = applyFirst(word, augmentativeRules)
+	word = applyFirst(word, feminineRules)
+	word = applyFirst(word, vowelRules)
+	return word
+}
+
+func stemSpanish(word string) string {
+	return stemWith(word, spanishRules)
+}
+
+func stemEnglish(word string) string {
+	return stemWith(word, englishRules)
+}
+
+// Aplica a primeira regra que couber de cada grupo, em ordem, como em Stem
+func stemWith(word string, groups [][]suffixRule) string {
+	if len(word) < 4 || !unicode.IsLetter(rune(word[0])) {
+		return word
+	}
+	for _, rules := range groups {
+		word = applyFirst(word, rules)
+	}
+	return word
+}
+
+func applyFirst(word string, rules []suffixRule) string {
+	for _, rule := range rules {
+		if strings.HasSuffix(word, rule.suffix) && len(word)-len(rule.suffix) >= rule.minStem {
+			return word[:len(word)-len(rule.suffix)] + rule.replacement
+		}
+	}
+	return word
+}
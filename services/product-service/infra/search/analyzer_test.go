@@ -0,0 +1,98 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"acentos agudos e til", "Ração Açúcar", "racao acucar"},
+		{"circunflexo e crase", "Você à Mão", "voce a mao"},
+		{"trema e eñe", "Pingüim Niño", "pinguim nino"},
+		{"sem acento", "camiseta", "camiseta"},
+		{"vazio", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Fold(tt.text); got != tt.want {
+				t.Errorf("Fold(%q) = %q, esperado %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		name string
+		word string
+		want string
+	}{
+		{"plural simples", "camisetas", "camiset"},
+		{"singular", "camiseta", "camiset"},
+		{"plural em oes", "botoes", "bota"},
+		{"plural em ais", "animais", "animal"},
+		{"advérbio", "rapidamente", "rapid"},
+		{"diminutivo", "copinho", "cop"},
+		{"feminino em ora", "impressora", "impressor"},
+		{"palavra curta", "cor", "cor"},
+		{"número", "1000", "1000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Stem(tt.word); got != tt.want {
+				t.Errorf("Stem(%q) = %q, esperado %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"remove stopwords", "Camiseta de Algodão", []string{"camiset", "algoda"}},
+		{"plural e singular iguais", "Camisetas", []string{"camiset"}},
+		{"separa por pontuação", "camisa-polo, 42", []string{"camis", "pol", "42"}},
+		{"só stopwords", "de para com", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Analyze(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Analyze(%q) = %q, esperado %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeLocale(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		locale string
+		want   []string
+	}{
+		{"inglês remove stopwords e plural", "The Running Shoes", "en", []string{"runn", "sho"}},
+		{"inglês plural em ies", "Batteries", "en", []string{"battery"}},
+		{"espanhol plural em es", "Colores del pantalón", "es", []string{"color", "pantalon"}},
+		{"espanhol plural em ces", "Luces", "es", []string{"luz"}},
+		{"idioma desconhecido usa o pt-BR", "Camisetas de Algodão", "fr", []string{"camiset", "algoda"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AnalyzeLocale(tt.text, tt.locale); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AnalyzeLocale(%q, %q) = %q, esperado %q", tt.text, tt.locale, got, tt.want)
+			}
+		})
+	}
+}
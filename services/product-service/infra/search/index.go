@@ -0,0 +1,564 @@
+package search
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Pesos de cada campo na relevância
+var fieldWeights = map[string]float64{
+	"name":        3.0,
+	"category":    2.0,
+	"description": 1.0,
+}
+
+// Parâmetros do BM25
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// PriceRange é uma faixa de preço usada no facet de preços. Max zero significa sem limite superior.
+type PriceRange struct {
+	Label string  `json:"label"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max,omitempty"`
+}
+
+func (r PriceRange) contains(price float64) bool {
+	return price >= r.Min && (r.Max == 0 || price < r.Max)
+}
+
+var DefaultPriceRanges = []PriceRange{
+	{Label: "até 50", Min: 0, Max: 50},
+	{Label: "50 a 100", Min: 50, Max: 100},
+	{Label: "100 a 200", Min: 100, Max: 200},
+	{Label: "200 a 500", Min: 200, Max: 500},
+	{Label: "acima de 500", Min: 500},
+}
+
+type SortOrder string
+
+const (
+	SortRelevance SortOrder = "relevance"
+	SortPriceAsc  SortOrder = "price_asc"
+	SortPriceDesc SortOrder = "price_desc"
+	SortName      SortOrder = "name"
+	SortNewest    SortOrder = "newest"
+)
+
+type Query struct {
+	Text     string
+	Category string
+	Status   model.ProductStatus
+	MinPrice float64
+	MaxPrice float64
+	// Filtros pelos atributos da categoria; sem o atributo no produto, o filtro é
+	// aplicado às opções das variantes, como antes dos atributos existirem
+	Attributes map[string]string
+	// Filtros pelas opções das variantes (cor, tamanho)
+	Options map[string]string
+	// Faixas de valores dos atributos numéricos, pela chave do atributo
+	AttributeRanges map[string]Range
+	Sort            SortOrder
+	Page            int
+	PageSize        int
+	// Idioma do texto da busca, dos campos usados na relevância, dos produtos
+	// retornados e dos nomes de categoria no facet; o filtro de categoria aceita o
+	// nome em qualquer idioma. Vazio vale o pt-BR.
+	Locale string
+}
+
+// Range é uma faixa de valores numéricos; limites nulos não restringem
+type Range struct {
+	Min *float64
+	Max *float64
+}
+
+func (r Range) contains(value float64) bool {
+	return (r.Min == nil || value >= *r.Min) && (r.Max == nil || value <= *r.Max)
+}
+
+type Hit struct {
+	Product *model.Product `json:"product"`
+	Score   float64        `json:"score"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type PriceRangeCount struct {
+	PriceRange
+	Count int `json:"count"`
+}
+
+type Facets struct {
+	Category   []FacetCount            `json:"category"`
+	Price      []PriceRangeCount       `json:"price"`
+	Status     []FacetCount            `json:"status"`
+	Attributes map[string][]FacetCount `json:"attributes"`
+	Options    map[string][]FacetCount `json:"options"`
+}
+
+type Result struct {
+	Total    int    `json:"total"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"pageSize"`
+	Hits     []Hit  `json:"hits"`
+	Facets   Facets `json:"facets"`
+}
+
+type document struct {
+	product *model.Product
+	// frequência dos termos e quantidade de termos de cada campo, por idioma
+	terms   map[string]map[string]map[string]int
+	lengths map[string]map[string]int
+	price   float64
+	attrs   map[string][]string
+	options map[string][]string
+	// nome da categoria em todos os idiomas, normalizado para o filtro
+	categories []string
+}
+
+// Index é um índice invertido em memória do catálogo. É seguro para uso concorrente
+// e não depende de nenhum serviço externo. Cada idioma tem os próprios campos,
+// analisados com o analisador do idioma, e as próprias listas de termos.
+type Index struct {
+	mu   sync.RWMutex
+	docs map[string]*document
+	// documentos de cada termo, por idioma
+	postings map[string]map[string]map[string]struct{}
+	// soma dos comprimentos de cada campo, por idioma, para o comprimento médio do BM25
+	totalLengths map[string]map[string]int
+}
+
+func NewIndex() *Index {
+	return &Index{
+		docs:         make(map[string]*document),
+		postings:     make(map[string]map[string]map[string]struct{}),
+		totalLengths: make(map[string]map[string]int),
+	}
+}
+
+// Upsert indexa o produto, substituindo a versão anterior se houver
+func (idx *Index) Upsert(product *model.Product) {
+	doc := newDocument(product)
+	id := product.ID.Hex()
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(id)
+	idx.docs[id] = doc
+	for locale, fields := range doc.terms {
+		if idx.postings[locale] == nil {
+			idx.postings[locale] = make(map[string]map[string]struct{})
+			idx.totalLengths[locale] = make(map[string]int)
+		}
+		postings := idx.postings[locale]
+		for field, terms := range fields {
+			for term := range terms {
+				if postings[term] == nil {
+					postings[term] = make(map[string]struct{})
+				}
+				postings[term][id] = struct{}{}
+			}
+			idx.totalLengths[locale][field] += doc.lengths[locale][field]
+		}
+	}
+}
+
+// Delete remove o produto do índice
+func (idx *Index) Delete(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(id)
+}
+
+// Replace reconstrói o índice inteiro com os produtos informados
+func (idx *Index) Replace(products []*model.Product) {
+	fresh := NewIndex()
+	for _, product := range products {
+		fresh.Upsert(product)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.docs = fresh.docs
+	idx.postings = fresh.postings
+	idx.totalLengths = fresh.totalLengths
+}
+
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return len(idx.docs)
+}
+
+func (idx *Index) remove(id string) {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+
+	for locale, fields := range doc.terms {
+		postings := idx.postings[locale]
+		for field, terms := range fields {
+			for term := range terms {
+				delete(postings[term], id)
+				if len(postings[term]) == 0 {
+					delete(postings, term)
+				}
+			}
+			idx.totalLengths[locale][field] -= doc.lengths[locale][field]
+		}
+		if len(postings) == 0 {
+			delete(idx.postings, locale)
+			delete(idx.totalLengths, locale)
+		}
+	}
+	delete(idx.docs, id)
+}
+
+func newDocument(product *model.Product) *document {
+	doc := &document{
+		product: product,
+		terms:   make(map[string]map[string]map[string]int),
+		lengths: make(map[string]map[string]int),
+		price:   lowestPrice(product),
+		attrs:   make(map[string][]string),
+		options: make(map[string][]string),
+	}
+
+	// Os campos de cada idioma têm o texto que o produto mostra nele (a tradução ou,
+	// sem ela, o texto em pt-BR), analisado com o analisador do idioma
+	for _, locale := range model.SupportedLocales {
+		localized := product.Localized(locale)
+		fields := map[string]string{
+			"name":        localized.Name,
+			"category":    product.Category.Localized(locale).Name,
+			"description": localized.Description,
+		}
+		doc.terms[locale] = make(map[string]map[string]int)
+		doc.lengths[locale] = make(map[string]int)
+		for field, text := range fields {
+			terms := AnalyzeLocale(text, locale)
+			doc.lengths[locale][field] = len(terms)
+			doc.terms[locale][field] = make(map[string]int)
+			for _, term := range terms {
+				doc.terms[locale][field][term]++
+			}
+		}
+	}
+
+	doc.categories = append(doc.categories, Fold(product.Category.Name))
+	for _, translation := range product.Category.Translations {
+		if translation.Name != "" {
+			doc.categories = append(doc.categories, Fold(translation.Name))
+		}
+	}
+
+	for _, option := range product.Options {
+		doc.options[Fold(option.Name)] = option.Values
+	}
+	for key, value := range product.Attributes {
+		doc.attrs[Fold(key)] = []string{model.FormatAttribute(value)}
+	}
+
+	return doc
+}
+
+// Menor preço entre o produto e as sobrescritas das variantes
+func lowestPrice(product *model.Product) float64 {
+	price := product.Price
+	for _, variant := range product.Variants {
+		if variant.Price != nil && *variant.Price < price {
+			price = *variant.Price
+		}
+	}
+	return price
+}
+
+// Search executa a consulta: todos os termos do texto precisam aparecer no produto
+// (em qualquer campo do idioma pedido), os resultados são ordenados por BM25
+// ponderado por campo e os facets são contados sobre o resultado, ignorando o
+// próprio filtro de cada facet.
+func (idx *Index) Search(query Query) *Result {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	locale := query.Locale
+	if _, ok := analyzers[locale]; !ok {
+		locale = model.DefaultLocale
+	}
+	scores := idx.match(AnalyzeLocale(query.Text, locale), locale)
+
+	facets := Facets{Attributes: make(map[string][]FacetCount), Options: make(map[string][]FacetCount)}
+	categoryCounts := make(map[string]int)
+	statusCounts := make(map[string]int)
+	priceCounts := make([]int, len(DefaultPriceRanges))
+	attrCounts := make(map[string]map[string]int)
+	optionCounts := make(map[string]map[string]int)
+
+	var hits []Hit
+	for id, score := range scores {
+		doc := idx.docs[id]
+		failed := query.failedFilters(doc)
+
+		if len(failed) == 0 {
+			hits = append(hits, Hit{Product: doc.product.Localized(query.Locale), Score: math.Round(score*1000) / 1000})
+		}
+		// Um documento conta no facet se só falhar no filtro do próprio facet
+		if onlyFails(failed, "category") {
+			categoryCounts[doc.product.Category.Localized(query.Locale).Name]++
+		}
+		if onlyFails(failed, "status") {
+			statusCounts[string(doc.product.Status)]++
+		}
+		if onlyFails(failed, "price") {
+			for i, r := range DefaultPriceRanges {
+				if r.contains(doc.price) {
+					priceCounts[i]++
+				}
+			}
+		}
+		countValues(attrCounts, doc.attrs, failed, "attr:")
+		countValues(optionCounts, doc.options, failed, "opt:")
+	}
+
+	facets.Category = sortedCounts(categoryCounts)
+	facets.Status = sortedCounts(statusCounts)
+	for i, r := range DefaultPriceRanges {
+		facets.Price = append(facets.Price, PriceRangeCount{PriceRange: r, Count: priceCounts[i]})
+	}
+	for name, counts := range attrCounts {
+		facets.Attributes[name] = sortedCounts(counts)
+	}
+	for name, counts := range optionCounts {
+		facets.Options[name] = sortedCounts(counts)
+	}
+
+	sortHits(hits, query.Sort, query.Text != "")
+
+	page, pageSize := query.Page, query.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	start := (page - 1) * pageSize
+	end := start + pageSize
+	if start > len(hits) {
+		start = len(hits)
+	}
+	if end > len(hits) {
+		end = len(hits)
+	}
+
+	return &Result{
+		Total:    len(hits),
+		Page:     page,
+		PageSize: pageSize,
+		Hits:     append([]Hit{}, hits[start:end]...),
+		Facets:   facets,
+	}
+}
+
+// Retorna a pontuação BM25 de cada documento que contém todos os termos nos campos
+// do idioma. Sem termos, todos os documentos são retornados com pontuação zero.
+func (idx *Index) match(terms []string, locale string) map[string]float64 {
+	scores := make(map[string]float64)
+	if len(terms) == 0 {
+		for id := range idx.docs {
+			scores[id] = 0
+		}
+		return scores
+	}
+
+	postings := idx.postings[locale]
+	for id := range postings[terms[0]] {
+		scores[id] = 0
+	}
+	for _, term := range terms[1:] {
+		for id := range scores {
+			if _, ok := postings[term][id]; !ok {
+				delete(scores, id)
+			}
+		}
+	}
+
+	total := float64(len(idx.docs))
+	for _, term := range terms {
+		df := float64(len(postings[term]))
+		idf := math.Log(1 + (total-df+0.5)/(df+0.5))
+
+		for id := range scores {
+			doc := idx.docs[id]
+			for field, weight := range fieldWeights {
+				tf := float64(doc.terms[locale][field][term])
+				if tf == 0 {
+					continue
+				}
+				avg := float64(idx.totalLengths[locale][field]) / total
+				if avg == 0 {
+					avg = 1
+				}
+				norm := tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(doc.lengths[locale][field])/avg))
+				scores[id] += weight * idf * norm
+			}
+		}
+	}
+
+	return scores
+}
+
+// Lista os filtros da consulta que o documento não atende
+func (q Query) failedFilters(doc *document) []string {
+	var failed []string
+
+	if q.Category != "" && !containsString(doc.categories, Fold(q.Category)) {
+		failed = append(failed, "category")
+	}
+	if q.Status != "" && doc.product.Status != q.Status {
+		failed = append(failed, "status")
+	}
+	if (q.MinPrice > 0 && doc.price < q.MinPrice) || (q.MaxPrice > 0 && doc.price > q.MaxPrice) {
+		failed = append(failed, "price")
+	}
+	for name, value := range q.Attributes {
+		key := Fold(name)
+		if !containsFolded(doc.attributeValues(key), value) {
+			failed = append(failed, "attr:"+key)
+		}
+	}
+	for name, r := range q.AttributeRanges {
+		key := Fold(name)
+		if !anyInRange(doc.attributeValues(key), r) && !containsString(failed, "attr:"+key) {
+			failed = append(failed, "attr:"+key)
+		}
+	}
+	for name, value := range q.Options {
+		key := Fold(name)
+		if !containsFolded(doc.options[key], value) {
+			failed = append(failed, "opt:"+key)
+		}
+	}
+
+	return failed
+}
+
+// Valores do atributo no produto; sem o atributo, os da opção de mesmo nome
+func (d *document) attributeValues(key string) []string {
+	if values, ok := d.attrs[key]; ok {
+		return values
+	}
+	return d.options[key]
+}
+
+// Soma os valores de cada campo ao facet, quando o documento só falha no filtro do próprio campo
+func countValues(counts map[string]map[string]int, values map[string][]string, failed []string, prefix string) {
+	for name, list := range values {
+		if !onlyFails(failed, prefix+name) {
+			continue
+		}
+		if counts[name] == nil {
+			counts[name] = make(map[string]int)
+		}
+		for _, value := range list {
+			counts[name][value]++
+		}
+	}
+}
+
+func onlyFails(failed []string, filter string) bool {
+	return len(failed) == 0 || (len(failed) == 1 && failed[0] == filter)
+}
+
+func containsFolded(values []string, value string) bool {
+	for _, v := range values {
+		if Fold(v) == Fold(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyInRange(values []string, r Range) bool {
+	for _, v := range values {
+		if number, err := strconv.ParseFloat(v, 64); err == nil && r.contains(number) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedCounts(counts map[string]int) []FacetCount {
+	result := make([]FacetCount, 0, len(counts))
+	for value, count := range counts {
+		result = append(result, FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result
+}
+
+func sortHits(hits []Hit, order SortOrder, hasText bool) {
+	if order == "" {
+		order = SortRelevance
+		if !hasText {
+			order = SortName
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		a, b := hits[i].Product, hits[j].Product
+		switch order {
+		case SortPriceAsc:
+			if lowestPrice(a) != lowestPrice(b) {
+				return lowestPrice(a) < lowestPrice(b)
+			}
+		case SortPriceDesc:
+			if lowestPrice(a) != lowestPrice(b) {
+				return lowestPrice(a) > lowestPrice(b)
+			}
+		case SortNewest:
+			if !a.AddedDate.Equal(b.AddedDate) {
+				return a.AddedDate.After(b.AddedDate)
+			}
+		case SortRelevance:
+			if hits[i].Score != hits[j].Score {
+				return hits[i].Score > hits[j].Score
+			}
+		}
+		// Desempate estável pelo nome e depois pelo ID
+		nameA, nameB := strings.ToLower(a.Name), strings.ToLower(b.Name)
+		if nameA != nameB {
+			return nameA < nameB
+		}
+		return a.ID.Hex() < b.ID.Hex()
+	})
+}
@@ -0,0 +1,124 @@
+package search
+
+import (
+	"Varejo-Golang-Microservices/services/product-service/domain/model"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newProduct(name, category, description string) *model.Product {
+	return &model.Product{
+		ID:          primitive.NewObjectID(),
+		Name:        name,
+		Description: description,
+		Category:    model.Category{Name: category},
+		Status:      model.Available,
+	}
+}
+
+func TestSearchRanking(t *testing.T) {
+	nameMatch := newProduct("Camiseta Azul", "Vestuário", "Algodão penteado")
+	descriptionMatch := newProduct("Regata Básica", "Vestuário", "Combina com camiseta e bermuda")
+	longName := newProduct("Camiseta Estampada Manga Longa Gola Careca Algodão", "Vestuário", "Tecido leve")
+	other := newProduct("Tênis de Corrida", "Calçados", "Amortecimento em gel")
+
+	idx := NewIndex()
+	idx.Replace([]*model.Product{nameMatch, descriptionMatch, longName, other})
+
+	tests := []struct {
+		name  string
+		query string
+		want  []*model.Product
+	}{
+		{"nome pesa mais que descrição e nome curto pesa mais", "camisetas", []*model.Product{nameMatch, longName, descriptionMatch}},
+		{"todos os termos são obrigatórios, no nome valem mais", "camiseta algodão", []*model.Product{longName, nameMatch}},
+		{"acento e caixa não importam", "TENIS CORRIDA", []*model.Product{other}},
+		{"termo ausente não retorna nada", "geladeira", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := idx.Search(Query{Text: tt.query})
+			if result.Total != len(tt.want) {
+				t.Fatalf("Search(%q) retornou %d produtos, esperado %d", tt.query, result.Total, len(tt.want))
+			}
+			for i, hit := range result.Hits {
+				if hit.Product.ID != tt.want[i].ID {
+					t.Errorf("posição %d: obtido %q, esperado %q", i, hit.Product.Name, tt.want[i].Name)
+				}
+			}
+			for i := 1; i < len(result.Hits); i++ {
+				if result.Hits[i].Score > result.Hits[i-1].Score {
+					t.Errorf("resultados fora de ordem de relevância: %v", result.Hits)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchUpsertAndDelete(t *testing.T) {
+	product := newProduct("Cafeteira Elétrica", "Eletroportáteis", "")
+	idx := NewIndex()
+	idx.Upsert(product)
+
+	renamed := *product
+	renamed.Name = "Chaleira Elétrica"
+	idx.Upsert(&renamed)
+
+	if got := idx.Search(Query{Text: "cafeteira"}).Total; got != 0 {
+		t.Errorf("termo antigo ainda indexado: %d resultados", got)
+	}
+	if got := idx.Search(Query{Text: "chaleira"}).Total; got != 1 {
+		t.Errorf("esperado 1 resultado para o novo nome, obtido %d", got)
+	}
+
+	idx.Delete(product.ID.Hex())
+	if idx.Len() != 0 || len(idx.postings) != 0 || len(idx.totalLengths) != 0 {
+		t.Errorf("índice não ficou vazio após remover: %d docs, %d termos", idx.Len(), len(idx.postings))
+	}
+}
+
+func TestSearchByLocale(t *testing.T) {
+	shirt := newProduct("Camiseta Azul", "Vestuário", "Algodão penteado")
+	shirt.Translations = map[string]model.Translation{
+		"en": {Name: "Blue Shirt", Description: "Combed cotton"},
+		"es": {Name: "Camiseta Azul", Description: "Algodón peinado"},
+	}
+	shirt.Category.Translations = map[string]model.Translation{"en": {Name: "Clothing"}}
+	shoes := newProduct("Tênis de Corrida", "Calçados", "Amortecimento em gel")
+	shoes.Translations = map[string]model.Translation{"en": {Name: "Running Shoes", Description: "Gel cushioning"}}
+
+	idx := NewIndex()
+	idx.Replace([]*model.Product{shirt, shoes})
+
+	tests := []struct {
+		name   string
+		query  string
+		locale string
+		want   []*model.Product
+	}{
+		{"termo em inglês no campo em inglês", "shirts", "en", []*model.Product{shirt}},
+		{"radical do inglês", "running shoe", "en", []*model.Product{shoes}},
+		{"categoria traduzida", "clothing", "en", []*model.Product{shirt}},
+		{"termo em inglês não pontua no campo em pt-BR", "shirt", "pt-BR", nil},
+		{"termo em pt-BR não pontua no campo em inglês", "camiseta", "en", nil},
+		{"sem tradução, o campo do idioma tem o texto em pt-BR", "tenis", "es", []*model.Product{shoes}},
+		{"stopwords do espanhol", "algodón del peinado", "es", []*model.Product{shirt}},
+		{"idioma vazio usa o pt-BR", "camisetas", "", []*model.Product{shirt}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := idx.Search(Query{Text: tt.query, Locale: tt.locale})
+			if result.Total != len(tt.want) {
+				t.Fatalf("Search(%q, %q) retornou %d produtos, esperado %d", tt.query, tt.locale, result.Total, len(tt.want))
+			}
+			for i, hit := range result.Hits {
+				if hit.Product.ID != tt.want[i].ID {
+					t.Errorf("posição %d: obtido %q, esperado %q", i, hit.Product.Name, tt.want[i].Name)
+				}
+			}
+		})
+	}
+}
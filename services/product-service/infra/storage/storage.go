@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("arquivo não encontrado")
+
+// Storage guarda os arquivos de mídia dos produtos. As chaves usam "/" como
+// separador (ex.: products/<id>/<arquivo>) e são independentes do backend.
+type Storage interface {
+	Save(key string, r io.Reader) error
+	Open(key string) (io.ReadSeekCloser, error)
+	Delete(key string) error
+	// URL retorna o endereço público do arquivo
+	URL(key string) string
+}
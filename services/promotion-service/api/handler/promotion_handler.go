@@ -32,6 +32,8 @@ func convertDTOToPromotion(promotionDTO dto.PromotionDTO) *model.Promotion {
 		Discount:      promotionDTO.Discount,
 		DiscountValue: promotionDTO.DiscountValue,
 		Status:        promotionDTO.Status,
+		ProductIDs:    promotionProductIDs(promotionDTO),
+		SKUs:          promotionDTO.SKUs,
 	}
 }
 
@@ -44,9 +46,20 @@ func convertDTOToPromotionWithoutID(promotionDTO dto.PromotionDTO) *model.Promot
 		Discount:      promotionDTO.Discount,
 		DiscountValue: promotionDTO.DiscountValue,
 		Status:        promotionDTO.Status,
+		ProductIDs:    promotionProductIDs(promotionDTO),
+		SKUs:          promotionDTO.SKUs,
 	}
 }
 
+// Aceita tanto a lista productIds quanto o campo legado productId
+func promotionProductIDs(promotionDTO dto.PromotionDTO) []string {
+	productIDs := promotionDTO.ProductIDs
+	if promotionDTO.ProductID != "" {
+		productIDs = append(productIDs, promotionDTO.ProductID)
+	}
+	return productIDs
+}
+
 // Listar Promoções
 func (h *PromotionHandler) ListPromotions(c *gin.Context) {
 	promotions, err := h.Service.ListAllPromotions()
@@ -15,6 +15,9 @@ type Promotion struct {
 	Discount      float64            `json:"discount" bson:"discount"`
 	DiscountValue float64            `json:"discountValue" bson:"discountValue"`
 	Status        PromoStatus        `json:"status" bson:"status"`
+	// Produtos e SKUs de variantes participantes; vazios, a promoção vale para todo o catálogo
+	ProductIDs []string `json:"productIds,omitempty" bson:"productIds,omitempty"`
+	SKUs       []string `json:"skus,omitempty" bson:"skus,omitempty"`
 }
 
 type PromoStatus string
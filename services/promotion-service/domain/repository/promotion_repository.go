@@ -152,6 +152,8 @@ func (r *MongoPromotionRepository) Update(promotion *model.Promotion) error {
 		"discount":      promotion.Discount,
 		"discountValue": promotion.DiscountValue,
 		"status":        promotion.Status,
+		"productIds":    promotion.ProductIDs,
+		"skus":          promotion.SKUs,
 	}
 
 	// Atualiza o documento
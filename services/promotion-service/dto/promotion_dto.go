@@ -15,6 +15,8 @@ type PromotionDTO struct {
 	EndDate       time.Time         `json:"endDate"`
 	DiscountValue float64           `json:"discountValue"`
 	ProductID     string            `json:"productId"`
+	ProductIDs    []string          `json:"productIds"`
+	SKUs          []string          `json:"skus"`
 	Status        model.PromoStatus `json:"status"`
 	UpdatedAt     time.Time         `json:"updatedAt"`
 